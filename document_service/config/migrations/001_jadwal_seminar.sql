@@ -0,0 +1,25 @@
+-- Jadwal seminar per final_proposal / final_laporan70 / final_laporan100
+CREATE TABLE IF NOT EXISTS jadwal_seminar (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	tahap ENUM('proposal', 'laporan70', 'laporan100') NOT NULL,
+	final_id INT NOT NULL,
+	user_id INT NOT NULL,
+	mulai DATETIME NOT NULL,
+	selesai DATETIME NOT NULL,
+	ruangan VARCHAR(255) NOT NULL DEFAULT '',
+	keterangan TEXT,
+	status ENUM('terjadwal', 'dibatalkan') NOT NULL DEFAULT 'terjadwal',
+	sequence INT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	UNIQUE KEY uniq_jadwal_tahap_final (tahap, final_id),
+	KEY idx_jadwal_user (user_id)
+);
+
+-- Token rahasia untuk feed kalender (webcal) per user
+CREATE TABLE IF NOT EXISTS kalender_token (
+	user_id INT PRIMARY KEY,
+	token CHAR(64) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE KEY uniq_kalender_token (token)
+);
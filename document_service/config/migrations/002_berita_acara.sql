@@ -0,0 +1,27 @@
+-- Nilai angka dan rekomendasi tiap dosen penguji per seminar
+CREATE TABLE IF NOT EXISTS nilai_seminar (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	tahap ENUM('proposal', 'laporan70', 'laporan100') NOT NULL,
+	final_id INT NOT NULL,
+	dosen_id INT NOT NULL,
+	nilai DECIMAL(5,2) NOT NULL,
+	rekomendasi ENUM('lulus', 'lulus_perbaikan', 'tidak_lulus') NOT NULL,
+	catatan TEXT,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	UNIQUE KEY uniq_nilai_seminar (tahap, final_id, dosen_id)
+);
+
+-- Dokumen yang dihasilkan sistem (BAP, dll). Setiap versi disimpan, tidak pernah ditimpa.
+CREATE TABLE IF NOT EXISTS dokumen_seminar (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	jenis VARCHAR(32) NOT NULL,
+	tahap ENUM('proposal', 'laporan70', 'laporan100') NOT NULL,
+	final_id INT NOT NULL,
+	versi INT NOT NULL,
+	file_path VARCHAR(512) NOT NULL,
+	file_hash CHAR(64) NOT NULL,
+	data_hash CHAR(64) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE KEY uniq_dokumen_versi (jenis, tahap, final_id, versi)
+);
@@ -0,0 +1,28 @@
+-- Kode verifikasi publik dan salinan bertanda tangan untuk dokumen yang dihasilkan sistem
+ALTER TABLE dokumen_seminar
+	ADD COLUMN kode_verifikasi CHAR(16) NULL AFTER data_hash,
+	ADD COLUMN signed_file_path VARCHAR(512) NULL AFTER kode_verifikasi,
+	ADD COLUMN signed_file_hash CHAR(64) NULL AFTER signed_file_path,
+	ADD UNIQUE KEY uniq_dokumen_kode (kode_verifikasi);
+
+-- Dokumen lama mendapat kode verifikasi agar tetap bisa ditandatangani
+UPDATE dokumen_seminar
+SET kode_verifikasi = UPPER(SUBSTRING(SHA2(CONCAT(id, '-', file_hash, '-', RAND()), 256), 1, 16))
+WHERE kode_verifikasi IS NULL;
+
+-- Tanda tangan elektronik dosen pada dokumen (BAP / lembar pengesahan)
+CREATE TABLE IF NOT EXISTS tanda_tangan (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	dokumen_id INT NOT NULL,
+	dosen_id INT NOT NULL,
+	peran VARCHAR(32) NOT NULL,
+	nama_dosen VARCHAR(255) NOT NULL,
+	signed_at DATETIME NOT NULL,
+	document_hash CHAR(64) NOT NULL,
+	signed_file_hash CHAR(64) NOT NULL,
+	session_hash CHAR(64) NOT NULL,
+	ip_address VARCHAR(64) NOT NULL DEFAULT '',
+	user_agent VARCHAR(255) NOT NULL DEFAULT '',
+	UNIQUE KEY uniq_tanda_tangan (dokumen_id, dosen_id),
+	KEY idx_tanda_tangan_dokumen (dokumen_id)
+);
@@ -0,0 +1,21 @@
+-- Catatan perbaikan terstruktur: satu baris per butir perbaikan dari dosen penguji
+CREATE TABLE IF NOT EXISTS catatan_perbaikan_item (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	tahap ENUM('proposal', 'laporan70', 'laporan100') NOT NULL,
+	final_id INT NOT NULL,
+	user_id INT NOT NULL,
+	dosen_id INT NOT NULL,
+	bagian VARCHAR(255) NOT NULL,
+	halaman VARCHAR(32) NOT NULL DEFAULT '',
+	perubahan TEXT NOT NULL,
+	status ENUM('open', 'addressed', 'accepted', 'reopened') NOT NULL DEFAULT 'open',
+	tanggapan TEXT,
+	halaman_revisi VARCHAR(32),
+	catatan_review TEXT,
+	addressed_at DATETIME NULL,
+	reviewed_at DATETIME NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	INDEX idx_catatan_seminar (tahap, final_id),
+	INDEX idx_catatan_taruna (user_id, tahap)
+);
@@ -0,0 +1,68 @@
+-- Lama masa revisi per tahap (dihitung dari selesainya seminar) dan jadwal pengingat (H-n)
+CREATE TABLE IF NOT EXISTS batas_revisi_config (
+	tahap ENUM('proposal', 'laporan70', 'laporan100') NOT NULL PRIMARY KEY,
+	durasi_hari INT NOT NULL,
+	pengingat_hari VARCHAR(64) NOT NULL DEFAULT '7,3,1',
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+INSERT IGNORE INTO batas_revisi_config (tahap, durasi_hari, pengingat_hari) VALUES
+	('proposal', 14, '7,3,1'),
+	('laporan70', 14, '7,3,1'),
+	('laporan100', 30, '14,7,3,1');
+
+-- Permohonan perpanjangan masa revisi; hanya yang disetujui yang menambah batas
+CREATE TABLE IF NOT EXISTS perpanjangan_revisi (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	tahap ENUM('proposal', 'laporan70', 'laporan100') NOT NULL,
+	final_id INT NOT NULL,
+	user_id INT NOT NULL,
+	tambahan_hari INT NOT NULL,
+	alasan TEXT NOT NULL,
+	status ENUM('menunggu', 'disetujui', 'ditolak') NOT NULL DEFAULT 'menunggu',
+	catatan TEXT,
+	diputuskan_oleh INT NULL,
+	diputuskan_at DATETIME NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	INDEX idx_perpanjangan_seminar (tahap, final_id)
+);
+
+-- Log pengingat & eskalasi yang sudah dikirim; unik agar tiap pengingat hanya terkirim sekali
+CREATE TABLE IF NOT EXISTS pengingat_revisi (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	tahap ENUM('proposal', 'laporan70', 'laporan100') NOT NULL,
+	final_id INT NOT NULL,
+	user_id INT NOT NULL,
+	jenis VARCHAR(32) NOT NULL,
+	pesan TEXT NOT NULL,
+	dibaca TINYINT(1) NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE KEY uniq_pengingat (tahap, final_id, jenis, user_id),
+	INDEX idx_pengingat_user (user_id, dibaca)
+);
+
+-- Batas revisi tiap seminar terjadwal. Dipakai bersama oleh document_service dan user_service
+-- agar perhitungan batas hanya ada di satu tempat.
+CREATE OR REPLACE VIEW v_batas_revisi AS
+SELECT
+	js.tahap,
+	js.final_id,
+	js.user_id,
+	js.selesai AS tanggal_seminar,
+	c.durasi_hari,
+	COALESCE(p.tambahan_hari, 0) AS perpanjangan_hari,
+	DATE_ADD(js.selesai, INTERVAL c.durasi_hari + COALESCE(p.tambahan_hari, 0) DAY) AS batas,
+	CASE js.tahap
+		WHEN 'proposal' THEN (SELECT MIN(r.created_at) FROM revisi_proposal r WHERE r.user_id = js.user_id AND r.created_at >= js.selesai)
+		WHEN 'laporan70' THEN (SELECT MIN(r.created_at) FROM revisi_laporan70 r WHERE r.user_id = js.user_id AND r.created_at >= js.selesai)
+		WHEN 'laporan100' THEN (SELECT MIN(r.created_at) FROM revisi_laporan100 r WHERE r.user_id = js.user_id AND r.created_at >= js.selesai)
+	END AS submitted_at
+FROM jadwal_seminar js
+JOIN batas_revisi_config c ON c.tahap = js.tahap
+LEFT JOIN (
+	SELECT tahap, final_id, SUM(tambahan_hari) AS tambahan_hari
+	FROM perpanjangan_revisi
+	WHERE status = 'disetujui'
+	GROUP BY tahap, final_id
+) p ON p.tahap = js.tahap AND p.final_id = js.final_id
+WHERE js.status = 'terjadwal';
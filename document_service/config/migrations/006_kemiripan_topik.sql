@@ -0,0 +1,12 @@
+-- Topik tugas akhir yang mirip dengan ICP baru di atas ambang kemiripan saat ICP diunggah
+CREATE TABLE IF NOT EXISTS kemiripan_icp (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	icp_id INT NOT NULL,
+	user_id_mirip INT NOT NULL,
+	sumber VARCHAR(32) NOT NULL,
+	topik_mirip VARCHAR(500) NOT NULL,
+	skor DECIMAL(5,4) NOT NULL,
+	istilah VARCHAR(500) NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	INDEX idx_kemiripan_icp (icp_id)
+);
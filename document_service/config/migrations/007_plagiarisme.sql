@@ -0,0 +1,39 @@
+-- Teks final laporan (70% dan 100%) hasil ekstraksi PDF yang menjadi korpus pemeriksaan plagiarisme.
+-- Kolom kata berisi kata yang sudah dinormalkan dan dipisah spasi; posisi sidik jari merujuk ke urutan ini.
+CREATE TABLE IF NOT EXISTS teks_dokumen (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	tabel ENUM('final_laporan70', 'final_laporan100') NOT NULL,
+	dokumen_id INT NOT NULL,
+	user_id INT NOT NULL,
+	kata MEDIUMTEXT NOT NULL,
+	jumlah_kata INT NOT NULL DEFAULT 0,
+	pesan_error VARCHAR(500) NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE KEY uniq_teks_dokumen (tabel, dokumen_id),
+	INDEX idx_teks_dokumen_user (user_id)
+);
+
+-- Sidik jari winnowing tiap dokumen; dicari berdasarkan hash untuk menemukan dokumen sumber
+CREATE TABLE IF NOT EXISTS sidik_dokumen (
+	teks_dokumen_id INT NOT NULL,
+	hash BIGINT NOT NULL,
+	posisi INT NOT NULL,
+	INDEX idx_sidik_hash (hash),
+	INDEX idx_sidik_dokumen (teks_dokumen_id)
+);
+
+-- Laporan kemiripan dokumen untuk setiap final laporan 100%, dibuat oleh job latar belakang
+CREATE TABLE IF NOT EXISTS laporan_plagiarisme (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	final_laporan100_id INT NOT NULL,
+	status ENUM('antri', 'proses', 'selesai', 'gagal') NOT NULL DEFAULT 'antri',
+	persentase DECIMAL(5,2) NULL,
+	jumlah_kata INT NOT NULL DEFAULT 0,
+	sumber MEDIUMTEXT NULL,
+	bagian MEDIUMTEXT NULL,
+	pesan_error VARCHAR(500) NULL,
+	selesai_at DATETIME NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	UNIQUE KEY uniq_laporan_plagiarisme (final_laporan100_id)
+);
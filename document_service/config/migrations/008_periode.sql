@@ -0,0 +1,14 @@
+-- Setiap pengumpulan menyimpan periode (gelombang) taruna saat diunggah; tabel periode_ta dan
+-- taruna.periode_id dikelola user_service. Data lama tanpa periode tetap NULL.
+ALTER TABLE icp ADD COLUMN periode_id INT NULL;
+ALTER TABLE proposal ADD COLUMN periode_id INT NULL;
+ALTER TABLE laporan_70 ADD COLUMN periode_id INT NULL;
+ALTER TABLE laporan_100 ADD COLUMN periode_id INT NULL;
+ALTER TABLE revisi_icp ADD COLUMN periode_id INT NULL;
+ALTER TABLE revisi_proposal ADD COLUMN periode_id INT NULL;
+ALTER TABLE revisi_laporan70 ADD COLUMN periode_id INT NULL;
+ALTER TABLE revisi_laporan100 ADD COLUMN periode_id INT NULL;
+ALTER TABLE final_icp ADD COLUMN periode_id INT NULL;
+ALTER TABLE final_proposal ADD COLUMN periode_id INT NULL;
+ALTER TABLE final_laporan70 ADD COLUMN periode_id INT NULL;
+ALTER TABLE final_laporan100 ADD COLUMN periode_id INT NULL;
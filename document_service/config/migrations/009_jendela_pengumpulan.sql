@@ -0,0 +1,25 @@
+-- Jendela unggah per tahap dan periode; periode_id NULL berlaku untuk semua periode. Tahap yang
+-- belum memiliki jendela sama sekali tetap bebas diunggah.
+CREATE TABLE IF NOT EXISTS jendela_pengumpulan (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	tahap VARCHAR(32) NOT NULL,
+	periode_id INT NULL,
+	dibuka DATETIME NOT NULL,
+	ditutup DATETIME NOT NULL,
+	keterangan VARCHAR(255) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	INDEX idx_jendela_tahap (tahap, periode_id)
+);
+
+-- Perpanjangan jendela untuk satu taruna; menggantikan waktu tutup jendela bagi taruna tersebut
+CREATE TABLE IF NOT EXISTS perpanjangan_pengumpulan (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	jendela_id INT NOT NULL,
+	user_id INT NOT NULL,
+	ditutup DATETIME NOT NULL,
+	alasan VARCHAR(255) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE KEY uq_perpanjangan_pengumpulan (jendela_id, user_id),
+	CONSTRAINT fk_perpanjangan_jendela FOREIGN KEY (jendela_id) REFERENCES jendela_pengumpulan (id) ON DELETE CASCADE
+);
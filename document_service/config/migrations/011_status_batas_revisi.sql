@@ -0,0 +1,48 @@
+-- Status batas revisi dihitung di view agar document_service dan user_service memakai satu rumus.
+-- Jam dinding WIB dihitung dari UTC supaya tidak bergantung pada zona waktu sesi MySQL. Hanya seminar
+-- yang sudah selesai dilaksanakan yang memiliki batas revisi.
+CREATE OR REPLACE VIEW v_batas_revisi AS
+SELECT
+	b.tahap,
+	b.final_id,
+	b.user_id,
+	b.tanggal_seminar,
+	b.durasi_hari,
+	b.perpanjangan_hari,
+	b.batas,
+	b.submitted_at,
+	CASE
+		WHEN b.submitted_at IS NOT NULL THEN 'selesai'
+		WHEN b.batas < b.sekarang THEN 'terlambat'
+		ELSE 'berjalan'
+	END AS status,
+	CASE
+		WHEN b.submitted_at IS NULL AND b.batas >= b.sekarang THEN TIMESTAMPDIFF(SECOND, b.sekarang, b.batas)
+		ELSE 0
+	END AS sisa_detik
+FROM (
+	SELECT
+		js.tahap,
+		js.final_id,
+		js.user_id,
+		js.selesai AS tanggal_seminar,
+		c.durasi_hari,
+		COALESCE(p.tambahan_hari, 0) AS perpanjangan_hari,
+		DATE_ADD(js.selesai, INTERVAL c.durasi_hari + COALESCE(p.tambahan_hari, 0) DAY) AS batas,
+		CASE js.tahap
+			WHEN 'proposal' THEN (SELECT MIN(r.created_at) FROM revisi_proposal r WHERE r.user_id = js.user_id AND r.created_at >= js.selesai)
+			WHEN 'laporan70' THEN (SELECT MIN(r.created_at) FROM revisi_laporan70 r WHERE r.user_id = js.user_id AND r.created_at >= js.selesai)
+			WHEN 'laporan100' THEN (SELECT MIN(r.created_at) FROM revisi_laporan100 r WHERE r.user_id = js.user_id AND r.created_at >= js.selesai)
+		END AS submitted_at,
+		CONVERT_TZ(UTC_TIMESTAMP(), '+00:00', '+07:00') AS sekarang
+	FROM jadwal_seminar js
+	JOIN batas_revisi_config c ON c.tahap = js.tahap
+	LEFT JOIN (
+		SELECT tahap, final_id, SUM(tambahan_hari) AS tambahan_hari
+		FROM perpanjangan_revisi
+		WHERE status = 'disetujui'
+		GROUP BY tahap, final_id
+	) p ON p.tahap = js.tahap AND p.final_id = js.final_id
+	WHERE js.status = 'terjadwal'
+		AND js.selesai <= CONVERT_TZ(UTC_TIMESTAMP(), '+00:00', '+07:00')
+) b;
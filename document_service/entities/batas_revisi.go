@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+// BatasRevisiConfig adalah lama masa revisi per tahap beserta jadwal pengingatnya
+type BatasRevisiConfig struct {
+	Tahap         string    `json:"tahap"`
+	DurasiHari    int       `json:"durasi_hari"`
+	PengingatHari []int     `json:"pengingat_hari"` // H-n sebelum batas, mis. [7, 3, 1]
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// BatasRevisi adalah batas pengumpulan revisi satu taruna setelah seminar
+type BatasRevisi struct {
+	Tahap            string     `json:"tahap"`
+	FinalID          int        `json:"final_id"`
+	UserID           int        `json:"user_id"`
+	NamaTaruna       string     `json:"nama_taruna"`
+	TanggalSeminar   time.Time  `json:"tanggal_seminar"`
+	DurasiHari       int        `json:"durasi_hari"`
+	PerpanjanganHari int        `json:"perpanjangan_hari"`
+	Batas            time.Time  `json:"batas"`
+	SubmittedAt      *time.Time `json:"submitted_at"`
+	Status           string     `json:"status"` // berjalan | selesai | terlambat
+	SisaDetik        int64      `json:"sisa_detik"`
+}
+
+// PerpanjanganRevisi adalah permohonan tambahan waktu revisi dari taruna
+type PerpanjanganRevisi struct {
+	ID             int        `json:"id"`
+	Tahap          string     `json:"tahap"`
+	FinalID        int        `json:"final_id"`
+	UserID         int        `json:"user_id"`
+	TambahanHari   int        `json:"tambahan_hari"`
+	Alasan         string     `json:"alasan"`
+	Status         string     `json:"status"` // menunggu | disetujui | ditolak
+	Catatan        string     `json:"catatan"`
+	DiputuskanOleh *int       `json:"diputuskan_oleh"`
+	DiputuskanAt   *time.Time `json:"diputuskan_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	NamaTaruna string `json:"nama_taruna,omitempty"`
+}
+
+// PengingatRevisi adalah pengingat / eskalasi yang dikirim ke satu pengguna
+type PengingatRevisi struct {
+	ID        int       `json:"id"`
+	Tahap     string    `json:"tahap"`
+	FinalID   int       `json:"final_id"`
+	UserID    int       `json:"user_id"`
+	Jenis     string    `json:"jenis"`
+	Pesan     string    `json:"pesan"`
+	Dibaca    bool      `json:"dibaca"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,78 @@
+package entities
+
+import "time"
+
+type NilaiSeminar struct {
+	ID          int       `json:"id"`
+	Tahap       string    `json:"tahap"`
+	FinalID     int       `json:"final_id"`
+	DosenID     int       `json:"dosen_id"`
+	Nilai       float64   `json:"nilai"`
+	Rekomendasi string    `json:"rekomendasi"` // lulus | lulus_perbaikan | tidak_lulus
+	Catatan     string    `json:"catatan"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DokumenSeminar adalah satu versi dokumen yang dihasilkan sistem (BAP / lembar pengesahan)
+type DokumenSeminar struct {
+	ID             int       `json:"id"`
+	Jenis          string    `json:"jenis"`
+	Tahap          string    `json:"tahap"`
+	FinalID        int       `json:"final_id"`
+	Versi          int       `json:"versi"`
+	FilePath       string    `json:"file_path"`
+	FileHash       string    `json:"file_hash"`
+	DataHash       string    `json:"data_hash"`
+	KodeVerifikasi string    `json:"kode_verifikasi"`
+	SignedFilePath string    `json:"signed_file_path"`
+	SignedFileHash string    `json:"signed_file_hash"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TandaTangan adalah tanda tangan elektronik satu dosen pada satu versi dokumen
+type TandaTangan struct {
+	ID             int       `json:"id"`
+	DokumenID      int       `json:"dokumen_id"`
+	DosenID        int       `json:"dosen_id"`
+	Peran          string    `json:"peran"`
+	NamaDosen      string    `json:"nama_dosen"`
+	SignedAt       time.Time `json:"signed_at"`
+	DocumentHash   string    `json:"document_hash"`
+	SignedFileHash string    `json:"-"`
+	SessionHash    string    `json:"-"`
+	IPAddress      string    `json:"-"`
+	UserAgent      string    `json:"-"`
+}
+
+type PengujiBAP struct {
+	Peran       string   `json:"peran"`
+	DosenID     int      `json:"dosen_id"`
+	Nama        string   `json:"nama"`
+	Nilai       *float64 `json:"nilai"`
+	Rekomendasi string   `json:"rekomendasi"`
+}
+
+// BeritaAcaraData berisi seluruh data yang dicetak pada Berita Acara Seminar.
+// Hash dari struct ini dipakai untuk mendeteksi perlu tidaknya versi baru.
+type BeritaAcaraData struct {
+	Tahap           string       `json:"tahap"`
+	FinalID         int          `json:"final_id"`
+	UserID          int          `json:"user_id"`
+	NamaTaruna      string       `json:"nama_taruna"`
+	NPM             string       `json:"npm"`
+	Jurusan         string       `json:"jurusan"`
+	Kelas           string       `json:"kelas"`
+	TopikPenelitian string       `json:"topik_penelitian"`
+	PembimbingID    int          `json:"pembimbing_id"`
+	Pembimbing      string       `json:"pembimbing"`
+	PendampingID    int          `json:"pendamping_id,omitempty"` // kosong bila taruna tidak punya pembimbing pendamping
+	Pendamping      string       `json:"pendamping,omitempty"`
+	Mulai           *time.Time   `json:"mulai"`
+	Selesai         *time.Time   `json:"selesai"`
+	Ruangan         string       `json:"ruangan"`
+	Penguji         []PengujiBAP `json:"penguji"`
+	RataRata        *float64     `json:"rata_rata"`
+	NilaiHuruf      string       `json:"nilai_huruf"`
+	Keputusan       string       `json:"keputusan"`
+}
@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// CatatanPerbaikanItem adalah satu butir perbaikan dari dosen penguji beserta tanggapan taruna
+type CatatanPerbaikanItem struct {
+	ID            int        `json:"id"`
+	Tahap         string     `json:"tahap"`
+	FinalID       int        `json:"final_id"`
+	UserID        int        `json:"user_id"`
+	DosenID       int        `json:"dosen_id"`
+	Bagian        string     `json:"bagian"`
+	Halaman       string     `json:"halaman"`
+	Perubahan     string     `json:"perubahan"`
+	Status        string     `json:"status"` // open | addressed | accepted | reopened
+	Tanggapan     string     `json:"tanggapan"`
+	HalamanRevisi string     `json:"halaman_revisi"`
+	CatatanReview string     `json:"catatan_review"`
+	AddressedAt   *time.Time `json:"addressed_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Diisi saat join untuk keperluan tampilan
+	NamaDosen string `json:"nama_dosen,omitempty"`
+}
@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+type JadwalSeminar struct {
+	ID         int       `json:"id"`
+	Tahap      string    `json:"tahap"` // proposal | laporan70 | laporan100
+	FinalID    int       `json:"final_id"`
+	UserID     int       `json:"user_id"`
+	Mulai      time.Time `json:"mulai"`
+	Selesai    time.Time `json:"selesai"`
+	Ruangan    string    `json:"ruangan"`
+	Keterangan string    `json:"keterangan"`
+	Status     string    `json:"status"` // terjadwal | dibatalkan
+	Sequence   int       `json:"sequence"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Diisi saat join untuk keperluan tampilan / feed kalender
+	NamaTaruna      string `json:"nama_taruna,omitempty"`
+	TopikPenelitian string `json:"topik_penelitian,omitempty"`
+	Peran           string `json:"peran,omitempty"`
+}
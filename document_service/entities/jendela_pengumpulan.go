@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// JendelaPengumpulan adalah rentang waktu unggah satu tahap; PeriodeID nil berarti semua periode
+type JendelaPengumpulan struct {
+	ID               int        `json:"id"`
+	Tahap            string     `json:"tahap"`
+	PeriodeID        *int       `json:"periode_id"`
+	Dibuka           time.Time  `json:"dibuka"`
+	Ditutup          time.Time  `json:"ditutup"`
+	Keterangan       string     `json:"keterangan"`
+	Status           string     `json:"status"` // belum_dibuka | dibuka | ditutup
+	JumlahPerpanjang int        `json:"jumlah_perpanjangan"`
+	DitutupUntukUser *time.Time `json:"ditutup_untuk_user,omitempty"` // terisi bila taruna mendapat perpanjangan
+}
+
+// PerpanjanganPengumpulan memundurkan waktu tutup jendela untuk satu taruna
+type PerpanjanganPengumpulan struct {
+	ID         int       `json:"id"`
+	JendelaID  int       `json:"jendela_id"`
+	UserID     int       `json:"user_id"`
+	NamaTaruna string    `json:"nama_taruna,omitempty"`
+	Ditutup    time.Time `json:"ditutup"`
+	Alasan     string    `json:"alasan"`
+}
@@ -0,0 +1,12 @@
+package entities
+
+// KemiripanTopik adalah tugas akhir lain (satu per taruna) yang mirip dengan teks yang diperiksa
+type KemiripanTopik struct {
+	UserID          int      `json:"user_id"`
+	NamaTaruna      string   `json:"nama_taruna"`
+	TopikPenelitian string   `json:"topik_penelitian"`
+	Sumber          string   `json:"sumber"` // tahap terakhir taruna tersebut, mis. final_laporan100
+	Tahun           string   `json:"tahun"`
+	Skor            float64  `json:"skor"`
+	Istilah         []string `json:"istilah"`
+}
@@ -0,0 +1,38 @@
+package entities
+
+// LaporanPlagiarisme adalah hasil pemeriksaan kemiripan dokumen satu final laporan 100% terhadap
+// seluruh final laporan lain yang tersimpan di SIMTA
+type LaporanPlagiarisme struct {
+	ID                int                 `json:"id"`
+	FinalLaporan100ID int                 `json:"final_laporan100_id"`
+	Status            string              `json:"status"` // antri, proses, selesai, gagal
+	Persentase        float64             `json:"persentase"`
+	JumlahKata        int                 `json:"jumlah_kata"`
+	Sumber            []SumberPlagiarisme `json:"sumber"`
+	Bagian            []BagianPlagiarisme `json:"bagian"`
+	PesanError        string              `json:"pesan_error,omitempty"`
+	SelesaiAt         string              `json:"selesai_at,omitempty"`
+	CreatedAt         string              `json:"created_at"`
+}
+
+// SumberPlagiarisme adalah dokumen lain yang memiliki bagian teks yang sama
+type SumberPlagiarisme struct {
+	Tabel           string  `json:"tabel"` // final_laporan70 atau final_laporan100
+	DokumenID       int     `json:"dokumen_id"`
+	UserID          int     `json:"user_id"`
+	NamaTaruna      string  `json:"nama_taruna"`
+	TopikPenelitian string  `json:"topik_penelitian"`
+	Tahun           string  `json:"tahun"`
+	Persentase      float64 `json:"persentase"` // porsi kata dokumen yang diperiksa yang cocok dengan sumber ini
+	JumlahBagian    int     `json:"jumlah_bagian"`
+}
+
+// BagianPlagiarisme adalah satu bagian teks yang cocok; Mulai dan Selesai adalah posisi kata
+// pada dokumen yang diperiksa, Sumber adalah indeks pada LaporanPlagiarisme.Sumber
+type BagianPlagiarisme struct {
+	Mulai      int    `json:"mulai"`
+	Selesai    int    `json:"selesai"`
+	Teks       string `json:"teks"`
+	Sumber     int    `json:"sumber"`
+	TeksSumber string `json:"teks_sumber"`
+}
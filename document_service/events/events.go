@@ -0,0 +1,114 @@
+// Package events mendefinisikan event domain document_service dan pengirimannya ke konsumen.
+// Event tidak dikirim langsung dari handler: handler mencatatnya di outbox dalam transaksi yang
+// sama dengan perubahan data, lalu relay (jobs.StartRelayOutbox) mengirimnya minimal sekali.
+// Konsumen wajib mengabaikan event dengan id yang sudah pernah diterima.
+package events
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Tipe event yang dikenali notification_service
+const (
+	DokumenDiunggah   = "document.submitted"
+	ReviewDiunggah    = "review.uploaded"
+	StatusBerubah     = "status.changed"
+	PengujiDitugaskan = "examiner.assigned"
+)
+
+const sumber = "document_service"
+
+// Data adalah muatan event dokumen
+type Data struct {
+	Tahap        string `json:"tahap"`   // kunci tahap unggah, mis. icp, final_proposal
+	Dokumen      string `json:"dokumen"` // label tahap untuk pesan, mis. "final proposal"
+	DokumenID    int    `json:"dokumen_id"`
+	TarunaUserID int    `json:"taruna_user_id"`
+	DosenUserIDs []int  `json:"dosen_user_ids,omitempty"`
+	Topik        string `json:"topik,omitempty"`
+	Status       string `json:"status,omitempty"`
+}
+
+// Event adalah amplop yang dikirim ke POST /events
+type Event struct {
+	ID     string    `json:"id"`
+	Tipe   string    `json:"tipe"`
+	Sumber string    `json:"sumber"`
+	Waktu  time.Time `json:"waktu"`
+	Data   Data      `json:"data"`
+}
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// ErrDitolak menandai event yang ditolak konsumen secara permanen (4xx); mengirim ulang tidak berguna
+var ErrDitolak = errors.New("event ditolak konsumen")
+
+// Baru menyusun event dengan id acak
+func Baru(tipe string, data Data) Event {
+	return Event{ID: idBaru(), Tipe: tipe, Sumber: sumber, Waktu: time.Now(), Data: data}
+}
+
+// Konsumen mengembalikan endpoint penerima event. Env EVENT_KONSUMEN berisi daftar URL dipisah koma;
+// default-nya POST /events pada NOTIFICATION_SERVICE_URL.
+func Konsumen() []string {
+	var urls []string
+	for _, u := range strings.Split(os.Getenv("EVENT_KONSUMEN"), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	if len(urls) == 0 {
+		urls = []string{strings.TrimRight(getEnv("NOTIFICATION_SERVICE_URL", "http://localhost:8083"), "/") + "/events"}
+	}
+	return urls
+}
+
+// Kirim mengirim payload event (JSON Event) ke satu konsumen
+func Kirim(url string, payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := os.Getenv("EVENT_SECRET"); token != "" {
+		req.Header.Set("X-Event-Token", token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s %s", ErrDitolak, url, resp.Status)
+	}
+	return fmt.Errorf("%s: %s", url, resp.Status)
+}
+
+func idBaru() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
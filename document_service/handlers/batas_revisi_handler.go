@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"database/sql"
+	"document_service/config"
+	"document_service/entities"
+	"document_service/models"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// maksTambahanHari membatasi satu permohonan perpanjangan
+const maksTambahanHari = 30
+
+// GetBatasRevisiConfigHandler menampilkan lama masa revisi dan hari pengingat per tahap
+func GetBatasRevisiConfigHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	configs, err := models.NewBatasRevisiModel(db).GetConfig()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   configs,
+	})
+}
+
+// UpdateBatasRevisiConfigHandler mengubah lama masa revisi satu tahap (admin)
+func UpdateBatasRevisiConfigHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req entities.BatasRevisiConfig
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Format request tidak valid"})
+		return
+	}
+	if !models.IsValidTahap(req.Tahap) || req.DurasiHari < 1 || req.DurasiHari > 365 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap wajib valid dan durasi_hari antara 1 sampai 365"})
+		return
+	}
+	for _, h := range req.PengingatHari {
+		if h < 1 || h > req.DurasiHari {
+			respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Hari pengingat harus antara 1 dan durasi_hari"})
+			return
+		}
+	}
+	// Normalisasi urutan dan duplikat lewat parser yang sama dengan yang dipakai saat membaca
+	parts := make([]string, len(req.PengingatHari))
+	for i, h := range req.PengingatHari {
+		parts[i] = strconv.Itoa(h)
+	}
+	req.PengingatHari, _ = models.ParsePengingatHari(strings.Join(parts, ","))
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	if err := models.NewBatasRevisiModel(db).UpdateConfig(&req); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Pengaturan masa revisi berhasil disimpan",
+		"data":    req,
+	})
+}
+
+// GetBatasRevisiHandler menampilkan batas revisi beserta sisa waktunya.
+// ?user_id= untuk taruna, ?tahap=&final_id= untuk satu seminar, ?status=berjalan|terlambat untuk admin.
+func GetBatasRevisiHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	q := r.URL.Query()
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	model := models.NewBatasRevisiModel(db)
+
+	var data interface{}
+	switch {
+	case q.Get("user_id") != "":
+		userID, convErr := strconv.Atoi(q.Get("user_id"))
+		if convErr != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "user_id tidak valid"})
+			return
+		}
+		data, err = model.GetBatasByUser(userID)
+	case q.Get("final_id") != "":
+		finalID, convErr := strconv.Atoi(q.Get("final_id"))
+		if convErr != nil || !models.IsValidTahap(q.Get("tahap")) {
+			respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap dan final_id tidak valid"})
+			return
+		}
+		var batas *entities.BatasRevisi
+		batas, err = model.GetBatas(q.Get("tahap"), finalID)
+		if err == sql.ErrNoRows {
+			respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Seminar belum dilaksanakan atau belum dijadwalkan"})
+			return
+		}
+		data = batas
+	default:
+		status := q.Get("status")
+		if status != "" && status != models.BatasBerjalan && status != models.BatasTerlambat {
+			respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "status harus berjalan atau terlambat"})
+			return
+		}
+		data, err = model.GetBatasBelumSubmit(status)
+	}
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   data,
+	})
+}
+
+// AjukanPerpanjanganRevisiHandler menyimpan permohonan perpanjangan masa revisi dari taruna
+func AjukanPerpanjanganRevisiHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req entities.PerpanjanganRevisi
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Format request tidak valid"})
+		return
+	}
+	req.Alasan = strings.TrimSpace(req.Alasan)
+	if !models.IsValidTahap(req.Tahap) || req.FinalID == 0 || req.UserID == 0 || req.Alasan == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap, final_id, user_id, dan alasan wajib diisi"})
+		return
+	}
+	if req.TambahanHari < 1 || req.TambahanHari > maksTambahanHari {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": fmt.Sprintf("tambahan_hari harus antara 1 dan %d", maksTambahanHari)})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	model := models.NewBatasRevisiModel(db)
+	batas, err := model.GetBatas(req.Tahap, req.FinalID)
+	if err == sql.ErrNoRows || (err == nil && batas.UserID != req.UserID) {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Batas revisi tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	if batas.Status == models.BatasSelesai {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{"status": "error", "message": "Revisi sudah dikumpulkan"})
+		return
+	}
+
+	if err := model.CreatePerpanjangan(&req); err == models.ErrPerpanjanganMenunggu {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"status":  "success",
+		"message": "Permohonan perpanjangan berhasil diajukan",
+		"data":    req,
+	})
+}
+
+// GetPerpanjanganRevisiHandler menampilkan permohonan perpanjangan (?status=menunggu, ?user_id=)
+func GetPerpanjanganRevisiHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	userID, _ := strconv.Atoi(r.URL.Query().Get("user_id"))
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	list, err := models.NewBatasRevisiModel(db).ListPerpanjangan(r.URL.Query().Get("status"), userID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// PutuskanPerpanjanganRevisiHandler menyetujui atau menolak permohonan perpanjangan (admin)
+func PutuskanPerpanjanganRevisiHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID permohonan tidak valid"})
+		return
+	}
+
+	var req struct {
+		Status  string `json:"status"`
+		Catatan string `json:"catatan"`
+		AdminID int    `json:"admin_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Format request tidak valid"})
+		return
+	}
+	if (req.Status != "disetujui" && req.Status != "ditolak") || req.AdminID == 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "status harus disetujui atau ditolak dan admin_id wajib diisi"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	model := models.NewBatasRevisiModel(db)
+	p, err := model.PutuskanPerpanjangan(id, req.Status, strings.TrimSpace(req.Catatan), req.AdminID)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{"status": "error", "message": "Permohonan tidak ditemukan atau sudah diputuskan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	pesan := fmt.Sprintf("Permohonan perpanjangan revisi %s Anda ditolak.", tahapLabel[p.Tahap])
+	if p.Status == "disetujui" {
+		pesan = fmt.Sprintf("Permohonan perpanjangan revisi %s Anda disetujui (+%d hari).", tahapLabel[p.Tahap], p.TambahanHari)
+		if batas, err := model.GetBatas(p.Tahap, p.FinalID); err == nil {
+			pesan += " Batas baru: " + batas.Batas.Format("02-01-2006 15:04") + " WIB."
+		}
+	}
+	if _, err := model.CatatPengingat(&entities.PengingatRevisi{
+		Tahap:   p.Tahap,
+		FinalID: p.FinalID,
+		UserID:  p.UserID,
+		Jenis:   fmt.Sprintf("perpanjangan-%d", p.ID),
+		Pesan:   pesan,
+	}); err != nil {
+		log.Printf("Gagal mencatat pemberitahuan perpanjangan #%d: %v", p.ID, err)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Permohonan perpanjangan " + p.Status,
+		"data":    p,
+	})
+}
+
+// GetPengingatHandler menampilkan pengingat & eskalasi milik pengguna (?user_id=)
+func GetPengingatHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	userID, err := strconv.Atoi(r.URL.Query().Get("user_id"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "user_id wajib diisi"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	list, err := models.NewBatasRevisiModel(db).GetPengingatByUser(userID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// TandaiPengingatDibacaHandler menandai satu pengingat sudah dibaca
+func TandaiPengingatDibacaHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	userID, errUser := strconv.Atoi(r.URL.Query().Get("user_id"))
+	if err != nil || errUser != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID pengingat dan user_id wajib diisi"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	if err := models.NewBatasRevisiModel(db).TandaiPengingatDibaca(id, userID); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"status": "success"})
+}
@@ -0,0 +1,479 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"document_service/config"
+	"document_service/entities"
+	"document_service/models"
+	"document_service/utils"
+	"document_service/utils/beritaacara"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const beritaAcaraDir = "uploads/berita_acara"
+
+var prefixDokumen = map[string]string{
+	models.JenisBAP:        "BAP",
+	models.JenisPengesahan: "PENGESAHAN",
+}
+
+// jenisDokumen membaca parameter jenis (default bap)
+func jenisDokumen(value string) (string, bool) {
+	if value == "" {
+		return models.JenisBAP, true
+	}
+	return value, models.IsValidJenisDokumen(value)
+}
+
+// dokumenData mengambil data yang dicetak pada dokumen. Lembar pengesahan tidak memuat
+// nilai dan jadwal, sehingga perubahan nilai tidak membatalkan tanda tangan pengesahan.
+func dokumenData(model *models.BeritaAcaraModel, jenis, tahap string, finalID int) (*entities.BeritaAcaraData, string, error) {
+	data, err := model.GetBeritaAcaraData(tahap, finalID)
+	if err != nil {
+		return nil, "", err
+	}
+	if jenis == models.JenisPengesahan {
+		data.Mulai, data.Selesai, data.Ruangan = nil, nil, ""
+		data.RataRata, data.NilaiHuruf, data.Keputusan = nil, "", ""
+		for i := range data.Penguji {
+			data.Penguji[i].Nilai = nil
+			data.Penguji[i].Rekomendasi = ""
+		}
+	}
+	dataHash, err := models.HashData(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, dataHash, nil
+}
+
+func renderDokumen(jenis string, data *entities.BeritaAcaraData, opts *beritaacara.Options) []byte {
+	if jenis == models.JenisPengesahan {
+		return beritaacara.RenderPengesahan(data, opts)
+	}
+	return beritaacara.Render(data, opts)
+}
+
+// verifyURL menyusun URL publik verifikasi dokumen yang dicetak sebagai QR code
+func verifyURL(kode string) string {
+	base := strings.TrimRight(getEnv("PUBLIC_BASE_URL", "https://securesimta.my.id"), "/")
+	return fmt.Sprintf("%s/api/document/verify/%s", base, kode)
+}
+
+func newKodeVerifikasi() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(buf)), nil
+}
+
+func writeDokumenFile(jenis, tahap string, finalID int, suffix string, pdf []byte) (string, string, error) {
+	if err := os.MkdirAll(beritaAcaraDir, 0750); err != nil {
+		return "", "", err
+	}
+	filename := fmt.Sprintf("%s_%s_%d_%s_%s.pdf", prefixDokumen[jenis], tahap, finalID, time.Now().Format("20060102150405"), suffix)
+	filePath := filepath.Join(beritaAcaraDir, filename)
+	if err := os.WriteFile(filePath, pdf, 0640); err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256(pdf)
+	return filePath, hex.EncodeToString(sum[:]), nil
+}
+
+// syncDokumenSeminar membuat versi dokumen baru bila data seminar berubah sejak versi terakhir.
+// Dengan force=true versi baru selalu dibuat. Nilai bool menandakan apakah versi baru dibuat.
+func syncDokumenSeminar(db *sql.DB, jenis, tahap string, finalID int, force bool) (*entities.DokumenSeminar, bool, error) {
+	model := models.NewBeritaAcaraModel(db)
+
+	data, dataHash, err := dokumenData(model, jenis, tahap, finalID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	latest, err := model.GetLatestDokumen(jenis, tahap, finalID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, false, err
+	}
+	if !force && latest != nil && latest.DataHash == dataHash {
+		return latest, false, nil
+	}
+
+	kode, err := newKodeVerifikasi()
+	if err != nil {
+		return nil, false, err
+	}
+	pdf := renderDokumen(jenis, data, &beritaacara.Options{KodeVerifikasi: kode, VerifyURL: verifyURL(kode)})
+
+	filePath, fileHash, err := writeDokumenFile(jenis, tahap, finalID, dataHash[:8], pdf)
+	if err != nil {
+		return nil, false, err
+	}
+
+	dokumen := &entities.DokumenSeminar{
+		Jenis:          jenis,
+		Tahap:          tahap,
+		FinalID:        finalID,
+		FilePath:       filePath,
+		FileHash:       fileHash,
+		DataHash:       dataHash,
+		KodeVerifikasi: kode,
+	}
+	if err := model.CreateDokumen(dokumen); err != nil {
+		_ = os.Remove(filePath)
+		return nil, false, err
+	}
+	return dokumen, true, nil
+}
+
+// refreshBeritaAcara dipanggil setelah data seminar berubah (jadwal, nilai). BAP selalu diperbarui,
+// lembar pengesahan hanya bila sudah pernah dibuat. Kegagalan hanya dicatat.
+func refreshBeritaAcara(db *sql.DB, tahap string, finalID int) {
+	for _, jenis := range []string{models.JenisBAP, models.JenisPengesahan} {
+		if jenis != models.JenisBAP {
+			if _, err := models.NewBeritaAcaraModel(db).GetLatestDokumen(jenis, tahap, finalID); err != nil {
+				continue
+			}
+		}
+		if dokumen, created, err := syncDokumenSeminar(db, jenis, tahap, finalID, false); err != nil {
+			log.Printf("Gagal memperbarui dokumen %s %s #%d: %v", jenis, tahap, finalID, err)
+		} else if created {
+			log.Printf("Dokumen %s %s #%d versi %d dibuat", jenis, tahap, finalID, dokumen.Versi)
+		}
+	}
+}
+
+// aksesDokumenSeminar memastikan pemilik token adalah admin, taruna penyaji, pembimbing, atau penguji
+// seminar; respons 401/403/404 sudah ditulis bila tidak. Mengembalikan klaim token.
+func aksesDokumenSeminar(w http.ResponseWriter, r *http.Request, db *sql.DB, tahap string, finalID int) (*utils.Claims, bool) {
+	claims, userID, ok := sesiDariToken(w, r, db)
+	if !ok {
+		return nil, false
+	}
+	if strings.ToLower(claims.Role) == "admin" {
+		return claims, true
+	}
+
+	dosenID, err := models.DosenIDDariEmail(db, claims.Email)
+	if err != nil && err != sql.ErrNoRows {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return nil, false
+	}
+	terlibat, err := models.NewBeritaAcaraModel(db).TerlibatSeminar(tahap, finalID, userID, dosenID)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Dokumen final tidak ditemukan"})
+		return nil, false
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return nil, false
+	}
+	if !terlibat {
+		respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": "Anda tidak terlibat pada seminar ini"})
+		return nil, false
+	}
+	return claims, true
+}
+
+// SubmitNilaiSeminarHandler menyimpan nilai angka dan rekomendasi dari dosen penguji yang sedang login
+func SubmitNilaiSeminarHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var body struct {
+		Tahap       string  `json:"tahap"`
+		FinalID     int     `json:"final_id"`
+		Nilai       float64 `json:"nilai"`
+		Rekomendasi string  `json:"rekomendasi"`
+		Catatan     string  `json:"catatan"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Format request tidak valid"})
+		return
+	}
+	req := entities.NilaiSeminar{
+		Tahap:       body.Tahap,
+		FinalID:     body.FinalID,
+		Nilai:       body.Nilai,
+		Rekomendasi: body.Rekomendasi,
+		Catatan:     body.Catatan,
+	}
+
+	if !models.IsValidTahap(req.Tahap) || req.FinalID == 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap dan final_id wajib diisi"})
+		return
+	}
+	if req.Nilai < 0 || req.Nilai > 100 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Nilai harus di antara 0 dan 100"})
+		return
+	}
+	if !models.IsValidRekomendasi(req.Rekomendasi) {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Rekomendasi harus lulus, lulus_perbaikan, atau tidak_lulus"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	// Dosen penilai adalah pemilik token, bukan id yang dikirim klien
+	claims, _, ok := sesiDariToken(w, r, db)
+	if !ok {
+		return
+	}
+	req.DosenID, err = models.DosenIDDariEmail(db, claims.Email)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": "Akun yang login bukan akun dosen"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	model := models.NewBeritaAcaraModel(db)
+
+	// Hanya dosen yang ditugaskan sebagai penguji seminar ini yang boleh menilai
+	penguji, err := model.GetPengujiIDs(req.Tahap, req.FinalID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	ditugaskan := false
+	for _, p := range penguji {
+		if p.DosenID == req.DosenID {
+			ditugaskan = true
+			break
+		}
+	}
+	if !ditugaskan {
+		respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": "Dosen bukan penguji pada seminar ini"})
+		return
+	}
+
+	if err := model.UpsertNilai(&req); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal menyimpan nilai: " + err.Error()})
+		return
+	}
+
+	refreshBeritaAcara(db, req.Tahap, req.FinalID)
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Nilai seminar berhasil disimpan",
+	})
+}
+
+// GetBeritaAcaraHandler menampilkan data BAP terkini beserta daftar seluruh versi yang pernah dibuat.
+// Parameter jenis=pengesahan untuk lembar pengesahan. Aksesnya sama dengan unduhan dokumen.
+func GetBeritaAcaraHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	tahap := r.URL.Query().Get("tahap")
+	finalID, err := strconv.Atoi(r.URL.Query().Get("final_id"))
+	if !models.IsValidTahap(tahap) || err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap dan final_id wajib diisi"})
+		return
+	}
+	jenis, ok := jenisDokumen(r.URL.Query().Get("jenis"))
+	if !ok {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "jenis harus bap atau pengesahan"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	if _, ok := aksesDokumenSeminar(w, r, db, tahap, finalID); !ok {
+		return
+	}
+
+	model := models.NewBeritaAcaraModel(db)
+
+	data, _, err := dokumenData(model, jenis, tahap, finalID)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Dokumen final tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	versi, err := model.ListDokumen(jenis, tahap, finalID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	if versi == nil {
+		versi = []entities.DokumenSeminar{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data": map[string]interface{}{
+			"berita_acara": data,
+			"versi":        versi,
+		},
+	})
+}
+
+// GenerateBeritaAcaraHandler membuat BAP atau lembar pengesahan (versi baru hanya bila data berubah, kecuali force=true).
+// force=true membatalkan tanda tangan yang sudah terkumpul sehingga hanya boleh dipakai admin.
+func GenerateBeritaAcaraHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req struct {
+		Jenis   string `json:"jenis"`
+		Tahap   string `json:"tahap"`
+		FinalID int    `json:"final_id"`
+		Force   bool   `json:"force"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !models.IsValidTahap(req.Tahap) || req.FinalID == 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap dan final_id wajib diisi"})
+		return
+	}
+	jenis, ok := jenisDokumen(req.Jenis)
+	if !ok {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "jenis harus bap atau pengesahan"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	claims, ok := aksesDokumenSeminar(w, r, db, req.Tahap, req.FinalID)
+	if !ok {
+		return
+	}
+	if req.Force && strings.ToLower(claims.Role) != "admin" {
+		respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": "Hanya admin yang dapat membuat ulang dokumen"})
+		return
+	}
+
+	dokumen, created, err := syncDokumenSeminar(db, jenis, req.Tahap, req.FinalID, req.Force)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Dokumen final tidak ditemukan"})
+		return
+	} else if err != nil {
+		log.Printf("Gagal membuat dokumen %s: %v", jenis, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal membuat dokumen"})
+		return
+	}
+
+	message := "Dokumen tidak berubah, versi terakhir digunakan"
+	if created {
+		message = fmt.Sprintf("Dokumen versi %d berhasil dibuat", dokumen.Versi)
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": message,
+		"data":    dokumen,
+	})
+}
+
+// DownloadBeritaAcaraHandler mengunduh BAP / lembar pengesahan. Dengan {id} mengunduh versi tertentu,
+// tanpa {id} (jenis, tahap & final_id) mengunduh versi terbaru yang sesuai data saat ini.
+// Salinan bertanda tangan diberikan bila sudah ada tanda tangan. Hanya admin, taruna penyaji, pembimbing,
+// dan penguji seminar yang boleh mengunduh.
+func DownloadBeritaAcaraHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	var dokumen *entities.DokumenSeminar
+	if idStr, ok := mux.Vars(r)["id"]; ok {
+		id, convErr := strconv.Atoi(idStr)
+		if convErr != nil {
+			http.Error(w, "ID dokumen tidak valid", http.StatusBadRequest)
+			return
+		}
+		dokumen, err = models.NewBeritaAcaraModel(db).GetDokumenByID(id)
+		if err == nil {
+			if _, ok := aksesDokumenSeminar(w, r, db, dokumen.Tahap, dokumen.FinalID); !ok {
+				return
+			}
+		}
+	} else {
+		tahap := r.URL.Query().Get("tahap")
+		finalID, convErr := strconv.Atoi(r.URL.Query().Get("final_id"))
+		jenis, jenisOK := jenisDokumen(r.URL.Query().Get("jenis"))
+		if !models.IsValidTahap(tahap) || convErr != nil || !jenisOK {
+			http.Error(w, "jenis, tahap dan final_id tidak valid", http.StatusBadRequest)
+			return
+		}
+		if _, ok := aksesDokumenSeminar(w, r, db, tahap, finalID); !ok {
+			return
+		}
+		dokumen, _, err = syncDokumenSeminar(db, jenis, tahap, finalID, false)
+	}
+	if err == sql.ErrNoRows {
+		http.Error(w, "Dokumen tidak ditemukan", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filePath := dokumen.FilePath
+	if dokumen.SignedFilePath != "" {
+		filePath = dokumen.SignedFilePath
+	}
+	if stat, err := os.Stat(filePath); err != nil || stat.IsDir() {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
+	fileName := fmt.Sprintf("%s_%s_%d_v%d.pdf", prefixDokumen[dokumen.Jenis], dokumen.Tahap, dokumen.FinalID, dokumen.Versi)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+	w.Header().Set("Content-Type", "application/pdf")
+	http.ServeFile(w, r, filePath)
+}
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"database/sql"
+	"document_service/config"
+	"document_service/entities"
+	"document_service/models"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// isPengujiSeminar mengecek apakah dosen ditugaskan sebagai penguji pada seminar
+func isPengujiSeminar(db *sql.DB, tahap string, finalID, dosenID int) (bool, error) {
+	penguji, err := models.NewBeritaAcaraModel(db).GetPengujiIDs(tahap, finalID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range penguji {
+		if p.DosenID == dosenID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateCatatanPerbaikanItemHandler menambahkan butir catatan perbaikan oleh dosen penguji
+func CreateCatatanPerbaikanItemHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req entities.CatatanPerbaikanItem
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Format request tidak valid"})
+		return
+	}
+
+	req.Bagian = strings.TrimSpace(req.Bagian)
+	req.Halaman = strings.TrimSpace(req.Halaman)
+	req.Perubahan = strings.TrimSpace(req.Perubahan)
+	if !models.IsValidTahap(req.Tahap) || req.FinalID == 0 || req.DosenID == 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap, final_id, dan dosen_id wajib diisi"})
+		return
+	}
+	if req.Bagian == "" || req.Perubahan == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Bagian dan perubahan yang diminta wajib diisi"})
+		return
+	}
+	if len(req.Bagian) > 255 || len(req.Halaman) > 32 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Bagian maksimal 255 karakter dan halaman maksimal 32 karakter"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	ok, err := isPengujiSeminar(db, req.Tahap, req.FinalID, req.DosenID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	if !ok {
+		respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": "Dosen bukan penguji pada seminar ini"})
+		return
+	}
+
+	userID, err := models.NewJadwalSeminarModel(db).GetFinalOwner(req.Tahap, req.FinalID)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Data final tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	req.UserID = userID
+
+	if err := models.NewCatatanPerbaikanModel(db).Create(&req); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal menyimpan catatan: " + err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"status":  "success",
+		"message": "Butir catatan perbaikan berhasil ditambahkan",
+		"data":    req,
+	})
+}
+
+// GetCatatanPerbaikanItemsHandler menampilkan butir catatan perbaikan.
+// Dosen/admin: ?tahap=&final_id=[&dosen_id=]. Taruna: ?tahap=&user_id=.
+func GetCatatanPerbaikanItemsHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	q := r.URL.Query()
+	tahap := q.Get("tahap")
+	finalID, _ := strconv.Atoi(q.Get("final_id"))
+	userID, _ := strconv.Atoi(q.Get("user_id"))
+	dosenID, _ := strconv.Atoi(q.Get("dosen_id"))
+	if !models.IsValidTahap(tahap) || (finalID == 0 && userID == 0) {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap dan final_id atau user_id wajib diisi"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	model := models.NewCatatanPerbaikanModel(db)
+	var items []entities.CatatanPerbaikanItem
+	if finalID != 0 {
+		items, err = model.GetBySeminar(tahap, finalID, dosenID)
+	} else {
+		items, err = model.GetByUser(userID, tahap)
+	}
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	ringkasan := models.RingkasanStatus(items)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data": map[string]interface{}{
+			"items":     items,
+			"ringkasan": ringkasan,
+			"selesai":   len(items) > 0 && ringkasan[models.CatatanAccepted] == len(items),
+		},
+	})
+}
+
+// TanggapiCatatanPerbaikanHandler dipakai taruna untuk menandai butir sudah diperbaiki
+func TanggapiCatatanPerbaikanHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID catatan tidak valid"})
+		return
+	}
+
+	var req struct {
+		UserID        int    `json:"user_id"`
+		Tanggapan     string `json:"tanggapan"`
+		HalamanRevisi string `json:"halaman_revisi"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Format request tidak valid"})
+		return
+	}
+	req.Tanggapan = strings.TrimSpace(req.Tanggapan)
+	req.HalamanRevisi = strings.TrimSpace(req.HalamanRevisi)
+	if req.UserID == 0 || req.Tanggapan == "" || req.HalamanRevisi == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "user_id, tanggapan, dan halaman_revisi wajib diisi"})
+		return
+	}
+	if len(req.HalamanRevisi) > 32 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "halaman_revisi maksimal 32 karakter"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	model := models.NewCatatanPerbaikanModel(db)
+	item, err := model.GetByID(id)
+	if err == sql.ErrNoRows || (err == nil && item.UserID != req.UserID) {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Catatan tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	if err := model.Tanggapi(id, req.UserID, req.Tanggapan, req.HalamanRevisi); err == models.ErrStatusCatatan {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{"status": "error", "message": "Catatan dengan status " + item.Status + " tidak dapat ditanggapi"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	item, _ = model.GetByID(id)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Tanggapan berhasil disimpan",
+		"data":    item,
+	})
+}
+
+// ReviewCatatanPerbaikanHandler dipakai dosen penguji untuk menerima atau membuka kembali butir
+func ReviewCatatanPerbaikanHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID catatan tidak valid"})
+		return
+	}
+
+	var req struct {
+		DosenID int    `json:"dosen_id"`
+		Status  string `json:"status"`
+		Catatan string `json:"catatan"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Format request tidak valid"})
+		return
+	}
+	req.Catatan = strings.TrimSpace(req.Catatan)
+	if req.DosenID == 0 || (req.Status != models.CatatanAccepted && req.Status != models.CatatanReopened) {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "dosen_id wajib diisi dan status harus accepted atau reopened"})
+		return
+	}
+	if req.Status == models.CatatanReopened && req.Catatan == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Alasan wajib diisi saat membuka kembali catatan"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	model := models.NewCatatanPerbaikanModel(db)
+	item, err := model.GetByID(id)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Catatan tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	if item.DosenID != req.DosenID {
+		respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": "Hanya dosen pembuat catatan yang dapat melakukan review"})
+		return
+	}
+
+	if err := model.Review(id, req.DosenID, req.Status, req.Catatan); err == models.ErrStatusCatatan {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{"status": "error", "message": "Catatan belum ditanggapi taruna atau sudah direview"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	item, _ = model.GetByID(id)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Review catatan berhasil disimpan",
+		"data":    item,
+	})
+}
+
+// DeleteCatatanPerbaikanItemHandler menghapus butir yang belum ditanggapi (?dosen_id=)
+func DeleteCatatanPerbaikanItemHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "DELETE, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	dosenID, errDosen := strconv.Atoi(r.URL.Query().Get("dosen_id"))
+	if err != nil || errDosen != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID catatan dan dosen_id wajib diisi"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	if err := models.NewCatatanPerbaikanModel(db).Delete(id, dosenID); err == models.ErrStatusCatatan {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{"status": "error", "message": "Catatan tidak ditemukan atau sudah ditanggapi taruna"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Catatan berhasil dihapus",
+	})
+}
+
+// cekCatatanSelesai menolak persetujuan revisi bila masih ada butir catatan yang belum accepted.
+// Mengembalikan false bila response sudah ditulis.
+func cekCatatanSelesai(w http.ResponseWriter, db *sql.DB, tahap string, revisiID int) bool {
+	belum, err := models.NewCatatanPerbaikanModel(db).CountBelumDiterimaByRevisi(tahap, revisiID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return false
+	}
+	if belum > 0 {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{
+			"status":  "error",
+			"message": strconv.Itoa(belum) + " butir catatan perbaikan belum diterima dosen penguji",
+			"data":    map[string]interface{}{"belum_diterima": belum},
+		})
+		return false
+	}
+	return true
+}
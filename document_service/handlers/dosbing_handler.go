@@ -43,11 +43,29 @@ func GetDosbingByUserID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Pembimbing pendamping (opsional) ikut dikirim agar taruna tahu kedua pembimbingnya
+	data := map[string]interface{}{
+		"dosen_id": dosenID,
+		"nama":     namaLengkap,
+	}
+	var pendampingID int
+	var namaPendamping string
+	err = db.QueryRow(`
+		SELECT d.id, d.nama_lengkap FROM dosbing_pendamping dp
+		JOIN dosen d ON dp.dosen_id = d.id
+		WHERE dp.user_id = ? AND dp.status = 'aktif' LIMIT 1`, userID).Scan(&pendampingID, &namaPendamping)
+	if err == nil {
+		data["pendamping"] = map[string]interface{}{
+			"dosen_id": pendampingID,
+			"nama":     namaPendamping,
+		}
+	} else if err != sql.ErrNoRows {
+		http.Error(w, "Query error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status": "success",
-		"data": map[string]interface{}{
-			"dosen_id": dosenID,
-			"nama":     namaLengkap,
-		},
+		"data":   data,
 	})
 }
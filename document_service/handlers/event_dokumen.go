@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"database/sql"
+	"document_service/events"
+	"document_service/jobs"
+	"document_service/models"
+)
+
+// catatEventDokumen mencatat event untuk satu dokumen di outbox, di dalam transaksi yang menyimpan
+// perubahannya. Penerima notifikasi ditentukan konsumen dari taruna dan dosen pada event.
+// Dokumen yang tidak ditemukan tidak menghasilkan event.
+func catatEventDokumen(tx *sql.Tx, tipe, tahap string, id int, status string) error {
+	s, err := models.NewSubjekDokumenModel(tx).Get(tahap, id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	data := events.Data{
+		Tahap:        tahap,
+		Dokumen:      models.LabelTahapUnggah(tahap),
+		DokumenID:    id,
+		TarunaUserID: s.TarunaUserID,
+		Topik:        s.Topik,
+		Status:       status,
+	}
+	if s.DosenUserID != 0 {
+		data.DosenUserIDs = []int{s.DosenUserID}
+	}
+	return models.CatatOutbox(tx, events.Baru(tipe, data))
+}
+
+// simpanDenganEvent menjalankan simpan dan mencatat event dokumennya dalam satu transaksi, lalu
+// membangunkan relay outbox. simpan mengembalikan id dokumen yang berubah.
+func simpanDenganEvent(db *sql.DB, tipe, tahap, status string, simpan func(tx *sql.Tx) (int, error)) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	id, err := simpan(tx)
+	if err != nil {
+		return err
+	}
+	if err := catatEventDokumen(tx, tipe, tahap, id, status); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	jobs.SegerakanOutbox()
+	return nil
+}
@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
 	"document_service/models"
 	"document_service/utils/filemanager"
+	"document_service/utils/halaman"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -50,6 +52,9 @@ func UploadFinalICPHandler(w http.ResponseWriter, r *http.Request) {
 
 	// ===== Ambil field =====
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahFinalICP, userID) {
+		return
+	}
 	namaLengkap := r.FormValue("nama_lengkap")
 	jurusan := r.FormValue("jurusan")
 	kelas := r.FormValue("kelas")
@@ -216,7 +221,6 @@ func UploadFinalICPHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	finalICPModel := models.NewFinalICPModel(db)
 	finalICP := &entities.FinalICP{
 		UserID:          userIDInt,
 		NamaLengkap:     namaLengkap,
@@ -239,7 +243,11 @@ func UploadFinalICPHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := finalICPModel.Create(finalICP); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahFinalICP, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewFinalICPModel(tx).Create(finalICP)
+		return finalICP.ID, err
+	})
+	if err != nil {
 		for _, p := range savedPaths {
 			_ = os.Remove(p)
 		}
@@ -359,6 +367,11 @@ func GetAllFinalICPWithTarunaHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	k, kondisi, args, ok := kueriDaftarTaruna(w, r)
+	if !ok {
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -366,6 +379,17 @@ func GetAllFinalICPWithTarunaHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
+	dari := `
+		FROM taruna t
+		LEFT JOIN final_icp f ON t.user_id = f.user_id
+		WHERE ` + kondisi
+	total, err := halaman.Hitung(db, dari, args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	potong, argsPotong := k.Potong()
 	// Tambahkan kolom file_pendukung_path dari tabel final_icp
 	query := `
 		SELECT 
@@ -376,13 +400,10 @@ func GetAllFinalICPWithTarunaHandler(w http.ResponseWriter, r *http.Request) {
 			COALESCE(f.topik_penelitian, '') as topik_penelitian,
 			COALESCE(f.status, '') as status,
 			COALESCE(f.id, 0) as final_icp_id,
-			COALESCE(f.file_pendukung_path, '') as file_pendukung_path
-		FROM taruna t
-		LEFT JOIN final_icp f ON t.user_id = f.user_id
-		ORDER BY t.nama_lengkap ASC
-	`
+			COALESCE(f.file_pendukung_path, '') as file_pendukung_path` +
+		dari + k.OrderBy() + potong
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, append(args, argsPotong...)...)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -427,6 +448,7 @@ func GetAllFinalICPWithTarunaHandler(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
 		"status": "success",
 		"data":   results,
+		"meta":   k.Meta(total),
 	})
 }
 
@@ -460,7 +482,10 @@ func UpdateFinalICPStatusHandler(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	query := "UPDATE final_icp SET status = ? WHERE id = ?"
-	_, err = db.Exec(query, requestData.Status, requestData.ID)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahFinalICP, requestData.Status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(query, requestData.Status, requestData.ID)
+		return requestData.ID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -605,10 +630,10 @@ func SetPenelaahICPHandler(w http.ResponseWriter, r *http.Request) {
 	if err == sql.ErrNoRows {
 		// Insert baru
 		insertQuery := `
-			INSERT INTO penelaah_icp (final_icp_id, user_id, penelaah_1_id, penelaah_2_id, topik_penelitian)
-			VALUES (?, ?, ?, ?, ?)
+			INSERT INTO penelaah_icp (final_icp_id, user_id, penelaah_1_id, penelaah_2_id, topik_penelitian, periode_id)
+			VALUES (?, ?, ?, ?, ?, (SELECT periode_id FROM taruna WHERE user_id = ?))
 		`
-		_, err = db.Exec(insertQuery, requestData.FinalICPID, requestData.UserID, requestData.Penelaah1ID, requestData.Penelaah2ID, requestData.Topik)
+		_, err = db.Exec(insertQuery, requestData.FinalICPID, requestData.UserID, requestData.Penelaah1ID, requestData.Penelaah2ID, requestData.Topik, requestData.UserID)
 	} else {
 		// Update yang sudah ada
 		updateQuery := `
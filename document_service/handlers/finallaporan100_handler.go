@@ -4,11 +4,15 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
+	"document_service/jobs"
 	"document_service/models"
 	"document_service/utils"
 	"document_service/utils/filemanager"
+	"document_service/utils/halaman"
 	"encoding/json"
 	"fmt"
+	"log"
 	"mime"
 	"net/http"
 	"os"
@@ -52,6 +56,9 @@ func UploadFinalLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 
 	// ===== Ambil field =====
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahFinalLaporan100, userID) {
+		return
+	}
 	namaLengkap := r.FormValue("nama_lengkap")
 	jurusan := r.FormValue("jurusan")
 	kelas := r.FormValue("kelas")
@@ -257,7 +264,6 @@ func UploadFinalLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	finalLaporan100Model := models.NewFinalLaporan100Model(db)
 	finalLaporan100 := &entities.FinalLaporan100{
 		UserID:            userIDInt,
 		NamaLengkap:       namaLengkap,
@@ -278,7 +284,11 @@ func UploadFinalLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := finalLaporan100Model.Create(finalLaporan100); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahFinalLaporan100, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewFinalLaporan100Model(tx).Create(finalLaporan100)
+		return finalLaporan100.ID, err
+	})
+	if err != nil {
 		cleanup()
 		_ = json.NewEncoder(w).Encode(map[string]any{
 			"status":  "error",
@@ -287,10 +297,18 @@ func UploadFinalLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// ===== PEMERIKSAAN KEMIRIPAN (latar belakang) =====
+	// Laporan dibuat berstatus antri; bila gagal diantrekan, penyapuan berkala akan membuatnya
+	if err := models.NewPlagiarismeModel(db).Antrekan(finalLaporan100.ID); err != nil {
+		log.Printf("Gagal mengantrekan pemeriksaan plagiarisme final laporan 100%% %d: %v", finalLaporan100.ID, err)
+	} else {
+		jobs.AntrekanPlagiarisme(finalLaporan100.ID)
+	}
+
 	// ===== RESPONSE =====
 	_ = json.NewEncoder(w).Encode(map[string]any{
 		"status":  "success",
-		"message": "Final Laporan 100% dan file pendukung berhasil diunggah",
+		"message": "Final Laporan 100% dan file pendukung berhasil diunggah; laporan kemiripan dokumen sedang diproses",
 		"data": map[string]any{
 			"id":                  finalLaporan100.ID,
 			"file_path":           finalFilePath,
@@ -396,6 +414,11 @@ func GetAllFinalLaporan100WithTarunaHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	k, kondisi, args, ok := kueriDaftarTaruna(w, r)
+	if !ok {
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -403,6 +426,17 @@ func GetAllFinalLaporan100WithTarunaHandler(w http.ResponseWriter, r *http.Reque
 	}
 	defer db.Close()
 
+	dari := `
+		FROM taruna t
+		LEFT JOIN final_laporan100 f ON t.user_id = f.user_id
+		WHERE ` + kondisi
+	total, err := halaman.Hitung(db, dari, args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	potong, argsPotong := k.Potong()
 	// Tambahkan kolom file_pendukung_path dari tabel final_laporan100
 	query := `
 		SELECT 
@@ -413,13 +447,10 @@ func GetAllFinalLaporan100WithTarunaHandler(w http.ResponseWriter, r *http.Reque
 			COALESCE(f.topik_penelitian, '') AS topik_penelitian,
 			COALESCE(f.status, '') AS status,
 			COALESCE(f.id, 0) AS final_laporan100_id,
-			COALESCE(f.file_pendukung_path, '[]') AS file_pendukung_path
-		FROM taruna t
-		LEFT JOIN final_laporan100 f ON t.user_id = f.user_id
-		ORDER BY t.nama_lengkap ASC
-	`
+			COALESCE(f.file_pendukung_path, '[]') AS file_pendukung_path` +
+		dari + k.OrderBy() + potong
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, append(args, argsPotong...)...)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -463,6 +494,7 @@ func GetAllFinalLaporan100WithTarunaHandler(w http.ResponseWriter, r *http.Reque
 	_ = json.NewEncoder(w).Encode(map[string]any{
 		"status": "success",
 		"data":   results,
+		"meta":   k.Meta(total),
 	})
 }
 
@@ -496,7 +528,10 @@ func UpdateFinalLaporan100StatusHandler(w http.ResponseWriter, r *http.Request)
 	defer db.Close()
 
 	query := "UPDATE final_laporan100 SET status = ? WHERE id = ?"
-	_, err = db.Exec(query, requestData.Status, requestData.ID)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahFinalLaporan100, requestData.Status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(query, requestData.Status, requestData.ID)
+		return requestData.ID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
 	"document_service/models"
 	"document_service/utils"
 	"document_service/utils/filemanager"
+	"document_service/utils/halaman"
 	"encoding/json"
 	"fmt"
 	"mime"
@@ -52,6 +54,9 @@ func UploadFinalLaporan70Handler(w http.ResponseWriter, r *http.Request) {
 
 	// ===== Ambil field =====
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahFinalLaporan70, userID) {
+		return
+	}
 	namaLengkap := r.FormValue("nama_lengkap")
 	jurusan := r.FormValue("jurusan")
 	kelas := r.FormValue("kelas")
@@ -257,7 +262,6 @@ func UploadFinalLaporan70Handler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	finalLaporan70Model := models.NewFinalLaporan70Model(db)
 	finalLaporan70 := &entities.FinalLaporan70{
 		UserID:            userIDInt,
 		NamaLengkap:       namaLengkap,
@@ -278,7 +282,11 @@ func UploadFinalLaporan70Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := finalLaporan70Model.Create(finalLaporan70); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahFinalLaporan70, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewFinalLaporan70Model(tx).Create(finalLaporan70)
+		return finalLaporan70.ID, err
+	})
+	if err != nil {
 		cleanup()
 		_ = json.NewEncoder(w).Encode(map[string]any{
 			"status":  "error",
@@ -396,6 +404,11 @@ func GetAllFinalLaporan70WithTarunaHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	k, kondisi, args, ok := kueriDaftarTaruna(w, r)
+	if !ok {
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -403,6 +416,17 @@ func GetAllFinalLaporan70WithTarunaHandler(w http.ResponseWriter, r *http.Reques
 	}
 	defer db.Close()
 
+	dari := `
+		FROM taruna t
+		LEFT JOIN final_laporan70 f ON t.user_id = f.user_id
+		WHERE ` + kondisi
+	total, err := halaman.Hitung(db, dari, args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	potong, argsPotong := k.Potong()
 	// Tambahkan kolom file_pendukung_path dari tabel final_laporan70
 	query := `
 		SELECT 
@@ -413,13 +437,10 @@ func GetAllFinalLaporan70WithTarunaHandler(w http.ResponseWriter, r *http.Reques
 			COALESCE(f.topik_penelitian, '') AS topik_penelitian,
 			COALESCE(f.status, '') AS status,
 			COALESCE(f.id, 0) AS final_laporan70_id,
-			COALESCE(f.file_pendukung_path, '[]') AS file_pendukung_path
-		FROM taruna t
-		LEFT JOIN final_laporan70 f ON t.user_id = f.user_id
-		ORDER BY t.nama_lengkap ASC
-	`
+			COALESCE(f.file_pendukung_path, '[]') AS file_pendukung_path` +
+		dari + k.OrderBy() + potong
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, append(args, argsPotong...)...)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -463,6 +484,7 @@ func GetAllFinalLaporan70WithTarunaHandler(w http.ResponseWriter, r *http.Reques
 	_ = json.NewEncoder(w).Encode(map[string]any{
 		"status": "success",
 		"data":   results,
+		"meta":   k.Meta(total),
 	})
 }
 
@@ -496,7 +518,10 @@ func UpdateFinalLaporan70StatusHandler(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	query := "UPDATE final_laporan70 SET status = ? WHERE id = ?"
-	_, err = db.Exec(query, requestData.Status, requestData.ID)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahFinalLaporan70, requestData.Status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(query, requestData.Status, requestData.ID)
+		return requestData.ID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
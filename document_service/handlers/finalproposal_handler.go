@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
 	"document_service/models"
 	"document_service/utils"
 	"document_service/utils/filemanager"
+	"document_service/utils/halaman"
 	"encoding/json"
 	"fmt"
 	"mime"
@@ -52,6 +54,9 @@ func UploadFinalProposalHandler(w http.ResponseWriter, r *http.Request) {
 
 	// ===== Ambil field =====
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahFinalProposal, userID) {
+		return
+	}
 	namaLengkap := r.FormValue("nama_lengkap")
 	jurusan := r.FormValue("jurusan")
 	kelas := r.FormValue("kelas")
@@ -257,7 +262,6 @@ func UploadFinalProposalHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	finalProposalModel := models.NewFinalProposalModel(db)
 	finalProposal := &entities.FinalProposal{
 		UserID:            userIDInt,
 		NamaLengkap:       namaLengkap,
@@ -278,7 +282,11 @@ func UploadFinalProposalHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := finalProposalModel.Create(finalProposal); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahFinalProposal, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewFinalProposalModel(tx).Create(finalProposal)
+		return finalProposal.ID, err
+	})
+	if err != nil {
 		cleanup()
 		_ = json.NewEncoder(w).Encode(map[string]any{
 			"status":  "error",
@@ -401,6 +409,11 @@ func GetAllFinalProposalWithTarunaHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	k, kondisi, args, ok := kueriDaftarTaruna(w, r)
+	if !ok {
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -408,6 +421,17 @@ func GetAllFinalProposalWithTarunaHandler(w http.ResponseWriter, r *http.Request
 	}
 	defer db.Close()
 
+	dari := `
+		FROM taruna t
+		LEFT JOIN final_proposal f ON t.user_id = f.user_id
+		WHERE ` + kondisi
+	total, err := halaman.Hitung(db, dari, args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	potong, argsPotong := k.Potong()
 	// Tambahkan kolom file_pendukung_path dari tabel final_proposal
 	query := `
 		SELECT 
@@ -418,13 +442,10 @@ func GetAllFinalProposalWithTarunaHandler(w http.ResponseWriter, r *http.Request
 			COALESCE(f.topik_penelitian, '') AS topik_penelitian,
 			COALESCE(f.status, '') AS status,
 			COALESCE(f.id, 0) AS final_proposal_id,
-			COALESCE(f.file_pendukung_path, '[]') AS file_pendukung_path
-		FROM taruna t
-		LEFT JOIN final_proposal f ON t.user_id = f.user_id
-		ORDER BY t.nama_lengkap ASC
-	`
+			COALESCE(f.file_pendukung_path, '[]') AS file_pendukung_path` +
+		dari + k.OrderBy() + potong
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, append(args, argsPotong...)...)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -468,6 +489,7 @@ func GetAllFinalProposalWithTarunaHandler(w http.ResponseWriter, r *http.Request
 	_ = json.NewEncoder(w).Encode(map[string]any{
 		"status": "success",
 		"data":   results,
+		"meta":   k.Meta(total),
 	})
 }
 
@@ -501,7 +523,10 @@ func UpdateFinalProposalStatusHandler(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	query := "UPDATE final_proposal SET status = ? WHERE id = ?"
-	_, err = db.Exec(query, requestData.Status, requestData.ID)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahFinalProposal, requestData.Status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(query, requestData.Status, requestData.ID)
+		return requestData.ID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
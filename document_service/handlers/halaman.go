@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"document_service/models"
+	"document_service/utils/halaman"
+	"net/http"
+)
+
+// aturanDaftarTaruna adalah urutan yang diizinkan untuk daftar /all (taruna t LEFT JOIN dokumen f)
+var aturanDaftarTaruna = halaman.Aturan{
+	Urut: map[string]string{
+		"nama_lengkap":     "t.nama_lengkap",
+		"jurusan":          "t.jurusan",
+		"kelas":            "t.kelas",
+		"npm":              "t.npm",
+		"topik_penelitian": "f.topik_penelitian",
+		"status":           "f.status",
+	},
+	UrutDefault: "nama_lengkap",
+	Kunci:       "t.user_id, f.id",
+}
+
+// kueriDaftarTaruna membaca parameter halaman dan filter bersama endpoint /all: periode_id, jurusan,
+// kelas, status dokumen, dan q (nama, NPM, atau topik). Mengembalikan kondisi WHERE beserta argumennya;
+// respons 400 sudah ditulis bila parameter tidak valid.
+func kueriDaftarTaruna(w http.ResponseWriter, r *http.Request) (*halaman.Kueri, string, []interface{}, bool) {
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return nil, "", nil, false
+	}
+	k, err := halaman.Parse(r, aturanDaftarTaruna)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, "", nil, false
+	}
+
+	var f halaman.Filter
+	kondisiPeriode, argsPeriode := models.KondisiPeriode("t.periode_id", periodeID)
+	f.Tambah(kondisiPeriode, argsPeriode...)
+	f.Sama("t.jurusan", r.URL.Query().Get("jurusan"))
+	f.Sama("t.kelas", r.URL.Query().Get("kelas"))
+	f.Sama("COALESCE(f.status, '')", r.URL.Query().Get("status"))
+	f.Cari(k.Q, "t.nama_lengkap", "CAST(t.npm AS CHAR)", "f.topik_penelitian")
+	return k, f.SQL(), f.Args(), true
+}
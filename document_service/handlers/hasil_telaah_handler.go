@@ -3,6 +3,8 @@ package handlers
 import (
 	"database/sql"
 	"document_service/config"
+	"document_service/events"
+	"document_service/models"
 	"document_service/utils"
 	"document_service/utils/filemanager"
 	"encoding/json"
@@ -139,7 +141,15 @@ func UploadHasilTelaahHandler(w http.ResponseWriter, r *http.Request) {
 	query := `INSERT INTO hasil_telaah_icp (icp_id, dosen_id, user_id, topik_penelitian, file_path, tanggal_telaah) 
 			  VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
 
-	result, err := db.Exec(query, icpID, dosenID, userID, topikPenelitian, filePath)
+	var id int64
+	err = simpanDenganEvent(db, events.ReviewDiunggah, models.UnggahFinalICP, "", func(tx *sql.Tx) (int, error) {
+		result, err := tx.Exec(query, icpID, dosenID, userID, topikPenelitian, filePath)
+		if err != nil {
+			return 0, err
+		}
+		id, _ = result.LastInsertId()
+		return icpID, nil
+	})
 	if err != nil {
 		os.Remove(filePath)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -149,9 +159,6 @@ func UploadHasilTelaahHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get inserted ID
-	id, _ := result.LastInsertId()
-
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
 		"message": "Hasil telaah berhasil diunggah",
@@ -320,6 +327,12 @@ func GetMonitoringTelaahHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
+	kondisiPeriode, argsPeriode := models.KondisiPeriode("fi.periode_id", periodeID)
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, "Database error: "+err.Error(), http.StatusInternalServerError)
@@ -347,13 +360,14 @@ func GetMonitoringTelaahHandler(w http.ResponseWriter, r *http.Request) {
 		LEFT JOIN dosen d1 ON d1.id = pi.penelaah_1_id
 		LEFT JOIN dosen d2 ON d2.id = pi.penelaah_2_id
 		LEFT JOIN hasil_telaah_icp ht ON ht.icp_id = fi.id
+		WHERE ` + kondisiPeriode + `
 
 		GROUP BY fi.id, u.nama_lengkap, fi.jurusan, fi.kelas, fi.topik_penelitian,
 		         d1.nama_lengkap, d2.nama_lengkap, fi.status
 		ORDER BY u.nama_lengkap ASC
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, argsPeriode...)
 	if err != nil {
 		log.Printf("Query error: %v", err)
 		json.NewEncoder(w).Encode(map[string]interface{}{
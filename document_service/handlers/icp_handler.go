@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
 	"document_service/models"
 	"document_service/utils/filemanager"
 	"encoding/json"
@@ -27,7 +28,7 @@ func UploadICPHandler(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers
 	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 	w.Header().Set("Content-Type", "application/json")
 
 	if r.Method == "OPTIONS" {
@@ -54,6 +55,9 @@ func UploadICPHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Ambil form values
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahICP, userID) {
+		return
+	}
 	dosenID := r.FormValue("dosen_id")
 	topikPenelitian := r.FormValue("topik_penelitian")
 	keterangan := r.FormValue("keterangan")
@@ -151,8 +155,11 @@ func UploadICPHandler(w http.ResponseWriter, r *http.Request) {
 		FilePath:        filePath,
 	}
 
-	icpModel := models.NewICPModel(db)
-	if err := icpModel.Create(icp); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahICP, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewICPModel(tx).Create(icp)
+		return icp.ID, err
+	})
+	if err != nil {
 		os.Remove(filePath)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  "error",
@@ -161,12 +168,20 @@ func UploadICPHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Topik yang mirip dengan tugas akhir lain hanya ditandai, unggahan tetap diterima
+	mirip := cekKemiripanICP(models.NewKemiripanModel(db), icp)
+	message := "ICP berhasil diunggah"
+	if len(mirip) > 0 {
+		message += fmt.Sprintf(". Perhatian: topik mirip dengan %d tugas akhir lain", len(mirip))
+	}
+
 	// Sukses
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
-		"message": "ICP berhasil diunggah",
+		"message": message,
 		"data": map[string]interface{}{
 			"file_path": filePath,
+			"kemiripan": mirip,
 		},
 	})
 }
@@ -323,8 +338,8 @@ func GetICPByIDHandler(w http.ResponseWriter, r *http.Request) {
 	var args []interface{}
 
 	if dosenID != "" {
-		query = "SELECT EXISTS(SELECT 1 FROM icp WHERE id = ? AND dosen_id = ?)"
-		args = []interface{}{icpID, dosenID}
+		query = "SELECT EXISTS(SELECT 1 FROM icp i WHERE i.id = ? AND (i.dosen_id = ? OR " + models.KondisiPembimbingPendamping + "))"
+		args = []interface{}{icpID, dosenID, dosenID}
 	} else {
 		// For taruna, we need to check against user_id since that's what we store in ICP table
 		query = "SELECT EXISTS(SELECT 1 FROM icp WHERE id = ? AND user_id = ?)"
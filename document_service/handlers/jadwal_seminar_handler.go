@@ -0,0 +1,461 @@
+package handlers
+
+import (
+	"database/sql"
+	"document_service/config"
+	"document_service/entities"
+	"document_service/models"
+	"document_service/utils/icalendar"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var tahapLabel = map[string]string{
+	"proposal":   "Seminar Proposal",
+	"laporan70":  "Seminar Laporan 70%",
+	"laporan100": "Seminar Laporan 100%",
+}
+
+// Format waktu yang diterima dari form jadwal, ditafsirkan sebagai WIB
+var jadwalInputLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+}
+
+func parseJadwalTime(value string) (time.Time, error) {
+	for _, layout := range jadwalInputLayouts {
+		if t, err := time.ParseInLocation(layout, value, icalendar.WIB); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("format waktu tidak valid: %s", value)
+}
+
+func setJadwalCORS(w http.ResponseWriter, methods string) {
+	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
+	w.Header().Set("Access-Control-Allow-Methods", methods)
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+}
+
+// jadwalToEvent mengubah jadwal seminar menjadi VEVENT
+func jadwalToEvent(j entities.JadwalSeminar) icalendar.Event {
+	summary := tahapLabel[j.Tahap]
+	if j.NamaTaruna != "" {
+		summary += " - " + j.NamaTaruna
+	}
+
+	var desc []string
+	if j.TopikPenelitian != "" {
+		desc = append(desc, "Topik: "+j.TopikPenelitian)
+	}
+	if j.Peran != "" {
+		desc = append(desc, "Peran Anda: "+j.Peran)
+	}
+	if j.Keterangan != "" {
+		desc = append(desc, j.Keterangan)
+	}
+
+	return icalendar.Event{
+		UID:          fmt.Sprintf("jadwal-%s-%d@securesimta.my.id", j.Tahap, j.FinalID),
+		Summary:      summary,
+		Description:  strings.Join(desc, "\n"),
+		Location:     j.Ruangan,
+		Start:        j.Mulai,
+		End:          j.Selesai,
+		Sequence:     j.Sequence,
+		Cancelled:    j.Status == "dibatalkan",
+		LastModified: j.UpdatedAt,
+	}
+}
+
+func writeICS(w http.ResponseWriter, filename string, cal *icalendar.Calendar) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", filename))
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(cal.Bytes())
+}
+
+// SaveJadwalSeminarHandler membuat atau memperbarui jadwal seminar untuk satu dokumen final (khusus admin)
+func SaveJadwalSeminarHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req struct {
+		Tahap      string `json:"tahap"`
+		FinalID    int    `json:"final_id"`
+		Mulai      string `json:"mulai"`
+		Selesai    string `json:"selesai"`
+		Ruangan    string `json:"ruangan"`
+		Keterangan string `json:"keterangan"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Format request tidak valid"})
+		return
+	}
+
+	if !models.IsValidTahap(req.Tahap) || req.FinalID == 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap dan final_id wajib diisi dengan benar"})
+		return
+	}
+
+	mulai, err := parseJadwalTime(req.Mulai)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	selesai, err := parseJadwalTime(req.Selesai)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	if !selesai.After(mulai) {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Waktu selesai harus setelah waktu mulai"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	// Perubahan jadwal ikut tersebar ke feed kalender semua pelanggan, jadi hanya admin yang boleh
+	if _, ok := sesiAdmin(w, r, db); !ok {
+		return
+	}
+
+	model := models.NewJadwalSeminarModel(db)
+
+	userID, err := model.GetFinalOwner(req.Tahap, req.FinalID)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Dokumen final tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	jadwal := entities.JadwalSeminar{
+		Tahap:      req.Tahap,
+		FinalID:    req.FinalID,
+		UserID:     userID,
+		Mulai:      mulai,
+		Selesai:    selesai,
+		Ruangan:    strings.TrimSpace(req.Ruangan),
+		Keterangan: strings.TrimSpace(req.Keterangan),
+	}
+	if err := model.Upsert(&jadwal); err != nil {
+		log.Printf("Gagal menyimpan jadwal seminar: %v", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal menyimpan jadwal seminar"})
+		return
+	}
+
+	refreshBeritaAcara(db, jadwal.Tahap, jadwal.FinalID)
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Jadwal seminar berhasil disimpan",
+		"data":    jadwal,
+	})
+}
+
+// CancelJadwalSeminarHandler membatalkan jadwal seminar (khusus admin); event tetap ada di feed dengan
+// STATUS:CANCELLED
+func CancelJadwalSeminarHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req struct {
+		ID int `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "id jadwal wajib diisi"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	if _, ok := sesiAdmin(w, r, db); !ok {
+		return
+	}
+
+	model := models.NewJadwalSeminarModel(db)
+	if err := model.Cancel(req.ID); err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Jadwal tidak ditemukan atau sudah dibatalkan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	if jadwal, err := model.GetByID(req.ID); err == nil {
+		refreshBeritaAcara(db, jadwal.Tahap, jadwal.FinalID)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Jadwal seminar dibatalkan",
+	})
+}
+
+// GetJadwalSeminarHandler menampilkan daftar jadwal. Dengan user_id hanya jadwal yang melibatkan user tersebut.
+func GetJadwalSeminarHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	tahap := r.URL.Query().Get("tahap")
+	if tahap != "" && !models.IsValidTahap(tahap) {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap tidak valid"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	model := models.NewJadwalSeminarModel(db)
+
+	var list []entities.JadwalSeminar
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		userID, convErr := strconv.Atoi(userIDStr)
+		if convErr != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "user_id tidak valid"})
+			return
+		}
+		list, err = model.GetForUser(userID)
+		if err == nil && tahap != "" {
+			filtered := list[:0]
+			for _, j := range list {
+				if j.Tahap == tahap {
+					filtered = append(filtered, j)
+				}
+			}
+			list = filtered
+		}
+	} else {
+		list, err = model.GetAll(tahap)
+	}
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	if list == nil {
+		list = []entities.JadwalSeminar{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// DownloadJadwalSeminarICSHandler mengunduh satu jadwal sebagai undangan .ics (REQUEST / CANCEL)
+func DownloadJadwalSeminarICSHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID jadwal tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	jadwal, err := models.NewJadwalSeminarModel(db).GetByID(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Jadwal tidak ditemukan", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	method := icalendar.MethodRequest
+	if jadwal.Status == "dibatalkan" {
+		method = icalendar.MethodCancel
+	}
+
+	cal := &icalendar.Calendar{
+		Method:    method,
+		Organizer: getEnv("CALENDAR_ORGANIZER_EMAIL", "noreply@securesimta.my.id"),
+		Events:    []icalendar.Event{jadwalToEvent(*jadwal)},
+	}
+	writeICS(w, fmt.Sprintf("jadwal-%s-%d.ics", jadwal.Tahap, jadwal.FinalID), cal)
+}
+
+// kalenderFeedURL menyusun URL webcal untuk token feed
+func kalenderFeedURL(token string) string {
+	base := strings.TrimRight(getEnv("PUBLIC_BASE_URL", "https://securesimta.my.id"), "/")
+	base = strings.TrimPrefix(strings.TrimPrefix(base, "https://"), "http://")
+	return fmt.Sprintf("webcal://%s/api/document/kalender/%s.ics", base, token)
+}
+
+// GetKalenderTokenHandler mengembalikan URL feed kalender pribadi milik user yang login
+// (Authorization: Bearer)
+func GetKalenderTokenHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	_, userID, ok := sesiDariToken(w, r, db)
+	if !ok {
+		return
+	}
+
+	token, err := models.NewJadwalSeminarModel(db).GetOrCreateToken(userID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data": map[string]interface{}{
+			"url": kalenderFeedURL(token),
+		},
+	})
+}
+
+// ResetKalenderTokenHandler mengganti token feed milik user yang login (misalnya bila URL tersebar)
+func ResetKalenderTokenHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	_, userID, ok := sesiDariToken(w, r, db)
+	if !ok {
+		return
+	}
+
+	token, err := models.NewJadwalSeminarModel(db).ResetToken(userID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "URL kalender berhasil diperbarui, URL lama tidak berlaku lagi",
+		"data": map[string]interface{}{
+			"url": kalenderFeedURL(token),
+		},
+	})
+}
+
+// KalenderFeedHandler menyajikan feed webcal publik yang dilindungi token
+func KalenderFeedHandler(w http.ResponseWriter, r *http.Request) {
+	token := mux.Vars(r)["token"]
+	if len(token) != 64 {
+		http.Error(w, "Kalender tidak ditemukan", http.StatusNotFound)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	model := models.NewJadwalSeminarModel(db)
+
+	userID, err := model.GetUserIDByToken(token)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Kalender tidak ditemukan", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	list, err := model.GetForUser(userID)
+	if err != nil {
+		log.Printf("Gagal mengambil feed kalender user %d: %v", userID, err)
+		http.Error(w, "Gagal mengambil jadwal", http.StatusInternalServerError)
+		return
+	}
+
+	cal := &icalendar.Calendar{
+		Name:   "Jadwal Seminar SIMTA",
+		Method: icalendar.MethodPublish,
+	}
+	for _, j := range list {
+		cal.Events = append(cal.Events, jadwalToEvent(j))
+	}
+	writeICS(w, "simta.ics", cal)
+}
+
+// Helper untuk mengambil environment variable dengan nilai default
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,427 @@
+package handlers
+
+import (
+	"database/sql"
+	"document_service/config"
+	"document_service/entities"
+	"document_service/models"
+	"document_service/utils/beritaacara"
+	"document_service/utils/icalendar"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// jendelaRequest adalah input admin untuk membuat atau mengubah jendela pengumpulan.
+// Waktu diterima dalam format yang sama dengan jadwal seminar dan dibaca sebagai WIB.
+type jendelaRequest struct {
+	ID         int    `json:"id"`
+	Tahap      string `json:"tahap"`
+	PeriodeID  *int   `json:"periode_id"`
+	Dibuka     string `json:"dibuka"`
+	Ditutup    string `json:"ditutup"`
+	Keterangan string `json:"keterangan"`
+}
+
+type perpanjanganPengumpulanRequest struct {
+	UserID  int    `json:"user_id"`
+	Ditutup string `json:"ditutup"`
+	Alasan  string `json:"alasan"`
+}
+
+// waktuJendela menulis waktu dalam WIB, mis. "Senin, 3 Maret 2025 pukul 23.59 WIB"
+func waktuJendela(t time.Time) string {
+	t = t.In(icalendar.WIB)
+	return fmt.Sprintf("%s pukul %s WIB", beritaacara.TanggalIndonesia(t), t.Format("15.04"))
+}
+
+// tolakDiLuarJendela memeriksa jendela pengumpulan tahap bagi taruna dan menulis respons 403 bila
+// unggahan dilakukan di luar jendela. Taruna diambil dari sesi; user_id di form harus sama dengan
+// pemilik sesi agar jendela (dan perpanjangan) taruna lain tidak bisa dipakai. Mengembalikan true
+// bila permintaan sudah ditolak.
+func tolakDiLuarJendela(w http.ResponseWriter, r *http.Request, tahap, userID string) bool {
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return true
+	}
+	defer db.Close()
+
+	_, id, ok := sesiDariToken(w, r, db)
+	if !ok {
+		return true
+	}
+	if formID, err := strconv.Atoi(userID); err != nil || formID != id {
+		respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": "Anda hanya dapat mengunggah dokumen milik sendiri"})
+		return true
+	}
+
+	status, err := models.NewJendelaPengumpulanModel(db).Periksa(tahap, id)
+	if err != nil {
+		log.Printf("Gagal memeriksa jendela pengumpulan %s: %v", tahap, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal memeriksa jendela pengumpulan"})
+		return true
+	}
+	if !status.Dijadwalkan || status.Terbuka {
+		return false
+	}
+
+	label := models.LabelTahapUnggah(tahap)
+	var pesan string
+	if j := status.Berikutnya; j != nil {
+		pesan = fmt.Sprintf("Pengumpulan %s belum dibuka. Jendela pengumpulan dibuka %s sampai %s.",
+			label, waktuJendela(j.Dibuka), waktuJendela(j.Ditutup))
+	} else {
+		ditutup := status.Terakhir.Ditutup
+		if status.Terakhir.DitutupUntukUser != nil {
+			ditutup = *status.Terakhir.DitutupUntukUser
+		}
+		pesan = fmt.Sprintf("Pengumpulan %s sudah ditutup pada %s. Hubungi admin bila memerlukan perpanjangan.",
+			label, waktuJendela(ditutup))
+	}
+	respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": pesan})
+	return true
+}
+
+// GetJendelaPengumpulanHandler menampilkan jendela pengumpulan (GET ?tahap=&periode_id=)
+func GetJendelaPengumpulanHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	tahap := r.URL.Query().Get("tahap")
+	if tahap != "" && !models.IsValidTahapUnggah(tahap) {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap tidak valid"})
+		return
+	}
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	list, err := models.NewJendelaPengumpulanModel(db).GetJendela(tahap, periodeID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// SimpanJendelaPengumpulanHandler membuka jendela baru (id kosong) atau mengubah jendela yang ada (admin).
+// Menutup jendela lebih awal cukup dengan memajukan waktu ditutup.
+func SimpanJendelaPengumpulanHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var req jendelaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Format request tidak valid"})
+		return
+	}
+	if !models.IsValidTahapUnggah(req.Tahap) {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "tahap tidak valid"})
+		return
+	}
+	if req.PeriodeID != nil && *req.PeriodeID <= 0 {
+		req.PeriodeID = nil
+	}
+	dibuka, err := parseJadwalTime(req.Dibuka)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "dibuka: " + err.Error()})
+		return
+	}
+	ditutup, err := parseJadwalTime(req.Ditutup)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ditutup: " + err.Error()})
+		return
+	}
+	if !ditutup.After(dibuka) {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Waktu ditutup harus setelah waktu dibuka"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	jendela := entities.JendelaPengumpulan{
+		ID:         req.ID,
+		Tahap:      req.Tahap,
+		PeriodeID:  req.PeriodeID,
+		Dibuka:     dibuka,
+		Ditutup:    ditutup,
+		Keterangan: req.Keterangan,
+	}
+	model := models.NewJendelaPengumpulanModel(db)
+	if err := model.Simpan(&jendela); err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Jendela pengumpulan tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	saved, err := model.GetJendelaByID(jendela.ID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Jendela pengumpulan disimpan",
+		"data":    saved,
+	})
+}
+
+// HapusJendelaPengumpulanHandler menghapus jendela beserta perpanjangannya (admin)
+func HapusJendelaPengumpulanHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "DELETE, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID tidak valid"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	if err := models.NewJendelaPengumpulanModel(db).Hapus(id); err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Jendela pengumpulan tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Jendela pengumpulan dihapus",
+	})
+}
+
+// GetPerpanjanganPengumpulanHandler menampilkan perpanjangan per taruna pada satu jendela
+func GetPerpanjanganPengumpulanHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID tidak valid"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	list, err := models.NewJendelaPengumpulanModel(db).GetPerpanjangan(id)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// SimpanPerpanjanganPengumpulanHandler memberi satu taruna waktu tutup yang lebih lama dari jendela (admin).
+// Perpanjangan yang sudah ada untuk taruna yang sama akan ditimpa.
+func SimpanPerpanjanganPengumpulanHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID tidak valid"})
+		return
+	}
+
+	var req perpanjanganPengumpulanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Format request tidak valid"})
+		return
+	}
+	if req.UserID <= 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "user_id wajib diisi"})
+		return
+	}
+	ditutup, err := parseJadwalTime(req.Ditutup)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ditutup: " + err.Error()})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	model := models.NewJendelaPengumpulanModel(db)
+	jendela, err := model.GetJendelaByID(id)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Jendela pengumpulan tidak ditemukan"})
+		return
+	}
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	if !ditutup.After(jendela.Ditutup) {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"status":  "error",
+			"message": "Waktu tutup perpanjangan harus setelah " + waktuJendela(jendela.Ditutup),
+		})
+		return
+	}
+
+	p := entities.PerpanjanganPengumpulan{
+		JendelaID: id,
+		UserID:    req.UserID,
+		Ditutup:   ditutup,
+		Alasan:    req.Alasan,
+	}
+	if err := model.SimpanPerpanjangan(&p); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Perpanjangan pengumpulan disimpan",
+		"data":    p,
+	})
+}
+
+// HapusPerpanjanganPengumpulanHandler mencabut perpanjangan satu taruna (admin)
+func HapusPerpanjanganPengumpulanHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "DELETE, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID tidak valid"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	if err := models.NewJendelaPengumpulanModel(db).HapusPerpanjangan(id); err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Perpanjangan tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Perpanjangan pengumpulan dicabut",
+	})
+}
+
+// GetJendelaTerbukaHandler menampilkan jendela yang sedang atau akan dibuka untuk dasbor
+// (GET ?user_id=). Dengan user_id, hanya jendela yang berlaku bagi taruna tersebut beserta perpanjangannya.
+func GetJendelaTerbukaHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	userID := 0
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "user_id tidak valid"})
+			return
+		}
+		userID = id
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	list, err := models.NewJendelaPengumpulanModel(db).GetTerbuka(userID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   list,
+	})
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"document_service/config"
+	"document_service/entities"
+	"document_service/jobs"
+	"document_service/models"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// jumlahKemiripan adalah banyaknya tugas akhir terdekat yang ditampilkan
+const jumlahKemiripan = 5
+
+// cekKemiripanICP mencari tugas akhir yang mirip dengan ICP yang baru diunggah dan mencatat yang
+// melewati ambang. Kegagalan hanya dicatat di log agar tidak menggagalkan unggahan. ICP dinilai terhadap
+// indeks yang sudah ada (tugas akhir milik pengunggah memang tidak diikutkan), lalu indeks dibangun ulang
+// di latar belakang agar topik baru ini ikut dibandingkan pada pemeriksaan berikutnya.
+func cekKemiripanICP(m *models.KemiripanModel, icp *entities.ICP) []entities.KemiripanTopik {
+	defer jobs.SegerakanIndeksKemiripan()
+	hasil, err := m.Cari(icp.TopikPenelitian+" "+icp.TopikPenelitian+" "+icp.Keterangan, icp.UserID, jumlahKemiripan)
+	if err != nil {
+		log.Printf("Gagal memeriksa kemiripan ICP %d: %v", icp.ID, err)
+		return []entities.KemiripanTopik{}
+	}
+
+	ambang := models.AmbangKemiripan()
+	ditandai := []entities.KemiripanTopik{}
+	for _, h := range hasil {
+		if h.Skor >= ambang {
+			ditandai = append(ditandai, h)
+		}
+	}
+	if err := m.SimpanTanda(icp.ID, ditandai); err != nil {
+		log.Printf("Gagal menyimpan tanda kemiripan ICP %d: %v", icp.ID, err)
+	}
+	return ditandai
+}
+
+// GetKemiripanHandler menampilkan tugas akhir yang paling mirip dengan ICP atau final ICP beserta skornya
+// (GET ?sumber=icp|final_icp&id=); dipakai penelaah dan pembimbing saat mereview
+func GetKemiripanHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	sumber := r.URL.Query().Get("sumber")
+	if sumber == "" {
+		sumber = "icp"
+	}
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || (sumber != "icp" && sumber != "final_icp") {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "sumber (icp/final_icp) dan id wajib valid"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	model := models.NewKemiripanModel(db)
+	userID, teks, err := model.TeksDokumen(sumber, id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Dokumen tidak ditemukan"})
+		return
+	}
+	hasil, err := model.Cari(teks, userID, jumlahKemiripan)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal menghitung kemiripan: " + err.Error()})
+		return
+	}
+
+	ambang := models.AmbangKemiripan()
+	ditandai := false
+	for _, h := range hasil {
+		if h.Skor >= ambang {
+			ditandai = true
+		}
+	}
+	data := map[string]interface{}{
+		"ambang":   ambang,
+		"ditandai": ditandai,
+		"terdekat": hasil,
+	}
+	if sumber == "icp" {
+		tanda, err := model.GetTanda(id)
+		if err != nil {
+			respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+			return
+		}
+		data["tanda_unggah"] = tanda
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   data,
+	})
+}
+
+// CekKemiripanHandler memeriksa teks topik sebelum ICP diunggah (GET ?teks=&user_id=)
+func CekKemiripanHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	teks := strings.TrimSpace(r.URL.Query().Get("teks"))
+	if teks == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "teks wajib diisi"})
+		return
+	}
+	userID, _ := strconv.Atoi(r.URL.Query().Get("user_id"))
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	hasil, err := models.NewKemiripanModel(db).Cari(teks, userID, jumlahKemiripan)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal menghitung kemiripan: " + err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data": map[string]interface{}{
+			"ambang":   models.AmbangKemiripan(),
+			"terdekat": hasil,
+		},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
 	"document_service/models"
 	"document_service/utils/filemanager"
 	"encoding/json"
@@ -49,6 +50,9 @@ func UploadLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahLaporan100, userID) {
+		return
+	}
 	dosenID := r.FormValue("dosen_id")
 	topikPenelitian := r.FormValue("topik_penelitian")
 	keterangan := r.FormValue("keterangan")
@@ -142,8 +146,11 @@ func UploadLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 		FilePath:        filePath,
 	}
 
-	model := models.NewLaporan100Model(db)
-	if err := model.Create(laporan); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahLaporan100, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewLaporan100Model(tx).Create(laporan)
+		return laporan.ID, err
+	})
+	if err != nil {
 		os.Remove(filePath)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  "error",
@@ -276,8 +283,8 @@ func GetLaporan100ByIDHandler(w http.ResponseWriter, r *http.Request) {
 	var args []interface{}
 
 	if dosenID != "" {
-		query = "SELECT EXISTS(SELECT 1 FROM laporan_100 WHERE id = ? AND dosen_id = ?)"
-		args = []interface{}{laporan100ID, dosenID}
+		query = "SELECT EXISTS(SELECT 1 FROM laporan_100 i WHERE i.id = ? AND (i.dosen_id = ? OR " + models.KondisiPembimbingPendamping + "))"
+		args = []interface{}{laporan100ID, dosenID, dosenID}
 	} else {
 		// For taruna, we need to check against user_id since that's what we store in ICP table
 		query = "SELECT EXISTS(SELECT 1 FROM laporan_100 WHERE id = ? AND user_id = ?)"
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"database/sql"
+	"document_service/config"
+	"document_service/jobs"
+	"document_service/models"
+	"document_service/utils/halaman"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GetOutboxHandler menampilkan event outbox untuk pemantauan (GET ?status=menunggu|terkirim|gagal).
+// Mendukung page/limit/cursor, sort, dan q (tipe atau event_id).
+func GetOutboxHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.OutboxMenunggu, models.OutboxTerkirim, models.OutboxGagal:
+	default:
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "status tidak valid"})
+		return
+	}
+	k, err := halaman.Parse(r, models.AturanOutbox)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	list, total, err := models.NewOutboxModel(db).GetOutbox(status, k)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   list,
+		"meta":   k.Meta(total),
+	})
+}
+
+// AntrekanUlangOutboxHandler mengirim ulang event yang berstatus gagal (POST /outbox/{id}/ulang)
+func AntrekanUlangOutboxHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID tidak valid"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	err = models.NewOutboxModel(db).AntrekanUlang(id)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Event gagal tidak ditemukan"})
+		return
+	}
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	jobs.SegerakanOutbox()
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Event diantrekan untuk dikirim ulang",
+	})
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// periodeDariQuery membaca ?periode_id= untuk filter daftar; 0 berarti semua periode yang belum diarsipkan.
+// Bila nilainya tidak valid, response 400 ditulis.
+func periodeDariQuery(w http.ResponseWriter, r *http.Request) (int, bool) {
+	v := strings.TrimSpace(r.URL.Query().Get("periode_id"))
+	if v == "" {
+		return 0, true
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil || id < 0 {
+		http.Error(w, "periode_id tidak valid", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
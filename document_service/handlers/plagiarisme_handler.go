@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"database/sql"
+	"document_service/config"
+	"document_service/jobs"
+	"document_service/models"
+	"net/http"
+	"strconv"
+)
+
+// GetPlagiarismeHandler menampilkan laporan kemiripan dokumen final laporan 100% untuk penguji
+// (GET ?final_laporan100_id=): persentase keseluruhan, dokumen sumber, dan bagian teks yang sama
+func GetPlagiarismeHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(r.URL.Query().Get("final_laporan100_id"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "final_laporan100_id wajib valid"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	laporan, err := models.NewPlagiarismeModel(db).GetByFinalLaporan100(id)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Laporan kemiripan belum tersedia"})
+		return
+	}
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   laporan,
+	})
+}
+
+// PeriksaUlangPlagiarismeHandler mengantrekan ulang pemeriksaan (POST ?final_laporan100_id=), mis. setelah
+// korpus bertambah atau pemeriksaan sebelumnya gagal
+func PeriksaUlangPlagiarismeHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(r.URL.Query().Get("final_laporan100_id"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "final_laporan100_id wajib valid"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	model := models.NewPlagiarismeModel(db)
+	if _, err := model.GetFinalLaporan100(id); err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Final laporan 100% tidak ditemukan"})
+		return
+	}
+	if err := model.Antrekan(id); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	jobs.AntrekanPlagiarisme(id)
+
+	respondJSON(w, http.StatusAccepted, map[string]interface{}{
+		"status":  "success",
+		"message": "Pemeriksaan kemiripan dokumen diantrekan",
+	})
+}
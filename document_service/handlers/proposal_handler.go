@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
 	"document_service/models"
 	"document_service/utils/filemanager"
 	"encoding/json"
@@ -51,6 +52,9 @@ func UploadProposalHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Ambil data form
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahProposal, userID) {
+		return
+	}
 	dosenID := r.FormValue("dosen_id")
 	topikPenelitian := r.FormValue("topik_penelitian")
 	keterangan := r.FormValue("keterangan")
@@ -152,8 +156,11 @@ func UploadProposalHandler(w http.ResponseWriter, r *http.Request) {
 		FilePath:        filePath,
 	}
 
-	proposalModel := models.NewProposalModel(db)
-	if err := proposalModel.Create(proposal); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahProposal, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewProposalModel(tx).Create(proposal)
+		return proposal.ID, err
+	})
+	if err != nil {
 		os.Remove(filePath)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  "error",
@@ -287,8 +294,8 @@ func GetProposalByIDHandler(w http.ResponseWriter, r *http.Request) {
 	var args []interface{}
 
 	if dosenID != "" {
-		query = "SELECT EXISTS(SELECT 1 FROM proposal WHERE id = ? AND dosen_id = ?)"
-		args = []interface{}{proposalID, dosenID}
+		query = "SELECT EXISTS(SELECT 1 FROM proposal i WHERE i.id = ? AND (i.dosen_id = ? OR " + models.KondisiPembimbingPendamping + "))"
+		args = []interface{}{proposalID, dosenID, dosenID}
 	} else {
 		// For taruna, we need to check against user_id since that's what we store in ICP table
 		query = "SELECT EXISTS(SELECT 1 FROM proposal WHERE id = ? AND user_id = ?)"
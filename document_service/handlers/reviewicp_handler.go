@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
+	"document_service/jobs"
 	"document_service/models"
 	"document_service/utils"
 	"document_service/utils/filemanager"
@@ -29,6 +31,10 @@ func GetICPByDosenIDHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Dosen ID is required", http.StatusBadRequest)
 		return
 	}
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
 
 	db, err := config.GetDB()
 	if err != nil {
@@ -38,7 +44,7 @@ func GetICPByDosenIDHandler(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	icpModel := models.NewICPModel(db)
-	icps, err := icpModel.GetByDosenID(dosenID)
+	icps, err := icpModel.GetByDosenID(dosenID, periodeID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -65,6 +71,12 @@ func UpdateICPStatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	docID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "ID tidak valid", http.StatusBadRequest)
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -72,7 +84,10 @@ func UpdateICPStatusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	_, err = db.Exec("UPDATE icp SET status = ? WHERE id = ?", status, id)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahICP, status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec("UPDATE icp SET status = ? WHERE id = ?", status, docID)
+		return docID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -308,6 +323,16 @@ func UploadDosenReviewICPHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.ReviewDiunggah, models.UnggahICP, icpID, ""); err != nil {
+		tx.Rollback()
+		os.Remove(filePath)
+		utils.RespondWithJSON(w, http.StatusInternalServerError, map[string]interface{}{
+			"status":  "error",
+			"message": "Gagal mencatat event: " + err.Error(),
+		})
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		os.Remove(filePath)
@@ -317,6 +342,7 @@ func UploadDosenReviewICPHandler(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
 		"status":  "success",
@@ -577,6 +603,16 @@ func UploadTarunaRevisiICPHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.DokumenDiunggah, models.UnggahICP, icpID, ""); err != nil {
+		tx.Rollback()
+		os.Remove(filePath)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "error",
+			"message": "Failed to record event: " + err.Error(),
+		})
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		os.Remove(filePath)
@@ -586,6 +622,7 @@ func UploadTarunaRevisiICPHandler(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
+	"document_service/jobs"
 	"document_service/models"
 	"document_service/utils/filemanager"
 	"encoding/json"
@@ -27,6 +29,10 @@ func GetLaporan100ByDosenIDHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Dosen ID is required", http.StatusBadRequest)
 		return
 	}
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
 
 	db, err := config.GetDB()
 	if err != nil {
@@ -36,7 +42,7 @@ func GetLaporan100ByDosenIDHandler(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	laporan100Model := models.NewLaporan100Model(db)
-	laporan100s, err := laporan100Model.GetByDosenID(dosenID)
+	laporan100s, err := laporan100Model.GetByDosenID(dosenID, periodeID)
 	if err != nil {
 		http.Error(w, "Gagal mengambil data laporan 70%", http.StatusInternalServerError)
 		return
@@ -69,6 +75,12 @@ func UpdateLaporan100StatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	docID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "ID tidak valid", http.StatusBadRequest)
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -76,7 +88,10 @@ func UpdateLaporan100StatusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	_, err = db.Exec("UPDATE laporan_100 SET status = ? WHERE id = ?", status, id)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahLaporan100, status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec("UPDATE laporan_100 SET status = ? WHERE id = ?", status, docID)
+		return docID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -256,6 +271,16 @@ func UploadDosenReviewLaporan100Handler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.ReviewDiunggah, models.UnggahLaporan100, laporan100ID, ""); err != nil {
+		tx.Rollback()
+		os.Remove(filePath)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "error",
+			"message": "Gagal mencatat event: " + err.Error(),
+		})
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		os.Remove(filePath)
@@ -265,6 +290,7 @@ func UploadDosenReviewLaporan100Handler(w http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
@@ -524,6 +550,16 @@ func UploadTarunaRevisiLaporan100Handler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.DokumenDiunggah, models.UnggahLaporan100, laporan100ID, ""); err != nil {
+		tx.Rollback()
+		os.Remove(filePath)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "error",
+			"message": "Gagal mencatat event: " + err.Error(),
+		})
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		os.Remove(filePath)
@@ -533,6 +569,7 @@ func UploadTarunaRevisiLaporan100Handler(w http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
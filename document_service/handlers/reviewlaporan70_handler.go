@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
+	"document_service/jobs"
 	"document_service/models"
 	"document_service/utils/filemanager"
 	"encoding/json"
@@ -27,6 +29,10 @@ func GetLaporan70ByDosenIDHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Dosen ID is required", http.StatusBadRequest)
 		return
 	}
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
 
 	db, err := config.GetDB()
 	if err != nil {
@@ -36,7 +42,7 @@ func GetLaporan70ByDosenIDHandler(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	laporan70Model := models.NewLaporan70Model(db)
-	laporan70s, err := laporan70Model.GetByDosenID(dosenID)
+	laporan70s, err := laporan70Model.GetByDosenID(dosenID, periodeID)
 	if err != nil {
 		http.Error(w, "Gagal mengambil data laporan 70%", http.StatusInternalServerError)
 		return
@@ -69,6 +75,12 @@ func UpdateLaporan70StatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	docID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "ID tidak valid", http.StatusBadRequest)
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -76,7 +88,10 @@ func UpdateLaporan70StatusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	_, err = db.Exec("UPDATE laporan_70 SET status = ? WHERE id = ?", status, id)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahLaporan70, status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec("UPDATE laporan_70 SET status = ? WHERE id = ?", status, docID)
+		return docID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -267,6 +282,16 @@ func UploadDosenReviewLaporan70Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.ReviewDiunggah, models.UnggahLaporan70, laporan70ID, ""); err != nil {
+		tx.Rollback()
+		os.Remove(filePath)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "error",
+			"message": "Gagal mencatat event: " + err.Error(),
+		})
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		os.Remove(filePath)
@@ -276,6 +301,7 @@ func UploadDosenReviewLaporan70Handler(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
@@ -541,6 +567,16 @@ func UploadTarunaRevisiLaporan70Handler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.DokumenDiunggah, models.UnggahLaporan70, laporan70ID, ""); err != nil {
+		tx.Rollback()
+		os.Remove(filePath)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "error",
+			"message": "Gagal mencatat event: " + err.Error(),
+		})
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		os.Remove(filePath)
@@ -550,6 +586,7 @@ func UploadTarunaRevisiLaporan70Handler(w http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
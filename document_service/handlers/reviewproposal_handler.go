@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
+	"document_service/jobs"
 	"document_service/models"
 	"document_service/utils/filemanager"
 	"encoding/json"
@@ -27,6 +29,10 @@ func GetProposalByDosenIDHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Dosen ID is required", http.StatusBadRequest)
 		return
 	}
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
 
 	db, err := config.GetDB()
 	if err != nil {
@@ -36,7 +42,7 @@ func GetProposalByDosenIDHandler(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	proposalModel := models.NewProposalModel(db)
-	proposals, err := proposalModel.GetByDosenID(dosenID)
+	proposals, err := proposalModel.GetByDosenID(dosenID, periodeID)
 	if err != nil {
 		http.Error(w, "Gagal mengambil data proposal", http.StatusInternalServerError)
 		return
@@ -69,6 +75,12 @@ func UpdateProposalStatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	docID, err := strconv.Atoi(id)
+	if err != nil {
+		http.Error(w, "ID tidak valid", http.StatusBadRequest)
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -76,7 +88,10 @@ func UpdateProposalStatusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	_, err = db.Exec("UPDATE proposal SET status = ? WHERE id = ?", status, id)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahProposal, status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec("UPDATE proposal SET status = ? WHERE id = ?", status, docID)
+		return docID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -261,6 +276,16 @@ func UploadDosenReviewProposalHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.ReviewDiunggah, models.UnggahProposal, proposalID, ""); err != nil {
+		tx.Rollback()
+		os.Remove(filePath)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "error",
+			"message": "Gagal mencatat event: " + err.Error(),
+		})
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		os.Remove(filePath)
@@ -270,6 +295,7 @@ func UploadDosenReviewProposalHandler(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
@@ -539,6 +565,16 @@ func UploadTarunaRevisiProposalHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.DokumenDiunggah, models.UnggahProposal, proposalID, ""); err != nil {
+		tx.Rollback()
+		os.Remove(filePath)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "error",
+			"message": "Gagal mencatat event: " + err.Error(),
+		})
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		os.Remove(filePath)
@@ -548,6 +584,7 @@ func UploadTarunaRevisiProposalHandler(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
 	"document_service/models"
 	"document_service/utils"
 	"document_service/utils/filemanager"
+	"document_service/utils/halaman"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -50,6 +52,9 @@ func UploadRevisiICPHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Get form values
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahRevisiICP, userID) {
+		return
+	}
 	namaLengkap := r.FormValue("nama_lengkap")
 	jurusan := r.FormValue("jurusan")
 	kelas := r.FormValue("kelas")
@@ -113,7 +118,6 @@ func UploadRevisiICPHandler(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	// Buat entri baru
-	revisiICPModel := models.NewRevisiICPModel(db)
 	revisiICP := &entities.RevisiICP{
 		UserID:          utils.ParseInt(userID),
 		NamaLengkap:     namaLengkap,
@@ -124,7 +128,11 @@ func UploadRevisiICPHandler(w http.ResponseWriter, r *http.Request) {
 		Keterangan:      keterangan,
 	}
 
-	if err := revisiICPModel.Create(revisiICP); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahRevisiICP, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewRevisiICPModel(tx).Create(revisiICP)
+		return revisiICP.ID, err
+	})
+	if err != nil {
 		os.Remove(filePath)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  "error",
@@ -194,6 +202,11 @@ func GetAllRevisiICPWithTarunaHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	k, kondisi, args, ok := kueriDaftarTaruna(w, r)
+	if !ok {
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -201,6 +214,17 @@ func GetAllRevisiICPWithTarunaHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
+	dari := `
+		FROM taruna t
+		LEFT JOIN revisi_icp f ON t.user_id = f.user_id
+		WHERE ` + kondisi
+	total, err := halaman.Hitung(db, dari, args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	potong, argsPotong := k.Potong()
 	// Query untuk mengambil data gabungan
 	query := `
 		SELECT 
@@ -210,12 +234,10 @@ func GetAllRevisiICPWithTarunaHandler(w http.ResponseWriter, r *http.Request) {
 			t.kelas,
 			COALESCE(f.topik_penelitian, '') as topik_penelitian,
 			COALESCE(f.status, '') as status,
-			COALESCE(f.id, 0) as revisi_icp_id
-		FROM taruna t
-		LEFT JOIN revisi_icp f ON t.user_id = f.user_id
-		ORDER BY t.nama_lengkap ASC`
+			COALESCE(f.id, 0) as revisi_icp_id` +
+		dari + k.OrderBy() + potong
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, append(args, argsPotong...)...)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -254,6 +276,7 @@ func GetAllRevisiICPWithTarunaHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status": "success",
 		"data":   results,
+		"meta":   k.Meta(total),
 	})
 }
 
@@ -287,7 +310,10 @@ func UpdateRevisiICPStatusHandler(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	query := "UPDATE revisi_icp SET status = ? WHERE id = ?"
-	_, err = db.Exec(query, requestData.Status, requestData.ID)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahRevisiICP, requestData.Status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(query, requestData.Status, requestData.ID)
+		return requestData.ID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -4,13 +4,16 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
 	"document_service/models"
 	"document_service/utils"
 	"document_service/utils/filemanager"
+	"document_service/utils/halaman"
 	"document_service/utils/produkmanager"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -44,6 +47,9 @@ func UploadRevisiLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 
 	// Ambil data form
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahRevisiLaporan100, userID) {
+		return
+	}
 	namaLengkap := r.FormValue("nama_lengkap")
 	jurusan := r.FormValue("jurusan")
 	kelas := r.FormValue("kelas")
@@ -167,6 +173,15 @@ func UploadRevisiLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
+	// Tanpa upload BAP, gunakan BAP yang dihasilkan sistem dari seminar laporan 100%
+	if fileBapPath == "" {
+		generatedBap, err := models.NewBeritaAcaraModel(db).GetLatestBAPPathByUser("laporan100", utils.ParseInt(userID))
+		if err != nil && err != sql.ErrNoRows {
+			log.Printf("Gagal mengambil BAP hasil sistem: %v", err)
+		}
+		fileBapPath = generatedBap
+	}
+
 	revisi := &entities.RevisiLaporan100{
 		UserID:          utils.ParseInt(userID),
 		NamaLengkap:     namaLengkap,
@@ -184,8 +199,11 @@ func UploadRevisiLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 		Keterangan:      keterangan,
 	}
 
-	model := models.NewRevisiLaporan100Model(db)
-	if err := model.Create(revisi); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahRevisiLaporan100, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewRevisiLaporan100Model(tx).Create(revisi)
+		return revisi.ID, err
+	})
+	if err != nil {
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  "error",
 			"message": "Gagal menyimpan ke database: " + err.Error(),
@@ -305,6 +323,11 @@ func GetAllRevisiLaporan100WithTarunaHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	k, kondisi, args, ok := kueriDaftarTaruna(w, r)
+	if !ok {
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -312,6 +335,17 @@ func GetAllRevisiLaporan100WithTarunaHandler(w http.ResponseWriter, r *http.Requ
 	}
 	defer db.Close()
 
+	dari := `
+		FROM taruna t
+		LEFT JOIN revisi_laporan100 f ON t.user_id = f.user_id
+		WHERE ` + kondisi
+	total, err := halaman.Hitung(db, dari, args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	potong, argsPotong := k.Potong()
 	// Query untuk mengambil data gabungan
 	query := `
 		SELECT 
@@ -321,12 +355,10 @@ func GetAllRevisiLaporan100WithTarunaHandler(w http.ResponseWriter, r *http.Requ
 			t.kelas,
 			COALESCE(f.topik_penelitian, '') as topik_penelitian,
 			COALESCE(f.status, '') as status,
-			COALESCE(f.id, 0) as revisi_laporan100_id
-		FROM taruna t
-		LEFT JOIN revisi_laporan100 f ON t.user_id = f.user_id
-		ORDER BY t.nama_lengkap ASC`
+			COALESCE(f.id, 0) as revisi_laporan100_id` +
+		dari + k.OrderBy() + potong
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, append(args, argsPotong...)...)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -365,6 +397,7 @@ func GetAllRevisiLaporan100WithTarunaHandler(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status": "success",
 		"data":   results,
+		"meta":   k.Meta(total),
 	})
 }
 
@@ -397,8 +430,16 @@ func UpdateRevisiLaporan100StatusHandler(w http.ResponseWriter, r *http.Request)
 	}
 	defer db.Close()
 
+	// Revisi hanya bisa disetujui setelah semua butir catatan perbaikan diterima penguji
+	if requestData.Status == "approved" && !cekCatatanSelesai(w, db, "laporan100", requestData.ID) {
+		return
+	}
+
 	query := "UPDATE revisi_laporan100 SET status = ? WHERE id = ?"
-	_, err = db.Exec(query, requestData.Status, requestData.ID)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahRevisiLaporan100, requestData.Status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(query, requestData.Status, requestData.ID)
+		return requestData.ID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
 	"document_service/models"
 	"document_service/utils"
 	"document_service/utils/filemanager"
+	"document_service/utils/halaman"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -43,6 +45,9 @@ func UploadRevisiLaporan70Handler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahRevisiLaporan70, userID) {
+		return
+	}
 	namaLengkap := r.FormValue("nama_lengkap")
 	jurusan := r.FormValue("jurusan")
 	kelas := r.FormValue("kelas")
@@ -87,7 +92,6 @@ func UploadRevisiLaporan70Handler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	revisiLaporan70Model := models.NewRevisiLaporan70Model(db)
 	revisiLaporan70 := &entities.RevisiLaporan70{
 		UserID:          utils.ParseInt(userID),
 		NamaLengkap:     namaLengkap,
@@ -98,7 +102,11 @@ func UploadRevisiLaporan70Handler(w http.ResponseWriter, r *http.Request) {
 		Keterangan:      keterangan,
 	}
 
-	if err := revisiLaporan70Model.Create(revisiLaporan70); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahRevisiLaporan70, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewRevisiLaporan70Model(tx).Create(revisiLaporan70)
+		return revisiLaporan70.ID, err
+	})
+	if err != nil {
 		os.Remove(filePath)
 		http.Error(w, "Gagal menyimpan ke database: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -214,6 +222,11 @@ func GetAllRevisiLaporan70WithTarunaHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	k, kondisi, args, ok := kueriDaftarTaruna(w, r)
+	if !ok {
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -221,6 +234,17 @@ func GetAllRevisiLaporan70WithTarunaHandler(w http.ResponseWriter, r *http.Reque
 	}
 	defer db.Close()
 
+	dari := `
+		FROM taruna t
+		LEFT JOIN revisi_laporan70 f ON t.user_id = f.user_id
+		WHERE ` + kondisi
+	total, err := halaman.Hitung(db, dari, args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	potong, argsPotong := k.Potong()
 	// Query untuk mengambil data gabungan
 	query := `
 		SELECT 
@@ -230,12 +254,10 @@ func GetAllRevisiLaporan70WithTarunaHandler(w http.ResponseWriter, r *http.Reque
 			t.kelas,
 			COALESCE(f.topik_penelitian, '') as topik_penelitian,
 			COALESCE(f.status, '') as status,
-			COALESCE(f.id, 0) as revisi_laporan70_id
-		FROM taruna t
-		LEFT JOIN revisi_laporan70 f ON t.user_id = f.user_id
-		ORDER BY t.nama_lengkap ASC`
+			COALESCE(f.id, 0) as revisi_laporan70_id` +
+		dari + k.OrderBy() + potong
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, append(args, argsPotong...)...)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -274,6 +296,7 @@ func GetAllRevisiLaporan70WithTarunaHandler(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status": "success",
 		"data":   results,
+		"meta":   k.Meta(total),
 	})
 }
 
@@ -306,8 +329,16 @@ func UpdateRevisiLaporan70StatusHandler(w http.ResponseWriter, r *http.Request)
 	}
 	defer db.Close()
 
+	// Revisi hanya bisa disetujui setelah semua butir catatan perbaikan diterima penguji
+	if requestData.Status == "approved" && !cekCatatanSelesai(w, db, "laporan70", requestData.ID) {
+		return
+	}
+
 	query := "UPDATE revisi_laporan70 SET status = ? WHERE id = ?"
-	_, err = db.Exec(query, requestData.Status, requestData.ID)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahRevisiLaporan70, requestData.Status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(query, requestData.Status, requestData.ID)
+		return requestData.ID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
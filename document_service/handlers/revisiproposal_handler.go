@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"document_service/config"
 	"document_service/entities"
+	"document_service/events"
 	"document_service/models"
 	"document_service/utils"
 	"document_service/utils/filemanager"
+	"document_service/utils/halaman"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -39,6 +41,9 @@ func UploadRevisiProposalHandler(w http.ResponseWriter, r *http.Request) {
 
 	// === Form Values ===
 	userID := r.FormValue("user_id")
+	if tolakDiLuarJendela(w, r, models.UnggahRevisiProposal, userID) {
+		return
+	}
 	namaLengkap := r.FormValue("nama_lengkap")
 	jurusan := r.FormValue("jurusan")
 	kelas := r.FormValue("kelas")
@@ -96,7 +101,6 @@ func UploadRevisiProposalHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	revisiProposalModel := models.NewRevisiProposalModel(db)
 	revisiProposal := &entities.RevisiProposal{
 		UserID:          utils.ParseInt(userID),
 		NamaLengkap:     namaLengkap,
@@ -107,7 +111,11 @@ func UploadRevisiProposalHandler(w http.ResponseWriter, r *http.Request) {
 		Keterangan:      keterangan,
 	}
 
-	if err := revisiProposalModel.Create(revisiProposal); err != nil {
+	err = simpanDenganEvent(db, events.DokumenDiunggah, models.UnggahRevisiProposal, "", func(tx *sql.Tx) (int, error) {
+		err := models.NewRevisiProposalModel(tx).Create(revisiProposal)
+		return revisiProposal.ID, err
+	})
+	if err != nil {
 		os.Remove(uploadPath)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  "error",
@@ -176,6 +184,11 @@ func GetAllRevisiProposalWithTarunaHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	k, kondisi, args, ok := kueriDaftarTaruna(w, r)
+	if !ok {
+		return
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -183,6 +196,17 @@ func GetAllRevisiProposalWithTarunaHandler(w http.ResponseWriter, r *http.Reques
 	}
 	defer db.Close()
 
+	dari := `
+		FROM taruna t
+		LEFT JOIN revisi_proposal f ON t.user_id = f.user_id
+		WHERE ` + kondisi
+	total, err := halaman.Hitung(db, dari, args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	potong, argsPotong := k.Potong()
 	// Query untuk mengambil data gabungan
 	query := `
 		SELECT 
@@ -192,12 +216,10 @@ func GetAllRevisiProposalWithTarunaHandler(w http.ResponseWriter, r *http.Reques
 			t.kelas,
 			COALESCE(f.topik_penelitian, '') as topik_penelitian,
 			COALESCE(f.status, '') as status,
-			COALESCE(f.id, 0) as revisi_proposal_id
-		FROM taruna t
-		LEFT JOIN revisi_proposal f ON t.user_id = f.user_id
-		ORDER BY t.nama_lengkap ASC`
+			COALESCE(f.id, 0) as revisi_proposal_id` +
+		dari + k.OrderBy() + potong
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, append(args, argsPotong...)...)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -236,6 +258,7 @@ func GetAllRevisiProposalWithTarunaHandler(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status": "success",
 		"data":   results,
+		"meta":   k.Meta(total),
 	})
 }
 
@@ -268,8 +291,16 @@ func UpdateRevisiProposalStatusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
+	// Revisi hanya bisa disetujui setelah semua butir catatan perbaikan diterima penguji
+	if requestData.Status == "approved" && !cekCatatanSelesai(w, db, "proposal", requestData.ID) {
+		return
+	}
+
 	query := "UPDATE revisi_proposal SET status = ? WHERE id = ?"
-	_, err = db.Exec(query, requestData.Status, requestData.ID)
+	err = simpanDenganEvent(db, events.StatusBerubah, models.UnggahRevisiProposal, requestData.Status, func(tx *sql.Tx) (int, error) {
+		_, err := tx.Exec(query, requestData.Status, requestData.ID)
+		return requestData.ID, err
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3,6 +3,10 @@ package handlers
 import (
 	"database/sql"
 	"document_service/config"
+	"document_service/events"
+	"document_service/jobs"
+	"document_service/models"
+	"document_service/utils"
 	"document_service/utils/filemanager"
 	"encoding/json"
 	"fmt"
@@ -436,6 +440,16 @@ func PenilaianLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.ReviewDiunggah, models.UnggahFinalLaporan100, utils.ParseInt(finalLaporan100ID), ""); err != nil {
+		tx.Rollback()
+		_ = os.Remove(catatanperbaikanPath)
+		for _, p := range savedFiles {
+			_ = os.Remove(p)
+		}
+		sendError("Gagal mencatat event: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		_ = os.Remove(catatanperbaikanPath)
@@ -445,6 +459,7 @@ func PenilaianLaporan100Handler(w http.ResponseWriter, r *http.Request) {
 		sendError("Gagal commit data: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
@@ -563,6 +578,12 @@ func GetMonitoringPenilaianLaporan100Handler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
+	kondisiPeriode, argsPeriode := models.KondisiPeriode("fp.periode_id", periodeID)
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, "Database error: "+err.Error(), http.StatusInternalServerError)
@@ -607,13 +628,14 @@ func GetMonitoringPenilaianLaporan100Handler(w http.ResponseWriter, r *http.Requ
 
 		LEFT JOIN seminar_laporan100_penilaian spp
 			ON spp.final_laporan100_id = pp.final_laporan100_id
+		WHERE ` + kondisiPeriode + `
 
 		GROUP BY
 			fp.id, u.nama_lengkap, t.jurusan, fp.topik_penelitian,
 			d_ketua.nama_lengkap, d1.nama_lengkap, d2.nama_lengkap
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, argsPeriode...)
 	if err != nil {
 		log.Printf("Error querying database: %v", err)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -3,6 +3,10 @@ package handlers
 import (
 	"database/sql"
 	"document_service/config"
+	"document_service/events"
+	"document_service/jobs"
+	"document_service/models"
+	"document_service/utils"
 	"document_service/utils/filemanager"
 	"encoding/json"
 	"fmt"
@@ -427,6 +431,16 @@ func PenilaianLaporan70Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.ReviewDiunggah, models.UnggahFinalLaporan70, utils.ParseInt(finalLaporan70ID), ""); err != nil {
+		tx.Rollback()
+		_ = os.Remove(hasiltelaahPath)
+		for _, p := range savedFiles {
+			_ = os.Remove(p)
+		}
+		sendError("Gagal mencatat event: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		_ = os.Remove(hasiltelaahPath)
@@ -436,6 +450,7 @@ func PenilaianLaporan70Handler(w http.ResponseWriter, r *http.Request) {
 		sendError("Gagal commit data: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
@@ -554,6 +569,12 @@ func GetMonitoringPenilaianLaporan70Handler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
+	kondisiPeriode, argsPeriode := models.KondisiPeriode("fp.periode_id", periodeID)
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, "Database error: "+err.Error(), http.StatusInternalServerError)
@@ -588,13 +609,14 @@ func GetMonitoringPenilaianLaporan70Handler(w http.ResponseWriter, r *http.Reque
 		JOIN dosen d2 ON d2.id = pp.penguji_2_id
 		LEFT JOIN seminar_laporan70_penilaian spp
 			ON spp.final_laporan70_id = pp.final_laporan70_id
+		WHERE ` + kondisiPeriode + `
 		GROUP BY
 			fp.id, u.nama_lengkap, t.jurusan, fp.topik_penelitian,
 			d1.nama_lengkap, d2.nama_lengkap;
 
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, argsPeriode...)
 	if err != nil {
 		log.Printf("Error querying database: %v", err)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -3,6 +3,10 @@ package handlers
 import (
 	"database/sql"
 	"document_service/config"
+	"document_service/events"
+	"document_service/jobs"
+	"document_service/models"
+	"document_service/utils"
 	"document_service/utils/filemanager"
 	"encoding/json"
 	"fmt"
@@ -435,6 +439,16 @@ func PenilaianProposalHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := catatEventDokumen(tx, events.ReviewDiunggah, models.UnggahFinalProposal, utils.ParseInt(finalProposalID), ""); err != nil {
+		tx.Rollback()
+		_ = os.Remove(catatanperbaikanPath)
+		for _, p := range savedFiles {
+			_ = os.Remove(p)
+		}
+		sendError("Gagal mencatat event: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		tx.Rollback()
 		_ = os.Remove(catatanperbaikanPath)
@@ -444,6 +458,7 @@ func PenilaianProposalHandler(w http.ResponseWriter, r *http.Request) {
 		sendError("Gagal commit data: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	jobs.SegerakanOutbox()
 
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
@@ -562,6 +577,12 @@ func GetMonitoringPenilaianProposalHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
+	kondisiPeriode, argsPeriode := models.KondisiPeriode("fp.periode_id", periodeID)
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, "Database error: "+err.Error(), http.StatusInternalServerError)
@@ -606,13 +627,14 @@ func GetMonitoringPenilaianProposalHandler(w http.ResponseWriter, r *http.Reques
 
 		LEFT JOIN seminar_proposal_penilaian spp
 			ON spp.final_proposal_id = pp.final_proposal_id
+		WHERE ` + kondisiPeriode + `
 
 		GROUP BY
 			fp.id, u.nama_lengkap, t.jurusan, fp.topik_penelitian,
 			d_ketua.nama_lengkap, d1.nama_lengkap, d2.nama_lengkap
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, argsPeriode...)
 	if err != nil {
 		log.Printf("Error querying database: %v", err)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"database/sql"
+	"document_service/models"
+	"document_service/utils"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// sesiDariToken memverifikasi token Bearer dan memastikan akun pemiliknya masih aktif. Token tetap
+// berlaku sampai kedaluwarsa walaupun akunnya dinonaktifkan, sehingga status akun dicek di setiap
+// request. Mengembalikan klaim token dan id akun; respons 401 dikirim bila tidak valid.
+func sesiDariToken(w http.ResponseWriter, r *http.Request, db *sql.DB) (*utils.Claims, int, bool) {
+	token := utils.BearerToken(r)
+	claims, err := utils.ParseJWT(token)
+	if token == "" || err != nil {
+		respondJSON(w, http.StatusUnauthorized, map[string]interface{}{"status": "error", "message": "Sesi tidak valid, silakan login kembali"})
+		return nil, 0, false
+	}
+	userID, err := models.UserIDAktif(db, claims.Email)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusUnauthorized, map[string]interface{}{"status": "error", "message": "Akun Anda telah dinonaktifkan"})
+		return nil, 0, false
+	}
+	if err != nil {
+		log.Printf("Gagal memeriksa status akun %s: %v", claims.Email, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal memeriksa akun"})
+		return nil, 0, false
+	}
+	return claims, userID, true
+}
+
+// sesiAdmin seperti sesiDariToken, tetapi hanya menerima akun admin (403 untuk role lain)
+func sesiAdmin(w http.ResponseWriter, r *http.Request, db *sql.DB) (*utils.Claims, bool) {
+	claims, _, ok := sesiDariToken(w, r, db)
+	if !ok {
+		return nil, false
+	}
+	if strings.ToLower(claims.Role) != "admin" {
+		respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": "Hanya admin yang dapat mengakses fitur ini"})
+		return nil, false
+	}
+	return claims, true
+}
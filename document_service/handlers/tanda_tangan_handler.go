@@ -0,0 +1,389 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"document_service/config"
+	"document_service/entities"
+	"document_service/models"
+	"document_service/utils"
+	"document_service/utils/beritaacara"
+	"document_service/utils/filemanager"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// clientIP mengambil alamat IP klien, mengutamakan header dari reverse proxy
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return realIP
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SignDokumenHandler menandatangani BAP / lembar pengesahan secara elektronik oleh dosen yang login.
+// Identitas diambil dari token (Authorization: Bearer) dan harus cocok dengan akun dosen_id.
+func SignDokumenHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "POST, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	claims, _, ok := sesiDariToken(w, r, db)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		DokumenID int `json:"dokumen_id"`
+		DosenID   int `json:"dosen_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.DokumenID == 0 || req.DosenID == 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "dokumen_id dan dosen_id wajib diisi"})
+		return
+	}
+
+	ttModel := models.NewTandaTanganModel(db)
+
+	email, err := ttModel.GetDosenEmail(req.DosenID)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Dosen tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	if !strings.EqualFold(email, claims.Email) {
+		respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": "Akun yang login bukan milik dosen ini"})
+		return
+	}
+
+	baModel := models.NewBeritaAcaraModel(db)
+	dokumen, err := baModel.GetDokumenByID(req.DokumenID)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Dokumen tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	// Hanya versi terbaru yang sesuai data saat ini yang boleh ditandatangani
+	current, _, err := syncDokumenSeminar(db, dokumen.Jenis, dokumen.Tahap, dokumen.FinalID, false)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+	if current.ID != dokumen.ID {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{
+			"status":  "error",
+			"message": fmt.Sprintf("Dokumen sudah diperbarui ke versi %d, silakan tandatangani versi terbaru", current.Versi),
+			"data":    current,
+		})
+		return
+	}
+
+	data, _, err := dokumenData(baModel, dokumen.Jenis, dokumen.Tahap, dokumen.FinalID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	var signer *entities.PengujiBAP
+	for _, p := range beritaacara.Penandatangan(data) {
+		if p.DosenID == req.DosenID {
+			p := p
+			signer = &p
+			break
+		}
+	}
+	if signer == nil {
+		respondJSON(w, http.StatusForbidden, map[string]interface{}{"status": "error", "message": "Dosen bukan penguji atau pembimbing pada seminar ini"})
+		return
+	}
+
+	sessionSum := sha256.Sum256([]byte(utils.BearerToken(r)))
+	userAgent := r.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	tanda := &entities.TandaTangan{
+		DokumenID:    dokumen.ID,
+		DosenID:      req.DosenID,
+		Peran:        signer.Peran,
+		NamaDosen:    signer.Nama,
+		SignedAt:     time.Now(),
+		DocumentHash: dokumen.FileHash,
+		SessionHash:  hex.EncodeToString(sessionSum[:]),
+		IPAddress:    clientIP(r),
+		UserAgent:    userAgent,
+	}
+
+	var signedPath string
+	err = ttModel.Sign(tanda, func(existing []entities.TandaTangan) (string, string, error) {
+		opts := &beritaacara.Options{
+			KodeVerifikasi: dokumen.KodeVerifikasi,
+			VerifyURL:      verifyURL(dokumen.KodeVerifikasi),
+			Tanda:          map[int]beritaacara.Stamp{},
+		}
+		for _, e := range append(existing, *tanda) {
+			opts.Tanda[e.DosenID] = beritaacara.Stamp{Nama: e.NamaDosen, SignedAt: e.SignedAt}
+		}
+		suffix := fmt.Sprintf("v%d_ttd%d", dokumen.Versi, len(existing)+1)
+		path, hash, err := writeDokumenFile(dokumen.Jenis, dokumen.Tahap, dokumen.FinalID, suffix, renderDokumen(dokumen.Jenis, data, opts))
+		signedPath = path
+		return path, hash, err
+	})
+	if err == models.ErrSudahDitandatangani {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	} else if err != nil {
+		if signedPath != "" {
+			_ = os.Remove(signedPath)
+		}
+		log.Printf("Gagal menandatangani dokumen #%d: %v", dokumen.ID, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal menyimpan tanda tangan"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Dokumen berhasil ditandatangani",
+		"data": map[string]interface{}{
+			"tanda_tangan":    tanda,
+			"kode_verifikasi": dokumen.KodeVerifikasi,
+			"verify_url":      verifyURL(dokumen.KodeVerifikasi),
+		},
+	})
+}
+
+// GetTandaTanganDokumenHandler menampilkan status tanda tangan sebuah dokumen
+func GetTandaTanganDokumenHandler(w http.ResponseWriter, r *http.Request) {
+	setJadwalCORS(w, "GET, OPTIONS")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "ID dokumen tidak valid"})
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	dokumen, err := models.NewBeritaAcaraModel(db).GetDokumenByID(id)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Dokumen tidak ditemukan"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	status, err := statusTandaTangan(db, dokumen)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   status,
+	})
+}
+
+// statusTandaTangan menyusun ringkasan dokumen, tanda tangan yang ada, dan penandatangan yang belum
+func statusTandaTangan(db *sql.DB, dokumen *entities.DokumenSeminar) (map[string]interface{}, error) {
+	baModel := models.NewBeritaAcaraModel(db)
+
+	signatures, err := models.NewTandaTanganModel(db).GetByDokumen(dokumen.ID)
+	if err != nil {
+		return nil, err
+	}
+	signedBy := make(map[int]bool, len(signatures))
+	daftar := make([]map[string]interface{}, 0, len(signatures))
+	for _, t := range signatures {
+		signedBy[t.DosenID] = true
+		daftar = append(daftar, map[string]interface{}{
+			"nama_dosen":    t.NamaDosen,
+			"peran":         t.Peran,
+			"signed_at":     t.SignedAt,
+			"document_hash": t.DocumentHash,
+			"hash_cocok":    t.DocumentHash == dokumen.FileHash,
+		})
+	}
+
+	data, err := baModel.GetBeritaAcaraData(dokumen.Tahap, dokumen.FinalID)
+	if err != nil {
+		return nil, err
+	}
+	belum := []map[string]interface{}{}
+	for _, p := range beritaacara.Penandatangan(data) {
+		if !signedBy[p.DosenID] {
+			belum = append(belum, map[string]interface{}{"peran": p.Peran, "nama_dosen": p.Nama})
+		}
+	}
+
+	latest, err := baModel.GetLatestDokumen(dokumen.Jenis, dokumen.Tahap, dokumen.FinalID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Integritas: file di server harus sama dengan hash yang tercatat
+	filePath, expected := dokumen.FilePath, dokumen.FileHash
+	if dokumen.SignedFilePath != "" {
+		filePath, expected = dokumen.SignedFilePath, dokumen.SignedFileHash
+	}
+	actual, err := hashFile(filePath)
+	fileUtuh := err == nil && actual == expected
+
+	return map[string]interface{}{
+		"kode_verifikasi":      dokumen.KodeVerifikasi,
+		"jenis":                dokumen.Jenis,
+		"tahap":                dokumen.Tahap,
+		"versi":                dokumen.Versi,
+		"versi_terbaru":        latest.ID == dokumen.ID,
+		"nama_taruna":          data.NamaTaruna,
+		"topik_penelitian":     data.TopikPenelitian,
+		"dibuat_pada":          dokumen.CreatedAt,
+		"file_hash":            expected,
+		"file_utuh":            fileUtuh,
+		"tanda_tangan":         daftar,
+		"belum_ditandatangani": belum,
+		"lengkap":              len(belum) == 0,
+	}, nil
+}
+
+// VerifyDokumenHandler adalah endpoint publik /verify/{code} yang dibuka dari QR code pada dokumen
+func VerifyDokumenHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	kode := strings.ToUpper(mux.Vars(r)["code"])
+
+	db, err := config.GetDB()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal koneksi database"})
+		return
+	}
+	defer db.Close()
+
+	dokumen, err := models.NewBeritaAcaraModel(db).GetDokumenByKode(kode)
+	if err == sql.ErrNoRows {
+		respondJSON(w, http.StatusNotFound, map[string]interface{}{"status": "error", "message": "Kode verifikasi tidak dikenal, dokumen tidak terdaftar di SIMTA"})
+		return
+	} else if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal memeriksa dokumen"})
+		return
+	}
+
+	status, err := statusTandaTangan(db, dokumen)
+	if err != nil {
+		log.Printf("Gagal verifikasi dokumen %s: %v", utils.SanitizeLogInput(kode), err)
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal memeriksa dokumen"})
+		return
+	}
+
+	// POST: bandingkan file yang diunggah dengan dokumen asli atau salinan bertanda tangan
+	if r.Method == http.MethodPost {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "File wajib diunggah"})
+			return
+		}
+		defer file.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(h, io.LimitReader(file, filemanager.MaxFileSize)); err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "error", "message": "Gagal membaca file"})
+			return
+		}
+		uploaded := hex.EncodeToString(h.Sum(nil))
+
+		cocok := uploaded == dokumen.FileHash
+		signatures, err := models.NewTandaTanganModel(db).GetByDokumen(dokumen.ID)
+		if err != nil {
+			respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"status": "error", "message": "Gagal memeriksa dokumen"})
+			return
+		}
+		jumlahTtd := 0
+		for i, t := range signatures {
+			if uploaded == t.SignedFileHash {
+				cocok = true
+				jumlahTtd = i + 1
+			}
+		}
+
+		status["file_diunggah_hash"] = uploaded
+		status["file_diunggah_cocok"] = cocok
+		if cocok {
+			status["keterangan"] = fmt.Sprintf("File asli dan tidak diubah (memuat %d tanda tangan)", jumlahTtd)
+		} else {
+			status["keterangan"] = "File tidak sesuai dengan dokumen yang diterbitkan SIMTA atau telah diubah"
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   status,
+	})
+}
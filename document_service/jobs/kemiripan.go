@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"document_service/config"
+	"document_service/models"
+	"log"
+	"os"
+	"time"
+)
+
+// segeraKemiripan membangunkan pembangun indeks setelah ada topik baru (mis. ICP yang baru diunggah)
+var segeraKemiripan = make(chan struct{}, 1)
+
+// SegerakanIndeksKemiripan meminta indeks kemiripan dibangun ulang tanpa menunggu putaran berkala
+// berikutnya. Permintaan yang datang selama pembangunan berjalan digabung menjadi satu putaran lagi.
+func SegerakanIndeksKemiripan() {
+	select {
+	case segeraKemiripan <- struct{}{}:
+	default:
+	}
+}
+
+// StartIndeksKemiripan membangun ulang indeks kemiripan topik di latar belakang agar unggahan tidak
+// menunggu korpus dimuat dari database. Interval pembangunan berkala bisa diatur lewat env
+// KEMIRIPAN_INTERVAL (default 10 menit) untuk menangkap topik dari tahap lain.
+func StartIndeksKemiripan() {
+	interval := 10 * time.Minute
+	if v := os.Getenv("KEMIRIPAN_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			interval = d
+		} else {
+			log.Printf("KEMIRIPAN_INTERVAL tidak valid (%q), memakai %s", v, interval)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			runIndeksKemiripan()
+			select {
+			case <-segeraKemiripan:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func runIndeksKemiripan() {
+	db, err := config.GetDB()
+	if err != nil {
+		log.Printf("Kemiripan: gagal koneksi database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	if err := models.NewKemiripanModel(db).BangunIndeks(); err != nil {
+		log.Printf("Kemiripan: gagal membangun indeks: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package jobs
+
+import (
+	"document_service/config"
+	"document_service/events"
+	"document_service/models"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// batchOutbox adalah banyaknya event yang dikirim per putaran relay
+	batchOutbox = 50
+	// backoffAwalOutbox dan backoffMaksOutbox membatasi jeda coba ulang yang berlipat dua tiap kegagalan
+	backoffAwalOutbox = 30 * time.Second
+	backoffMaksOutbox = time.Hour
+)
+
+// segeraOutbox membangunkan relay setelah transaksi yang mencatat event di-commit
+var segeraOutbox = make(chan struct{}, 1)
+
+// SegerakanOutbox meminta relay mengirim event tanpa menunggu putaran berkala berikutnya
+func SegerakanOutbox() {
+	select {
+	case segeraOutbox <- struct{}{}:
+	default:
+	}
+}
+
+// StartRelayOutbox menjalankan relay yang mengirim event outbox ke konsumen minimal sekali.
+// Interval putaran bisa diatur lewat env OUTBOX_INTERVAL (default 15 detik) dan batas percobaan
+// sebelum event dipindah ke status gagal lewat OUTBOX_MAKS_PERCOBAAN (default 10).
+func StartRelayOutbox() {
+	interval := 15 * time.Second
+	if v := os.Getenv("OUTBOX_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			interval = d
+		} else {
+			log.Printf("OUTBOX_INTERVAL tidak valid (%q), memakai %s", v, interval)
+		}
+	}
+	maksPercobaan := 10
+	if v := os.Getenv("OUTBOX_MAKS_PERCOBAAN"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			maksPercobaan = n
+		} else {
+			log.Printf("OUTBOX_MAKS_PERCOBAAN tidak valid (%q), memakai %d", v, maksPercobaan)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			runRelayOutbox(maksPercobaan)
+			select {
+			case <-segeraOutbox:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func runRelayOutbox(maksPercobaan int) {
+	db, err := config.GetDB()
+	if err != nil {
+		log.Printf("Outbox: gagal koneksi database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	model := models.NewOutboxModel(db)
+	for {
+		list, err := model.AmbilSiapKirim(batchOutbox)
+		if err != nil {
+			log.Printf("Outbox: %v", err)
+			return
+		}
+		for _, e := range list {
+			errKirim := kirimKeKonsumen(e.Payload)
+			if errKirim == nil {
+				err = model.TandaiTerkirim(e.ID)
+			} else {
+				var cobaLagi *time.Time
+				if percobaan := e.Percobaan + 1; percobaan < maksPercobaan && !errors.Is(errKirim, events.ErrDitolak) {
+					t := time.Now().Add(backoffOutbox(percobaan))
+					cobaLagi = &t
+				} else {
+					log.Printf("Outbox: event %s (%s) gagal setelah %d percobaan: %v", e.Tipe, e.EventID, percobaan, errKirim)
+				}
+				err = model.TandaiGagalKirim(e.ID, errKirim.Error(), cobaLagi)
+			}
+			if err != nil {
+				log.Printf("Outbox: gagal memperbarui event %s: %v", e.EventID, err)
+				return
+			}
+		}
+		if len(list) < batchOutbox {
+			return
+		}
+	}
+}
+
+// kirimKeKonsumen mengirim satu event ke semua konsumen. Bila sebagian gagal, event dikirim ulang
+// ke semua konsumen pada percobaan berikutnya; konsumen mengabaikan duplikat berdasarkan id event.
+func kirimKeKonsumen(payload []byte) error {
+	var galat []string
+	ditolak := true // dead letter hanya bila semua kegagalan bersifat permanen
+	for _, url := range events.Konsumen() {
+		if err := events.Kirim(url, payload); err != nil {
+			galat = append(galat, err.Error())
+			ditolak = ditolak && errors.Is(err, events.ErrDitolak)
+		}
+	}
+	if len(galat) == 0 {
+		return nil
+	}
+	if ditolak {
+		return errors.Join(events.ErrDitolak, errors.New(strings.Join(galat, "; ")))
+	}
+	return errors.New(strings.Join(galat, "; "))
+}
+
+// backoffOutbox mengembalikan jeda sebelum percobaan berikutnya setelah n kegagalan
+func backoffOutbox(n int) time.Duration {
+	d := backoffAwalOutbox
+	for i := 1; i < n && d < backoffMaksOutbox; i++ {
+		d *= 2
+	}
+	if d > backoffMaksOutbox {
+		d = backoffMaksOutbox
+	}
+	return d
+}
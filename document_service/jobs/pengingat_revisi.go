@@ -0,0 +1,139 @@
+// Package jobs berisi pekerjaan latar belakang yang dijalankan berkala oleh document_service.
+package jobs
+
+import (
+	"database/sql"
+	"document_service/config"
+	"document_service/entities"
+	"document_service/models"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"time"
+)
+
+var labelTahap = map[string]string{
+	"proposal":   "Proposal",
+	"laporan70":  "Laporan 70%",
+	"laporan100": "Laporan 100%",
+}
+
+// StartPengingatRevisi menjalankan pengecekan batas revisi secara berkala.
+// Interval bisa diatur lewat env PENGINGAT_REVISI_INTERVAL (mis. "30m"), default 1 jam.
+func StartPengingatRevisi() {
+	interval := time.Hour
+	if v := os.Getenv("PENGINGAT_REVISI_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			interval = d
+		} else {
+			log.Printf("PENGINGAT_REVISI_INTERVAL tidak valid (%q), memakai %s", v, interval)
+		}
+	}
+
+	go func() {
+		for {
+			runPengingatRevisi()
+			time.Sleep(interval)
+		}
+	}()
+}
+
+func runPengingatRevisi() {
+	db, err := config.GetDB()
+	if err != nil {
+		log.Printf("Pengingat revisi: gagal koneksi database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	terkirim, err := KirimPengingatRevisi(db, time.Now())
+	if err != nil {
+		log.Printf("Pengingat revisi: %v", err)
+		return
+	}
+	if terkirim > 0 {
+		log.Printf("Pengingat revisi: %d pengingat/eskalasi terkirim", terkirim)
+	}
+}
+
+// KirimPengingatRevisi mengirim pengingat H-n kepada taruna yang belum mengumpulkan revisi dan
+// eskalasi ke pembimbing serta admin bila batas sudah lewat. Setiap pengingat hanya terkirim sekali
+// per batas; bila batas diperpanjang, pengingat dihitung ulang terhadap batas yang baru.
+func KirimPengingatRevisi(db *sql.DB, now time.Time) (int, error) {
+	model := models.NewBatasRevisiModel(db)
+
+	configs, err := model.GetConfig()
+	if err != nil {
+		return 0, err
+	}
+	pengingatHari := map[string][]int{}
+	for _, c := range configs {
+		pengingatHari[c.Tahap] = c.PengingatHari
+	}
+
+	list, err := model.GetBatasBelumSubmit("")
+	if err != nil {
+		return 0, err
+	}
+
+	terkirim := 0
+	kirim := func(b entities.BatasRevisi, userID int, jenis, pesan string) {
+		ok, err := model.CatatPengingat(&entities.PengingatRevisi{
+			Tahap:   b.Tahap,
+			FinalID: b.FinalID,
+			UserID:  userID,
+			Jenis:   jenis,
+			Pesan:   pesan,
+		})
+		if err != nil {
+			log.Printf("Pengingat revisi: gagal mencatat %s untuk user %d: %v", jenis, userID, err)
+			return
+		}
+		if ok {
+			terkirim++
+		}
+	}
+
+	for _, b := range list {
+		batas := b.Batas.Format("02-01-2006 15:04") + " WIB"
+		kunci := "@" + b.Batas.Format("2006-01-02")
+		tahap := labelTahap[b.Tahap]
+
+		sisa := b.Batas.Sub(now)
+		if sisa > 0 {
+			// Kirim hanya ambang terkecil yang sudah terlewati agar tidak menumpuk saat layanan baru hidup
+			hari := 0
+			for _, h := range pengingatHari[b.Tahap] {
+				if sisa <= time.Duration(h)*24*time.Hour {
+					hari = h
+				}
+			}
+			if hari == 0 {
+				continue
+			}
+			sisaHari := int(math.Ceil(sisa.Hours() / 24))
+			kirim(b, b.UserID, fmt.Sprintf("h-%d%s", hari, kunci), fmt.Sprintf(
+				"Batas pengumpulan revisi %s Anda %s (sisa %d hari). Segera unggah revisi atau ajukan perpanjangan.",
+				tahap, batas, sisaHari))
+			continue
+		}
+
+		// Batas terlewati: eskalasi ke taruna, dosen pembimbing, dan admin
+		jenis := "eskalasi" + kunci
+		kirim(b, b.UserID, jenis, fmt.Sprintf(
+			"Batas pengumpulan revisi %s Anda telah lewat (%s). Kasus ini diteruskan ke dosen pembimbing dan admin.",
+			tahap, batas))
+
+		pembimbing, admin, err := model.GetPenerimaEskalasi(b.UserID)
+		if err != nil {
+			log.Printf("Pengingat revisi: gagal mengambil penerima eskalasi user %d: %v", b.UserID, err)
+			continue
+		}
+		pesan := fmt.Sprintf("Taruna %s belum mengumpulkan revisi %s hingga batas %s.", b.NamaTaruna, tahap, batas)
+		for _, id := range append(pembimbing, admin...) {
+			kirim(b, id, jenis, pesan)
+		}
+	}
+	return terkirim, nil
+}
@@ -0,0 +1,159 @@
+package jobs
+
+import (
+	"database/sql"
+	"document_service/config"
+	"document_service/models"
+	"document_service/utils/pdfteks"
+	"document_service/utils/winnowing"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// batchIndeksPlagiarisme adalah banyaknya dokumen yang diekstrak per putaran saat melengkapi korpus
+const batchIndeksPlagiarisme = 20
+
+// antreanPlagiarisme menampung id final laporan 100% yang baru diunggah agar segera diperiksa
+var antreanPlagiarisme = make(chan int, 64)
+
+// AntrekanPlagiarisme meminta pemeriksaan final laporan 100% tanpa menunggu penyapuan berkala.
+// Laporannya harus sudah berstatus antri; bila antrean penuh, laporan diambil pada penyapuan berikutnya.
+func AntrekanPlagiarisme(finalLaporan100ID int) {
+	select {
+	case antreanPlagiarisme <- finalLaporan100ID:
+	default:
+	}
+}
+
+// StartPlagiarisme menjalankan pemeriksaan plagiarisme lokal di latar belakang. Satu worker memproses
+// unggahan baru dan secara berkala melengkapi korpus serta mengambil laporan yang masih antri.
+// Interval penyapuan bisa diatur lewat env PLAGIARISME_INTERVAL (mis. "10m"), default 30 menit.
+func StartPlagiarisme() {
+	interval := 30 * time.Minute
+	if v := os.Getenv("PLAGIARISME_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			interval = d
+		} else {
+			log.Printf("PLAGIARISME_INTERVAL tidak valid (%q), memakai %s", v, interval)
+		}
+	}
+
+	go func() {
+		runPlagiarisme(0)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case id := <-antreanPlagiarisme:
+				runPlagiarisme(id)
+			case <-ticker.C:
+				runPlagiarisme(0)
+			}
+		}
+	}()
+}
+
+// runPlagiarisme memeriksa satu final laporan 100%, atau seluruh antrean bila id = 0
+func runPlagiarisme(finalLaporan100ID int) {
+	db, err := config.GetDB()
+	if err != nil {
+		log.Printf("Plagiarisme: gagal koneksi database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	model := models.NewPlagiarismeModel(db)
+	ids := []int{finalLaporan100ID}
+	if finalLaporan100ID == 0 {
+		if err := model.AntrekanBelumDiperiksa(); err != nil {
+			log.Printf("Plagiarisme: %v", err)
+			return
+		}
+		if ids, err = model.GetAntrian(); err != nil {
+			log.Printf("Plagiarisme: %v", err)
+			return
+		}
+		if err := LengkapiKorpusPlagiarisme(db); err != nil {
+			log.Printf("Plagiarisme: gagal melengkapi korpus: %v", err)
+			return
+		}
+	}
+
+	for _, id := range ids {
+		if err := PeriksaPlagiarisme(db, id); err != nil {
+			log.Printf("Plagiarisme: final laporan 100%% %d: %v", id, err)
+		}
+	}
+}
+
+// LengkapiKorpusPlagiarisme mengekstrak teks dan sidik jari semua final laporan yang belum diindeks.
+// PDF yang gagal dibaca tetap dicatat beserta alasannya sehingga tidak dicoba ulang.
+func LengkapiKorpusPlagiarisme(db *sql.DB) error {
+	model := models.NewPlagiarismeModel(db)
+	for {
+		list, err := model.BelumDiindeks(batchIndeksPlagiarisme)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		for _, d := range list {
+			var kata []string
+			teks, err := pdfteks.EkstrakTeks(d.FilePath)
+			pesan := ""
+			if err != nil {
+				pesan = err.Error()
+			} else {
+				kata = winnowing.Kata(teks)
+			}
+			if err := model.Indeks(d, kata, pesan); err != nil {
+				return fmt.Errorf("gagal mengindeks %s %d: %v", d.Tabel, d.ID, err)
+			}
+		}
+	}
+}
+
+// PeriksaPlagiarisme membandingkan final laporan 100% dengan seluruh korpus lokal dan menyimpan
+// laporannya. Laporan yang tidak berstatus antri (sudah diproses atau belum diantrekan) dilewati.
+func PeriksaPlagiarisme(db *sql.DB, finalLaporan100ID int) error {
+	model := models.NewPlagiarismeModel(db)
+	mulai, err := model.Mulai(finalLaporan100ID)
+	if err != nil || !mulai {
+		return err
+	}
+
+	gagal := func(pesan string) error {
+		if err := model.Gagal(finalLaporan100ID, pesan); err != nil {
+			return err
+		}
+		return fmt.Errorf("%s", pesan)
+	}
+
+	// Korpus dilengkapi dulu agar unggahan lain yang belum diindeks ikut dibandingkan
+	if err := LengkapiKorpusPlagiarisme(db); err != nil {
+		return gagal(err.Error())
+	}
+	dokumen, err := model.GetFinalLaporan100(finalLaporan100ID)
+	if err != nil {
+		return gagal("Final laporan 100% tidak ditemukan: " + err.Error())
+	}
+	kata, pesanError, err := model.KataDokumen(dokumen.Tabel, dokumen.ID)
+	if err != nil {
+		return gagal("Teks dokumen belum diindeks: " + err.Error())
+	}
+	if pesanError != "" {
+		return gagal("Gagal membaca PDF: " + pesanError)
+	}
+	if len(kata) < winnowing.PanjangShingle {
+		return gagal("Teks PDF tidak dapat diekstrak; kemungkinan dokumen hasil pindaian (scan)")
+	}
+
+	laporan, err := model.Bandingkan(dokumen, kata)
+	if err != nil {
+		return gagal("Gagal membandingkan dokumen: " + err.Error())
+	}
+	return model.Simpan(laporan)
+}
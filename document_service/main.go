@@ -1,7 +1,9 @@
 package main
 
 import (
+	"document_service/config"
 	"document_service/handlers"
+	"document_service/jobs"
 	"document_service/utils/filemanager"
 	"log"
 	"net/http"
@@ -18,10 +20,34 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Jalankan migrasi skema yang belum diterapkan
+	db, err := config.GetDB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := config.Migrate(db); err != nil {
+		log.Fatal(err)
+	}
+	db.Close()
+
+	// Pengingat dan eskalasi batas revisi berjalan di latar belakang
+	jobs.StartPengingatRevisi()
+
+	// Pemeriksaan plagiarisme lokal untuk final laporan 100%
+	jobs.StartPlagiarisme()
+
+	// Relay outbox: kirim event domain ke notification_service minimal sekali
+	jobs.StartRelayOutbox()
+
+	// Indeks kemiripan topik dibangun ulang di latar belakang
+	jobs.StartIndeksKemiripan()
+
 	// Set up routes
 	r.HandleFunc("/upload/icp", handlers.UploadICPHandler).Methods("POST", "OPTIONS")
 	r.HandleFunc("/icp", handlers.GetICPHandler).Methods("GET", "OPTIONS")
 	r.HandleFunc("/icp/download", handlers.DownloadFileICPHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/kemiripan", handlers.GetKemiripanHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/kemiripan/cek", handlers.CekKemiripanHandler).Methods("GET", "OPTIONS")
 	r.HandleFunc("/icp/{id}", handlers.GetICPByIDHandler).Methods("GET", "OPTIONS")
 
 	// Route untuk review ICP
@@ -186,6 +212,8 @@ func main() {
 	r.HandleFunc("/finallaporan100/download/{id}", handlers.DownloadFinalLaporan100Handler).Methods("GET", "OPTIONS")
 	r.HandleFunc("/finallaporan100/dosen/download/{id}", handlers.DownloadFinalLaporan100DosenHandler).Methods("GET", "OPTIONS")
 	r.HandleFunc("/finallaporan100/taruna-topics", handlers.GetTarunaTopicsHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/plagiarisme", handlers.GetPlagiarismeHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/plagiarisme/ulang", handlers.PeriksaUlangPlagiarismeHandler).Methods("POST", "OPTIONS")
 
 	// Register seminar laporan100 routes
 	r.HandleFunc("/seminarlaporan100/dosen", handlers.GetSeminarLaporan100ByDosenHandler).Methods("GET", "OPTIONS")
@@ -210,6 +238,57 @@ func main() {
 	r.HandleFunc("/tugasakhir/detail/{id}", handlers.GetTugasAkhirDetailHandler).Methods("GET", "OPTIONS")
 	r.HandleFunc("/revisilaporan100/download/{id}/{jenis}", handlers.DownloadRevisiFileHandler).Methods("GET", "OPTIONS")
 
+	// Jadwal seminar & feed kalender (iCalendar)
+	r.HandleFunc("/jadwalseminar", handlers.SaveJadwalSeminarHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/jadwalseminar", handlers.GetJadwalSeminarHandler).Methods("GET")
+	r.HandleFunc("/jadwalseminar/cancel", handlers.CancelJadwalSeminarHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/jadwalseminar/{id}/ics", handlers.DownloadJadwalSeminarICSHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/kalender/token", handlers.GetKalenderTokenHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/kalender/token/reset", handlers.ResetKalenderTokenHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/kalender/{token:[a-f0-9]+}.ics", handlers.KalenderFeedHandler).Methods("GET")
+
+	// Nilai seminar & Berita Acara (BAP)
+	r.HandleFunc("/nilaiseminar", handlers.SubmitNilaiSeminarHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/beritaacara", handlers.GetBeritaAcaraHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/beritaacara/generate", handlers.GenerateBeritaAcaraHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/beritaacara/download", handlers.DownloadBeritaAcaraHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/beritaacara/download/{id}", handlers.DownloadBeritaAcaraHandler).Methods("GET", "OPTIONS")
+
+	// Catatan perbaikan terstruktur per butir
+	r.HandleFunc("/catatanperbaikan/item", handlers.CreateCatatanPerbaikanItemHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/catatanperbaikan/item", handlers.GetCatatanPerbaikanItemsHandler).Methods("GET")
+	r.HandleFunc("/catatanperbaikan/item/{id}", handlers.DeleteCatatanPerbaikanItemHandler).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/catatanperbaikan/item/{id}/tanggapan", handlers.TanggapiCatatanPerbaikanHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/catatanperbaikan/item/{id}/review", handlers.ReviewCatatanPerbaikanHandler).Methods("POST", "OPTIONS")
+
+	// Batas revisi pasca seminar, perpanjangan, dan pengingat
+	r.HandleFunc("/batasrevisi", handlers.GetBatasRevisiHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/batasrevisi/config", handlers.GetBatasRevisiConfigHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/batasrevisi/config", handlers.UpdateBatasRevisiConfigHandler).Methods("POST")
+	r.HandleFunc("/batasrevisi/perpanjangan", handlers.AjukanPerpanjanganRevisiHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/batasrevisi/perpanjangan", handlers.GetPerpanjanganRevisiHandler).Methods("GET")
+	r.HandleFunc("/batasrevisi/perpanjangan/{id}/keputusan", handlers.PutuskanPerpanjanganRevisiHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/pengingat", handlers.GetPengingatHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/pengingat/{id}/dibaca", handlers.TandaiPengingatDibacaHandler).Methods("POST", "OPTIONS")
+
+	// Jendela pengumpulan per tahap & periode, perpanjangan per taruna
+	r.HandleFunc("/jendela", handlers.GetJendelaPengumpulanHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/jendela", handlers.SimpanJendelaPengumpulanHandler).Methods("POST")
+	r.HandleFunc("/jendela/terbuka", handlers.GetJendelaTerbukaHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/jendela/perpanjangan/{id:[0-9]+}", handlers.HapusPerpanjanganPengumpulanHandler).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/jendela/{id:[0-9]+}", handlers.HapusJendelaPengumpulanHandler).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/jendela/{id:[0-9]+}/perpanjangan", handlers.GetPerpanjanganPengumpulanHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/jendela/{id:[0-9]+}/perpanjangan", handlers.SimpanPerpanjanganPengumpulanHandler).Methods("POST")
+
+	// Outbox event domain; event gagal dapat diantrekan ulang
+	r.HandleFunc("/outbox", handlers.GetOutboxHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/outbox/{id:[0-9]+}/ulang", handlers.AntrekanUlangOutboxHandler).Methods("POST", "OPTIONS")
+
+	// Tanda tangan elektronik & verifikasi dokumen (publik)
+	r.HandleFunc("/dokumen/sign", handlers.SignDokumenHandler).Methods("POST", "OPTIONS")
+	r.HandleFunc("/dokumen/{id}/tandatangan", handlers.GetTandaTanganDokumenHandler).Methods("GET", "OPTIONS")
+	r.HandleFunc("/verify/{code:[A-Fa-f0-9]{16}}", handlers.VerifyDokumenHandler).Methods("GET", "POST", "OPTIONS")
+
 	// Create custom server with increased limits
 	srv := &http.Server{
 		Handler:      r,
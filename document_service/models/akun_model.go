@@ -0,0 +1,24 @@
+package models
+
+import "database/sql"
+
+// UserIDAktif mengembalikan id akun pemilik email; sql.ErrNoRows bila akun tidak ada atau sudah
+// dinonaktifkan admin
+func UserIDAktif(db *sql.DB, email string) (int, error) {
+	var id int
+	err := db.QueryRow("SELECT id FROM users WHERE email = ? AND dinonaktifkan_at IS NULL", email).Scan(&id)
+	return id, err
+}
+
+// DosenIDDariEmail mengembalikan dosen.id milik akun dengan email tersebut, dicocokkan seperti
+// GetDosenEmail (email akun, atau email pada data dosen bila belum punya akun); sql.ErrNoRows bila
+// email bukan milik dosen
+func DosenIDDariEmail(db *sql.DB, email string) (int, error) {
+	var id int
+	err := db.QueryRow(`
+		SELECT d.id FROM dosen d
+		LEFT JOIN users u ON u.id = d.user_id
+		WHERE COALESCE(u.email, d.email) = ?
+		LIMIT 1`, email).Scan(&id)
+	return id, err
+}
@@ -0,0 +1,336 @@
+package models
+
+import (
+	"database/sql"
+	"document_service/entities"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type BatasRevisiModel struct {
+	db *sql.DB
+}
+
+func NewBatasRevisiModel(db *sql.DB) *BatasRevisiModel {
+	return &BatasRevisiModel{
+		db: db,
+	}
+}
+
+// Status batas revisi, dihitung oleh view v_batas_revisi
+const (
+	BatasBerjalan  = "berjalan"
+	BatasSelesai   = "selesai"
+	BatasTerlambat = "terlambat"
+)
+
+// ParsePengingatHari mengubah "7,3,1" menjadi [7 3 1] (urut menurun, tanpa duplikat)
+func ParsePengingatHari(value string) ([]int, error) {
+	seen := map[int]bool{}
+	var hari []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("hari pengingat tidak valid: %s", part)
+		}
+		if !seen[n] {
+			seen[n] = true
+			hari = append(hari, n)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(hari)))
+	return hari, nil
+}
+
+func formatPengingatHari(hari []int) string {
+	parts := make([]string, len(hari))
+	for i, n := range hari {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ",")
+}
+
+// GetConfig mengembalikan pengaturan masa revisi semua tahap
+func (m *BatasRevisiModel) GetConfig() ([]entities.BatasRevisiConfig, error) {
+	rows, err := m.db.Query(`
+		SELECT tahap, durasi_hari, pengingat_hari, updated_at
+		FROM batas_revisi_config
+		ORDER BY FIELD(tahap, 'proposal', 'laporan70', 'laporan100')`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []entities.BatasRevisiConfig
+	for rows.Next() {
+		var c entities.BatasRevisiConfig
+		var pengingat string
+		if err := rows.Scan(&c.Tahap, &c.DurasiHari, &pengingat, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if c.PengingatHari, err = ParsePengingatHari(pengingat); err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+// UpdateConfig menyimpan lama masa revisi dan hari pengingat untuk satu tahap
+func (m *BatasRevisiModel) UpdateConfig(c *entities.BatasRevisiConfig) error {
+	_, err := m.db.Exec(`
+		INSERT INTO batas_revisi_config (tahap, durasi_hari, pengingat_hari)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			durasi_hari = VALUES(durasi_hari),
+			pengingat_hari = VALUES(pengingat_hari)`,
+		c.Tahap, c.DurasiHari, formatPengingatHari(c.PengingatHari))
+	return err
+}
+
+const batasSelectColumns = `
+	b.tahap, b.final_id, b.user_id, COALESCE(u.nama_lengkap, ''), b.tanggal_seminar,
+	b.durasi_hari, b.perpanjangan_hari, b.batas, b.submitted_at, b.status, b.sisa_detik
+	FROM v_batas_revisi b
+	LEFT JOIN users u ON u.id = b.user_id`
+
+func (m *BatasRevisiModel) queryBatas(where string, args ...interface{}) ([]entities.BatasRevisi, error) {
+	rows, err := m.db.Query("SELECT "+batasSelectColumns+" WHERE "+where+" ORDER BY b.batas ASC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.BatasRevisi{}
+	for rows.Next() {
+		var b entities.BatasRevisi
+		var submittedAt sql.NullTime
+		if err := rows.Scan(&b.Tahap, &b.FinalID, &b.UserID, &b.NamaTaruna, &b.TanggalSeminar,
+			&b.DurasiHari, &b.PerpanjanganHari, &b.Batas, &submittedAt, &b.Status, &b.SisaDetik); err != nil {
+			return nil, err
+		}
+		b.TanggalSeminar = toWIB(b.TanggalSeminar)
+		b.Batas = toWIB(b.Batas)
+		if submittedAt.Valid {
+			t := toWIB(submittedAt.Time)
+			b.SubmittedAt = &t
+		}
+		list = append(list, b)
+	}
+	return list, rows.Err()
+}
+
+// GetBatas mengembalikan batas revisi satu seminar yang sudah selesai dilaksanakan
+func (m *BatasRevisiModel) GetBatas(tahap string, finalID int) (*entities.BatasRevisi, error) {
+	list, err := m.queryBatas("b.tahap = ? AND b.final_id = ?", tahap, finalID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &list[0], nil
+}
+
+// GetBatasByUser mengembalikan batas revisi seluruh seminar taruna yang sudah dilaksanakan
+func (m *BatasRevisiModel) GetBatasByUser(userID int) ([]entities.BatasRevisi, error) {
+	return m.queryBatas("b.user_id = ?", userID)
+}
+
+// GetBatasBelumSubmit mengembalikan semua batas revisi yang revisinya belum dikumpulkan;
+// status "" berarti semua, selain itu difilter (berjalan / terlambat)
+func (m *BatasRevisiModel) GetBatasBelumSubmit(status string) ([]entities.BatasRevisi, error) {
+	if status == "" {
+		return m.queryBatas("b.submitted_at IS NULL")
+	}
+	return m.queryBatas("b.submitted_at IS NULL AND b.status = ?", status)
+}
+
+// ErrPerpanjanganMenunggu dikembalikan bila masih ada permohonan yang belum diputuskan
+var ErrPerpanjanganMenunggu = errors.New("masih ada permohonan perpanjangan yang menunggu keputusan")
+
+// CreatePerpanjangan menyimpan permohonan perpanjangan. Hanya boleh ada satu permohonan menunggu per seminar.
+func (m *BatasRevisiModel) CreatePerpanjangan(p *entities.PerpanjanganRevisi) error {
+	var menunggu int
+	if err := m.db.QueryRow(`
+		SELECT COUNT(*) FROM perpanjangan_revisi
+		WHERE tahap = ? AND final_id = ? AND status = 'menunggu'`, p.Tahap, p.FinalID).Scan(&menunggu); err != nil {
+		return err
+	}
+	if menunggu > 0 {
+		return ErrPerpanjanganMenunggu
+	}
+
+	result, err := m.db.Exec(`
+		INSERT INTO perpanjangan_revisi (tahap, final_id, user_id, tambahan_hari, alasan, status)
+		VALUES (?, ?, ?, ?, ?, 'menunggu')`,
+		p.Tahap, p.FinalID, p.UserID, p.TambahanHari, p.Alasan)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	p.ID = int(id)
+	p.Status = "menunggu"
+	return nil
+}
+
+// ListPerpanjangan mengembalikan permohonan perpanjangan; filter status dan user_id opsional
+func (m *BatasRevisiModel) ListPerpanjangan(status string, userID int) ([]entities.PerpanjanganRevisi, error) {
+	query := `
+		SELECT p.id, p.tahap, p.final_id, p.user_id, p.tambahan_hari, p.alasan, p.status,
+			COALESCE(p.catatan, ''), p.diputuskan_oleh, p.diputuskan_at, p.created_at,
+			COALESCE(u.nama_lengkap, '')
+		FROM perpanjangan_revisi p
+		LEFT JOIN users u ON u.id = p.user_id
+		WHERE 1 = 1`
+	var args []interface{}
+	if status != "" {
+		query += " AND p.status = ?"
+		args = append(args, status)
+	}
+	if userID != 0 {
+		query += " AND p.user_id = ?"
+		args = append(args, userID)
+	}
+	query += " ORDER BY p.created_at DESC"
+
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.PerpanjanganRevisi{}
+	for rows.Next() {
+		var p entities.PerpanjanganRevisi
+		var oleh sql.NullInt64
+		var at sql.NullTime
+		if err := rows.Scan(&p.ID, &p.Tahap, &p.FinalID, &p.UserID, &p.TambahanHari, &p.Alasan, &p.Status,
+			&p.Catatan, &oleh, &at, &p.CreatedAt, &p.NamaTaruna); err != nil {
+			return nil, err
+		}
+		if oleh.Valid {
+			id := int(oleh.Int64)
+			p.DiputuskanOleh = &id
+		}
+		if at.Valid {
+			t := toWIB(at.Time)
+			p.DiputuskanAt = &t
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// PutuskanPerpanjangan menyetujui / menolak permohonan yang masih menunggu
+func (m *BatasRevisiModel) PutuskanPerpanjangan(id int, status, catatan string, adminID int) (*entities.PerpanjanganRevisi, error) {
+	if status != "disetujui" && status != "ditolak" {
+		return nil, fmt.Errorf("status keputusan tidak valid: %s", status)
+	}
+	result, err := m.db.Exec(`
+		UPDATE perpanjangan_revisi
+		SET status = ?, catatan = ?, diputuskan_oleh = ?, diputuskan_at = ?
+		WHERE id = ? AND status = 'menunggu'`,
+		status, catatan, adminID, nowWIB(), id)
+	if err != nil {
+		return nil, err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	var p entities.PerpanjanganRevisi
+	err = m.db.QueryRow(`
+		SELECT id, tahap, final_id, user_id, tambahan_hari, status
+		FROM perpanjangan_revisi WHERE id = ?`, id).
+		Scan(&p.ID, &p.Tahap, &p.FinalID, &p.UserID, &p.TambahanHari, &p.Status)
+	return &p, err
+}
+
+// CatatPengingat menyimpan pengingat untuk satu pengguna. Mengembalikan false bila pengingat
+// dengan jenis yang sama sudah pernah dikirim (tidak dikirim ulang).
+func (m *BatasRevisiModel) CatatPengingat(p *entities.PengingatRevisi) (bool, error) {
+	result, err := m.db.Exec(`
+		INSERT IGNORE INTO pengingat_revisi (tahap, final_id, user_id, jenis, pesan)
+		VALUES (?, ?, ?, ?, ?)`,
+		p.Tahap, p.FinalID, p.UserID, p.Jenis, p.Pesan)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// GetPengingatByUser mengembalikan pengingat terbaru milik pengguna
+func (m *BatasRevisiModel) GetPengingatByUser(userID int) ([]entities.PengingatRevisi, error) {
+	rows, err := m.db.Query(`
+		SELECT id, tahap, final_id, user_id, jenis, pesan, dibaca, created_at
+		FROM pengingat_revisi
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT 50`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.PengingatRevisi{}
+	for rows.Next() {
+		var p entities.PengingatRevisi
+		if err := rows.Scan(&p.ID, &p.Tahap, &p.FinalID, &p.UserID, &p.Jenis, &p.Pesan, &p.Dibaca, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// TandaiPengingatDibaca menandai pengingat milik pengguna sudah dibaca
+func (m *BatasRevisiModel) TandaiPengingatDibaca(id, userID int) error {
+	_, err := m.db.Exec("UPDATE pengingat_revisi SET dibaca = 1 WHERE id = ? AND user_id = ?", id, userID)
+	return err
+}
+
+// GetPenerimaEskalasi mengembalikan users.id dosen pembimbing aktif taruna dan seluruh admin
+func (m *BatasRevisiModel) GetPenerimaEskalasi(userID int) (pembimbing []int, admin []int, err error) {
+	pembimbing, err = m.userIDs(`
+		SELECT DISTINCT d.user_id
+		FROM v_pembimbing_taruna dp
+		JOIN dosen d ON d.id = dp.dosen_id
+		WHERE dp.user_id = ? AND dp.status = 'aktif' AND d.user_id IS NOT NULL`, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	admin, err = m.userIDs("SELECT id FROM users WHERE LOWER(role) = 'admin'")
+	return pembimbing, admin, err
+}
+
+func (m *BatasRevisiModel) userIDs(query string, args ...interface{}) ([]int, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
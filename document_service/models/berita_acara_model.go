@@ -0,0 +1,453 @@
+package models
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"document_service/entities"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	JenisBAP        = "bap"
+	JenisPengesahan = "pengesahan"
+)
+
+// IsValidJenisDokumen mengecek jenis dokumen yang dihasilkan sistem
+func IsValidJenisDokumen(jenis string) bool {
+	return jenis == JenisBAP || jenis == JenisPengesahan
+}
+
+type BeritaAcaraModel struct {
+	db *sql.DB
+}
+
+func NewBeritaAcaraModel(db *sql.DB) *BeritaAcaraModel {
+	return &BeritaAcaraModel{
+		db: db,
+	}
+}
+
+type pengujiKolom struct {
+	Kolom string
+	Peran string
+}
+
+// Susunan penguji per tahap: tabel penugasan, kolom FK ke dokumen final, dan kolom peran
+var pengujiByTahap = map[string]struct {
+	Table   string
+	FinalFK string
+	Kolom   []pengujiKolom
+}{
+	"proposal": {"penguji_proposal", "final_proposal_id", []pengujiKolom{
+		{"ketua_penguji_id", "Ketua Penguji"},
+		{"penguji_1_id", "Penguji 1"},
+		{"penguji_2_id", "Penguji 2"},
+	}},
+	"laporan70": {"penguji_laporan70", "final_laporan70_id", []pengujiKolom{
+		{"penguji_1_id", "Penguji 1"},
+		{"penguji_2_id", "Penguji 2"},
+	}},
+	"laporan100": {"penguji_laporan100", "final_laporan100_id", []pengujiKolom{
+		{"ketua_penguji_id", "Ketua Penguji"},
+		{"penguji_1_id", "Penguji 1"},
+		{"penguji_2_id", "Penguji 2"},
+	}},
+}
+
+var rekomendasiValid = map[string]bool{
+	"lulus":           true,
+	"lulus_perbaikan": true,
+	"tidak_lulus":     true,
+}
+
+// IsValidRekomendasi mengecek nilai rekomendasi penguji
+func IsValidRekomendasi(rekomendasi string) bool {
+	return rekomendasiValid[rekomendasi]
+}
+
+// GetPengujiIDs mengembalikan dosen_id penguji yang ditugaskan pada seminar (peran -> dosen_id)
+func (m *BeritaAcaraModel) GetPengujiIDs(tahap string, finalID int) ([]entities.PengujiBAP, error) {
+	cfg, ok := pengujiByTahap[tahap]
+	if !ok {
+		return nil, fmt.Errorf("tahap tidak valid: %s", tahap)
+	}
+
+	cols := make([]string, len(cfg.Kolom))
+	ids := make([]sql.NullInt64, len(cfg.Kolom))
+	dest := make([]interface{}, len(cfg.Kolom))
+	for i, k := range cfg.Kolom {
+		cols[i] = k.Kolom
+		dest[i] = &ids[i]
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ? LIMIT 1", strings.Join(cols, ", "), cfg.Table, cfg.FinalFK)
+	err := m.db.QueryRow(query, finalID).Scan(dest...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var penguji []entities.PengujiBAP
+	for i, k := range cfg.Kolom {
+		if ids[i].Valid && ids[i].Int64 > 0 {
+			penguji = append(penguji, entities.PengujiBAP{Peran: k.Peran, DosenID: int(ids[i].Int64)})
+		}
+	}
+	return penguji, nil
+}
+
+// TerlibatSeminar mengecek apakah akun adalah taruna penyaji, pembimbing aktif, atau penguji seminar.
+// dosenID 0 berarti akun tersebut bukan dosen.
+func (m *BeritaAcaraModel) TerlibatSeminar(tahap string, finalID, userID, dosenID int) (bool, error) {
+	table, ok := finalTableByTahap[tahap]
+	if !ok {
+		return false, fmt.Errorf("tahap tidak valid: %s", tahap)
+	}
+
+	var tarunaID int
+	if err := m.db.QueryRow("SELECT user_id FROM "+table+" WHERE id = ?", finalID).Scan(&tarunaID); err != nil {
+		return false, err
+	}
+	if tarunaID == userID {
+		return true, nil
+	}
+	if dosenID == 0 {
+		return false, nil
+	}
+
+	var pembimbing bool
+	if err := m.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM v_pembimbing_taruna
+			WHERE user_id = ? AND dosen_id = ? AND status = 'aktif')`, tarunaID, dosenID).Scan(&pembimbing); err != nil {
+		return false, err
+	}
+	if pembimbing {
+		return true, nil
+	}
+
+	penguji, err := m.GetPengujiIDs(tahap, finalID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range penguji {
+		if p.DosenID == dosenID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UpsertNilai menyimpan nilai dan rekomendasi dari satu dosen penguji
+func (m *BeritaAcaraModel) UpsertNilai(n *entities.NilaiSeminar) error {
+	_, err := m.db.Exec(`
+		INSERT INTO nilai_seminar (tahap, final_id, dosen_id, nilai, rekomendasi, catatan)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			nilai = VALUES(nilai),
+			rekomendasi = VALUES(rekomendasi),
+			catatan = VALUES(catatan)`,
+		n.Tahap, n.FinalID, n.DosenID, n.Nilai, n.Rekomendasi, n.Catatan)
+	return err
+}
+
+// GetNilai mengembalikan seluruh nilai yang sudah masuk untuk satu seminar
+func (m *BeritaAcaraModel) GetNilai(tahap string, finalID int) ([]entities.NilaiSeminar, error) {
+	rows, err := m.db.Query(`
+		SELECT id, tahap, final_id, dosen_id, nilai, rekomendasi, COALESCE(catatan, ''), created_at, updated_at
+		FROM nilai_seminar
+		WHERE tahap = ? AND final_id = ?`, tahap, finalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []entities.NilaiSeminar
+	for rows.Next() {
+		var n entities.NilaiSeminar
+		if err := rows.Scan(&n.ID, &n.Tahap, &n.FinalID, &n.DosenID, &n.Nilai, &n.Rekomendasi,
+			&n.Catatan, &n.CreatedAt, &n.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+	}
+	return list, rows.Err()
+}
+
+// GetBeritaAcaraData mengumpulkan identitas taruna, jadwal, penguji, dan nilai untuk BAP
+func (m *BeritaAcaraModel) GetBeritaAcaraData(tahap string, finalID int) (*entities.BeritaAcaraData, error) {
+	table, ok := finalTableByTahap[tahap]
+	if !ok {
+		return nil, fmt.Errorf("tahap tidak valid: %s", tahap)
+	}
+
+	data := &entities.BeritaAcaraData{Tahap: tahap, FinalID: finalID}
+
+	err := m.db.QueryRow(`
+		SELECT f.user_id, COALESCE(f.nama_lengkap, ''), COALESCE(f.jurusan, ''), COALESCE(f.kelas, ''),
+			COALESCE(f.topik_penelitian, ''), COALESCE(t.npm, '')
+		FROM `+table+` f
+		LEFT JOIN taruna t ON t.user_id = f.user_id
+		WHERE f.id = ?`, finalID).Scan(
+		&data.UserID, &data.NamaTaruna, &data.Jurusan, &data.Kelas, &data.TopikPenelitian, &data.NPM)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.isiPembimbing(data); err != nil {
+		return nil, err
+	}
+
+	jadwal, err := NewJadwalSeminarModel(m.db).GetByTahapFinal(tahap, finalID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if jadwal != nil && jadwal.Status != "dibatalkan" {
+		data.Mulai = &jadwal.Mulai
+		data.Selesai = &jadwal.Selesai
+		data.Ruangan = jadwal.Ruangan
+	}
+
+	penguji, err := m.GetPengujiIDs(tahap, finalID)
+	if err != nil {
+		return nil, err
+	}
+
+	nilaiList, err := m.GetNilai(tahap, finalID)
+	if err != nil {
+		return nil, err
+	}
+	nilaiByDosen := make(map[int]entities.NilaiSeminar, len(nilaiList))
+	for _, n := range nilaiList {
+		nilaiByDosen[n.DosenID] = n
+	}
+
+	for i := range penguji {
+		if err := m.db.QueryRow("SELECT nama_lengkap FROM dosen WHERE id = ?", penguji[i].DosenID).
+			Scan(&penguji[i].Nama); err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		if n, ok := nilaiByDosen[penguji[i].DosenID]; ok {
+			nilai := n.Nilai
+			penguji[i].Nilai = &nilai
+			penguji[i].Rekomendasi = n.Rekomendasi
+		}
+	}
+	data.Penguji = penguji
+
+	hitungKeputusan(data)
+	return data, nil
+}
+
+// isiPembimbing mengisi pembimbing utama dan pendamping yang masih aktif; pembimbing yang sudah
+// diganti tidak dicetak dan tidak berhak menandatangani
+func (m *BeritaAcaraModel) isiPembimbing(data *entities.BeritaAcaraData) error {
+	rows, err := m.db.Query(`
+		SELECT pt.peran, d.id, COALESCE(d.nama_lengkap, '')
+		FROM v_pembimbing_taruna pt
+		JOIN dosen d ON d.id = pt.dosen_id
+		WHERE pt.user_id = ? AND pt.status = 'aktif'
+		ORDER BY pt.tanggal_ditetapkan DESC`, data.UserID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var peran, nama string
+		var dosenID int
+		if err := rows.Scan(&peran, &dosenID, &nama); err != nil {
+			return err
+		}
+		switch {
+		case peran == "utama" && data.PembimbingID == 0:
+			data.PembimbingID, data.Pembimbing = dosenID, nama
+		case peran == "pendamping" && data.PendampingID == 0:
+			data.PendampingID, data.Pendamping = dosenID, nama
+		}
+	}
+	return rows.Err()
+}
+
+// hitungKeputusan mengisi rata-rata, nilai huruf, dan keputusan bila semua penguji sudah menilai.
+// Satu rekomendasi "tidak_lulus" atau rata-rata di bawah 60 berarti tidak lulus.
+func hitungKeputusan(data *entities.BeritaAcaraData) {
+	if len(data.Penguji) == 0 {
+		data.Keputusan = "Menunggu penugasan penguji"
+		return
+	}
+
+	var (
+		total          float64
+		perluPerbaikan bool
+		tidakLulus     bool
+	)
+	for _, p := range data.Penguji {
+		if p.Nilai == nil {
+			data.Keputusan = "Menunggu penilaian penguji"
+			return
+		}
+		total += *p.Nilai
+		switch p.Rekomendasi {
+		case "tidak_lulus":
+			tidakLulus = true
+		case "lulus_perbaikan":
+			perluPerbaikan = true
+		}
+	}
+
+	rata := total / float64(len(data.Penguji))
+	rata = float64(int(rata*100+0.5)) / 100
+	data.RataRata = &rata
+	data.NilaiHuruf = NilaiHuruf(rata)
+
+	switch {
+	case tidakLulus || rata < 60:
+		data.Keputusan = "Tidak Lulus"
+	case perluPerbaikan:
+		data.Keputusan = "Lulus dengan Perbaikan"
+	default:
+		data.Keputusan = "Lulus"
+	}
+}
+
+// NilaiHuruf mengonversi nilai angka (0-100) menjadi nilai huruf
+func NilaiHuruf(nilai float64) string {
+	switch {
+	case nilai >= 85:
+		return "A"
+	case nilai >= 80:
+		return "A-"
+	case nilai >= 75:
+		return "B+"
+	case nilai >= 70:
+		return "B"
+	case nilai >= 65:
+		return "B-"
+	case nilai >= 60:
+		return "C+"
+	case nilai >= 55:
+		return "C"
+	case nilai >= 45:
+		return "D"
+	default:
+		return "E"
+	}
+}
+
+// HashData menghasilkan sha256 dari data BAP untuk mendeteksi perubahan
+func HashData(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+const dokumenColumns = `id, jenis, tahap, final_id, versi, file_path, file_hash, data_hash,
+	COALESCE(kode_verifikasi, ''), COALESCE(signed_file_path, ''), COALESCE(signed_file_hash, ''), created_at`
+
+func scanDokumen(scanner interface{ Scan(...interface{}) error }, d *entities.DokumenSeminar) error {
+	return scanner.Scan(&d.ID, &d.Jenis, &d.Tahap, &d.FinalID, &d.Versi, &d.FilePath, &d.FileHash, &d.DataHash,
+		&d.KodeVerifikasi, &d.SignedFilePath, &d.SignedFileHash, &d.CreatedAt)
+}
+
+// GetLatestDokumen mengembalikan versi terbaru dokumen; sql.ErrNoRows bila belum pernah dibuat
+func (m *BeritaAcaraModel) GetLatestDokumen(jenis, tahap string, finalID int) (*entities.DokumenSeminar, error) {
+	var d entities.DokumenSeminar
+	row := m.db.QueryRow(`SELECT `+dokumenColumns+` FROM dokumen_seminar
+		WHERE jenis = ? AND tahap = ? AND final_id = ?
+		ORDER BY versi DESC LIMIT 1`, jenis, tahap, finalID)
+	if err := scanDokumen(row, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (m *BeritaAcaraModel) GetDokumenByID(id int) (*entities.DokumenSeminar, error) {
+	var d entities.DokumenSeminar
+	row := m.db.QueryRow(`SELECT `+dokumenColumns+` FROM dokumen_seminar WHERE id = ?`, id)
+	if err := scanDokumen(row, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListDokumen mengembalikan semua versi dokumen, terbaru di awal
+func (m *BeritaAcaraModel) ListDokumen(jenis, tahap string, finalID int) ([]entities.DokumenSeminar, error) {
+	rows, err := m.db.Query(`SELECT `+dokumenColumns+` FROM dokumen_seminar
+		WHERE jenis = ? AND tahap = ? AND final_id = ?
+		ORDER BY versi DESC`, jenis, tahap, finalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []entities.DokumenSeminar
+	for rows.Next() {
+		var d entities.DokumenSeminar
+		if err := scanDokumen(rows, &d); err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+// CreateDokumen menyimpan versi baru dengan nomor versi berikutnya
+func (m *BeritaAcaraModel) CreateDokumen(d *entities.DokumenSeminar) error {
+	result, err := m.db.Exec(`
+		INSERT INTO dokumen_seminar (jenis, tahap, final_id, versi, file_path, file_hash, data_hash, kode_verifikasi)
+		SELECT ?, ?, ?, COALESCE(MAX(versi), 0) + 1, ?, ?, ?, ?
+		FROM dokumen_seminar
+		WHERE jenis = ? AND tahap = ? AND final_id = ?`,
+		d.Jenis, d.Tahap, d.FinalID, d.FilePath, d.FileHash, d.DataHash, d.KodeVerifikasi,
+		d.Jenis, d.Tahap, d.FinalID)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	saved, err := m.GetDokumenByID(int(id))
+	if err != nil {
+		return err
+	}
+	*d = *saved
+	return nil
+}
+
+// GetDokumenByKode mencari dokumen berdasarkan kode verifikasi publik
+func (m *BeritaAcaraModel) GetDokumenByKode(kode string) (*entities.DokumenSeminar, error) {
+	var d entities.DokumenSeminar
+	row := m.db.QueryRow(`SELECT `+dokumenColumns+` FROM dokumen_seminar WHERE kode_verifikasi = ?`, kode)
+	if err := scanDokumen(row, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// GetLatestBAPPathByUser mengembalikan path BAP terbaru milik taruna pada tahap tertentu.
+// Salinan bertanda tangan diutamakan bila ada.
+func (m *BeritaAcaraModel) GetLatestBAPPathByUser(tahap string, userID int) (string, error) {
+	table, ok := finalTableByTahap[tahap]
+	if !ok {
+		return "", fmt.Errorf("tahap tidak valid: %s", tahap)
+	}
+	var filePath string
+	err := m.db.QueryRow(`
+		SELECT COALESCE(ds.signed_file_path, ds.file_path) FROM dokumen_seminar ds
+		JOIN `+table+` f ON f.id = ds.final_id
+		WHERE ds.jenis = ? AND ds.tahap = ? AND f.user_id = ?
+		ORDER BY f.id DESC, ds.versi DESC
+		LIMIT 1`, JenisBAP, tahap, userID).Scan(&filePath)
+	return filePath, err
+}
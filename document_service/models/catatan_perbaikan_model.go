@@ -0,0 +1,211 @@
+package models
+
+import (
+	"database/sql"
+	"document_service/entities"
+	"document_service/utils/icalendar"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type CatatanPerbaikanModel struct {
+	db *sql.DB
+}
+
+func NewCatatanPerbaikanModel(db *sql.DB) *CatatanPerbaikanModel {
+	return &CatatanPerbaikanModel{
+		db: db,
+	}
+}
+
+// Status butir catatan perbaikan
+const (
+	CatatanOpen      = "open"
+	CatatanAddressed = "addressed"
+	CatatanAccepted  = "accepted"
+	CatatanReopened  = "reopened"
+)
+
+// ErrStatusCatatan dikembalikan bila perubahan status tidak diizinkan dari status butir saat ini
+var ErrStatusCatatan = errors.New("status butir catatan tidak memungkinkan aksi ini")
+
+// revisiTableByTahap memetakan tahap seminar ke tabel revisi yang disetujui admin
+var revisiTableByTahap = map[string]string{
+	"proposal":   "revisi_proposal",
+	"laporan70":  "revisi_laporan70",
+	"laporan100": "revisi_laporan100",
+}
+
+const catatanSelectColumns = `
+	c.id, c.tahap, c.final_id, c.user_id, c.dosen_id, c.bagian, c.halaman, c.perubahan, c.status,
+	COALESCE(c.tanggapan, ''), COALESCE(c.halaman_revisi, ''), COALESCE(c.catatan_review, ''),
+	c.addressed_at, c.reviewed_at, c.created_at, c.updated_at, COALESCE(d.nama_lengkap, '')
+	FROM catatan_perbaikan_item c
+	LEFT JOIN dosen d ON d.id = c.dosen_id`
+
+func scanCatatan(scanner interface{ Scan(...interface{}) error }, c *entities.CatatanPerbaikanItem) error {
+	var addressedAt, reviewedAt sql.NullTime
+	if err := scanner.Scan(&c.ID, &c.Tahap, &c.FinalID, &c.UserID, &c.DosenID, &c.Bagian, &c.Halaman,
+		&c.Perubahan, &c.Status, &c.Tanggapan, &c.HalamanRevisi, &c.CatatanReview,
+		&addressedAt, &reviewedAt, &c.CreatedAt, &c.UpdatedAt, &c.NamaDosen); err != nil {
+		return err
+	}
+	if addressedAt.Valid {
+		t := toWIB(addressedAt.Time)
+		c.AddressedAt = &t
+	}
+	if reviewedAt.Valid {
+		t := toWIB(reviewedAt.Time)
+		c.ReviewedAt = &t
+	}
+	return nil
+}
+
+func nowWIB() string {
+	return time.Now().In(icalendar.WIB).Format(jadwalDateTimeLayout)
+}
+
+// Create menambahkan butir catatan perbaikan baru dengan status open
+func (m *CatatanPerbaikanModel) Create(c *entities.CatatanPerbaikanItem) error {
+	result, err := m.db.Exec(`
+		INSERT INTO catatan_perbaikan_item (tahap, final_id, user_id, dosen_id, bagian, halaman, perubahan, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 'open')`,
+		c.Tahap, c.FinalID, c.UserID, c.DosenID, c.Bagian, c.Halaman, c.Perubahan)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	c.ID = int(id)
+	c.Status = CatatanOpen
+	return nil
+}
+
+// GetByID mengambil satu butir catatan perbaikan
+func (m *CatatanPerbaikanModel) GetByID(id int) (*entities.CatatanPerbaikanItem, error) {
+	var c entities.CatatanPerbaikanItem
+	row := m.db.QueryRow("SELECT "+catatanSelectColumns+" WHERE c.id = ?", id)
+	if err := scanCatatan(row, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetBySeminar mengembalikan butir catatan satu seminar; dosenID 0 berarti semua penguji
+func (m *CatatanPerbaikanModel) GetBySeminar(tahap string, finalID, dosenID int) ([]entities.CatatanPerbaikanItem, error) {
+	query := "SELECT " + catatanSelectColumns + " WHERE c.tahap = ? AND c.final_id = ?"
+	args := []interface{}{tahap, finalID}
+	if dosenID != 0 {
+		query += " AND c.dosen_id = ?"
+		args = append(args, dosenID)
+	}
+	query += " ORDER BY c.dosen_id, c.id"
+	return m.query(query, args...)
+}
+
+// GetByUser mengembalikan butir catatan milik taruna pada satu tahap
+func (m *CatatanPerbaikanModel) GetByUser(userID int, tahap string) ([]entities.CatatanPerbaikanItem, error) {
+	return m.query("SELECT "+catatanSelectColumns+" WHERE c.user_id = ? AND c.tahap = ? ORDER BY c.dosen_id, c.id",
+		userID, tahap)
+}
+
+func (m *CatatanPerbaikanModel) query(query string, args ...interface{}) ([]entities.CatatanPerbaikanItem, error) {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.CatatanPerbaikanItem{}
+	for rows.Next() {
+		var c entities.CatatanPerbaikanItem
+		if err := scanCatatan(rows, &c); err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+// Tanggapi menandai butir sudah diperbaiki oleh taruna. Hanya butir open/reopened yang bisa ditanggapi.
+func (m *CatatanPerbaikanModel) Tanggapi(id, userID int, tanggapan, halamanRevisi string) error {
+	result, err := m.db.Exec(`
+		UPDATE catatan_perbaikan_item
+		SET status = 'addressed', tanggapan = ?, halaman_revisi = ?, addressed_at = ?
+		WHERE id = ? AND user_id = ? AND status IN ('open', 'reopened')`,
+		tanggapan, halamanRevisi, nowWIB(), id, userID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// Review menerima atau membuka kembali butir yang sudah ditanggapi taruna
+func (m *CatatanPerbaikanModel) Review(id, dosenID int, status, catatan string) error {
+	if status != CatatanAccepted && status != CatatanReopened {
+		return fmt.Errorf("status review tidak valid: %s", status)
+	}
+	result, err := m.db.Exec(`
+		UPDATE catatan_perbaikan_item
+		SET status = ?, catatan_review = ?, reviewed_at = ?
+		WHERE id = ? AND dosen_id = ? AND status = 'addressed'`,
+		status, catatan, nowWIB(), id, dosenID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// Delete menghapus butir yang belum pernah ditanggapi taruna
+func (m *CatatanPerbaikanModel) Delete(id, dosenID int) error {
+	result, err := m.db.Exec(`
+		DELETE FROM catatan_perbaikan_item
+		WHERE id = ? AND dosen_id = ? AND status = 'open'`, id, dosenID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrStatusCatatan
+	}
+	return nil
+}
+
+// CountBelumDiterimaByRevisi menghitung butir catatan yang belum accepted milik pemilik baris revisi
+func (m *CatatanPerbaikanModel) CountBelumDiterimaByRevisi(tahap string, revisiID int) (int, error) {
+	table, ok := revisiTableByTahap[tahap]
+	if !ok {
+		return 0, fmt.Errorf("tahap tidak valid: %s", tahap)
+	}
+	var count int
+	err := m.db.QueryRow(fmt.Sprintf(`
+		SELECT COUNT(c.id)
+		FROM %s r
+		JOIN catatan_perbaikan_item c ON c.user_id = r.user_id AND c.tahap = ?
+		WHERE r.id = ? AND c.status <> 'accepted'`, table), tahap, revisiID).Scan(&count)
+	return count, err
+}
+
+// RingkasanStatus menghitung jumlah butir per status
+func RingkasanStatus(items []entities.CatatanPerbaikanItem) map[string]int {
+	ringkasan := map[string]int{
+		CatatanOpen:      0,
+		CatatanAddressed: 0,
+		CatatanAccepted:  0,
+		CatatanReopened:  0,
+	}
+	for _, c := range items {
+		ringkasan[c.Status]++
+	}
+	return ringkasan
+}
@@ -1,15 +1,14 @@
 package models
 
 import (
-	"database/sql"
 	"document_service/entities"
 )
 
 type FinalICPModel struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewFinalICPModel(db *sql.DB) *FinalICPModel {
+func NewFinalICPModel(db DBTX) *FinalICPModel {
 	return &FinalICPModel{
 		db: db,
 	}
@@ -20,8 +19,9 @@ func (m *FinalICPModel) Create(finalICP *entities.FinalICP) error {
 		INSERT INTO final_icp (
 			user_id, nama_lengkap, jurusan, 
 			kelas, topik_penelitian, file_path, file_pendukung_path, keterangan, 
-			status
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			status,
+			periode_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ` + periodeTarunaSQL + `)`
 
 	result, err := m.db.Exec(query,
 		finalICP.UserID,
@@ -32,7 +32,8 @@ func (m *FinalICPModel) Create(finalICP *entities.FinalICP) error {
 		finalICP.FilePath,
 		finalICP.FilePendukungPath, // << kolom baru
 		finalICP.Keterangan,
-		"pending", // default status
+		"pending",       // default status
+		finalICP.UserID, // periode_id
 	)
 	if err != nil {
 		return err
@@ -1,15 +1,14 @@
 package models
 
 import (
-	"database/sql"
 	"document_service/entities"
 )
 
 type FinalLaporan100Model struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewFinalLaporan100Model(db *sql.DB) *FinalLaporan100Model {
+func NewFinalLaporan100Model(db DBTX) *FinalLaporan100Model {
 	return &FinalLaporan100Model{
 		db: db,
 	}
@@ -21,8 +20,9 @@ func (m *FinalLaporan100Model) Create(finalLaporan100 *entities.FinalLaporan100)
 		INSERT INTO final_laporan100 (
 			user_id, nama_lengkap, jurusan,
 			kelas, topik_penelitian, file_path,
-			form_bimbingan_path, file_pendukung_path, keterangan, status
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			form_bimbingan_path, file_pendukung_path, keterangan, status,
+			periode_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ` + periodeTarunaSQL + `)`
 
 	result, err := m.db.Exec(query,
 		finalLaporan100.UserID,
@@ -34,7 +34,8 @@ func (m *FinalLaporan100Model) Create(finalLaporan100 *entities.FinalLaporan100)
 		finalLaporan100.FormBimbinganPath,
 		finalLaporan100.FilePendukungPath, // <- JSON array string
 		finalLaporan100.Keterangan,
-		"pending", // default status
+		"pending",              // default status
+		finalLaporan100.UserID, // periode_id
 	)
 	if err != nil {
 		return err
@@ -1,15 +1,14 @@
 package models
 
 import (
-	"database/sql"
 	"document_service/entities"
 )
 
 type FinalLaporan70Model struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewFinalLaporan70Model(db *sql.DB) *FinalLaporan70Model {
+func NewFinalLaporan70Model(db DBTX) *FinalLaporan70Model {
 	return &FinalLaporan70Model{
 		db: db,
 	}
@@ -21,8 +20,9 @@ func (m *FinalLaporan70Model) Create(finalLaporan70 *entities.FinalLaporan70) er
 		INSERT INTO final_laporan70 (
 			user_id, nama_lengkap, jurusan,
 			kelas, topik_penelitian, file_path,
-			form_bimbingan_path, file_pendukung_path, keterangan, status
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			form_bimbingan_path, file_pendukung_path, keterangan, status,
+			periode_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ` + periodeTarunaSQL + `)`
 
 	result, err := m.db.Exec(query,
 		finalLaporan70.UserID,
@@ -34,7 +34,8 @@ func (m *FinalLaporan70Model) Create(finalLaporan70 *entities.FinalLaporan70) er
 		finalLaporan70.FormBimbinganPath,
 		finalLaporan70.FilePendukungPath, // <- JSON array string
 		finalLaporan70.Keterangan,
-		"pending", // default status
+		"pending",             // default status
+		finalLaporan70.UserID, // periode_id
 	)
 	if err != nil {
 		return err
@@ -1,15 +1,14 @@
 package models
 
 import (
-	"database/sql"
 	"document_service/entities"
 )
 
 type FinalProposalModel struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewFinalProposalModel(db *sql.DB) *FinalProposalModel {
+func NewFinalProposalModel(db DBTX) *FinalProposalModel {
 	return &FinalProposalModel{
 		db: db,
 	}
@@ -21,8 +20,9 @@ func (m *FinalProposalModel) Create(finalProposal *entities.FinalProposal) error
 		INSERT INTO final_proposal (
 			user_id, nama_lengkap, jurusan,
 			kelas, topik_penelitian, file_path,
-			form_bimbingan_path, file_pendukung_path, keterangan, status
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			form_bimbingan_path, file_pendukung_path, keterangan, status,
+			periode_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ` + periodeTarunaSQL + `)`
 
 	result, err := m.db.Exec(query,
 		finalProposal.UserID,
@@ -34,7 +34,8 @@ func (m *FinalProposalModel) Create(finalProposal *entities.FinalProposal) error
 		finalProposal.FormBimbinganPath,
 		finalProposal.FilePendukungPath, // <- JSON array string
 		finalProposal.Keterangan,
-		"pending", // default status
+		"pending",            // default status
+		finalProposal.UserID, // periode_id
 	)
 	if err != nil {
 		return err
@@ -7,10 +7,10 @@ import (
 )
 
 type ICPModel struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewICPModel(db *sql.DB) *ICPModel {
+func NewICPModel(db DBTX) *ICPModel {
 	return &ICPModel{db: db}
 }
 
@@ -18,25 +18,35 @@ func (m *ICPModel) Create(icp *entities.ICP) error {
 	query := `
 		INSERT INTO icp (
 			user_id, dosen_id, topik_penelitian, keterangan, 
-			file_path, status, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			file_path, status, created_at, updated_at,
+			periode_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ` + periodeTarunaSQL + `)
 	`
 
 	now := time.Now().Format("2006-01-02 15:04:05")
 
-	_, err := m.db.Exec(
+	result, err := m.db.Exec(
 		query,
 		icp.UserID,
 		icp.DosenID,
 		icp.TopikPenelitian,
 		icp.Keterangan,
 		icp.FilePath,
-		"pending", // status awal
-		now,       // created_at
-		now,       // updated_at
+		"pending",  // status awal
+		now,        // created_at
+		now,        // updated_at
+		icp.UserID, // periode_id
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	icp.ID = int(id)
+	return nil
 }
 
 func (m *ICPModel) GetByUserID(userID string) ([]entities.ICP, error) {
@@ -117,7 +127,10 @@ func (m *ICPModel) Update(icp *entities.ICP) error {
 	return err
 }
 
-func (m *ICPModel) GetByDosenID(dosenID string) ([]entities.ICP, error) {
+// GetByDosenID mengambil dokumen bimbingan dosen (pembimbing atau pendamping) pada periode tertentu;
+// periodeID 0 berarti semua periode yang belum diarsipkan
+func (m *ICPModel) GetByDosenID(dosenID string, periodeID int) ([]entities.ICP, error) {
+	kondisiPeriode, argsPeriode := KondisiPeriode("i.periode_id", periodeID)
 	query := `
         SELECT 
             i.id, i.user_id, i.dosen_id, i.topik_penelitian,
@@ -125,10 +138,11 @@ func (m *ICPModel) GetByDosenID(dosenID string) ([]entities.ICP, error) {
             i.updated_at, t.nama_lengkap as nama_taruna, t.kelas
         FROM icp i
         LEFT JOIN taruna t ON i.user_id = t.user_id
-        WHERE i.dosen_id = ?
+        WHERE (i.dosen_id = ? OR ` + KondisiPembimbingPendamping + `)
+            AND ` + kondisiPeriode + `
     `
 
-	rows, err := m.db.Query(query, dosenID)
+	rows, err := m.db.Query(query, append([]interface{}{dosenID, dosenID}, argsPeriode...)...)
 	if err != nil {
 		return nil, err
 	}
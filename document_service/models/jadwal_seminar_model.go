@@ -0,0 +1,282 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"document_service/entities"
+	"document_service/utils/icalendar"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const jadwalDateTimeLayout = "2006-01-02 15:04:05"
+
+type JadwalSeminarModel struct {
+	db *sql.DB
+}
+
+func NewJadwalSeminarModel(db *sql.DB) *JadwalSeminarModel {
+	return &JadwalSeminarModel{
+		db: db,
+	}
+}
+
+// Tabel final per tahap seminar, dipakai untuk validasi dan join topik
+var finalTableByTahap = map[string]string{
+	"proposal":   "final_proposal",
+	"laporan70":  "final_laporan70",
+	"laporan100": "final_laporan100",
+}
+
+// IsValidTahap mengecek apakah tahap seminar dikenali
+func IsValidTahap(tahap string) bool {
+	_, ok := finalTableByTahap[tahap]
+	return ok
+}
+
+// Kolom jadwal yang dipilih bersama nama taruna dan topik penelitian
+const jadwalSelectColumns = `
+	js.id, js.tahap, js.final_id, js.user_id, js.mulai, js.selesai,
+	js.ruangan, COALESCE(js.keterangan, ''), js.status, js.sequence,
+	js.created_at, js.updated_at,
+	COALESCE(u.nama_lengkap, ''),
+	COALESCE(fp.topik_penelitian, fl70.topik_penelitian, fl100.topik_penelitian, '')`
+
+const jadwalJoins = `
+	FROM jadwal_seminar js
+	LEFT JOIN users u ON u.id = js.user_id
+	LEFT JOIN final_proposal fp ON js.tahap = 'proposal' AND fp.id = js.final_id
+	LEFT JOIN final_laporan70 fl70 ON js.tahap = 'laporan70' AND fl70.id = js.final_id
+	LEFT JOIN final_laporan100 fl100 ON js.tahap = 'laporan100' AND fl100.id = js.final_id`
+
+// toWIB menafsirkan ulang DATETIME dari database (disimpan sebagai jam dinding WIB)
+func toWIB(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, icalendar.WIB)
+}
+
+func scanJadwal(scanner interface{ Scan(...interface{}) error }, j *entities.JadwalSeminar, extra ...interface{}) error {
+	dest := []interface{}{
+		&j.ID, &j.Tahap, &j.FinalID, &j.UserID, &j.Mulai, &j.Selesai,
+		&j.Ruangan, &j.Keterangan, &j.Status, &j.Sequence,
+		&j.CreatedAt, &j.UpdatedAt,
+		&j.NamaTaruna, &j.TopikPenelitian,
+	}
+	if err := scanner.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	j.Mulai = toWIB(j.Mulai)
+	j.Selesai = toWIB(j.Selesai)
+	return nil
+}
+
+// GetFinalOwner mengembalikan user_id taruna pemilik dokumen final pada tahap tertentu
+func (m *JadwalSeminarModel) GetFinalOwner(tahap string, finalID int) (int, error) {
+	table, ok := finalTableByTahap[tahap]
+	if !ok {
+		return 0, fmt.Errorf("tahap tidak valid: %s", tahap)
+	}
+	var userID int
+	err := m.db.QueryRow("SELECT user_id FROM "+table+" WHERE id = ?", finalID).Scan(&userID)
+	return userID, err
+}
+
+// Upsert membuat jadwal baru atau memperbarui jadwal yang sudah ada untuk (tahap, final_id).
+// Setiap perubahan menaikkan sequence agar klien kalender mengganti event lama.
+func (m *JadwalSeminarModel) Upsert(j *entities.JadwalSeminar) error {
+	query := `
+		INSERT INTO jadwal_seminar (tahap, final_id, user_id, mulai, selesai, ruangan, keterangan, status, sequence)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 'terjadwal', 0)
+		ON DUPLICATE KEY UPDATE
+			user_id = VALUES(user_id),
+			mulai = VALUES(mulai),
+			selesai = VALUES(selesai),
+			ruangan = VALUES(ruangan),
+			keterangan = VALUES(keterangan),
+			status = 'terjadwal',
+			sequence = sequence + 1`
+
+	_, err := m.db.Exec(query,
+		j.Tahap,
+		j.FinalID,
+		j.UserID,
+		j.Mulai.In(icalendar.WIB).Format(jadwalDateTimeLayout),
+		j.Selesai.In(icalendar.WIB).Format(jadwalDateTimeLayout),
+		j.Ruangan,
+		j.Keterangan,
+	)
+	if err != nil {
+		return err
+	}
+
+	saved, err := m.GetByTahapFinal(j.Tahap, j.FinalID)
+	if err != nil {
+		return err
+	}
+	*j = *saved
+	return nil
+}
+
+// Cancel menandai jadwal dibatalkan dan menaikkan sequence
+func (m *JadwalSeminarModel) Cancel(id int) error {
+	result, err := m.db.Exec(`
+		UPDATE jadwal_seminar
+		SET status = 'dibatalkan', sequence = sequence + 1
+		WHERE id = ? AND status <> 'dibatalkan'`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (m *JadwalSeminarModel) GetByID(id int) (*entities.JadwalSeminar, error) {
+	var j entities.JadwalSeminar
+	row := m.db.QueryRow("SELECT "+jadwalSelectColumns+jadwalJoins+" WHERE js.id = ?", id)
+	if err := scanJadwal(row, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (m *JadwalSeminarModel) GetByTahapFinal(tahap string, finalID int) (*entities.JadwalSeminar, error) {
+	var j entities.JadwalSeminar
+	row := m.db.QueryRow("SELECT "+jadwalSelectColumns+jadwalJoins+" WHERE js.tahap = ? AND js.final_id = ?", tahap, finalID)
+	if err := scanJadwal(row, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// GetAll mengembalikan seluruh jadwal, opsional difilter berdasarkan tahap
+func (m *JadwalSeminarModel) GetAll(tahap string) ([]entities.JadwalSeminar, error) {
+	query := "SELECT " + jadwalSelectColumns + jadwalJoins
+	var args []interface{}
+	if tahap != "" {
+		query += " WHERE js.tahap = ?"
+		args = append(args, tahap)
+	}
+	query += " ORDER BY js.mulai ASC"
+
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []entities.JadwalSeminar
+	for rows.Next() {
+		var j entities.JadwalSeminar
+		if err := scanJadwal(rows, &j); err != nil {
+			return nil, err
+		}
+		list = append(list, j)
+	}
+	return list, rows.Err()
+}
+
+// GetForUser mengembalikan jadwal seminar yang melibatkan user, baik sebagai
+// penyaji (taruna), pembimbing, maupun penguji. Peran diisi pada field Peran.
+func (m *JadwalSeminarModel) GetForUser(userID int) ([]entities.JadwalSeminar, error) {
+	// Dosen direferensikan lewat dosen.id, bukan users.id
+	var dosenID int
+	err := m.db.QueryRow("SELECT id FROM dosen WHERE user_id = ?", userID).Scan(&dosenID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	query := "SELECT " + jadwalSelectColumns + `,
+		js.user_id = ? AS sebagai_penyaji,
+		EXISTS (
+			SELECT 1 FROM v_pembimbing_taruna dp
+			WHERE dp.user_id = js.user_id AND dp.dosen_id = ? AND dp.status = 'aktif'
+		) AS sebagai_pembimbing,
+		CASE js.tahap
+			WHEN 'proposal' THEN EXISTS (
+				SELECT 1 FROM penguji_proposal pp
+				WHERE pp.final_proposal_id = js.final_id
+				  AND ? IN (pp.ketua_penguji_id, pp.penguji_1_id, pp.penguji_2_id))
+			WHEN 'laporan70' THEN EXISTS (
+				SELECT 1 FROM penguji_laporan70 pl70
+				WHERE pl70.final_laporan70_id = js.final_id
+				  AND ? IN (pl70.penguji_1_id, pl70.penguji_2_id))
+			WHEN 'laporan100' THEN EXISTS (
+				SELECT 1 FROM penguji_laporan100 pl100
+				WHERE pl100.final_laporan100_id = js.final_id
+				  AND ? IN (pl100.ketua_penguji_id, pl100.penguji_1_id, pl100.penguji_2_id))
+			ELSE 0
+		END AS sebagai_penguji` + jadwalJoins + `
+		HAVING sebagai_penyaji OR sebagai_pembimbing OR sebagai_penguji
+		ORDER BY js.mulai ASC`
+
+	rows, err := m.db.Query(query, userID, dosenID, dosenID, dosenID, dosenID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []entities.JadwalSeminar
+	for rows.Next() {
+		var (
+			j                            entities.JadwalSeminar
+			penyaji, pembimbing, penguji bool
+		)
+		if err := scanJadwal(rows, &j, &penyaji, &pembimbing, &penguji); err != nil {
+			return nil, err
+		}
+		switch {
+		case penyaji:
+			j.Peran = "penyaji"
+		case penguji:
+			j.Peran = "penguji"
+		case pembimbing:
+			j.Peran = "pembimbing"
+		}
+		list = append(list, j)
+	}
+	return list, rows.Err()
+}
+
+// GetOrCreateToken mengembalikan token feed kalender milik user, membuatnya bila belum ada
+func (m *JadwalSeminarModel) GetOrCreateToken(userID int) (string, error) {
+	var token string
+	err := m.db.QueryRow("SELECT token FROM kalender_token WHERE user_id = ?", userID).Scan(&token)
+	if err == nil {
+		return token, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", err
+	}
+	return m.ResetToken(userID)
+}
+
+// ResetToken membuat token baru sehingga URL feed lama tidak berlaku lagi
+func (m *JadwalSeminarModel) ResetToken(userID int) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	_, err := m.db.Exec(`
+		INSERT INTO kalender_token (user_id, token) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE token = VALUES(token), created_at = CURRENT_TIMESTAMP`,
+		userID, token)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// GetUserIDByToken mencari pemilik token feed kalender
+func (m *JadwalSeminarModel) GetUserIDByToken(token string) (int, error) {
+	var userID int
+	err := m.db.QueryRow("SELECT user_id FROM kalender_token WHERE token = ?", token).Scan(&userID)
+	return userID, err
+}
@@ -0,0 +1,354 @@
+package models
+
+import (
+	"database/sql"
+	"document_service/entities"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Tahap unggah yang dapat dibatasi jendela pengumpulan, beserta labelnya untuk pesan ke taruna
+const (
+	UnggahICP              = "icp"
+	UnggahProposal         = "proposal"
+	UnggahLaporan70        = "laporan70"
+	UnggahLaporan100       = "laporan100"
+	UnggahRevisiICP        = "revisi_icp"
+	UnggahRevisiProposal   = "revisi_proposal"
+	UnggahRevisiLaporan70  = "revisi_laporan70"
+	UnggahRevisiLaporan100 = "revisi_laporan100"
+	UnggahFinalICP         = "final_icp"
+	UnggahFinalProposal    = "final_proposal"
+	UnggahFinalLaporan70   = "final_laporan70"
+	UnggahFinalLaporan100  = "final_laporan100"
+)
+
+var labelTahapUnggah = map[string]string{
+	UnggahICP:              "ICP",
+	UnggahProposal:         "proposal",
+	UnggahLaporan70:        "laporan 70%",
+	UnggahLaporan100:       "laporan 100%",
+	UnggahRevisiICP:        "revisi ICP",
+	UnggahRevisiProposal:   "revisi proposal",
+	UnggahRevisiLaporan70:  "revisi laporan 70%",
+	UnggahRevisiLaporan100: "revisi laporan 100%",
+	UnggahFinalICP:         "final ICP",
+	UnggahFinalProposal:    "final proposal",
+	UnggahFinalLaporan70:   "final laporan 70%",
+	UnggahFinalLaporan100:  "final laporan 100%",
+}
+
+// Status jendela pada saat diperiksa
+const (
+	JendelaBelumDibuka = "belum_dibuka"
+	JendelaDibuka      = "dibuka"
+	JendelaDitutup     = "ditutup"
+)
+
+// IsValidTahapUnggah mengecek apakah tahap unggah dikenali
+func IsValidTahapUnggah(tahap string) bool {
+	_, ok := labelTahapUnggah[tahap]
+	return ok
+}
+
+// LabelTahapUnggah mengembalikan nama tahap untuk ditampilkan
+func LabelTahapUnggah(tahap string) string {
+	return labelTahapUnggah[tahap]
+}
+
+// StatusUnggah adalah hasil pemeriksaan jendela bagi satu taruna. Bila Dijadwalkan false, tahap
+// tersebut tidak dibatasi jendela. Berikutnya terisi bila ada jendela yang belum dibuka, Terakhir
+// berisi jendela yang paling akhir ditutup.
+type StatusUnggah struct {
+	Dijadwalkan bool
+	Terbuka     bool
+	Berikutnya  *entities.JendelaPengumpulan
+	Terakhir    *entities.JendelaPengumpulan
+}
+
+type JendelaPengumpulanModel struct {
+	db *sql.DB
+}
+
+func NewJendelaPengumpulanModel(db *sql.DB) *JendelaPengumpulanModel {
+	return &JendelaPengumpulanModel{db: db}
+}
+
+// statusJendela menghitung status jendela pada waktu now, memakai waktu tutup perpanjangan bila ada
+func statusJendela(j *entities.JendelaPengumpulan, now time.Time) string {
+	ditutup := j.Ditutup
+	if j.DitutupUntukUser != nil {
+		ditutup = *j.DitutupUntukUser
+	}
+	switch {
+	case now.Before(j.Dibuka):
+		return JendelaBelumDibuka
+	case now.After(ditutup):
+		return JendelaDitutup
+	default:
+		return JendelaDibuka
+	}
+}
+
+// GetJendela mengambil jendela menurut tahap dan periode (keduanya opsional)
+func (m *JendelaPengumpulanModel) GetJendela(tahap string, periodeID int) ([]entities.JendelaPengumpulan, error) {
+	var kondisi []string
+	var args []interface{}
+	if tahap != "" {
+		kondisi = append(kondisi, "j.tahap = ?")
+		args = append(args, tahap)
+	}
+	if periodeID > 0 {
+		kondisi = append(kondisi, "(j.periode_id = ? OR j.periode_id IS NULL)")
+		args = append(args, periodeID)
+	}
+	where := ""
+	if len(kondisi) > 0 {
+		where = "WHERE " + strings.Join(kondisi, " AND ")
+	}
+
+	rows, err := m.db.Query(`
+		SELECT j.id, j.tahap, j.periode_id, j.dibuka, j.ditutup, j.keterangan,
+			(SELECT COUNT(*) FROM perpanjangan_pengumpulan p WHERE p.jendela_id = j.id)
+		FROM jendela_pengumpulan j
+		`+where+`
+		ORDER BY j.dibuka DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	list := []entities.JendelaPengumpulan{}
+	for rows.Next() {
+		var j entities.JendelaPengumpulan
+		var periode sql.NullInt64
+		if err := rows.Scan(&j.ID, &j.Tahap, &periode, &j.Dibuka, &j.Ditutup, &j.Keterangan, &j.JumlahPerpanjang); err != nil {
+			return nil, err
+		}
+		if periode.Valid {
+			id := int(periode.Int64)
+			j.PeriodeID = &id
+		}
+		j.Status = statusJendela(&j, now)
+		list = append(list, j)
+	}
+	return list, rows.Err()
+}
+
+// Simpan membuat jendela baru (ID 0) atau mengubah jendela yang ada
+func (m *JendelaPengumpulanModel) Simpan(j *entities.JendelaPengumpulan) error {
+	if j.ID == 0 {
+		res, err := m.db.Exec(`
+			INSERT INTO jendela_pengumpulan (tahap, periode_id, dibuka, ditutup, keterangan)
+			VALUES (?, ?, ?, ?, ?)`, j.Tahap, j.PeriodeID, j.Dibuka, j.Ditutup, j.Keterangan)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		j.ID = int(id)
+		return err
+	}
+
+	res, err := m.db.Exec(`
+		UPDATE jendela_pengumpulan SET tahap = ?, periode_id = ?, dibuka = ?, ditutup = ?, keterangan = ?
+		WHERE id = ?`, j.Tahap, j.PeriodeID, j.Dibuka, j.Ditutup, j.Keterangan, j.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		var ada bool
+		if err := m.db.QueryRow("SELECT EXISTS(SELECT 1 FROM jendela_pengumpulan WHERE id = ?)", j.ID).Scan(&ada); err != nil {
+			return err
+		}
+		if !ada {
+			return sql.ErrNoRows
+		}
+	}
+	return nil
+}
+
+// Hapus menghapus jendela beserta perpanjangannya
+func (m *JendelaPengumpulanModel) Hapus(id int) error {
+	res, err := m.db.Exec("DELETE FROM jendela_pengumpulan WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetJendelaByID mengambil satu jendela
+func (m *JendelaPengumpulanModel) GetJendelaByID(id int) (*entities.JendelaPengumpulan, error) {
+	var j entities.JendelaPengumpulan
+	var periode sql.NullInt64
+	err := m.db.QueryRow(`
+		SELECT id, tahap, periode_id, dibuka, ditutup, keterangan
+		FROM jendela_pengumpulan WHERE id = ?`, id).
+		Scan(&j.ID, &j.Tahap, &periode, &j.Dibuka, &j.Ditutup, &j.Keterangan)
+	if err != nil {
+		return nil, err
+	}
+	if periode.Valid {
+		pid := int(periode.Int64)
+		j.PeriodeID = &pid
+	}
+	j.Status = statusJendela(&j, time.Now())
+	return &j, nil
+}
+
+// GetPerpanjangan mengambil perpanjangan satu jendela
+func (m *JendelaPengumpulanModel) GetPerpanjangan(jendelaID int) ([]entities.PerpanjanganPengumpulan, error) {
+	rows, err := m.db.Query(`
+		SELECT p.id, p.jendela_id, p.user_id, COALESCE(u.nama_lengkap, ''), p.ditutup, p.alasan
+		FROM perpanjangan_pengumpulan p
+		LEFT JOIN users u ON u.id = p.user_id
+		WHERE p.jendela_id = ?
+		ORDER BY u.nama_lengkap`, jendelaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.PerpanjanganPengumpulan{}
+	for rows.Next() {
+		var p entities.PerpanjanganPengumpulan
+		if err := rows.Scan(&p.ID, &p.JendelaID, &p.UserID, &p.NamaTaruna, &p.Ditutup, &p.Alasan); err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// SimpanPerpanjangan memberi atau mengganti perpanjangan jendela untuk satu taruna
+func (m *JendelaPengumpulanModel) SimpanPerpanjangan(p *entities.PerpanjanganPengumpulan) error {
+	res, err := m.db.Exec(`
+		INSERT INTO perpanjangan_pengumpulan (jendela_id, user_id, ditutup, alasan)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id), ditutup = VALUES(ditutup), alasan = VALUES(alasan)`,
+		p.JendelaID, p.UserID, p.Ditutup, p.Alasan)
+	if err != nil {
+		return err
+	}
+	if id, err := res.LastInsertId(); err == nil && id > 0 {
+		p.ID = int(id)
+	}
+	return nil
+}
+
+// HapusPerpanjangan mencabut perpanjangan
+func (m *JendelaPengumpulanModel) HapusPerpanjangan(id int) error {
+	res, err := m.db.Exec("DELETE FROM perpanjangan_pengumpulan WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// jendelaTaruna mengambil jendela yang berlaku bagi taruna (jendela semua periode dan jendela periode
+// taruna), beserta perpanjangan miliknya. tahap kosong berarti semua tahap.
+func (m *JendelaPengumpulanModel) jendelaTaruna(tahap string, userID int) ([]entities.JendelaPengumpulan, error) {
+	query := `
+		SELECT j.id, j.tahap, j.periode_id, j.dibuka, j.ditutup, j.keterangan, p.ditutup
+		FROM jendela_pengumpulan j
+		LEFT JOIN perpanjangan_pengumpulan p ON p.jendela_id = j.id AND p.user_id = ?
+		WHERE (j.periode_id IS NULL OR j.periode_id = (SELECT periode_id FROM taruna WHERE user_id = ?))`
+	args := []interface{}{userID, userID}
+	if tahap != "" {
+		query += " AND j.tahap = ?"
+		args = append(args, tahap)
+	}
+	rows, err := m.db.Query(query+" ORDER BY j.dibuka", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var list []entities.JendelaPengumpulan
+	for rows.Next() {
+		var j entities.JendelaPengumpulan
+		var periode sql.NullInt64
+		var perpanjangan sql.NullTime
+		if err := rows.Scan(&j.ID, &j.Tahap, &periode, &j.Dibuka, &j.Ditutup, &j.Keterangan, &perpanjangan); err != nil {
+			return nil, err
+		}
+		if periode.Valid {
+			id := int(periode.Int64)
+			j.PeriodeID = &id
+		}
+		if perpanjangan.Valid && perpanjangan.Time.After(j.Ditutup) {
+			t := perpanjangan.Time
+			j.DitutupUntukUser = &t
+		}
+		j.Status = statusJendela(&j, now)
+		list = append(list, j)
+	}
+	return list, rows.Err()
+}
+
+// Periksa menentukan apakah taruna boleh mengunggah tahap tersebut saat ini
+func (m *JendelaPengumpulanModel) Periksa(tahap string, userID int) (*StatusUnggah, error) {
+	if !IsValidTahapUnggah(tahap) {
+		return nil, fmt.Errorf("tahap unggah tidak dikenali: %s", tahap)
+	}
+	list, err := m.jendelaTaruna(tahap, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StatusUnggah{Dijadwalkan: len(list) > 0}
+	for i := range list {
+		j := &list[i]
+		switch j.Status {
+		case JendelaDibuka:
+			s.Terbuka = true
+		case JendelaBelumDibuka:
+			if s.Berikutnya == nil {
+				s.Berikutnya = j
+			}
+		case JendelaDitutup:
+			if s.Terakhir == nil || tutupEfektif(j).After(tutupEfektif(s.Terakhir)) {
+				s.Terakhir = j
+			}
+		}
+	}
+	return s, nil
+}
+
+func tutupEfektif(j *entities.JendelaPengumpulan) time.Time {
+	if j.DitutupUntukUser != nil {
+		return *j.DitutupUntukUser
+	}
+	return j.Ditutup
+}
+
+// GetTerbuka mengambil jendela yang sedang dibuka atau akan dibuka untuk dasbor. Bila userID > 0,
+// hanya jendela yang berlaku bagi taruna tersebut (termasuk perpanjangannya).
+func (m *JendelaPengumpulanModel) GetTerbuka(userID int) ([]entities.JendelaPengumpulan, error) {
+	var list []entities.JendelaPengumpulan
+	var err error
+	if userID > 0 {
+		list, err = m.jendelaTaruna("", userID)
+	} else {
+		list, err = m.GetJendela("", 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hasil := []entities.JendelaPengumpulan{}
+	for _, j := range list {
+		if j.Status != JendelaDitutup {
+			hasil = append(hasil, j)
+		}
+	}
+	return hasil, nil
+}
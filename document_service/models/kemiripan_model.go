@@ -0,0 +1,282 @@
+package models
+
+import (
+	"database/sql"
+	"document_service/entities"
+	"document_service/utils/kemiripan"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AmbangKemiripanDefault adalah skor kosinus minimal agar ICP baru ditandai mirip dengan tugas akhir lain;
+// dapat diubah lewat environment KEMIRIPAN_AMBANG
+const AmbangKemiripanDefault = 0.5
+
+// Urutan tahap sumber teks; topik dari tahap paling akhir dipakai sebagai judul tugas akhir taruna
+var urutanSumberKemiripan = map[string]int{
+	"icp":               1,
+	"final_icp":         2,
+	"final_proposal":    3,
+	"final_laporan70":   4,
+	"final_laporan100":  5,
+	"revisi_laporan100": 6,
+}
+
+const querySumberKemiripan = `
+	SELECT user_id, 'icp' AS sumber, topik_penelitian AS topik, '' AS abstrak_id, '' AS abstrak_en,
+		'' AS kata_kunci, created_at FROM icp
+	UNION ALL
+	SELECT user_id, 'final_icp', topik_penelitian, '', '', '', created_at FROM final_icp
+	UNION ALL
+	SELECT user_id, 'final_proposal', topik_penelitian, '', '', '', created_at FROM final_proposal
+	UNION ALL
+	SELECT user_id, 'final_laporan70', topik_penelitian, '', '', '', created_at FROM final_laporan70
+	UNION ALL
+	SELECT user_id, 'final_laporan100', topik_penelitian, '', '', '', created_at FROM final_laporan100
+	UNION ALL
+	SELECT user_id, 'revisi_laporan100', topik_penelitian, COALESCE(abstrak_id, ''), COALESCE(abstrak_en, ''),
+		COALESCE(kata_kunci, ''), created_at FROM revisi_laporan100`
+
+func AmbangKemiripan() float64 {
+	if v, err := strconv.ParseFloat(os.Getenv("KEMIRIPAN_AMBANG"), 64); err == nil && v > 0 && v <= 1 {
+		return v
+	}
+	return AmbangKemiripanDefault
+}
+
+// dokumenTA menggabungkan seluruh teks tugas akhir satu taruna dari semua tahap
+type dokumenTA struct {
+	UserID int
+	Nama   string
+	Topik  string
+	Sumber string
+	Tahun  string
+	teks   []string
+	waktu  time.Time
+}
+
+// indeksKemiripan dipakai bersama oleh semua request. Pembangunan ulang dilakukan di luar kunci lalu
+// ditukar sekaligus, sehingga pencarian tetap memakai indeks lama selama korpus dimuat dari database.
+var indeksKemiripan struct {
+	sync.Mutex
+	korpus  *kemiripan.Korpus
+	dokumen []dokumenTA
+}
+
+type KemiripanModel struct {
+	db *sql.DB
+}
+
+func NewKemiripanModel(db *sql.DB) *KemiripanModel {
+	return &KemiripanModel{db: db}
+}
+
+// BangunIndeks memuat ulang seluruh teks tugas akhir dan mengganti indeks yang dipakai pencarian
+func (m *KemiripanModel) BangunIndeks() error {
+	dokumen, err := m.muatDokumen()
+	if err != nil {
+		return err
+	}
+	tokens := make([][]string, len(dokumen))
+	for i, d := range dokumen {
+		tokens[i] = kemiripan.Tokenize(strings.Join(d.teks, " "))
+	}
+	korpus := kemiripan.NewKorpus(tokens)
+
+	indeksKemiripan.Lock()
+	indeksKemiripan.korpus = korpus
+	indeksKemiripan.dokumen = dokumen
+	indeksKemiripan.Unlock()
+	return nil
+}
+
+// indeks mengembalikan indeks yang sedang berlaku; hanya dibangun di sini bila belum pernah ada
+func (m *KemiripanModel) indeks() (*kemiripan.Korpus, []dokumenTA, error) {
+	indeksKemiripan.Lock()
+	korpus, dokumen := indeksKemiripan.korpus, indeksKemiripan.dokumen
+	indeksKemiripan.Unlock()
+	if korpus != nil {
+		return korpus, dokumen, nil
+	}
+
+	if err := m.BangunIndeks(); err != nil {
+		return nil, nil, err
+	}
+	indeksKemiripan.Lock()
+	defer indeksKemiripan.Unlock()
+	return indeksKemiripan.korpus, indeksKemiripan.dokumen, nil
+}
+
+func (m *KemiripanModel) muatDokumen() ([]dokumenTA, error) {
+	rows, err := m.db.Query(`
+		SELECT s.user_id, COALESCE(t.nama_lengkap, ''), s.sumber, COALESCE(s.topik, ''),
+			s.abstrak_id, s.abstrak_en, s.kata_kunci, s.created_at
+		FROM (` + querySumberKemiripan + `) AS s
+		LEFT JOIN taruna t ON t.user_id = s.user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dokumen []dokumenTA
+	indeks := map[int]int{}
+	sudah := map[int]map[string]bool{}
+	for rows.Next() {
+		var (
+			userID                                        int
+			nama, sumber, topik, abstrakID, abstrakEN, kk string
+			waktu                                         time.Time
+		)
+		if err := rows.Scan(&userID, &nama, &sumber, &topik, &abstrakID, &abstrakEN, &kk, &waktu); err != nil {
+			return nil, err
+		}
+
+		i, ok := indeks[userID]
+		if !ok {
+			i = len(dokumen)
+			indeks[userID] = i
+			dokumen = append(dokumen, dokumenTA{UserID: userID, Nama: nama})
+			sudah[userID] = map[string]bool{}
+		}
+		d := &dokumen[i]
+
+		// Topik dan kata kunci diberi bobot dua kali; teks yang sama di beberapa tahap hanya dihitung sekali
+		for _, bagian := range []struct {
+			teks  string
+			bobot int
+		}{{topik, 2}, {kk, 2}, {abstrakID, 1}, {abstrakEN, 1}} {
+			kunci := strings.ToLower(strings.TrimSpace(bagian.teks))
+			if kunci == "" || sudah[userID][kunci] {
+				continue
+			}
+			sudah[userID][kunci] = true
+			for n := 0; n < bagian.bobot; n++ {
+				d.teks = append(d.teks, bagian.teks)
+			}
+		}
+
+		if strings.TrimSpace(topik) != "" && (d.Sumber == "" || urutanSumberKemiripan[sumber] > urutanSumberKemiripan[d.Sumber] ||
+			(sumber == d.Sumber && waktu.After(d.waktu))) {
+			d.Topik, d.Sumber, d.waktu = topik, sumber, waktu
+			d.Tahun = waktu.Format("2006")
+		}
+	}
+	return dokumen, rows.Err()
+}
+
+// Cari mengembalikan paling banyak batas tugas akhir yang paling mirip dengan teks; tugas akhir milik
+// kecualiUserID (taruna yang sedang diperiksa) tidak diikutkan
+func (m *KemiripanModel) Cari(teks string, kecualiUserID, batas int) ([]entities.KemiripanTopik, error) {
+	korpus, dokumen, err := m.indeks()
+	if err != nil {
+		return nil, err
+	}
+
+	hasil := korpus.Cari(kemiripan.Tokenize(teks), batas, func(i int) bool {
+		return dokumen[i].UserID == kecualiUserID
+	})
+	list := make([]entities.KemiripanTopik, 0, len(hasil))
+	for _, h := range hasil {
+		d := dokumen[h.Indeks]
+		istilah := h.Istilah
+		if len(istilah) > 10 {
+			istilah = istilah[:10]
+		}
+		list = append(list, entities.KemiripanTopik{
+			UserID:          d.UserID,
+			NamaTaruna:      d.Nama,
+			TopikPenelitian: d.Topik,
+			Sumber:          d.Sumber,
+			Tahun:           d.Tahun,
+			Skor:            math.Round(h.Skor*10000) / 10000,
+			Istilah:         istilah,
+		})
+	}
+	return list, nil
+}
+
+// TeksDokumen mengambil user_id dan teks (topik dan keterangan) ICP atau final ICP yang diperiksa
+func (m *KemiripanModel) TeksDokumen(sumber string, id int) (int, string, error) {
+	var table string
+	switch sumber {
+	case "icp":
+		table = "icp"
+	case "final_icp":
+		table = "final_icp"
+	default:
+		return 0, "", fmt.Errorf("sumber tidak valid: %s", sumber)
+	}
+
+	var userID int
+	var topik, keterangan sql.NullString
+	err := m.db.QueryRow("SELECT user_id, topik_penelitian, keterangan FROM "+table+" WHERE id = ?", id).
+		Scan(&userID, &topik, &keterangan)
+	if err != nil {
+		return 0, "", err
+	}
+	return userID, topik.String + " " + topik.String + " " + keterangan.String, nil
+}
+
+// SimpanTanda mencatat tugas akhir yang melewati ambang kemiripan untuk ICP yang baru diunggah
+func (m *KemiripanModel) SimpanTanda(icpID int, hasil []entities.KemiripanTopik) error {
+	for _, h := range hasil {
+		_, err := m.db.Exec(`
+			INSERT INTO kemiripan_icp (icp_id, user_id_mirip, sumber, topik_mirip, skor, istilah)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			icpID, h.UserID, h.Sumber, potong(h.TopikPenelitian, 500), h.Skor, potong(strings.Join(h.Istilah, ","), 500))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTanda mengambil catatan kemiripan yang disimpan saat ICP diunggah
+func (m *KemiripanModel) GetTanda(icpID int) ([]entities.KemiripanTopik, error) {
+	rows, err := m.db.Query(`
+		SELECT k.user_id_mirip, COALESCE(t.nama_lengkap, ''), k.topik_mirip, k.sumber, k.skor,
+			COALESCE(k.istilah, '')
+		FROM kemiripan_icp k
+		LEFT JOIN taruna t ON t.user_id = k.user_id_mirip
+		WHERE k.icp_id = ?
+		ORDER BY k.skor DESC`, icpID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.KemiripanTopik{}
+	for rows.Next() {
+		var k entities.KemiripanTopik
+		var istilah string
+		if err := rows.Scan(&k.UserID, &k.NamaTaruna, &k.TopikPenelitian, &k.Sumber, &k.Skor, &istilah); err != nil {
+			return nil, err
+		}
+		k.Istilah = []string{}
+		if istilah != "" {
+			k.Istilah = strings.Split(istilah, ",")
+		}
+		list = append(list, k)
+	}
+	return list, rows.Err()
+}
+
+// potong memendekkan s menjadi paling banyak n byte tanpa memotong karakter UTF-8
+func potong(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	batas := 0
+	for i := range s {
+		if i > n {
+			break
+		}
+		batas = i
+	}
+	return s[:batas]
+}
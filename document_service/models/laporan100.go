@@ -7,10 +7,10 @@ import (
 )
 
 type Laporan100Model struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewLaporan100Model(db *sql.DB) *Laporan100Model {
+func NewLaporan100Model(db DBTX) *Laporan100Model {
 	return &Laporan100Model{db: db}
 }
 
@@ -18,25 +18,35 @@ func (m *Laporan100Model) Create(laporan100 *entities.Laporan100) error {
 	query := `
 		INSERT INTO laporan_100 (
 			user_id, dosen_id, topik_penelitian, keterangan, 
-			file_path, status, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			file_path, status, created_at, updated_at,
+			periode_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ` + periodeTarunaSQL + `)
 	`
 
 	now := time.Now().Format("2006-01-02 15:04:05")
 
-	_, err := m.db.Exec(
+	result, err := m.db.Exec(
 		query,
 		laporan100.UserID,
 		laporan100.DosenID,
 		laporan100.TopikPenelitian,
 		laporan100.Keterangan,
 		laporan100.FilePath,
-		"pending", // status awal
-		now,       // created_at
-		now,       // updated_at
+		"pending",         // status awal
+		now,               // created_at
+		now,               // updated_at
+		laporan100.UserID, // periode_id
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	laporan100.ID = int(id)
+	return nil
 }
 
 func (m *Laporan100Model) GetByUserID(userID string) ([]entities.Laporan100, error) {
@@ -117,7 +127,10 @@ func (m *Laporan100Model) Update(laporan100 *entities.Laporan100) error {
 	return err
 }
 
-func (m *Laporan100Model) GetByDosenID(dosenID string) ([]entities.Laporan100, error) {
+// GetByDosenID mengambil dokumen bimbingan dosen (pembimbing atau pendamping) pada periode tertentu;
+// periodeID 0 berarti semua periode yang belum diarsipkan
+func (m *Laporan100Model) GetByDosenID(dosenID string, periodeID int) ([]entities.Laporan100, error) {
+	kondisiPeriode, argsPeriode := KondisiPeriode("i.periode_id", periodeID)
 	query := `
         SELECT 
             i.id, i.user_id, i.dosen_id, i.topik_penelitian,
@@ -125,10 +138,11 @@ func (m *Laporan100Model) GetByDosenID(dosenID string) ([]entities.Laporan100, e
             i.updated_at, t.nama_lengkap as nama_taruna, t.kelas
         FROM laporan_100 i
         LEFT JOIN taruna t ON i.user_id = t.user_id
-        WHERE i.dosen_id = ?
+        WHERE (i.dosen_id = ? OR ` + KondisiPembimbingPendamping + `)
+            AND ` + kondisiPeriode + `
     `
 
-	rows, err := m.db.Query(query, dosenID)
+	rows, err := m.db.Query(query, append([]interface{}{dosenID, dosenID}, argsPeriode...)...)
 	if err != nil {
 		return nil, err
 	}
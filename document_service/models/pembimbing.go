@@ -0,0 +1,8 @@
+package models
+
+// KondisiPembimbingPendamping adalah kondisi SQL untuk tabel beralias i (dengan kolom user_id) yang
+// bernilai benar bila dosen (parameter ?) adalah pembimbing pendamping aktif taruna pemilik dokumen.
+// Dipakai bersama "i.dosen_id = ?" agar pembimbing pendamping ikut melihat dan mereview dokumen bimbingan.
+const KondisiPembimbingPendamping = `EXISTS (
+	SELECT 1 FROM v_pembimbing_taruna pt
+	WHERE pt.user_id = i.user_id AND pt.dosen_id = ? AND pt.peran = 'pendamping' AND pt.status = 'aktif')`
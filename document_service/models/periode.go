@@ -0,0 +1,16 @@
+package models
+
+import "fmt"
+
+// periodeTarunaSQL adalah subquery periode taruna saat ini berdasarkan user_id, untuk menstempel
+// periode pada dokumen yang baru diunggah. Periode dikelola user_service (tabel periode_ta).
+const periodeTarunaSQL = "(SELECT periode_id FROM taruna WHERE user_id = ?)"
+
+// KondisiPeriode menyusun kondisi SQL untuk kolom periode: periode tertentu bila periodeID > 0,
+// selain itu semua data kecuali yang periodenya sudah diarsipkan (data tanpa periode tetap tampil)
+func KondisiPeriode(kolom string, periodeID int) (string, []interface{}) {
+	if periodeID > 0 {
+		return kolom + " = ?", []interface{}{periodeID}
+	}
+	return fmt.Sprintf("COALESCE(%s, 0) NOT IN (SELECT id FROM periode_ta WHERE status = 'diarsipkan')", kolom), nil
+}
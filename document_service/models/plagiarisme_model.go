@@ -0,0 +1,562 @@
+package models
+
+import (
+	"database/sql"
+	"document_service/entities"
+	"document_service/utils/winnowing"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// ukuranBatchSidik adalah banyaknya hash per query IN dan baris per INSERT sidik jari
+	ukuranBatchSidik = 500
+	// maksSumberPlagiarisme dan maksBagianPlagiarisme membatasi isi laporan agar tetap terbaca
+	maksSumberPlagiarisme = 20
+	maksBagianPlagiarisme = 100
+	// Hash yang muncul di sebagian besar dokumen dianggap teks templat (lembar pengesahan, judul bab)
+	// dan tidak dihitung sebagai kemiripan
+	rasioHashUmum   = 0.5
+	minDokumenUmum  = 3
+	panjangCuplikan = 1000
+)
+
+// DokumenPlagiarisme adalah final laporan yang menjadi korpus atau sedang diperiksa
+type DokumenPlagiarisme struct {
+	Tabel    string
+	ID       int
+	UserID   int
+	FilePath string
+}
+
+type PlagiarismeModel struct {
+	db *sql.DB
+}
+
+func NewPlagiarismeModel(db *sql.DB) *PlagiarismeModel {
+	return &PlagiarismeModel{db: db}
+}
+
+// BelumDiindeks mengambil paling banyak batas final laporan yang teksnya belum diekstrak
+func (m *PlagiarismeModel) BelumDiindeks(batas int) ([]DokumenPlagiarisme, error) {
+	rows, err := m.db.Query(`
+		SELECT 'final_laporan70', f.id, f.user_id, f.file_path
+		FROM final_laporan70 f
+		LEFT JOIN teks_dokumen t ON t.tabel = 'final_laporan70' AND t.dokumen_id = f.id
+		WHERE t.id IS NULL
+		UNION ALL
+		SELECT 'final_laporan100', f.id, f.user_id, f.file_path
+		FROM final_laporan100 f
+		LEFT JOIN teks_dokumen t ON t.tabel = 'final_laporan100' AND t.dokumen_id = f.id
+		WHERE t.id IS NULL
+		LIMIT ?`, batas)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []DokumenPlagiarisme
+	for rows.Next() {
+		var d DokumenPlagiarisme
+		if err := rows.Scan(&d.Tabel, &d.ID, &d.UserID, &d.FilePath); err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+// Indeks menyimpan kata dan sidik jari dokumen, menggantikan indeks lama bila ada. Dokumen yang
+// teksnya gagal diekstrak tetap dicatat (dengan pesanError) agar tidak diekstrak ulang terus-menerus.
+func (m *PlagiarismeModel) Indeks(d DokumenPlagiarisme, kata []string, pesanError string) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var lamaID int
+	err = tx.QueryRow(`SELECT id FROM teks_dokumen WHERE tabel = ? AND dokumen_id = ?`, d.Tabel, d.ID).Scan(&lamaID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		if _, err := tx.Exec(`DELETE FROM sidik_dokumen WHERE teks_dokumen_id = ?`, lamaID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM teks_dokumen WHERE id = ?`, lamaID); err != nil {
+			return err
+		}
+	}
+
+	res, err := tx.Exec(`
+		INSERT INTO teks_dokumen (tabel, dokumen_id, user_id, kata, jumlah_kata, pesan_error)
+		VALUES (?, ?, ?, ?, ?, NULLIF(?, ''))`,
+		d.Tabel, d.ID, d.UserID, strings.Join(kata, " "), len(kata), potong(pesanError, 500))
+	if err != nil {
+		return err
+	}
+	teksID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	sidik := winnowing.SidikJari(kata)
+	for awal := 0; awal < len(sidik); awal += ukuranBatchSidik {
+		akhir := awal + ukuranBatchSidik
+		if akhir > len(sidik) {
+			akhir = len(sidik)
+		}
+		placeholder := make([]string, 0, akhir-awal)
+		args := make([]interface{}, 0, (akhir-awal)*3)
+		for _, s := range sidik[awal:akhir] {
+			placeholder = append(placeholder, "(?, ?, ?)")
+			args = append(args, teksID, s.Hash, s.Posisi)
+		}
+		_, err := tx.Exec(`INSERT INTO sidik_dokumen (teks_dokumen_id, hash, posisi) VALUES `+
+			strings.Join(placeholder, ", "), args...)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// KataDokumen mengambil kata hasil ekstraksi dokumen yang sudah diindeks beserta pesan galat ekstraksinya
+func (m *PlagiarismeModel) KataDokumen(tabel string, id int) ([]string, string, error) {
+	var kata string
+	var pesanError sql.NullString
+	err := m.db.QueryRow(`SELECT kata, pesan_error FROM teks_dokumen WHERE tabel = ? AND dokumen_id = ?`, tabel, id).
+		Scan(&kata, &pesanError)
+	if err != nil {
+		return nil, "", err
+	}
+	return strings.Fields(kata), pesanError.String, nil
+}
+
+// GetFinalLaporan100 mengambil final laporan 100% yang akan diperiksa
+func (m *PlagiarismeModel) GetFinalLaporan100(id int) (DokumenPlagiarisme, error) {
+	d := DokumenPlagiarisme{Tabel: "final_laporan100", ID: id}
+	err := m.db.QueryRow(`SELECT user_id, file_path FROM final_laporan100 WHERE id = ?`, id).Scan(&d.UserID, &d.FilePath)
+	return d, err
+}
+
+// Antrekan membuat (atau mengulang) laporan berstatus antri untuk final laporan 100%
+func (m *PlagiarismeModel) Antrekan(finalLaporan100ID int) error {
+	_, err := m.db.Exec(`
+		INSERT INTO laporan_plagiarisme (final_laporan100_id) VALUES (?)
+		ON DUPLICATE KEY UPDATE status = 'antri', persentase = NULL, jumlah_kata = 0, sumber = NULL,
+			bagian = NULL, pesan_error = NULL, selesai_at = NULL`, finalLaporan100ID)
+	return err
+}
+
+// AntrekanBelumDiperiksa mengantrekan final laporan 100% yang belum memiliki laporan dan mengembalikan
+// laporan yang terhenti di tengah proses (mis. layanan dimatikan) ke antrean
+func (m *PlagiarismeModel) AntrekanBelumDiperiksa() error {
+	_, err := m.db.Exec(`
+		INSERT IGNORE INTO laporan_plagiarisme (final_laporan100_id)
+		SELECT f.id FROM final_laporan100 f
+		WHERE NOT EXISTS (SELECT 1 FROM laporan_plagiarisme l WHERE l.final_laporan100_id = f.id)`)
+	if err != nil {
+		return err
+	}
+	_, err = m.db.Exec(`UPDATE laporan_plagiarisme SET status = 'antri' WHERE status = 'proses'`)
+	return err
+}
+
+// GetAntrian mengambil id final laporan 100% yang laporannya masih antri, yang terlama lebih dulu
+func (m *PlagiarismeModel) GetAntrian() ([]int, error) {
+	rows, err := m.db.Query(`SELECT final_laporan100_id FROM laporan_plagiarisme WHERE status = 'antri' ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Mulai menandai laporan sedang diproses; false bila laporan tidak sedang antri
+func (m *PlagiarismeModel) Mulai(finalLaporan100ID int) (bool, error) {
+	res, err := m.db.Exec(`UPDATE laporan_plagiarisme SET status = 'proses' WHERE final_laporan100_id = ? AND status = 'antri'`,
+		finalLaporan100ID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (m *PlagiarismeModel) Gagal(finalLaporan100ID int, pesan string) error {
+	_, err := m.db.Exec(`
+		UPDATE laporan_plagiarisme SET status = 'gagal', pesan_error = ?, selesai_at = NOW()
+		WHERE final_laporan100_id = ?`, potong(pesan, 500), finalLaporan100ID)
+	return err
+}
+
+func (m *PlagiarismeModel) Simpan(l *entities.LaporanPlagiarisme) error {
+	sumber, err := json.Marshal(l.Sumber)
+	if err != nil {
+		return err
+	}
+	bagian, err := json.Marshal(l.Bagian)
+	if err != nil {
+		return err
+	}
+	_, err = m.db.Exec(`
+		UPDATE laporan_plagiarisme SET status = 'selesai', persentase = ?, jumlah_kata = ?, sumber = ?,
+			bagian = ?, pesan_error = NULL, selesai_at = NOW()
+		WHERE final_laporan100_id = ?`,
+		l.Persentase, l.JumlahKata, string(sumber), string(bagian), l.FinalLaporan100ID)
+	return err
+}
+
+// GetByFinalLaporan100 mengambil laporan kemiripan yang dilampirkan pada final laporan 100%
+func (m *PlagiarismeModel) GetByFinalLaporan100(finalLaporan100ID int) (*entities.LaporanPlagiarisme, error) {
+	var (
+		l              entities.LaporanPlagiarisme
+		persentase     sql.NullFloat64
+		sumber, bagian sql.NullString
+		pesanError     sql.NullString
+		selesaiAt      sql.NullTime
+		createdAt      sql.NullTime
+	)
+	err := m.db.QueryRow(`
+		SELECT id, final_laporan100_id, status, persentase, jumlah_kata, sumber, bagian, pesan_error,
+			selesai_at, created_at
+		FROM laporan_plagiarisme WHERE final_laporan100_id = ?`, finalLaporan100ID).
+		Scan(&l.ID, &l.FinalLaporan100ID, &l.Status, &persentase, &l.JumlahKata, &sumber, &bagian, &pesanError,
+			&selesaiAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Persentase = persentase.Float64
+	l.PesanError = pesanError.String
+	l.Sumber = []entities.SumberPlagiarisme{}
+	l.Bagian = []entities.BagianPlagiarisme{}
+	if sumber.Valid && sumber.String != "" {
+		if err := json.Unmarshal([]byte(sumber.String), &l.Sumber); err != nil {
+			return nil, err
+		}
+	}
+	if bagian.Valid && bagian.String != "" {
+		if err := json.Unmarshal([]byte(bagian.String), &l.Bagian); err != nil {
+			return nil, err
+		}
+	}
+	if selesaiAt.Valid {
+		l.SelesaiAt = selesaiAt.Time.Format("2006-01-02 15:04:05")
+	}
+	if createdAt.Valid {
+		l.CreatedAt = createdAt.Time.Format("2006-01-02 15:04:05")
+	}
+	return &l, nil
+}
+
+// kemunculan adalah satu sidik jari yang sama di dokumen lain
+type kemunculan struct {
+	teksID int
+	posisi int
+}
+
+// pasangan menghubungkan posisi kata di dokumen yang diperiksa dengan posisi di dokumen sumber
+type pasangan struct {
+	posisi       int
+	posisiSumber int
+}
+
+type sumberKorpus struct {
+	entities.SumberPlagiarisme
+	teksID   int
+	pasangan []pasangan
+}
+
+// Bandingkan mencocokkan sidik jari dokumen d dengan seluruh korpus (kecuali dokumen milik taruna yang
+// sama) dan menyusun laporan: persentase kata yang tercakup bagian yang cocok, dokumen sumber, dan
+// bagian-bagian teks yang sama beserta cuplikan dari sumbernya.
+func (m *PlagiarismeModel) Bandingkan(d DokumenPlagiarisme, kata []string) (*entities.LaporanPlagiarisme, error) {
+	laporan := &entities.LaporanPlagiarisme{
+		FinalLaporan100ID: d.ID,
+		JumlahKata:        len(kata),
+		Sumber:            []entities.SumberPlagiarisme{},
+		Bagian:            []entities.BagianPlagiarisme{},
+	}
+	sidik := winnowing.SidikJari(kata)
+	if len(sidik) == 0 {
+		return laporan, nil
+	}
+
+	var jumlahDokumen int
+	err := m.db.QueryRow(`SELECT COUNT(*) FROM teks_dokumen WHERE user_id <> ? AND jumlah_kata > 0`, d.UserID).
+		Scan(&jumlahDokumen)
+	if err != nil {
+		return nil, err
+	}
+
+	unik := map[int64]bool{}
+	var hashes []int64
+	for _, s := range sidik {
+		if !unik[s.Hash] {
+			unik[s.Hash] = true
+			hashes = append(hashes, s.Hash)
+		}
+	}
+
+	cocok, korpus, err := m.cariSidik(hashes, d.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	batasUmum := int(math.Ceil(rasioHashUmum * float64(jumlahDokumen)))
+	if batasUmum < minDokumenUmum {
+		batasUmum = minDokumenUmum
+	}
+	for h, list := range cocok {
+		dokumen := map[int]bool{}
+		for _, k := range list {
+			dokumen[k.teksID] = true
+		}
+		if len(dokumen) >= batasUmum {
+			delete(cocok, h)
+		}
+	}
+
+	var semuaPosisi []int
+	for _, s := range sidik {
+		list, ok := cocok[s.Hash]
+		if !ok {
+			continue
+		}
+		semuaPosisi = append(semuaPosisi, s.Posisi)
+		for _, k := range list {
+			korpus[k.teksID].pasangan = append(korpus[k.teksID].pasangan, pasangan{posisi: s.Posisi, posisiSumber: k.posisi})
+		}
+	}
+	if len(semuaPosisi) == 0 {
+		return laporan, nil
+	}
+	laporan.Persentase = persen(cakupan(semuaPosisi), len(kata))
+
+	// Urutkan sumber dari yang porsi kecocokannya terbesar
+	var daftar []*sumberKorpus
+	for _, s := range korpus {
+		if len(s.pasangan) == 0 {
+			continue
+		}
+		posisi := make([]int, len(s.pasangan))
+		for i, p := range s.pasangan {
+			posisi[i] = p.posisi
+		}
+		s.Persentase = persen(cakupan(posisi), len(kata))
+		daftar = append(daftar, s)
+	}
+	sort.Slice(daftar, func(a, b int) bool {
+		if daftar[a].Persentase != daftar[b].Persentase {
+			return daftar[a].Persentase > daftar[b].Persentase
+		}
+		return daftar[a].DokumenID < daftar[b].DokumenID
+	})
+	if len(daftar) > maksSumberPlagiarisme {
+		daftar = daftar[:maksSumberPlagiarisme]
+	}
+
+	bagian := susunBagian(semuaPosisi, daftar)
+	kataSumber := map[int][]string{}
+	for i := range bagian {
+		b := &bagian[i]
+		teksID := daftar[b.Sumber].teksID
+		if _, ok := kataSumber[teksID]; !ok {
+			var ks string
+			if err := m.db.QueryRow(`SELECT kata FROM teks_dokumen WHERE id = ?`, teksID).Scan(&ks); err != nil {
+				return nil, err
+			}
+			kataSumber[teksID] = strings.Fields(ks)
+		}
+		b.Teks = potong(strings.Join(kata[b.Mulai:b.Selesai], " "), panjangCuplikan)
+		ks := kataSumber[teksID]
+		if b.mulaiSumber < len(ks) {
+			akhir := b.selesaiSumber
+			if akhir > len(ks) {
+				akhir = len(ks)
+			}
+			b.TeksSumber = potong(strings.Join(ks[b.mulaiSumber:akhir], " "), panjangCuplikan)
+		}
+		laporan.Bagian = append(laporan.Bagian, b.BagianPlagiarisme)
+	}
+
+	for _, s := range daftar {
+		if err := m.lengkapiSumber(&s.SumberPlagiarisme); err != nil {
+			return nil, err
+		}
+		laporan.Sumber = append(laporan.Sumber, s.SumberPlagiarisme)
+	}
+	return laporan, nil
+}
+
+// cariSidik mencari dokumen lain yang memiliki hash yang sama, per batch agar query IN tidak terlalu panjang
+func (m *PlagiarismeModel) cariSidik(hashes []int64, kecualiUserID int) (map[int64][]kemunculan, map[int]*sumberKorpus, error) {
+	cocok := map[int64][]kemunculan{}
+	korpus := map[int]*sumberKorpus{}
+	for awal := 0; awal < len(hashes); awal += ukuranBatchSidik {
+		akhir := awal + ukuranBatchSidik
+		if akhir > len(hashes) {
+			akhir = len(hashes)
+		}
+		args := []interface{}{kecualiUserID}
+		for _, h := range hashes[awal:akhir] {
+			args = append(args, h)
+		}
+		rows, err := m.db.Query(`
+			SELECT s.hash, s.posisi, t.id, t.tabel, t.dokumen_id, t.user_id
+			FROM sidik_dokumen s
+			JOIN teks_dokumen t ON t.id = s.teks_dokumen_id
+			WHERE t.user_id <> ? AND s.hash IN (?`+strings.Repeat(", ?", akhir-awal-1)+`)`, args...)
+		if err != nil {
+			return nil, nil, err
+		}
+		for rows.Next() {
+			var (
+				h int64
+				k kemunculan
+				s entities.SumberPlagiarisme
+			)
+			if err := rows.Scan(&h, &k.posisi, &k.teksID, &s.Tabel, &s.DokumenID, &s.UserID); err != nil {
+				rows.Close()
+				return nil, nil, err
+			}
+			cocok[h] = append(cocok[h], k)
+			if _, ok := korpus[k.teksID]; !ok {
+				korpus[k.teksID] = &sumberKorpus{SumberPlagiarisme: s, teksID: k.teksID}
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return cocok, korpus, nil
+}
+
+func (m *PlagiarismeModel) lengkapiSumber(s *entities.SumberPlagiarisme) error {
+	table := "final_laporan100"
+	if s.Tabel == "final_laporan70" {
+		table = "final_laporan70"
+	}
+	err := m.db.QueryRow(`
+		SELECT COALESCE(nama_lengkap, ''), COALESCE(topik_penelitian, ''), DATE_FORMAT(created_at, '%Y')
+		FROM `+table+` WHERE id = ?`, s.DokumenID).Scan(&s.NamaTaruna, &s.TopikPenelitian, &s.Tahun)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+// bagianCocok menyimpan rentang kata di dokumen sumber selama laporan disusun
+type bagianCocok struct {
+	entities.BagianPlagiarisme
+	mulaiSumber   int
+	selesaiSumber int
+}
+
+// susunBagian menggabungkan posisi shingle yang cocok dan saling tumpang tindih menjadi bagian teks,
+// lalu memilih sumber dengan kecocokan terbanyak di setiap bagian. Bagian yang sumbernya tidak
+// termasuk daftar sumber teratas tetap dihitung dalam persentase tetapi tidak ditampilkan.
+func susunBagian(posisi []int, daftar []*sumberKorpus) []bagianCocok {
+	sort.Ints(posisi)
+	var rentang [][2]int
+	for _, p := range posisi {
+		n := len(rentang)
+		if n > 0 && p <= rentang[n-1][1] {
+			if p+winnowing.PanjangShingle > rentang[n-1][1] {
+				rentang[n-1][1] = p + winnowing.PanjangShingle
+			}
+			continue
+		}
+		rentang = append(rentang, [2]int{p, p + winnowing.PanjangShingle})
+	}
+
+	var hasil []bagianCocok
+	for _, r := range rentang {
+		terbaik, jumlahTerbaik := -1, 0
+		var mulaiSumber, selesaiSumber int
+		for i, s := range daftar {
+			jumlah, min, max := 0, math.MaxInt32, -1
+			for _, p := range s.pasangan {
+				if p.posisi < r[0] || p.posisi >= r[1] {
+					continue
+				}
+				jumlah++
+				if p.posisiSumber < min {
+					min = p.posisiSumber
+				}
+				if p.posisiSumber > max {
+					max = p.posisiSumber
+				}
+			}
+			if jumlah > jumlahTerbaik {
+				terbaik, jumlahTerbaik = i, jumlah
+				mulaiSumber, selesaiSumber = min, max+winnowing.PanjangShingle
+			}
+		}
+		if terbaik < 0 {
+			continue
+		}
+		daftar[terbaik].JumlahBagian++
+		hasil = append(hasil, bagianCocok{
+			BagianPlagiarisme: entities.BagianPlagiarisme{Mulai: r[0], Selesai: r[1], Sumber: terbaik},
+			mulaiSumber:       mulaiSumber,
+			selesaiSumber:     selesaiSumber,
+		})
+	}
+
+	// Tampilkan bagian terpanjang, lalu kembalikan ke urutan kemunculan di dokumen
+	if len(hasil) > maksBagianPlagiarisme {
+		sort.SliceStable(hasil, func(a, b int) bool {
+			return hasil[a].Selesai-hasil[a].Mulai > hasil[b].Selesai-hasil[b].Mulai
+		})
+		hasil = hasil[:maksBagianPlagiarisme]
+		sort.Slice(hasil, func(a, b int) bool { return hasil[a].Mulai < hasil[b].Mulai })
+	}
+	return hasil
+}
+
+// cakupan menghitung banyaknya kata yang tercakup shingle yang dimulai di posisi-posisi tersebut
+func cakupan(posisi []int) int {
+	p := append([]int(nil), posisi...)
+	sort.Ints(p)
+	total, akhir := 0, -1
+	for _, x := range p {
+		selesai := x + winnowing.PanjangShingle
+		if x >= akhir {
+			total += winnowing.PanjangShingle
+		} else if selesai > akhir {
+			total += selesai - akhir
+		} else {
+			continue
+		}
+		akhir = selesai
+	}
+	return total
+}
+
+func persen(bagian, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	p := float64(bagian) / float64(total) * 100
+	if p > 100 {
+		p = 100
+	}
+	return math.Round(p*100) / 100
+}
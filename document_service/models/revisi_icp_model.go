@@ -1,15 +1,14 @@
 package models
 
 import (
-	"database/sql"
 	"document_service/entities"
 )
 
 type RevisiICPModel struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewRevisiICPModel(db *sql.DB) *RevisiICPModel {
+func NewRevisiICPModel(db DBTX) *RevisiICPModel {
 	return &RevisiICPModel{
 		db: db,
 	}
@@ -20,8 +19,9 @@ func (m *RevisiICPModel) Create(revisiICP *entities.RevisiICP) error {
 		INSERT INTO revisi_icp (
 			user_id, nama_lengkap, jurusan, 
 			kelas, topik_penelitian, file_path, keterangan, 
-			status
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+			status,
+			periode_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ` + periodeTarunaSQL + `)`
 
 	result, err := m.db.Exec(query,
 		revisiICP.UserID,
@@ -31,7 +31,8 @@ func (m *RevisiICPModel) Create(revisiICP *entities.RevisiICP) error {
 		revisiICP.TopikPenelitian,
 		revisiICP.FilePath,
 		revisiICP.Keterangan,
-		"pending", // default status
+		"pending",        // default status
+		revisiICP.UserID, // periode_id
 	)
 
 	if err != nil {
@@ -6,10 +6,10 @@ import (
 )
 
 type RevisiLaporan100Model struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewRevisiLaporan100Model(db *sql.DB) *RevisiLaporan100Model {
+func NewRevisiLaporan100Model(db DBTX) *RevisiLaporan100Model {
 	return &RevisiLaporan100Model{
 		db: db,
 	}
@@ -21,15 +21,17 @@ func (m *RevisiLaporan100Model) Create(revisi *entities.RevisiLaporan100) error
 			user_id, nama_lengkap, jurusan, kelas, tahun_akademik,
 			topik_penelitian, abstrak_id, abstrak_en, kata_kunci, link_repo,
 			file_path, file_produk_path, file_bap_path,
-			keterangan, status
+			keterangan, status,
+			periode_id
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ` + periodeTarunaSQL + `)`
 
 	result, err := m.db.Exec(query,
 		revisi.UserID, revisi.NamaLengkap, revisi.Jurusan, revisi.Kelas, revisi.TahunAkademik,
 		revisi.TopikPenelitian, revisi.AbstrakID, revisi.AbstrakEN, revisi.KataKunci, revisi.LinkRepo,
 		revisi.FilePath, revisi.FileProdukPath, revisi.FileBapPath,
 		revisi.Keterangan, "pending",
+		revisi.UserID, // periode_id
 	)
 	if err != nil {
 		return err
@@ -1,15 +1,14 @@
 package models
 
 import (
-	"database/sql"
 	"document_service/entities"
 )
 
 type RevisiLaporan70Model struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewRevisiLaporan70Model(db *sql.DB) *RevisiLaporan70Model {
+func NewRevisiLaporan70Model(db DBTX) *RevisiLaporan70Model {
 	return &RevisiLaporan70Model{
 		db: db,
 	}
@@ -20,8 +19,9 @@ func (m *RevisiLaporan70Model) Create(revisiLaporan70 *entities.RevisiLaporan70)
 		INSERT INTO revisi_laporan70 (
 			user_id, nama_lengkap, jurusan, 
 			kelas, topik_penelitian, file_path, keterangan, 
-			status
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+			status,
+			periode_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ` + periodeTarunaSQL + `)`
 
 	result, err := m.db.Exec(query,
 		revisiLaporan70.UserID,
@@ -31,7 +31,8 @@ func (m *RevisiLaporan70Model) Create(revisiLaporan70 *entities.RevisiLaporan70)
 		revisiLaporan70.TopikPenelitian,
 		revisiLaporan70.FilePath,
 		revisiLaporan70.Keterangan,
-		"pending", // default status
+		"pending",              // default status
+		revisiLaporan70.UserID, // periode_id
 	)
 
 	if err != nil {
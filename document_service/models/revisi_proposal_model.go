@@ -1,15 +1,14 @@
 package models
 
 import (
-	"database/sql"
 	"document_service/entities"
 )
 
 type RevisiProposalModel struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewRevisiProposalModel(db *sql.DB) *RevisiProposalModel {
+func NewRevisiProposalModel(db DBTX) *RevisiProposalModel {
 	return &RevisiProposalModel{
 		db: db,
 	}
@@ -20,8 +19,9 @@ func (m *RevisiProposalModel) Create(revisiProposal *entities.RevisiProposal) er
 		INSERT INTO revisi_proposal (
 			user_id, nama_lengkap, jurusan, 
 			kelas, topik_penelitian, file_path, keterangan, 
-			status
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+			status,
+			periode_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ` + periodeTarunaSQL + `)`
 
 	result, err := m.db.Exec(query,
 		revisiProposal.UserID,
@@ -31,7 +31,8 @@ func (m *RevisiProposalModel) Create(revisiProposal *entities.RevisiProposal) er
 		revisiProposal.TopikPenelitian,
 		revisiProposal.FilePath,
 		revisiProposal.Keterangan,
-		"pending", // default status
+		"pending",             // default status
+		revisiProposal.UserID, // periode_id
 	)
 
 	if err != nil {
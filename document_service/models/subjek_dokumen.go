@@ -0,0 +1,76 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// tabelTahapUnggah memetakan tahap unggah ke tabel dokumennya
+var tabelTahapUnggah = map[string]string{
+	UnggahICP:              "icp",
+	UnggahProposal:         "proposal",
+	UnggahLaporan70:        "laporan_70",
+	UnggahLaporan100:       "laporan_100",
+	UnggahRevisiICP:        "revisi_icp",
+	UnggahRevisiProposal:   "revisi_proposal",
+	UnggahRevisiLaporan70:  "revisi_laporan70",
+	UnggahRevisiLaporan100: "revisi_laporan100",
+	UnggahFinalICP:         "final_icp",
+	UnggahFinalProposal:    "final_proposal",
+	UnggahFinalLaporan70:   "final_laporan70",
+	UnggahFinalLaporan100:  "final_laporan100",
+}
+
+// tahapBerdosen adalah tahap yang dokumennya menyimpan dosen tujuan pada kolom dosen_id
+var tahapBerdosen = map[string]bool{
+	UnggahICP:        true,
+	UnggahProposal:   true,
+	UnggahLaporan70:  true,
+	UnggahLaporan100: true,
+}
+
+// SubjekDokumen adalah pihak yang terkait dengan satu dokumen, dalam users.id
+type SubjekDokumen struct {
+	TarunaUserID int
+	DosenUserID  int // 0 bila dokumen tidak mencatat dosen tujuan
+	Topik        string
+}
+
+type SubjekDokumenModel struct {
+	db DBTX
+}
+
+func NewSubjekDokumenModel(db DBTX) *SubjekDokumenModel {
+	return &SubjekDokumenModel{db: db}
+}
+
+// Get mengambil pemilik, dosen tujuan, dan topik dokumen pada tahap tertentu
+func (m *SubjekDokumenModel) Get(tahap string, id int) (*SubjekDokumen, error) {
+	tabel, ok := tabelTahapUnggah[tahap]
+	if !ok {
+		return nil, fmt.Errorf("tahap tidak dikenali: %s", tahap)
+	}
+
+	var s SubjekDokumen
+	var topik sql.NullString
+	if tahapBerdosen[tahap] {
+		var dosen sql.NullInt64
+		err := m.db.QueryRow(`
+			SELECT t.user_id, t.topik_penelitian, d.user_id
+			FROM `+tabel+` t
+			LEFT JOIN dosen d ON d.id = t.dosen_id
+			WHERE t.id = ?`, id).Scan(&s.TarunaUserID, &topik, &dosen)
+		if err != nil {
+			return nil, err
+		}
+		s.DosenUserID = int(dosen.Int64)
+	} else {
+		err := m.db.QueryRow(`SELECT user_id, topik_penelitian FROM `+tabel+` WHERE id = ?`, id).
+			Scan(&s.TarunaUserID, &topik)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.Topik = topik.String
+	return &s, nil
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"database/sql"
+	"document_service/entities"
+	"document_service/utils/icalendar"
+	"errors"
+)
+
+type TandaTanganModel struct {
+	db *sql.DB
+}
+
+func NewTandaTanganModel(db *sql.DB) *TandaTanganModel {
+	return &TandaTanganModel{
+		db: db,
+	}
+}
+
+// ErrSudahDitandatangani dikembalikan bila dosen sudah menandatangani dokumen yang sama
+var ErrSudahDitandatangani = errors.New("dokumen sudah ditandatangani oleh dosen ini")
+
+// Sign menyimpan tanda tangan dalam satu transaksi. Baris dokumen dikunci (FOR UPDATE) agar
+// penandatanganan bersamaan tidak saling menimpa salinan bertanda tangan. Fungsi render menerima
+// tanda tangan yang sudah ada dan mengembalikan path serta hash salinan bertanda tangan yang baru.
+func (m *TandaTanganModel) Sign(t *entities.TandaTangan, render func(existing []entities.TandaTangan) (string, string, error)) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	if err := tx.QueryRow(`SELECT id FROM dokumen_seminar WHERE id = ? FOR UPDATE`, t.DokumenID).Scan(&locked); err != nil {
+		return err
+	}
+
+	existing, err := queryTandaTangan(tx, t.DokumenID)
+	if err != nil {
+		return err
+	}
+	for _, e := range existing {
+		if e.DosenID == t.DosenID {
+			return ErrSudahDitandatangani
+		}
+	}
+
+	signedFilePath, signedFileHash, err := render(existing)
+	if err != nil {
+		return err
+	}
+	t.SignedFileHash = signedFileHash
+
+	result, err := tx.Exec(`
+		INSERT INTO tanda_tangan (
+			dokumen_id, dosen_id, peran, nama_dosen, signed_at,
+			document_hash, signed_file_hash, session_hash, ip_address, user_agent
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.DokumenID, t.DosenID, t.Peran, t.NamaDosen,
+		t.SignedAt.In(icalendar.WIB).Format(jadwalDateTimeLayout),
+		t.DocumentHash, t.SignedFileHash, t.SessionHash, t.IPAddress, t.UserAgent)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE dokumen_seminar SET signed_file_path = ?, signed_file_hash = ? WHERE id = ?`,
+		signedFilePath, signedFileHash, t.DokumenID); err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	t.ID = int(id)
+
+	return tx.Commit()
+}
+
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// GetByDokumen mengembalikan semua tanda tangan pada dokumen, urut waktu tanda tangan
+func (m *TandaTanganModel) GetByDokumen(dokumenID int) ([]entities.TandaTangan, error) {
+	return queryTandaTangan(m.db, dokumenID)
+}
+
+func queryTandaTangan(q queryer, dokumenID int) ([]entities.TandaTangan, error) {
+	rows, err := q.Query(`
+		SELECT id, dokumen_id, dosen_id, peran, nama_dosen, signed_at,
+			document_hash, signed_file_hash, session_hash, ip_address, user_agent
+		FROM tanda_tangan
+		WHERE dokumen_id = ?
+		ORDER BY signed_at ASC, id ASC`, dokumenID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []entities.TandaTangan
+	for rows.Next() {
+		var t entities.TandaTangan
+		if err := rows.Scan(&t.ID, &t.DokumenID, &t.DosenID, &t.Peran, &t.NamaDosen, &t.SignedAt,
+			&t.DocumentHash, &t.SignedFileHash, &t.SessionHash, &t.IPAddress, &t.UserAgent); err != nil {
+			return nil, err
+		}
+		t.SignedAt = toWIB(t.SignedAt)
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// GetDosenEmail mengembalikan email akun dosen untuk dicocokkan dengan klaim token
+func (m *TandaTanganModel) GetDosenEmail(dosenID int) (string, error) {
+	var email string
+	err := m.db.QueryRow(`
+		SELECT COALESCE(u.email, d.email, '')
+		FROM dosen d
+		LEFT JOIN users u ON u.id = d.user_id
+		WHERE d.id = ?`, dosenID).Scan(&email)
+	return email, err
+}
@@ -0,0 +1,292 @@
+// Package beritaacara menyusun PDF Berita Acara Seminar dari data seminar.
+package beritaacara
+
+import (
+	"document_service/entities"
+	"document_service/utils/pdfgen"
+	"document_service/utils/qrcode"
+	"fmt"
+	"time"
+)
+
+const (
+	marginX    = 56.0
+	lineHeight = 15.0
+	fontSize   = 10.0
+	labelWidth = 120.0
+)
+
+var judulTahap = map[string]string{
+	"proposal":   "SEMINAR PROPOSAL",
+	"laporan70":  "SEMINAR LAPORAN 70%",
+	"laporan100": "SEMINAR LAPORAN 100%",
+}
+
+var wib = time.FixedZone("WIB", 7*60*60)
+
+var namaHari = [...]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+
+var namaBulan = [...]string{"", "Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+var labelRekomendasi = map[string]string{
+	"lulus":           "Lulus",
+	"lulus_perbaikan": "Lulus dengan Perbaikan",
+	"tidak_lulus":     "Tidak Lulus",
+}
+
+// TanggalIndonesia memformat tanggal seperti "Senin, 3 Maret 2025"
+func TanggalIndonesia(t time.Time) string {
+	return fmt.Sprintf("%s, %d %s %d", namaHari[t.Weekday()], t.Day(), namaBulan[t.Month()], t.Year())
+}
+
+type writer struct {
+	page *pdfgen.Page
+	y    float64
+}
+
+func (w *writer) field(label, value string) {
+	w.page.Text(marginX, w.y, pdfgen.Regular, fontSize, label)
+	w.page.Text(marginX+labelWidth, w.y, pdfgen.Regular, fontSize, ":")
+	lines := pdfgen.WrapText(pdfgen.Regular, fontSize, pdfgen.A4Width-2*marginX-labelWidth-10, value)
+	for i, line := range lines {
+		if i > 0 {
+			w.y -= lineHeight
+		}
+		w.page.Text(marginX+labelWidth+10, w.y, pdfgen.Regular, fontSize, line)
+	}
+	w.y -= lineHeight
+}
+
+func (w *writer) heading(text string) {
+	w.y -= 6
+	w.page.Text(marginX, w.y, pdfgen.Bold, 11, text)
+	w.y -= lineHeight + 2
+}
+
+// Stamp adalah cap tanda tangan elektronik yang dicetak pada dokumen
+type Stamp struct {
+	Nama     string
+	SignedAt time.Time
+}
+
+// Options berisi data tambahan di luar data seminar: kode verifikasi dan tanda tangan
+type Options struct {
+	KodeVerifikasi string
+	VerifyURL      string
+	Tanda          map[int]Stamp // dosen_id -> cap
+}
+
+func (o *Options) tanda(dosenID int) (Stamp, bool) {
+	if o == nil {
+		return Stamp{}, false
+	}
+	stamp, ok := o.Tanda[dosenID]
+	return stamp, ok
+}
+
+// Render menghasilkan PDF Berita Acara Seminar
+func Render(data *entities.BeritaAcaraData, opts *Options) []byte {
+	judul := "BERITA ACARA " + judulTahap[data.Tahap]
+	doc := pdfgen.New(judul + " - " + data.NamaTaruna)
+	w := &writer{page: doc.AddPage(), y: pdfgen.A4Height - 64}
+	right := pdfgen.A4Width - marginX
+	center := pdfgen.A4Width / 2
+
+	// ===== Kop =====
+	w.page.TextCenter(center, w.y, pdfgen.Bold, 14, judul)
+	w.y -= 16
+	w.page.TextCenter(center, w.y, pdfgen.Regular, 9, "Sistem Informasi Manajemen Tugas Akhir (SIMTA)")
+	w.y -= 10
+	w.page.Line(marginX, w.y, right, w.y, 1)
+	w.y -= lineHeight + 6
+
+	// ===== Identitas =====
+	w.heading("A. Identitas Taruna")
+	w.field("Nama Taruna", data.NamaTaruna)
+	w.field("NPM", data.NPM)
+	w.field("Jurusan / Kelas", fmt.Sprintf("%s / %s", data.Jurusan, data.Kelas))
+	w.field("Topik Penelitian", data.TopikPenelitian)
+	w.field("Dosen Pembimbing", orDash(data.Pembimbing))
+	if data.PendampingID != 0 {
+		w.field("Pembimbing Pendamping", data.Pendamping)
+	}
+
+	// ===== Pelaksanaan =====
+	w.heading("B. Pelaksanaan Seminar")
+	if data.Mulai != nil && data.Selesai != nil {
+		w.field("Hari / Tanggal", TanggalIndonesia(*data.Mulai))
+		w.field("Waktu", fmt.Sprintf("%s - %s WIB", data.Mulai.Format("15:04"), data.Selesai.Format("15:04")))
+	} else {
+		w.field("Hari / Tanggal", "-")
+		w.field("Waktu", "-")
+	}
+	w.field("Ruangan", orDash(data.Ruangan))
+
+	// ===== Tabel penilaian =====
+	w.heading("C. Hasil Penilaian")
+	cols := []float64{marginX, marginX + 30, marginX + 130, marginX + 320, marginX + 380, right}
+	rowH := 20.0
+	drawRow := func(font pdfgen.Font, cells []string) {
+		top := w.y
+		for i := 0; i < len(cols)-1; i++ {
+			w.page.Rect(cols[i], top-rowH, cols[i+1]-cols[i], rowH, false)
+			w.page.Text(cols[i]+4, top-rowH+6, font, 9, cells[i])
+		}
+		w.y -= rowH
+	}
+	drawRow(pdfgen.Bold, []string{"No", "Peran", "Nama Dosen", "Nilai", "Rekomendasi"})
+	for i, p := range data.Penguji {
+		nilai := "-"
+		if p.Nilai != nil {
+			nilai = fmt.Sprintf("%.2f", *p.Nilai)
+		}
+		drawRow(pdfgen.Regular, []string{
+			fmt.Sprintf("%d", i+1), p.Peran, orDash(p.Nama), nilai, orDash(labelRekomendasi[p.Rekomendasi]),
+		})
+	}
+	if len(data.Penguji) == 0 {
+		drawRow(pdfgen.Regular, []string{"-", "-", "Penguji belum ditetapkan", "-", "-"})
+	}
+	w.y -= lineHeight
+
+	rata := "-"
+	if data.RataRata != nil {
+		rata = fmt.Sprintf("%.2f", *data.RataRata)
+	}
+	w.field("Nilai Rata-rata", rata)
+	w.field("Nilai Huruf", orDash(data.NilaiHuruf))
+	w.page.Text(marginX, w.y, pdfgen.Bold, fontSize, "Keputusan")
+	w.page.Text(marginX+labelWidth, w.y, pdfgen.Bold, fontSize, ":")
+	w.page.Text(marginX+labelWidth+10, w.y, pdfgen.Bold, fontSize, data.Keputusan)
+	w.y -= lineHeight * 2
+
+	// ===== Tanda tangan =====
+	w.heading("D. Tanda Tangan Penguji dan Pembimbing")
+	w.signatures(data, opts)
+	w.footer(opts)
+
+	return doc.Bytes()
+}
+
+// RenderPengesahan menghasilkan PDF Lembar Pengesahan yang ditandatangani pembimbing dan penguji
+func RenderPengesahan(data *entities.BeritaAcaraData, opts *Options) []byte {
+	judul := "LEMBAR PENGESAHAN " + judulTahap[data.Tahap]
+	doc := pdfgen.New(judul + " - " + data.NamaTaruna)
+	w := &writer{page: doc.AddPage(), y: pdfgen.A4Height - 64}
+	right := pdfgen.A4Width - marginX
+	center := pdfgen.A4Width / 2
+
+	w.page.TextCenter(center, w.y, pdfgen.Bold, 14, judul)
+	w.y -= 16
+	w.page.TextCenter(center, w.y, pdfgen.Regular, 9, "Sistem Informasi Manajemen Tugas Akhir (SIMTA)")
+	w.y -= 10
+	w.page.Line(marginX, w.y, right, w.y, 1)
+	w.y -= lineHeight * 2
+
+	for _, line := range pdfgen.WrapText(pdfgen.Bold, 12, right-marginX, data.TopikPenelitian) {
+		w.page.TextCenter(center, w.y, pdfgen.Bold, 12, line)
+		w.y -= lineHeight + 2
+	}
+	w.y -= lineHeight
+
+	w.field("Nama Taruna", data.NamaTaruna)
+	w.field("NPM", data.NPM)
+	w.field("Jurusan / Kelas", fmt.Sprintf("%s / %s", data.Jurusan, data.Kelas))
+	w.y -= lineHeight
+
+	for _, line := range pdfgen.WrapText(pdfgen.Regular, fontSize, right-marginX,
+		"Telah diperiksa dan disetujui oleh dosen pembimbing dan dosen penguji sebagai berikut:") {
+		w.page.Text(marginX, w.y, pdfgen.Regular, fontSize, line)
+		w.y -= lineHeight
+	}
+	w.y -= lineHeight
+
+	w.signatures(data, opts)
+	w.footer(opts)
+
+	return doc.Bytes()
+}
+
+// Penandatangan mengembalikan daftar dosen yang berhak menandatangani dokumen seminar:
+// seluruh penguji ditambah dosen pembimbing dan pembimbing pendamping.
+func Penandatangan(data *entities.BeritaAcaraData) []entities.PengujiBAP {
+	signers := make([]entities.PengujiBAP, 0, len(data.Penguji)+2)
+	signers = append(signers, data.Penguji...)
+	if data.PembimbingID != 0 {
+		signers = append(signers, entities.PengujiBAP{
+			Peran:   "Pembimbing",
+			DosenID: data.PembimbingID,
+			Nama:    data.Pembimbing,
+		})
+	}
+	if data.PendampingID != 0 {
+		signers = append(signers, entities.PengujiBAP{
+			Peran:   "Pembimbing Pendamping",
+			DosenID: data.PendampingID,
+			Nama:    data.Pendamping,
+		})
+	}
+	return signers
+}
+
+// signatures menggambar kotak tanda tangan; yang sudah menandatangani diberi cap elektronik
+func (w *writer) signatures(data *entities.BeritaAcaraData, opts *Options) {
+	right := pdfgen.A4Width - marginX
+	boxW := (right - marginX) / 3
+	top := w.y
+	for i, p := range Penandatangan(data) {
+		if i > 0 && i%3 == 0 {
+			top -= 95
+		}
+		x := marginX + float64(i%3)*boxW
+		w.page.Text(x+4, top, pdfgen.Regular, 9, p.Peran)
+
+		if stamp, ok := opts.tanda(p.DosenID); ok {
+			w.page.Rect(x+4, top-58, boxW-16, 48, false)
+			w.page.Text(x+8, top-22, pdfgen.Bold, 7, "DITANDATANGANI SECARA")
+			w.page.Text(x+8, top-31, pdfgen.Bold, 7, "ELEKTRONIK")
+			w.page.Text(x+8, top-42, pdfgen.Regular, 7, stamp.SignedAt.In(wib).Format("02-01-2006 15:04")+" WIB")
+			w.page.Text(x+8, top-51, pdfgen.Regular, 7, "Kode: "+opts.KodeVerifikasi)
+		}
+
+		w.page.Line(x+4, top-64, x+boxW-12, top-64, 0.5)
+		w.page.Text(x+4, top-76, pdfgen.Regular, 9, orDash(p.Nama))
+	}
+	w.y = top - 95
+}
+
+// footer menggambar QR code verifikasi di kaki halaman
+func (w *writer) footer(opts *Options) {
+	if opts == nil || opts.VerifyURL == "" {
+		return
+	}
+	code, err := qrcode.Encode(opts.VerifyURL)
+	if err != nil {
+		return
+	}
+
+	module := 70.0 / float64(code.Size)
+	x0 := pdfgen.A4Width - marginX - 70
+	y0 := 40.0
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Dark(x, y) {
+				w.page.Rect(x0+float64(x)*module, y0+70-float64(y+1)*module, module, module, true)
+			}
+		}
+	}
+
+	w.page.Line(marginX, y0+80, pdfgen.A4Width-marginX, y0+80, 0.5)
+	w.page.Text(marginX, y0+58, pdfgen.Bold, 8, "Verifikasi keaslian dokumen")
+	w.page.Text(marginX, y0+46, pdfgen.Regular, 8, "Pindai QR code atau buka:")
+	w.page.Text(marginX, y0+34, pdfgen.Regular, 8, opts.VerifyURL)
+	w.page.Text(marginX, y0+22, pdfgen.Regular, 8, "Kode verifikasi: "+opts.KodeVerifikasi)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,150 @@
+// Package icalendar menyusun berkas iCalendar (RFC 5545) sederhana untuk jadwal seminar.
+// Semua waktu ditulis dengan TZID Asia/Jakarta beserta komponen VTIMEZONE-nya,
+// sehingga tidak bergantung pada database zona waktu di sisi klien maupun server.
+package icalendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MethodPublish = "PUBLISH"
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+
+	TZID = "Asia/Jakarta"
+
+	prodID        = "-//SIMTA//Jadwal Seminar//ID"
+	maxLineOctets = 75
+)
+
+// WIB adalah zona waktu tetap UTC+7 (Indonesia tidak memakai daylight saving).
+var WIB = time.FixedZone("WIB", 7*60*60)
+
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	Sequence     int
+	Cancelled    bool
+	LastModified time.Time
+}
+
+type Calendar struct {
+	Name      string
+	Method    string
+	Organizer string // alamat email, wajib untuk METHOD REQUEST / CANCEL
+	Events    []Event
+}
+
+// Bytes menghasilkan isi berkas .ics lengkap dengan akhir baris CRLF.
+func (c *Calendar) Bytes() []byte {
+	var b strings.Builder
+	now := time.Now().UTC()
+
+	method := c.Method
+	if method == "" {
+		method = MethodPublish
+	}
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+prodID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:"+method)
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+		writeLine(&b, "X-WR-TIMEZONE:"+TZID)
+	}
+	if method == MethodPublish {
+		// Saran interval sinkronisasi untuk klien kalender yang berlangganan
+		writeLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+		writeLine(&b, "X-PUBLISHED-TTL:PT1H")
+	}
+
+	writeLine(&b, "BEGIN:VTIMEZONE")
+	writeLine(&b, "TZID:"+TZID)
+	writeLine(&b, "BEGIN:STANDARD")
+	writeLine(&b, "DTSTART:19700101T000000")
+	writeLine(&b, "TZOFFSETFROM:+0700")
+	writeLine(&b, "TZOFFSETTO:+0700")
+	writeLine(&b, "TZNAME:WIB")
+	writeLine(&b, "END:STANDARD")
+	writeLine(&b, "END:VTIMEZONE")
+
+	for _, e := range c.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+e.UID)
+		writeLine(&b, "DTSTAMP:"+formatUTC(now))
+		writeLine(&b, fmt.Sprintf("DTSTART;TZID=%s:%s", TZID, formatLocal(e.Start)))
+		writeLine(&b, fmt.Sprintf("DTEND;TZID=%s:%s", TZID, formatLocal(e.End)))
+		writeLine(&b, fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+		if !e.LastModified.IsZero() {
+			writeLine(&b, "LAST-MODIFIED:"+formatUTC(e.LastModified))
+		}
+		writeLine(&b, "SUMMARY:"+escapeText(e.Summary))
+		if e.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		if e.Location != "" {
+			writeLine(&b, "LOCATION:"+escapeText(e.Location))
+		}
+		if c.Organizer != "" {
+			writeLine(&b, "ORGANIZER;CN=SIMTA:mailto:"+c.Organizer)
+		}
+		if e.Cancelled {
+			writeLine(&b, "STATUS:CANCELLED")
+		} else {
+			writeLine(&b, "STATUS:CONFIRMED")
+		}
+		writeLine(&b, "TRANSP:OPAQUE")
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func formatLocal(t time.Time) string {
+	return t.In(WIB).Format("20060102T150405")
+}
+
+// escapeText meng-escape nilai bertipe TEXT sesuai RFC 5545 bagian 3.3.11
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, ";", "\\;")
+	s = strings.ReplaceAll(s, ",", "\\,")
+	s = strings.ReplaceAll(s, "\r\n", "\\n")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	s = strings.ReplaceAll(s, "\r", "\\n")
+	return s
+}
+
+// writeLine menulis satu content line dan melipatnya tiap 75 oktet
+// tanpa memotong karakter UTF-8 di tengah.
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Baris lanjutan diawali satu spasi, sehingga sisa ruangnya 74 oktet
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,159 @@
+// Package kemiripan menghitung kemiripan teks topik/abstrak tugas akhir dengan pembobotan TF-IDF
+// dan kemiripan kosinus. Korpusnya kecil (satu dokumen per taruna) sehingga indeks disimpan di memori.
+package kemiripan
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// stopwords bahasa Indonesia dan Inggris yang umum di judul dan abstrak tugas akhir
+var stopwords = map[string]bool{
+	"dan": true, "atau": true, "yang": true, "di": true, "ke": true, "dari": true, "untuk": true,
+	"pada": true, "dengan": true, "dalam": true, "sebagai": true, "terhadap": true, "oleh": true,
+	"ini": true, "itu": true, "adalah": true, "akan": true, "juga": true, "serta": true, "secara": true,
+	"dapat": true, "tersebut": true, "hasil": true, "menjadi": true, "telah": true, "tidak": true,
+	"berbasis": true, "menggunakan": true, "penggunaan": true, "studi": true, "kasus": true,
+	"analisis": true, "implementasi": true, "perancangan": true, "rancang": true, "bangun": true,
+	"sistem": true, "metode": true, "penerapan": true, "pengembangan": true, "penelitian": true,
+	"the": true, "of": true, "and": true, "or": true, "for": true, "in": true, "on": true, "to": true,
+	"a": true, "an": true, "with": true, "using": true, "based": true, "by": true, "is": true,
+	"this": true, "that": true, "are": true, "was": true, "be": true, "as": true, "from": true,
+}
+
+// Tokenize memecah teks menjadi kata huruf kecil tanpa tanda baca dan stopword
+func Tokenize(teks string) []string {
+	kata := strings.FieldsFunc(strings.ToLower(teks), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	hasil := make([]string, 0, len(kata))
+	for _, k := range kata {
+		if len(k) < 3 || stopwords[k] {
+			continue
+		}
+		hasil = append(hasil, k)
+	}
+	return hasil
+}
+
+// Korpus menyimpan vektor TF-IDF setiap dokumen
+type Korpus struct {
+	idf     map[string]float64
+	vektor  []map[string]float64
+	panjang []float64
+}
+
+// Hasil adalah skor kemiripan query terhadap satu dokumen beserta istilah yang paling berkontribusi
+type Hasil struct {
+	Indeks  int
+	Skor    float64
+	Istilah []string
+}
+
+// NewKorpus membangun korpus dari daftar dokumen yang sudah ditokenisasi
+func NewKorpus(dokumen [][]string) *Korpus {
+	df := map[string]int{}
+	for _, doc := range dokumen {
+		unik := map[string]bool{}
+		for _, t := range doc {
+			if !unik[t] {
+				unik[t] = true
+				df[t]++
+			}
+		}
+	}
+
+	// IDF dengan smoothing agar istilah yang muncul di semua dokumen tetap berbobot kecil, bukan nol
+	n := float64(len(dokumen))
+	k := &Korpus{idf: map[string]float64{}}
+	for t, f := range df {
+		k.idf[t] = math.Log((1+n)/(1+float64(f))) + 1
+	}
+	for _, doc := range dokumen {
+		v := k.bobot(doc)
+		k.vektor = append(k.vektor, v)
+		k.panjang = append(k.panjang, norma(v))
+	}
+	return k
+}
+
+func (k *Korpus) Len() int {
+	return len(k.vektor)
+}
+
+// bobot menghitung vektor TF-IDF; istilah yang tidak ada di korpus diabaikan
+func (k *Korpus) bobot(tokens []string) map[string]float64 {
+	tf := map[string]float64{}
+	for _, t := range tokens {
+		if _, ok := k.idf[t]; ok {
+			tf[t]++
+		}
+	}
+	for t, f := range tf {
+		tf[t] = (1 + math.Log(f)) * k.idf[t]
+	}
+	return tf
+}
+
+func norma(v map[string]float64) float64 {
+	var total float64
+	for _, x := range v {
+		total += x * x
+	}
+	return math.Sqrt(total)
+}
+
+// Cari mengembalikan paling banyak batas dokumen dengan skor kosinus tertinggi (skor > 0).
+// Dokumen yang lewati(i) bernilai true tidak ikut dinilai.
+func (k *Korpus) Cari(query []string, batas int, lewati func(i int) bool) []Hasil {
+	q := k.bobot(query)
+	nq := norma(q)
+	if nq == 0 {
+		return []Hasil{}
+	}
+
+	var hasil []Hasil
+	for i, v := range k.vektor {
+		if k.panjang[i] == 0 || (lewati != nil && lewati(i)) {
+			continue
+		}
+		var dot float64
+		kontribusi := map[string]float64{}
+		for t, w := range q {
+			if d, ok := v[t]; ok {
+				dot += w * d
+				kontribusi[t] = w * d
+			}
+		}
+		if dot == 0 {
+			continue
+		}
+		istilah := make([]string, 0, len(kontribusi))
+		for t := range kontribusi {
+			istilah = append(istilah, t)
+		}
+		sort.Slice(istilah, func(a, b int) bool {
+			if kontribusi[istilah[a]] != kontribusi[istilah[b]] {
+				return kontribusi[istilah[a]] > kontribusi[istilah[b]]
+			}
+			return istilah[a] < istilah[b]
+		})
+		hasil = append(hasil, Hasil{Indeks: i, Skor: dot / (nq * k.panjang[i]), Istilah: istilah})
+	}
+
+	sort.Slice(hasil, func(a, b int) bool {
+		if hasil[a].Skor != hasil[b].Skor {
+			return hasil[a].Skor > hasil[b].Skor
+		}
+		return hasil[a].Indeks < hasil[b].Indeks
+	})
+	if batas > 0 && len(hasil) > batas {
+		hasil = hasil[:batas]
+	}
+	if hasil == nil {
+		hasil = []Hasil{}
+	}
+	return hasil
+}
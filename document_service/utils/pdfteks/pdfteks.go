@@ -0,0 +1,44 @@
+// Package pdfteks mengekstrak teks polos dari berkas PDF tugas akhir. PDF hasil pindaian (gambar)
+// tidak memiliki lapisan teks sehingga hasilnya kosong; pemanggil yang memutuskan cara menanganinya.
+package pdfteks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// EkstrakTeks membaca seluruh halaman PDF di path dan menggabungkan teksnya. Halaman yang gagal
+// dibaca (font atau stream rusak) dilewati agar satu halaman bermasalah tidak menggagalkan dokumen.
+func EkstrakTeks(path string) (teks string, err error) {
+	// Pustaka PDF dapat panic pada berkas yang strukturnya rusak (mis. saat membaca daftar font)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("PDF tidak dapat dibaca: %v", r)
+		}
+	}()
+
+	f, reader, err := pdf.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	for i := 1; i <= reader.NumPage(); i++ {
+		p := reader.Page(i)
+		if p.V.IsNull() {
+			continue
+		}
+		// Nama resource font (F1, F2, ...) hanya berlaku di halamannya sendiri; dengan nil setiap
+		// halaman memakai font dari resource-nya sendiri
+		halaman, err := p.GetPlainText(nil)
+		if err != nil || halaman == "" {
+			continue
+		}
+		b.WriteString(halaman)
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
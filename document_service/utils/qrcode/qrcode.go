@@ -0,0 +1,500 @@
+// Package qrcode adalah encoder QR Code (ISO/IEC 18004) minimalis tanpa dependensi luar.
+// Hanya mendukung mode byte dengan tingkat koreksi galat M untuk versi 1 sampai 10,
+// cukup untuk URL verifikasi dokumen.
+package qrcode
+
+import "errors"
+
+// ErrTooLong dikembalikan bila data melebihi kapasitas versi 10-M (213 byte)
+var ErrTooLong = errors.New("qrcode: data terlalu panjang")
+
+// Code adalah matriks modul QR; true berarti modul gelap
+type Code struct {
+	Size    int
+	modules [][]bool
+}
+
+// Dark mengembalikan true bila modul pada kolom x, baris y berwarna gelap
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Susunan blok tingkat M per versi (indeks 1..10)
+var versionsM = [...]struct {
+	ecPerBlock int
+	g1Blocks   int
+	g1Data     int
+	g2Blocks   int
+	g2Data     int
+}{
+	{},
+	{10, 1, 16, 0, 0},
+	{16, 1, 28, 0, 0},
+	{26, 1, 44, 0, 0},
+	{18, 2, 32, 0, 0},
+	{24, 2, 43, 0, 0},
+	{16, 4, 27, 0, 0},
+	{18, 4, 31, 0, 0},
+	{22, 2, 38, 2, 39},
+	{22, 3, 36, 2, 37},
+	{26, 4, 43, 1, 44},
+}
+
+var alignmentPositions = [...][]int{
+	nil, {}, {6, 18}, {6, 22}, {6, 26}, {6, 30}, {6, 34},
+	{6, 22, 38}, {6, 24, 42}, {6, 26, 46}, {6, 28, 50},
+}
+
+// Encode membuat QR Code dari teks dengan versi terkecil yang cukup
+func Encode(text string) (*Code, error) {
+	return encode(text, -1)
+}
+
+// encode membuat QR Code dengan mask tertentu; mask negatif berarti dipilih yang penaltinya terkecil
+func encode(text string, mask int) (*Code, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v <= 10; v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+len(data)*8 <= dataCodewords(v)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	codewords := addECCAndInterleave(version, encodeData(version, data))
+
+	size := version*4 + 17
+	q := &qr{
+		size:       size,
+		modules:    newGrid(size),
+		isFunction: newGrid(size),
+	}
+	q.drawFunctionPatterns(version)
+	q.drawCodewords(codewords)
+
+	// Tanpa mask tertentu, pilih mask dengan penalti terkecil
+	if mask < 0 {
+		bestPenalty := -1
+		for m := 0; m < 8; m++ {
+			q.applyMask(m)
+			q.drawFormatBits(m)
+			if p := q.penalty(); bestPenalty < 0 || p < bestPenalty {
+				mask, bestPenalty = m, p
+			}
+			q.applyMask(m) // XOR dua kali mengembalikan kondisi semula
+		}
+	}
+	q.applyMask(mask)
+	q.drawFormatBits(mask)
+
+	return &Code{Size: size, modules: q.modules}, nil
+}
+
+func dataCodewords(version int) int {
+	v := versionsM[version]
+	return v.g1Blocks*v.g1Data + v.g2Blocks*v.g2Data
+}
+
+func newGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+// ===== Segmen data =====
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>uint(i))&1 == 1)
+	}
+}
+
+func encodeData(version int, data []byte) []byte {
+	capacity := dataCodewords(version) * 8
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+
+	var bb bitBuffer
+	bb.append(0x4, 4) // mode byte
+	bb.append(len(data), countBits)
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+
+	// Terminator dan padding ke batas byte
+	terminator := capacity - len(bb)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	if rem := len(bb) % 8; rem != 0 {
+		bb.append(0, 8-rem)
+	}
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	out := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			out[i>>3] |= 1 << uint(7-i&7)
+		}
+	}
+	return out
+}
+
+// ===== Reed-Solomon =====
+
+var gfExp [512]byte
+var gfLog [256]byte
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < 512; i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func rsGenerator(degree int) []byte {
+	gen := []byte{1}
+	for i := 0; i < degree; i++ {
+		next := make([]byte, len(gen)+1)
+		for j, c := range gen {
+			next[j] ^= c
+			next[j+1] ^= gfMul(c, gfExp[i])
+		}
+		gen = next
+	}
+	return gen
+}
+
+func rsRemainder(data, gen []byte) []byte {
+	degree := len(gen) - 1
+	res := make([]byte, degree)
+	for _, b := range data {
+		factor := b ^ res[0]
+		copy(res, res[1:])
+		res[degree-1] = 0
+		for j := 0; j < degree; j++ {
+			res[j] ^= gfMul(gen[j+1], factor)
+		}
+	}
+	return res
+}
+
+func addECCAndInterleave(version int, data []byte) []byte {
+	v := versionsM[version]
+	gen := rsGenerator(v.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for i := 0; i < v.g1Blocks+v.g2Blocks; i++ {
+		n := v.g1Data
+		if i >= v.g1Blocks {
+			n = v.g2Data
+		}
+		block := data[offset : offset+n]
+		offset += n
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, gen))
+	}
+
+	maxData := v.g1Data
+	if v.g2Data > maxData {
+		maxData = v.g2Data
+	}
+
+	var out []byte
+	for i := 0; i < maxData; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				out = append(out, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			out = append(out, block[i])
+		}
+	}
+	return out
+}
+
+// ===== Penempatan modul =====
+
+type qr struct {
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+func (q *qr) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+func (q *qr) drawFunctionPatterns(version int) {
+	// Timing pattern
+	for i := 0; i < q.size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder pattern beserta separator
+	q.drawFinder(3, 3)
+	q.drawFinder(q.size-4, 3)
+	q.drawFinder(3, q.size-4)
+
+	// Alignment pattern, kecuali yang bertumpuk dengan finder
+	pos := alignmentPositions[version]
+	last := len(pos) - 1
+	for i := range pos {
+		for j := range pos {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.setFunction(pos[i]+dx, pos[j]+dy, maxAbs(dx, dy) != 1)
+				}
+			}
+		}
+	}
+
+	// Cadangkan area format (diisi ulang setelah mask dipilih)
+	q.drawFormatBits(0)
+
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := (bits>>uint(i))&1 == 1
+			a := q.size - 11 + i%3
+			b := i / 3
+			q.setFunction(a, b, dark)
+			q.setFunction(b, a, dark)
+		}
+	}
+}
+
+func (q *qr) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || x >= q.size || y < 0 || y >= q.size {
+				continue
+			}
+			dist := maxAbs(dx, dy)
+			q.setFunction(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawFormatBits menulis informasi format (tingkat M = 00) dan mask ke dua lokasinya
+func (q *qr) drawFormatBits(mask int) {
+	data := mask // bit tingkat koreksi M adalah 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>uint(i))&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, bit(i))
+	}
+	q.setFunction(8, q.size-8, true) // modul gelap tetap
+}
+
+func (q *qr) drawCodewords(data []byte) {
+	i := 0
+	total := len(data) * 8
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < q.size; vert++ {
+			y := vert
+			if upward {
+				y = q.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if !q.isFunction[y][x] && i < total {
+					q.modules[y][x] = (data[i>>3]>>uint(7-i&7))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (q *qr) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.isFunction[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty menghitung skor penalti mask sesuai empat aturan standar
+func (q *qr) penalty() int {
+	score := 0
+	size := q.size
+	get := func(x, y int, horizontal bool) bool {
+		if horizontal {
+			return q.modules[y][x]
+		}
+		return q.modules[x][y]
+	}
+
+	finderLike := [][]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+
+	for _, horizontal := range []bool{true, false} {
+		for line := 0; line < size; line++ {
+			// Aturan 1: deretan warna sama sepanjang 5 atau lebih
+			run := 1
+			for i := 1; i < size; i++ {
+				if get(i, line, horizontal) == get(i-1, line, horizontal) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += 3 + run - 5
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += 3 + run - 5
+			}
+
+			// Aturan 3: pola menyerupai finder
+			for i := 0; i+11 <= size; i++ {
+				for _, pattern := range finderLike {
+					match := true
+					for k, dark := range pattern {
+						if get(i+k, line, horizontal) != dark {
+							match = false
+							break
+						}
+					}
+					if match {
+						score += 40
+					}
+				}
+			}
+		}
+	}
+
+	// Aturan 2: blok 2x2 berwarna sama
+	dark := 0
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x < size-1 && y < size-1 {
+				c := q.modules[y][x]
+				if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+					score += 3
+				}
+			}
+		}
+	}
+
+	// Aturan 4: keseimbangan modul gelap dan terang
+	total := size * size
+	deviation := dark*100/total - 50
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	score += deviation / 5 * 10
+
+	return score
+}
+
+func maxAbs(a, b int) int {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,324 @@
+package qrcode
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// Matriks dan sidik acuan di berkas ini dibuat dengan encoder independen github.com/skip2/go-qrcode
+// (tingkat M, tanpa quiet zone) yang mask-nya dipaksa sama dengan kasus uji.
+
+// Vektor Reed-Solomon contoh "HELLO WORLD" (mode alfanumerik) dari tutorial QR Code thonky.com. Sisa
+// pembagian tidak bergantung pada mode sehingga dipakai langsung untuk memeriksa GF(256) dan generator.
+func TestReedSolomon(t *testing.T) {
+	tests := []struct {
+		nama string
+		data []byte
+		ecc  []byte
+	}{
+		{
+			nama: "1-M",
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			ecc:  []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+		{
+			nama: "1-Q",
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236},
+			ecc:  []byte{168, 72, 22, 82, 217, 54, 156, 0, 46, 15, 180, 122, 16},
+		},
+	}
+	for _, tt := range tests {
+		if got := rsRemainder(tt.data, rsGenerator(len(tt.ecc))); !bytes.Equal(got, tt.ecc) {
+			t.Errorf("%s: ECC = %v, ingin %v", tt.nama, got, tt.ecc)
+		}
+	}
+}
+
+func TestEncodeData(t *testing.T) {
+	// Mode byte 0100, panjang 11 (8 bit), "hello world", terminator, lalu padding 0xEC/0x11
+	data := encodeData(1, []byte("hello world"))
+	ingin := []byte{64, 182, 134, 86, 198, 198, 242, 7, 118, 247, 38, 198, 64, 236, 17, 236}
+	if !bytes.Equal(data, ingin) {
+		t.Fatalf("codeword data = %v, ingin %v", data, ingin)
+	}
+
+	ingin = append(ingin, 57, 58, 220, 32, 213, 8, 197, 250, 63, 193)
+	if got := addECCAndInterleave(1, data); !bytes.Equal(got, ingin) {
+		t.Errorf("codeword akhir = %v, ingin %v", got, ingin)
+	}
+}
+
+// TestEncodeMask membandingkan matriks versi 1-M "hello world" untuk setiap mask, termasuk bit formatnya
+func TestEncodeMask(t *testing.T) {
+	acuan := [8][]string{
+		{ // mask 0
+			"#######...###.#######",
+			"#.....#.#.###.#.....#",
+			"#.###.#....#..#.###.#",
+			"#.###.#..###..#.###.#",
+			"#.###.#.##..#.#.###.#",
+			"#.....#.....#.#.....#",
+			"#######.#.#.#.#######",
+			"..........###........",
+			"#.#.#.#...##....#..#.",
+			"#.#.#....#....###..##",
+			"#..#.####...##.######",
+			".##....#.#......#..#.",
+			"..#.####..#.##.##....",
+			"........#.##.#..#.###",
+			"#######..###...##.###",
+			"#.....#....##..#....#",
+			"#.###.#.####....#....",
+			"#.###.#..#.#..###.##.",
+			"#.###.#.#.#.#.#.#.#.#",
+			"#.....#..###....#..#.",
+			"#######.#.###..#...##",
+		},
+		{ // mask 1
+			"#######.###.#.#######",
+			"#.....#..##.#.#.....#",
+			"#.###.#.##....#.###.#",
+			"#.###.#...#...#.###.#",
+			"#.###.#....##.#.###.#",
+			"#.....#.##.##.#.....#",
+			"#######.#.#.#.#######",
+			".........##.#........",
+			"#.#...##.##....#..#.#",
+			"######.#...#.##.##..#",
+			"##....#.##.##...#.#.#",
+			"..##.#.....#.#.###...",
+			".####.#..####...##.#.",
+			"........###....####.#",
+			"#######.#.#..#..###.#",
+			"#.....#..#..##...#.##",
+			"#.###.#...#..#.###.#.",
+			"#.###.#......##.###..",
+			"#.###.#.#############",
+			"#.....#...#..#.###...",
+			"#######.###.##...#..#",
+		},
+		{ // mask 2
+			"#######..#.##.#######",
+			"#.....#...#...#.....#",
+			"#.###.#.####..#.###.#",
+			"#.###.#.###.#.#.###.#",
+			"#.###.#.#.#.#.#.###.#",
+			"#.....#.#..#..#.....#",
+			"#######.#.#.#.#######",
+			"........#.#..........",
+			"#.#####..#.#..#####..",
+			".##.##.#.#.########.#",
+			"#.#.####.##.###..###.",
+			"#.#..#...#.###..###..",
+			"...#.#####..###.....#",
+			"........#.#.#...##..#",
+			"#######....#..#...##.",
+			"#.....#.#....#.#.####",
+			"#.###.#.#..#..##....#",
+			"#.###.#.##..######...",
+			"#.###.#.##..#..#..#..",
+			"#.....#..##.##..###..",
+			"#######.##.##.#.#..#.",
+		},
+		{ // mask 3
+			"#######.##.##.#######",
+			"#.....#.#####.#.....#",
+			"#.###.#....##.#.###.#",
+			"#.###.#.###.#.#.###.#",
+			"#.###.#..###..#.###.#",
+			"#.....#..####.#.....#",
+			"#######.#.#.#.#######",
+			"........#####........",
+			"#.##.###..###.#..#.##",
+			".##.##.#.#.########.#",
+			"...##.###.##.#.#...##",
+			".#####.#..##...#.#.#.",
+			"...#.#####..###.....#",
+			"........####..###.#..",
+			"#######.#########....",
+			"#.....#.#....#.#.####",
+			"#.###.#..#..#....##..",
+			"#.###.#.#.#...#..###.",
+			"#.###.#.##..#..#..#..",
+			"#.....#...##.####...#",
+			"#######.#.##.###..#..",
+		},
+		{ // mask 4
+			"#######.#..##.#######",
+			"#.....#..##...#.....#",
+			"#.###.#..#..#.#.###.#",
+			"#.###.#.##.#..#.###.#",
+			"#.###.#.###.#.#.###.#",
+			"#.....#.##.#..#.....#",
+			"#######.#.#.#.#######",
+			"........#..##........",
+			"#...#.###..#.#####..#",
+			"...###..#..##...####.",
+			"..#...##.#.#.##.#..#.",
+			"..#.#....##..#.......",
+			".##..##.....#..#...#.",
+			"........###.######.#.",
+			"#######.#.#.#.#.##.#.",
+			"#.....#...####.##..##",
+			"#.###.#.##.#.#.....#.",
+			"#.###.#.....#...##.##",
+			"#.###.#..###...###...",
+			"#.....#..#.#.#.......",
+			"#######.#..###.##...#",
+		},
+		{ // mask 5
+			"#######..##.#.#######",
+			"#.....#.###...#.....#",
+			"#.###.#.####..#.###.#",
+			"#.###.#.#...#.#.###.#",
+			"#.###.#...#.#.#.###.#",
+			"#.....#..#.#..#.....#",
+			"#######.#.#.#.#######",
+			"........###..........",
+			"#.....#.##.#.##..###.",
+			".#.#.#.##.####...##..",
+			"#.#.####.##.###..###.",
+			"#.##.#.....###.####..",
+			".####.#..####...##.#.",
+			"........###.#..###..#",
+			"#######....#..#...##.",
+			"#.....#..##..##.####.",
+			"#.###.#....#..##....#",
+			"#.###.#.....###.##...",
+			"#.###.#..############",
+			"#.....#...#.##.####..",
+			"#######.##.##.#.#..#.",
+		},
+		{ // mask 6
+			"#######.###.#.#######",
+			"#.....#.###...#.....#",
+			"#.###.#.##.#..#.###.#",
+			"#.###.#.....#.#.###.#",
+			"#.###.#.#.###.#.###.#",
+			"#.....#..##...#.....#",
+			"#######.#.#.#.#######",
+			".........##..........",
+			"#..#########.#..#.###",
+			".#.#.#.##.####...##..",
+			"#...#.########....###",
+			"#.###.....#.##.#..#..",
+			".####.#..####...##.#.",
+			"........###.######.#.",
+			"#######.#.##.##.#.#..",
+			"#.....#.###..##.####.",
+			"#.###.#.#......#.#...",
+			"#.###.#.#.#####......",
+			"#.###.#..############",
+			"#.....#...#.#.#######",
+			"#######.#######......",
+		},
+		{ // mask 7
+			"#######...###.#######",
+			"#.....#....##.#.....#",
+			"#.###.#.......#.###.#",
+			"#.###.#..###..#.###.#",
+			"#.###.#..##.#.#.###.#",
+			"#.....#.#..##.#.....#",
+			"#######.#.#.#.#######",
+			"...........##........",
+			"#..#.##.#.#..#.#.....",
+			"#.#.#....#....###..##",
+			"##.####.#.#.#..#.##.#",
+			".#...#.###.#..#.##.##",
+			"..#.####..#.##.##....",
+			"........#..#......#.#",
+			"#######..##...######.",
+			"#.....#.#..##..#....#",
+			"#.###.#..#.#.#.....#.",
+			"#.###.#.##.....######",
+			"#.###.#...#.#.#.#.#.#",
+			"#.....#..#.#.#.......",
+			"#######.#.#.#.##.#.#.",
+		},
+	}
+	for mask, matriks := range acuan {
+		code, err := encode("hello world", mask)
+		if err != nil {
+			t.Fatalf("mask %d: %v", mask, err)
+		}
+		if got := gambar(code); got != strings.Join(matriks, "\n") {
+			t.Errorf("mask %d tidak sama dengan acuan:\n%s", mask, got)
+		}
+	}
+}
+
+// TestEncodeVersi memeriksa data terpanjang yang muat di setiap versi 1-10 (susunan blok, alignment,
+// informasi versi, dan panjang 16 bit di versi 10) lewat sidik SHA-256 matriks acuan. Standar tidak
+// menetapkan satu mask yang benar, jadi Encode cukup menghasilkan salah satu dari delapan simbol yang sah.
+func TestEncodeVersi(t *testing.T) {
+	teks := strings.Repeat("lembar pengesahan tugas akhir simta berita acara ujian ", 5)
+	tests := []struct {
+		versi   int
+		panjang int
+		mask    int
+		sha256  string
+	}{
+		{1, 14, 0, "89c3f0aad7592168d2018669ed5069491651c10f210a440fb461bbe936d10111"},
+		{2, 26, 1, "99e5909b91b199cb35b648dd5ce31377c11e593839592c77a5667214b16418c0"},
+		{3, 42, 2, "46655c7c9d61a68e83208c8fdc5708bea9c3a4740fb073339774394f86daef10"},
+		{4, 62, 3, "313c517fc6c97a37048d87907dd758f3674d10473a1558035abbec3b2d4e084d"},
+		{5, 84, 4, "63dfa01d7cdfc34b168b4400336e47fff11d32ec1192d2e7fd827f24dd2dfd91"},
+		{6, 106, 5, "14e481b454f960e4113a27f850ef03862b8ec9798272c52efdbb7de5ae9e2012"},
+		{7, 122, 6, "bc995b2f3c36c706acf4fbc509e27821903b6de11ad6f7eac0075c3935ef79e6"},
+		{8, 152, 7, "3bb7985435ab18028db6b824687d28514399eeee1c5241123842896a9ddb666f"},
+		{9, 180, 0, "a5e23f1ea7ebf90e2d2bb65b1296de0de82549a0758eac511aa3cf5494d523ad"},
+		{10, 213, 1, "1ef62ed2151f3e689424acb7979d2facfa3e1704f26647b5c3199d7c3092aead"},
+	}
+	for _, tt := range tests {
+		code, err := encode(teks[:tt.panjang], tt.mask)
+		if err != nil {
+			t.Fatalf("%d byte: %v", tt.panjang, err)
+		}
+		if code.Size != tt.versi*4+17 {
+			t.Errorf("%d byte: ukuran %d, ingin versi %d", tt.panjang, code.Size, tt.versi)
+			continue
+		}
+		sidik := sha256.Sum256([]byte(gambar(code)))
+		if got := hex.EncodeToString(sidik[:]); got != tt.sha256 {
+			t.Errorf("versi %d mask %d: sidik matriks %s, ingin %s", tt.versi, tt.mask, got, tt.sha256)
+		}
+
+		auto, err := Encode(teks[:tt.panjang])
+		if err != nil {
+			t.Fatalf("%d byte: %v", tt.panjang, err)
+		}
+		sah := false
+		for mask := 0; mask < 8 && !sah; mask++ {
+			code, _ := encode(teks[:tt.panjang], mask)
+			sah = gambar(code) == gambar(auto)
+		}
+		if !sah {
+			t.Errorf("versi %d: Encode menghasilkan matriks yang bukan salah satu mask", tt.versi)
+		}
+	}
+
+	if _, err := Encode(teks[:214]); err != ErrTooLong {
+		t.Errorf("214 byte: galat = %v, ingin ErrTooLong", err)
+	}
+}
+
+// gambar menuliskan matriks baris demi baris dengan '#' untuk modul gelap
+func gambar(c *Code) string {
+	var sb strings.Builder
+	for y := 0; y < c.Size; y++ {
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+		for x := 0; x < c.Size; x++ {
+			if c.Dark(x, y) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+	}
+	return sb.String()
+}
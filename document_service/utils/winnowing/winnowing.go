@@ -0,0 +1,75 @@
+// Package winnowing membuat sidik jari dokumen dengan algoritme winnowing (Schleimer dkk., 2003):
+// teks dipecah menjadi shingle k kata, setiap shingle di-hash, lalu dari setiap jendela w hash
+// berurutan dipilih hash terkecil. Dua dokumen yang berbagi satu bagian teks sepanjang minimal
+// k+w-1 kata dijamin berbagi paling sedikit satu sidik jari.
+package winnowing
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// PanjangShingle adalah banyaknya kata dalam satu shingle
+	PanjangShingle = 8
+	// LebarJendela adalah banyaknya hash shingle berurutan dalam satu jendela winnowing
+	LebarJendela = 4
+)
+
+// Sidik adalah hash satu shingle terpilih beserta posisi kata pertamanya di dokumen
+type Sidik struct {
+	Hash   int64
+	Posisi int
+}
+
+// Kata menormalkan teks menjadi daftar kata huruf kecil tanpa tanda baca. Posisi pada Sidik
+// merujuk ke indeks daftar ini sehingga bagian yang cocok dapat ditampilkan kembali.
+func Kata(teks string) []string {
+	return strings.FieldsFunc(strings.ToLower(teks), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SidikJari menghitung sidik jari winnowing dari daftar kata hasil Kata. Dokumen yang lebih pendek
+// dari satu shingle tidak memiliki sidik jari.
+func SidikJari(kata []string) []Sidik {
+	if len(kata) < PanjangShingle {
+		return []Sidik{}
+	}
+
+	hashes := make([]int64, len(kata)-PanjangShingle+1)
+	for i := range hashes {
+		h := fnv.New64a()
+		for j, k := range kata[i : i+PanjangShingle] {
+			if j > 0 {
+				h.Write([]byte{' '})
+			}
+			h.Write([]byte(k))
+		}
+		// Disimpan sebagai BIGINT bertanda di database
+		hashes[i] = int64(h.Sum64())
+	}
+
+	w := LebarJendela
+	if len(hashes) < w {
+		w = len(hashes)
+	}
+
+	hasil := []Sidik{}
+	terpilih := -1
+	for mulai := 0; mulai+w <= len(hashes); mulai++ {
+		// Minimum paling kanan dipilih agar jendela berikutnya cenderung memilih hash yang sama
+		min := mulai
+		for i := mulai + 1; i < mulai+w; i++ {
+			if hashes[i] <= hashes[min] {
+				min = i
+			}
+		}
+		if min != terpilih {
+			terpilih = min
+			hasil = append(hasil, Sidik{Hash: hashes[min], Posisi: min})
+		}
+	}
+	return hasil
+}
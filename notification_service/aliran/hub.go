@@ -0,0 +1,113 @@
+// Package aliran membagikan pesan real-time ke koneksi SSE di dalam proses. Setiap koneksi punya
+// buffer sendiri; koneksi yang tertinggal sampai buffernya penuh diputus agar tidak menahan pengirim,
+// dan klien menyusul lewat Last-Event-ID saat tersambung kembali.
+package aliran
+
+import "sync"
+
+// BufferKoneksi adalah kapasitas antrean pesan per koneksi
+const BufferKoneksi = 64
+
+// Pesan adalah satu event SSE
+type Pesan struct {
+	ID    int64
+	Event string
+	Data  []byte // JSON satu baris
+}
+
+// Langganan adalah satu koneksi SSE milik user
+type Langganan struct {
+	userID int
+	Pesan  chan Pesan
+	Putus  chan struct{} // ditutup bila hub memutus langganan karena buffernya penuh
+	sekali sync.Once
+}
+
+func (l *Langganan) putus() {
+	l.sekali.Do(func() { close(l.Putus) })
+}
+
+// Hub memegang seluruh langganan aktif per user
+type Hub struct {
+	mu    sync.RWMutex
+	klien map[int]map[*Langganan]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{klien: map[int]map[*Langganan]struct{}{}}
+}
+
+// Pusat adalah hub yang dipakai seluruh handler
+var Pusat = NewHub()
+
+// Langgan mendaftarkan koneksi baru untuk user; panggil Berhenti saat koneksi selesai
+func (h *Hub) Langgan(userID int) *Langganan {
+	l := &Langganan{userID: userID, Pesan: make(chan Pesan, BufferKoneksi), Putus: make(chan struct{})}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.klien[userID] == nil {
+		h.klien[userID] = map[*Langganan]struct{}{}
+	}
+	h.klien[userID][l] = struct{}{}
+	return l
+}
+
+// Berhenti melepas langganan dari hub
+func (h *Hub) Berhenti(l *Langganan) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lepas(l)
+}
+
+func (h *Hub) lepas(l *Langganan) {
+	if set := h.klien[l.userID]; set != nil {
+		delete(set, l)
+		if len(set) == 0 {
+			delete(h.klien, l.userID)
+		}
+	}
+	l.putus()
+}
+
+// Tersambung mengembalikan user yang sedang punya koneksi dari daftar userIDs; tanpa daftar, seluruh
+// user yang tersambung
+func (h *Hub) Tersambung(userIDs ...int) []int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var hasil []int
+	if len(userIDs) == 0 {
+		for id := range h.klien {
+			hasil = append(hasil, id)
+		}
+		return hasil
+	}
+	for _, id := range userIDs {
+		if _, ok := h.klien[id]; ok {
+			hasil = append(hasil, id)
+		}
+	}
+	return hasil
+}
+
+// Kirim menaruh pesan di buffer setiap koneksi user tanpa menunggu. Koneksi yang buffernya penuh
+// diputus.
+func (h *Hub) Kirim(userID int, p Pesan) {
+	var tertinggal []*Langganan
+	h.mu.RLock()
+	for l := range h.klien[userID] {
+		select {
+		case l.Pesan <- p:
+		default:
+			tertinggal = append(tertinggal, l)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(tertinggal) > 0 {
+		h.mu.Lock()
+		for _, l := range tertinggal {
+			h.lepas(l)
+		}
+		h.mu.Unlock()
+	}
+}
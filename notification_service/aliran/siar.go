@@ -0,0 +1,57 @@
+package aliran
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"notification_service/models"
+)
+
+// Nama event SSE
+const (
+	EventNotifikasi = "notifikasi" // notifikasi inbox baru
+	EventStatus     = "status"     // notifikasi perubahan status dokumen
+	EventTerhubung  = "terhubung"  // pesan pertama koneksi baru, membawa id terakhir
+	EventSinkron    = "sinkron"    // terlalu banyak event terlewat; muat ulang GET /inbox
+)
+
+// dataAliran adalah isi event notifikasi dan status
+type dataAliran struct {
+	Notifikasi models.ItemInbox `json:"notifikasi"`
+	Event      *eventAsal       `json:"event,omitempty"`
+}
+
+type eventAsal struct {
+	Tipe string          `json:"tipe"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// PesanDari menyusun event SSE untuk satu baris inbox
+func PesanDari(a models.ItemAliran) Pesan {
+	d := dataAliran{Notifikasi: a.Item}
+	event := EventNotifikasi
+	if a.EventTipe != "" {
+		d.Event = &eventAsal{Tipe: a.EventTipe, Data: a.EventData}
+		if a.EventTipe == models.EventStatusBerubah {
+			event = EventStatus
+		}
+	}
+	data, _ := json.Marshal(d)
+	return Pesan{ID: a.ID, Event: event, Data: data}
+}
+
+// Siarkan mendorong notifikasi yang baru disimpan ke koneksi SSE penerimanya di Pusat
+func Siarkan(db *sql.DB, notificationIDs ...int64) {
+	userIDs := Pusat.Tersambung()
+	if len(userIDs) == 0 || len(notificationIDs) == 0 {
+		return
+	}
+	list, err := models.NewInboxModel(db).AliranNotifikasi(notificationIDs, userIDs)
+	if err != nil {
+		log.Printf("⚠️ Gagal memuat notifikasi untuk SSE: %v", err)
+		return
+	}
+	for _, a := range list {
+		Pusat.Kirim(a.UserID, PesanDari(a))
+	}
+}
@@ -0,0 +1,42 @@
+-- Tabel broadcast lama; dibuat bila belum ada agar instalasi baru bisa berjalan dari migrasi
+CREATE TABLE IF NOT EXISTS notifications (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	judul VARCHAR(255) NOT NULL,
+	deskripsi TEXT,
+	target VARCHAR(255) NOT NULL DEFAULT '',
+	file_urls TEXT,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Sasaran notifikasi: user tertentu, role, jurusan, kelas, atau periode TA.
+-- Penerima = user yang disebut langsung ditambah user yang cocok dengan semua jenis sasaran lain
+-- (OR di dalam satu jenis, AND antarjenis).
+CREATE TABLE IF NOT EXISTS notifikasi_sasaran (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	notification_id INT NOT NULL,
+	jenis ENUM('user', 'role', 'jurusan', 'kelas', 'periode') NOT NULL,
+	nilai VARCHAR(100) NOT NULL,
+	INDEX idx_notifikasi_sasaran (notification_id),
+	FOREIGN KEY (notification_id) REFERENCES notifications(id) ON DELETE CASCADE
+);
+
+-- Satu baris per penerima, diisi saat notifikasi dikirim
+CREATE TABLE IF NOT EXISTS notifikasi_penerima (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	notification_id INT NOT NULL,
+	user_id INT NOT NULL,
+	status ENUM('belum_dibaca', 'dibaca', 'diarsipkan') NOT NULL DEFAULT 'belum_dibaca',
+	dibaca_at DATETIME NULL,
+	diarsipkan_at DATETIME NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE KEY uk_notifikasi_penerima (notification_id, user_id),
+	INDEX idx_notifikasi_penerima_user (user_id, status),
+	FOREIGN KEY (notification_id) REFERENCES notifications(id) ON DELETE CASCADE
+);
+
+-- Broadcast lama dibagikan ke pemegang role tujuannya dengan status sudah dibaca agar
+-- penghitung belum dibaca dimulai dari nol
+INSERT IGNORE INTO notifikasi_penerima (notification_id, user_id, status, dibaca_at, created_at)
+SELECT n.id, u.id, 'dibaca', n.created_at, n.created_at
+FROM notifications n
+JOIN users u ON FIND_IN_SET(u.role, n.target) > 0;
@@ -0,0 +1,8 @@
+-- Event domain yang sudah diolah. Pengirim mengirim ulang event (at-least-once), sehingga id event
+-- dicatat dalam transaksi yang sama dengan notifikasinya dan event dengan id yang sama diabaikan.
+CREATE TABLE IF NOT EXISTS event_diterima (
+	event_id VARCHAR(64) NOT NULL PRIMARY KEY,
+	tipe VARCHAR(64) NOT NULL,
+	sumber VARCHAR(64) NOT NULL DEFAULT '',
+	diterima_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
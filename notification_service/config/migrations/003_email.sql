@@ -0,0 +1,31 @@
+-- Preferensi email per user dan kategori (tipe event atau 'broadcast'). Tanpa baris berarti aktif.
+CREATE TABLE IF NOT EXISTS preferensi_email (
+	user_id INT NOT NULL,
+	kategori VARCHAR(64) NOT NULL,
+	aktif TINYINT(1) NOT NULL DEFAULT 1,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, kategori)
+);
+
+-- Antrean email notifikasi. Baris ditulis dalam transaksi yang sama dengan notifikasinya, lalu dikirim
+-- pekerja email dengan coba ulang berjeda. Email yang terus gagal berhenti di status 'gagal'.
+CREATE TABLE IF NOT EXISTS antrean_email (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	notification_id INT NOT NULL,
+	user_id INT NOT NULL,
+	kategori VARCHAR(64) NOT NULL,
+	alamat VARCHAR(255) NOT NULL,
+	subjek VARCHAR(255) NOT NULL,
+	isi_teks TEXT NOT NULL,
+	isi_html MEDIUMTEXT NOT NULL,
+	status ENUM('menunggu', 'terkirim', 'gagal') NOT NULL DEFAULT 'menunggu',
+	percobaan INT NOT NULL DEFAULT 0,
+	coba_lagi_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	galat_terakhir TEXT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	terkirim_at DATETIME NULL,
+	INDEX idx_antrean_email_antre (status, coba_lagi_at),
+	INDEX idx_antrean_email_notifikasi (notification_id),
+	INDEX idx_antrean_email_user (user_id),
+	FOREIGN KEY (notification_id) REFERENCES notifications(id) ON DELETE CASCADE
+);
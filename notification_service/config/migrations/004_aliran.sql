@@ -0,0 +1,7 @@
+-- Event asal notifikasi (kosong untuk broadcast) agar perubahan status dokumen bisa dikirim ulang
+-- lewat SSE setelah klien tersambung kembali
+ALTER TABLE notifications ADD COLUMN event_tipe VARCHAR(64) NULL;
+ALTER TABLE notifications ADD COLUMN event_data JSON NULL;
+
+-- id baris penerima naik terus per user dan dipakai sebagai id event SSE (Last-Event-ID)
+CREATE INDEX idx_notifikasi_penerima_aliran ON notifikasi_penerima (user_id, id);
@@ -0,0 +1,41 @@
+-- Notifikasi yang melewati kedaluwarsa_at tidak lagi tampil di inbox maupun daftar notifikasi
+ALTER TABLE notifications ADD COLUMN kedaluwarsa_at DATETIME NULL;
+
+-- Pengumuman terjadwal dan berulang. berikutnya_at adalah waktu kiriman berikutnya, NULL bila jadwal
+-- sudah selesai atau dibatalkan. email berisi email yang sudah disusun saat jadwal dibuat (NULL bila
+-- tidak dikirim lewat email). Pengulangan berhenti setelah sampai_at atau saat jendela pengumpulan
+-- sampai_jendela_id ditutup (dibaca ulang setiap kali kirim sehingga perpanjangan jendela ikut berlaku).
+CREATE TABLE IF NOT EXISTS jadwal_notifikasi (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	judul VARCHAR(255) NOT NULL,
+	deskripsi TEXT,
+	target VARCHAR(255) NOT NULL DEFAULT '',
+	file_urls TEXT,
+	sasaran JSON NOT NULL,
+	email JSON NULL,
+	kirim_at DATETIME NOT NULL,
+	ulang ENUM('sekali', 'harian', 'mingguan') NOT NULL DEFAULT 'sekali',
+	setiap INT NOT NULL DEFAULT 1,
+	sampai_at DATETIME NULL,
+	sampai_jendela_id INT NULL,
+	kedaluwarsa_at DATETIME NULL,
+	masa_berlaku_jam INT NULL,
+	status ENUM('aktif', 'selesai', 'dibatalkan') NOT NULL DEFAULT 'aktif',
+	berikutnya_at DATETIME NULL,
+	jumlah_kirim INT NOT NULL DEFAULT 0,
+	terakhir_kirim_at DATETIME NULL,
+	dibuat_oleh VARCHAR(255) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	INDEX idx_jadwal_notifikasi_antre (status, berikutnya_at)
+);
+
+-- Satu baris per kiriman jadwal. Kunci (jadwal_id, jadwal_at) memastikan setiap kiriman hanya dibuat
+-- sekali walaupun penjadwal berjalan ulang atau lebih dari satu instance.
+CREATE TABLE IF NOT EXISTS jadwal_kiriman (
+	jadwal_id INT NOT NULL,
+	jadwal_at DATETIME NOT NULL,
+	notification_id INT NOT NULL,
+	dikirim_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (jadwal_id, jadwal_at),
+	FOREIGN KEY (jadwal_id) REFERENCES jadwal_notifikasi(id) ON DELETE CASCADE
+);
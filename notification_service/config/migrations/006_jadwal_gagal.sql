@@ -0,0 +1,6 @@
+-- Jadwal yang gagal dijalankan dicoba ulang dengan jeda (coba_lagi_at) tanpa menahan jadwal lain.
+-- Jadwal yang datanya rusak atau terus gagal dipindah ke status gagal.
+ALTER TABLE jadwal_notifikasi MODIFY COLUMN status ENUM('aktif', 'selesai', 'dibatalkan', 'gagal') NOT NULL DEFAULT 'aktif';
+ALTER TABLE jadwal_notifikasi ADD COLUMN percobaan INT NOT NULL DEFAULT 0;
+ALTER TABLE jadwal_notifikasi ADD COLUMN coba_lagi_at DATETIME NULL;
+ALTER TABLE jadwal_notifikasi ADD COLUMN galat_terakhir TEXT NULL;
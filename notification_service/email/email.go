@@ -0,0 +1,191 @@
+// Package email mengirim email notifikasi lewat SMTP. Konfigurasi diambil dari env:
+//
+//	SMTP_HOST      host server SMTP; kosong berarti kanal email nonaktif
+//	SMTP_PORT      default 1025 (port bawaan MailHog untuk pengujian lokal)
+//	SMTP_USER      bila diisi, login dengan AUTH PLAIN memakai SMTP_PASSWORD
+//	SMTP_PASSWORD
+//	SMTP_FROM      alamat pengirim, default "SIMTA <no-reply@securesimta.my.id>"
+//
+// STARTTLS dipakai bila server mendukungnya, sehingga server uji tanpa TLS tetap bisa dipakai.
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+)
+
+const batasWaktu = 30 * time.Second
+
+// ErrPermanen menandai email yang ditolak server secara permanen (balasan 5xx atau alamat tidak
+// valid); mengirim ulang tidak berguna
+var ErrPermanen = errors.New("email ditolak permanen")
+
+// Pesan adalah satu email dengan isi teks dan HTML
+type Pesan struct {
+	Ke     string
+	Subjek string
+	Teks   string
+	HTML   string
+}
+
+// Aktif mengecek apakah kanal email dikonfigurasi
+func Aktif() bool {
+	return os.Getenv("SMTP_HOST") != ""
+}
+
+// Kirim mengirim satu pesan ke server SMTP
+func Kirim(p Pesan) error {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return errors.New("SMTP_HOST tidak diatur")
+	}
+	dari, err := mail.ParseAddress(getEnv("SMTP_FROM", "SIMTA <no-reply@securesimta.my.id>"))
+	if err != nil {
+		return fmt.Errorf("SMTP_FROM tidak valid: %v", err)
+	}
+	ke, err := mail.ParseAddress(p.Ke)
+	if err != nil {
+		return fmt.Errorf("%w: alamat %q tidak valid", ErrPermanen, p.Ke)
+	}
+	p.Ke = ke.Address
+
+	isi, err := susunPesan(dari, p)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, getEnv("SMTP_PORT", "1025")), batasWaktu)
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(batasWaktu))
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if user := os.Getenv("SMTP_USER"); user != "" {
+		if err := c.Auth(smtp.PlainAuth("", user, os.Getenv("SMTP_PASSWORD"), host)); err != nil {
+			return klasifikasi(err)
+		}
+	}
+	if err := c.Mail(dari.Address); err != nil {
+		return klasifikasi(err)
+	}
+	if err := c.Rcpt(p.Ke); err != nil {
+		return klasifikasi(err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return klasifikasi(err)
+	}
+	if _, err := w.Write(isi); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return klasifikasi(err)
+	}
+	return c.Quit()
+}
+
+// klasifikasi membungkus balasan 5xx dari server sebagai ErrPermanen
+func klasifikasi(err error) error {
+	var balasan *textproto.Error
+	if errors.As(err, &balasan) && balasan.Code >= 500 {
+		return fmt.Errorf("%w: %v", ErrPermanen, err)
+	}
+	return err
+}
+
+// susunPesan menyusun pesan MIME multipart/alternative berisi bagian teks dan HTML
+func susunPesan(dari *mail.Address, p Pesan) ([]byte, error) {
+	var buf bytes.Buffer
+	mp := multipart.NewWriter(&buf)
+
+	header := []string{
+		"From: " + dari.String(),
+		"To: " + p.Ke,
+		"Subject: " + mime.QEncoding.Encode("utf-8", p.Subjek),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: <" + idPesan() + "@" + domain(dari.Address) + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + mp.Boundary(),
+	}
+	var pesan bytes.Buffer
+	pesan.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	for _, bagian := range []struct{ tipe, isi string }{
+		{"text/plain", p.Teks},
+		{"text/html", p.HTML},
+	} {
+		if bagian.isi == "" {
+			continue
+		}
+		w, err := mp.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {bagian.tipe + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := tulisQP(w, bagian.isi); err != nil {
+			return nil, err
+		}
+	}
+	if err := mp.Close(); err != nil {
+		return nil, err
+	}
+	pesan.Write(buf.Bytes())
+	return pesan.Bytes(), nil
+}
+
+func tulisQP(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	// mode teks: setiap baris baru ditulis sebagai CRLF
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func idPesan() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func domain(alamat string) string {
+	if i := strings.LastIndex(alamat, "@"); i >= 0 {
+		return alamat[i+1:]
+	}
+	return "localhost"
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"notification_service/aliran"
+	"notification_service/config"
+	"notification_service/models"
+	"notification_service/utils"
+	"strconv"
+	"time"
+)
+
+const (
+	// detakAliran adalah jeda komentar heartbeat agar proxy tidak memutus koneksi yang diam
+	detakAliran = 25 * time.Second
+	// batasReplayAliran adalah jumlah maksimum event yang dikirim ulang saat tersambung kembali;
+	// bila lebih, klien diminta memuat ulang inbox lewat event "sinkron"
+	batasReplayAliran = 200
+	// jedaSambungUlang dikirim sebagai retry: agar EventSource menunggu sebelum tersambung ulang (ms)
+	jedaSambungUlang = 5000
+)
+
+// Aliran mengirim notifikasi inbox baru dan perubahan status dokumen milik user yang login secara
+// real-time (GET /stream, Server-Sent Events). EventSource tidak dapat mengirim header, sehingga token
+// boleh dikirim lewat ?token=. Saat tersambung kembali dengan Last-Event-ID (atau ?last_event_id=),
+// event setelahnya dikirim ulang dari database. Id event adalah id baris inbox; klien sebaiknya
+// mengabaikan notifikasi yang sudah ditampilkan.
+func Aliran(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming tidak didukung", http.StatusInternalServerError)
+		return
+	}
+	token := utils.BearerToken(r)
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	idTerakhir := r.Header.Get("Last-Event-ID")
+	if idTerakhir == "" {
+		idTerakhir = r.URL.Query().Get("last_event_id")
+	}
+	var setelah int64
+	if idTerakhir != "" {
+		n, err := strconv.ParseInt(idTerakhir, 10, 64)
+		if err != nil || n < 0 {
+			http.Error(w, "Last-Event-ID tidak valid", http.StatusBadRequest)
+			return
+		}
+		setelah = n
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	inbox := models.NewInboxModel(db)
+	userID, ok := penerimaDari(w, token, inbox)
+	if !ok {
+		db.Close()
+		return
+	}
+
+	// Berlangganan sebelum membaca database agar tidak ada notifikasi yang jatuh di antara keduanya
+	langganan := aliran.Pusat.Langgan(userID)
+	defer aliran.Pusat.Berhenti(langganan)
+
+	var replay []models.ItemAliran
+	sinkron := false
+	if idTerakhir != "" {
+		replay, err = inbox.AliranUser(userID, setelah, batasReplayAliran+1)
+		if err == nil && len(replay) > batasReplayAliran {
+			replay, sinkron = nil, true
+		}
+	}
+	if err == nil && (idTerakhir == "" || sinkron) {
+		setelah, err = inbox.IDAliranTerakhir(userID)
+	}
+	// Koneksi database tidak ditahan selama stream berjalan
+	db.Close()
+	if err != nil {
+		log.Printf("❌ Gagal memuat inbox user %d untuk SSE: %v", userID, err)
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", jedaSambungUlang)
+
+	terkirim := map[int64]bool{}
+	switch {
+	case sinkron:
+		tulisEventAliran(w, aliran.Pesan{ID: setelah, Event: aliran.EventSinkron, Data: []byte("{}")})
+	case idTerakhir == "":
+		tulisEventAliran(w, aliran.Pesan{ID: setelah, Event: aliran.EventTerhubung, Data: []byte("{}")})
+	}
+	for _, a := range replay {
+		tulisEventAliran(w, aliran.PesanDari(a))
+		terkirim[a.ID] = true
+	}
+	flusher.Flush()
+
+	detak := time.NewTicker(detakAliran)
+	defer detak.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-langganan.Putus:
+			// buffer penuh: klien akan tersambung ulang dan menyusul lewat Last-Event-ID
+			return
+		case p := <-langganan.Pesan:
+			if terkirim[p.ID] {
+				continue
+			}
+			tulisEventAliran(w, p)
+			flusher.Flush()
+		case <-detak.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func tulisEventAliran(w http.ResponseWriter, p aliran.Pesan) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", p.ID, p.Event, p.Data)
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"notification_service/config"
+	"notification_service/jobs"
+	"notification_service/models"
+	"notification_service/utils"
+	"notification_service/utils/halaman"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// hanyaAdmin memastikan token milik akun admin yang masih aktif; respons 401/403 bila bukan
+func hanyaAdmin(w http.ResponseWriter, r *http.Request) bool {
+	claims, err := utils.ParseJWT(utils.BearerToken(r))
+	if err != nil {
+		http.Error(w, "Sesi tidak valid, silakan login kembali", http.StatusUnauthorized)
+		return false
+	}
+	if strings.ToLower(claims.Role) != "admin" {
+		http.Error(w, "Hanya admin yang dapat mengakses fitur ini", http.StatusForbidden)
+		return false
+	}
+
+	// Token tetap berlaku sampai kedaluwarsa walaupun akunnya dinonaktifkan
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return false
+	}
+	defer db.Close()
+	if _, err := models.NewInboxModel(db).UserIDDariEmail(claims.Email); err == sql.ErrNoRows {
+		http.Error(w, "Akun Anda telah dinonaktifkan", http.StatusUnauthorized)
+		return false
+	} else if err != nil {
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// GetPreferensiEmail menampilkan kategori email yang diterima user yang login
+func GetPreferensiEmail(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	userID, ok := penerimaDariToken(w, r, models.NewInboxModel(db))
+	if !ok {
+		return
+	}
+
+	list, err := models.NewEmailModel(db).Preferensi(userID)
+	if err != nil {
+		log.Printf("❌ Gagal mengambil preferensi email user %d: %v", userID, err)
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// SimpanPreferensiEmail mengubah kategori email yang diterima user yang login.
+// Body berupa objek kategori → aktif, mis. {"document.submitted": false, "broadcast": true};
+// kategori yang tidak disebut tidak berubah.
+func SimpanPreferensiEmail(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var pilihan map[string]bool
+	if err := json.NewDecoder(r.Body).Decode(&pilihan); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	for kategori := range pilihan {
+		if !models.IsValidKategoriEmail(kategori) {
+			http.Error(w, "Kategori email tidak dikenali: "+kategori, http.StatusBadRequest)
+			return
+		}
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	userID, ok := penerimaDariToken(w, r, models.NewInboxModel(db))
+	if !ok {
+		return
+	}
+
+	model := models.NewEmailModel(db)
+	if err := model.SimpanPreferensi(userID, pilihan); err != nil {
+		log.Printf("❌ Gagal menyimpan preferensi email user %d: %v", userID, err)
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+	list, err := model.Preferensi(userID)
+	if err != nil {
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status":  "success",
+		"message": "Preferensi email disimpan",
+		"data":    list,
+	})
+}
+
+// GetAntreanEmail menampilkan status pengiriman email untuk admin
+// (GET ?status=menunggu|terkirim|gagal&notification_id=). Mendukung page/limit/cursor, sort, dan q
+// (alamat atau subjek).
+func GetAntreanEmail(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !hanyaAdmin(w, r) {
+		return
+	}
+	q := r.URL.Query()
+	status := q.Get("status")
+	switch status {
+	case "", models.EmailMenunggu, models.EmailTerkirim, models.EmailGagal:
+	default:
+		http.Error(w, "status tidak valid", http.StatusBadRequest)
+		return
+	}
+	var notificationID int
+	if v := q.Get("notification_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "notification_id tidak valid", http.StatusBadRequest)
+			return
+		}
+		notificationID = n
+	}
+	k, err := halaman.Parse(r, models.AturanAntreanEmail)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	list, total, err := models.NewEmailModel(db).GetAntrean(status, notificationID, k)
+	if err != nil {
+		log.Printf("❌ Gagal mengambil antrean email: %v", err)
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status": "success",
+		"data":   list,
+		"meta":   k.Meta(total),
+	})
+}
+
+// AntrekanUlangEmail mengirim ulang email yang berstatus gagal (POST /email/antrean/{id}/ulang)
+func AntrekanUlangEmail(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !hanyaAdmin(w, r) {
+		return
+	}
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	if err := models.NewEmailModel(db).AntrekanUlang(id); err == sql.ErrNoRows {
+		http.Error(w, "Email gagal tidak ditemukan", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+	jobs.SegerakanEmail()
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status":  "success",
+		"message": "Email diantrekan untuk dikirim ulang",
+	})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"notification_service/models"
+	"os"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// dataEmail adalah nilai yang dapat dipakai di templat email; field dataTemplat dapat dipakai langsung
+type dataEmail struct {
+	dataTemplat
+	Judul     string // judul notifikasi, juga subjek email
+	Deskripsi string // isi broadcast
+	Lampiran  []lampiranEmail
+	Tautan    string // alamat aplikasi
+}
+
+type lampiranEmail struct {
+	Nama string
+	URL  string
+}
+
+type templatEmail struct {
+	teks *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// layoutEmail membungkus isi HTML tiap templat; isi didefinisikan sebagai templat "isi"
+var layoutEmail = htmltemplate.Must(htmltemplate.New("layout").Parse(`<!DOCTYPE html>
+<html lang="id">
+<head><meta charset="utf-8"><title>{{.Judul}}</title></head>
+<body style="margin:0;padding:0;background:#f4f5f7;font-family:Arial,Helvetica,sans-serif;color:#1f2933;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0"><tr><td align="center" style="padding:24px;">
+<table role="presentation" width="600" cellpadding="0" cellspacing="0" style="background:#ffffff;border-radius:6px;">
+<tr><td style="padding:20px 24px;background:#1e3a8a;color:#ffffff;font-size:18px;font-weight:bold;">SIMTA</td></tr>
+<tr><td style="padding:24px;font-size:14px;line-height:1.6;">
+<h2 style="margin:0 0 16px;font-size:18px;">{{.Judul}}</h2>
+{{template "isi" .}}
+<p style="margin:24px 0 0;"><a href="{{.Tautan}}" style="display:inline-block;padding:10px 18px;background:#1e3a8a;color:#ffffff;text-decoration:none;border-radius:4px;">Buka SIMTA</a></p>
+</td></tr>
+<tr><td style="padding:16px 24px;font-size:12px;color:#6b7280;border-top:1px solid #e5e7eb;">Email ini dikirim otomatis oleh SIMTA. Anda dapat mengatur email yang ingin diterima melalui menu preferensi notifikasi.</td></tr>
+</table>
+</td></tr></table>
+</body>
+</html>`))
+
+// penutupTeks ditambahkan di akhir setiap email teks
+const penutupTeks = `
+
+Buka SIMTA: {{.Tautan}}
+
+--
+Email ini dikirim otomatis oleh SIMTA. Anda dapat mengatur email yang ingin diterima melalui menu preferensi notifikasi.`
+
+func templatEmailBaru(nama, teks, html string) templatEmail {
+	return templatEmail{
+		teks: texttemplate.Must(texttemplate.New(nama).Parse(teks + penutupTeks)),
+		html: htmltemplate.Must(htmltemplate.Must(layoutEmail.Clone()).New("isi").Parse(html)),
+	}
+}
+
+// templatEmailEvent berisi templat email per tipe event dan jenis penerima (kunci sama dengan
+// templatEvent), ditambah kunci "broadcast" untuk pengumuman admin
+var templatEmailEvent = map[string]templatEmail{
+	"document.submitted/dosen": templatEmailBaru("document.submitted/dosen",
+		`Yth. Bapak/Ibu Dosen,
+
+{{.NamaTaruna}} telah mengunggah {{.Dokumen}}{{if .Topik}} dengan topik "{{.Topik}}"{{end}}.
+Silakan lakukan review melalui SIMTA.`,
+		`<p>Yth. Bapak/Ibu Dosen,</p>
+<p><strong>{{.NamaTaruna}}</strong> telah mengunggah {{.Dokumen}}{{if .Topik}} dengan topik <em>"{{.Topik}}"</em>{{end}}.</p>
+<p>Silakan lakukan review melalui SIMTA.</p>`),
+
+	"review.uploaded/taruna": templatEmailBaru("review.uploaded/taruna",
+		`Halo {{.NamaTaruna}},
+
+Dosen telah mengunggah hasil review {{.Dokumen}} Anda{{if .Topik}} ("{{.Topik}}"){{end}}.
+Silakan periksa catatan review dan tindak lanjuti.`,
+		`<p>Halo {{.NamaTaruna}},</p>
+<p>Dosen telah mengunggah hasil review {{.Dokumen}} Anda{{if .Topik}} (<em>"{{.Topik}}"</em>){{end}}.</p>
+<p>Silakan periksa catatan review dan tindak lanjuti.</p>`),
+
+	"status.changed/taruna": templatEmailBaru("status.changed/taruna",
+		`Halo {{.NamaTaruna}},
+
+Status {{.Dokumen}} Anda{{if .Topik}} ("{{.Topik}}"){{end}} kini {{.Status}}.`,
+		`<p>Halo {{.NamaTaruna}},</p>
+<p>Status {{.Dokumen}} Anda{{if .Topik}} (<em>"{{.Topik}}"</em>){{end}} kini <strong>{{.Status}}</strong>.</p>`),
+
+	"examiner.assigned/dosen": templatEmailBaru("examiner.assigned/dosen",
+		`Yth. Bapak/Ibu Dosen,
+
+Anda ditugaskan sebagai {{.Peran}} pada {{.Kegiatan}} taruna {{.NamaTaruna}}.`,
+		`<p>Yth. Bapak/Ibu Dosen,</p>
+<p>Anda ditugaskan sebagai <strong>{{.Peran}}</strong> pada {{.Kegiatan}} taruna <strong>{{.NamaTaruna}}</strong>.</p>`),
+
+	"examiner.assigned/taruna": templatEmailBaru("examiner.assigned/taruna",
+		`Halo {{.NamaTaruna}},
+
+Dosen yang ditugaskan pada {{.Kegiatan}} Anda:{{range .Penugasan}}
+- {{.Peran}}: {{.NamaDosen}}{{end}}`,
+		`<p>Halo {{.NamaTaruna}},</p>
+<p>Dosen yang ditugaskan pada {{.Kegiatan}} Anda:</p>
+<ul>{{range .Penugasan}}<li>{{.Peran}}: <strong>{{.NamaDosen}}</strong></li>{{end}}</ul>`),
+
+	models.KategoriBroadcast: templatEmailBaru(models.KategoriBroadcast,
+		`{{.Judul}}
+
+{{.Deskripsi}}{{if .Lampiran}}
+
+Lampiran:{{range .Lampiran}}
+- {{.Nama}}: {{.URL}}{{end}}{{end}}`,
+		`<div style="white-space:pre-line;">{{.Deskripsi}}</div>{{if .Lampiran}}
+<p style="margin:16px 0 4px;"><strong>Lampiran:</strong></p>
+<ul>{{range .Lampiran}}<li><a href="{{.URL}}">{{.Nama}}</a></li>{{end}}</ul>{{end}}`),
+}
+
+// renderEmail mengisi templat email dengan kunci tertentu; kategori menentukan preferensi penerima
+func renderEmail(kunci, kategori string, data dataEmail) (*models.EmailNotifikasi, error) {
+	t, ok := templatEmailEvent[kunci]
+	if !ok {
+		return nil, fmt.Errorf("templat email %s tidak ada", kunci)
+	}
+	if data.Tautan == "" {
+		data.Tautan = tautanAplikasi()
+	}
+	var teks, html bytes.Buffer
+	if err := t.teks.Execute(&teks, data); err != nil {
+		return nil, err
+	}
+	if err := t.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return nil, err
+	}
+	return &models.EmailNotifikasi{Kategori: kategori, Subjek: data.Judul, Teks: teks.String(), HTML: html.String()}, nil
+}
+
+// tautanAplikasi adalah alamat frontend yang dicantumkan di email (env APP_URL)
+func tautanAplikasi() string {
+	if v := os.Getenv("APP_URL"); v != "" {
+		return v
+	}
+	return "https://securesimta.my.id"
+}
+
+// lampiranDariURL mengubah path file broadcast (/uploads/<nama>) menjadi tautan unduhan publik.
+// Alamat dasar diambil dari env NOTIFICATION_PUBLIC_URL, default APP_URL.
+func lampiranDariURL(fileURLs []string) []lampiranEmail {
+	dasar := os.Getenv("NOTIFICATION_PUBLIC_URL")
+	if dasar == "" {
+		dasar = tautanAplikasi()
+	}
+	dasar = strings.TrimRight(dasar, "/")
+
+	lampiran := make([]lampiranEmail, 0, len(fileURLs))
+	for _, u := range fileURLs {
+		nama := path.Base(u)
+		lampiran = append(lampiran, lampiranEmail{Nama: nama, URL: dasar + "/download/" + nama})
+	}
+	return lampiran
+}
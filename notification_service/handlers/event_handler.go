@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"notification_service/aliran"
+	"notification_service/config"
+	"notification_service/email"
+	"notification_service/jobs"
+	"notification_service/models"
+	"os"
+)
+
+// TerimaEvent menerima event domain dari layanan lain (POST /events) dan mengubahnya menjadi
+// notifikasi inbox. Header X-Event-Token harus sama dengan EVENT_SECRET; selama EVENT_SECRET belum
+// diatur semua event ditolak (503) sehingga pengirim menyimpannya di outbox dan mencoba lagi nanti.
+// Pengirim bisa mengirim event yang sama lebih dari sekali; event dengan id yang sudah diterima
+// dijawab sukses dengan duplikat=true tanpa membuat notifikasi baru.
+func TerimaEvent(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	secret := os.Getenv("EVENT_SECRET")
+	if secret == "" {
+		log.Printf("❌ Event ditolak: EVENT_SECRET belum diatur")
+		http.Error(w, "Penerimaan event belum dikonfigurasi", http.StatusServiceUnavailable)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Event-Token")), []byte(secret)) != 1 {
+		http.Error(w, "Token event tidak valid", http.StatusUnauthorized)
+		return
+	}
+
+	var e models.Event
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if e.ID == "" || len(e.ID) > 64 {
+		http.Error(w, "id event wajib diisi (maks. 64 karakter)", http.StatusBadRequest)
+		return
+	}
+	if !models.IsValidTipeEvent(e.Tipe) {
+		http.Error(w, "Tipe event tidak dikenali", http.StatusBadRequest)
+		return
+	}
+	if e.Data.TarunaUserID == 0 {
+		http.Error(w, "taruna_user_id wajib diisi", http.StatusBadRequest)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	model := models.NewEventModel(db)
+	notifikasi, err := susunNotifikasiEvent(model, &e)
+	if err != nil {
+		log.Printf("❌ Gagal mengolah event %s (%s): %v", e.Tipe, e.ID, err)
+		http.Error(w, "Gagal mengolah event", http.StatusInternalServerError)
+		return
+	}
+
+	hasil, duplikat, err := model.KirimEvent(&e, notifikasi)
+	if err != nil {
+		log.Printf("❌ Gagal menyimpan notifikasi event %s (%s): %v", e.Tipe, e.ID, err)
+		http.Error(w, "Gagal menyimpan notifikasi", http.StatusInternalServerError)
+		return
+	}
+
+	var total, jumlahEmail int64
+	ids := make([]int64, len(hasil))
+	for i, h := range hasil {
+		total += h.Penerima
+		jumlahEmail += h.Email
+		ids[i] = h.ID
+	}
+	if jumlahEmail > 0 {
+		jobs.SegerakanEmail()
+	}
+	aliran.Siarkan(db, ids...)
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status": "success",
+		"data":   map[string]any{"penerima": total, "duplikat": duplikat},
+	})
+}
+
+// susunNotifikasiEvent menentukan penerima dan isi notifikasi untuk satu event, beserta emailnya bila
+// kanal email aktif:
+//
+//	document.submitted  dosen tujuan dokumen dan pembimbing aktif taruna
+//	review.uploaded     taruna pemilik dokumen
+//	status.changed      taruna pemilik dokumen
+//	examiner.assigned   tiap dosen yang ditugaskan, dan taruna (daftar seluruh dosen)
+func susunNotifikasiEvent(m *models.EventModel, e *models.Event) ([]models.NotifikasiEvent, error) {
+	d := e.Data
+	namaTaruna, err := m.NamaUser(d.TarunaUserID)
+	if err != nil {
+		return nil, err
+	}
+	data := dataTemplat{
+		NamaTaruna: namaTaruna,
+		Dokumen:    d.Dokumen,
+		Topik:      d.Topik,
+		Status:     labelAtauAsli(labelStatus, d.Status, "diperbarui"),
+		Kegiatan:   labelAtauAsli(kegiatanPenugasan, d.Jenis, "tugas akhir"),
+	}
+	if data.Dokumen == "" {
+		data.Dokumen = "dokumen"
+	}
+
+	satu := func(penerima string, ids []int) ([]models.NotifikasiEvent, error) {
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		judul, isi, err := renderEvent(e.Tipe, penerima, data)
+		if err != nil {
+			return nil, err
+		}
+		n := models.NotifikasiEvent{Penerima: ids, Judul: judul, Isi: isi}
+		if email.Aktif() {
+			if n.Email, err = renderEmail(e.Tipe+"/"+penerima, e.Tipe, dataEmail{dataTemplat: data, Judul: judul}); err != nil {
+				return nil, err
+			}
+		}
+		return []models.NotifikasiEvent{n}, nil
+	}
+
+	switch e.Tipe {
+	case models.EventDokumenDiunggah:
+		pembimbing, err := m.PembimbingAktif(d.TarunaUserID)
+		if err != nil {
+			return nil, err
+		}
+		return satu(penerimaDosen, unik(append(append([]int{}, d.DosenUserIDs...), pembimbing...)))
+
+	case models.EventReviewDiunggah, models.EventStatusBerubah:
+		return satu(penerimaTaruna, []int{d.TarunaUserID})
+
+	case models.EventPengujiDitugaskan:
+		var hasil []models.NotifikasiEvent
+		for _, p := range d.Penugasan {
+			namaDosen, err := m.NamaUser(p.DosenUserID)
+			if err != nil {
+				return nil, err
+			}
+			data.Penugasan = append(data.Penugasan, penugasanTemplat{Peran: p.Peran, NamaDosen: namaDosen})
+
+			data.Peran = p.Peran
+			n, err := satu(penerimaDosen, []int{p.DosenUserID})
+			if err != nil {
+				return nil, err
+			}
+			hasil = append(hasil, n...)
+		}
+		data.Peran = ""
+		n, err := satu(penerimaTaruna, []int{d.TarunaUserID})
+		if err != nil {
+			return nil, err
+		}
+		return append(hasil, n...), nil
+	}
+	return nil, nil
+}
+
+func unik(ids []int) []int {
+	ada := map[int]bool{}
+	var hasil []int
+	for _, id := range ids {
+		if id != 0 && !ada[id] {
+			ada[id] = true
+			hasil = append(hasil, id)
+		}
+	}
+	return hasil
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// dataTemplat adalah nilai yang dapat dipakai di templat notifikasi event
+type dataTemplat struct {
+	NamaTaruna string
+	Dokumen    string // label tahap, mis. "final proposal"
+	Topik      string
+	Status     string // label status dalam bahasa Indonesia
+	Peran      string // peran dosen penerima pada event penugasan
+	Kegiatan   string // kegiatan penugasan, mis. "seminar proposal"
+	Penugasan  []penugasanTemplat
+}
+
+type penugasanTemplat struct {
+	Peran     string
+	NamaDosen string
+}
+
+type templatNotifikasi struct {
+	judul, isi *template.Template
+}
+
+func templat(nama, judul, isi string) templatNotifikasi {
+	return templatNotifikasi{
+		judul: template.Must(template.New(nama + "/judul").Parse(judul)),
+		isi:   template.Must(template.New(nama + "/isi").Parse(isi)),
+	}
+}
+
+// Penerima notifikasi event
+const (
+	penerimaDosen  = "dosen"
+	penerimaTaruna = "taruna"
+)
+
+// templatEvent berisi templat per tipe event dan jenis penerima (kunci "<tipe>/<penerima>")
+var templatEvent = map[string]templatNotifikasi{
+	"document.submitted/dosen": templat("document.submitted/dosen",
+		`Unggahan {{.Dokumen}} dari {{.NamaTaruna}}`,
+		`{{.NamaTaruna}} telah mengunggah {{.Dokumen}}{{if .Topik}} dengan topik "{{.Topik}}"{{end}}. Silakan lakukan review.`),
+	"review.uploaded/taruna": templat("review.uploaded/taruna",
+		`Hasil review {{.Dokumen}} tersedia`,
+		`Dosen telah mengunggah hasil review {{.Dokumen}} Anda{{if .Topik}} ("{{.Topik}}"){{end}}. Silakan periksa dan tindak lanjuti.`),
+	"status.changed/taruna": templat("status.changed/taruna",
+		`Status {{.Dokumen}}: {{.Status}}`,
+		`Status {{.Dokumen}} Anda{{if .Topik}} ("{{.Topik}}"){{end}} kini {{.Status}}.`),
+	"examiner.assigned/dosen": templat("examiner.assigned/dosen",
+		`Penugasan baru sebagai {{.Peran}}`,
+		`Anda ditugaskan sebagai {{.Peran}} pada {{.Kegiatan}} taruna {{.NamaTaruna}}.`),
+	"examiner.assigned/taruna": templat("examiner.assigned/taruna",
+		`Dosen {{.Kegiatan}} telah ditetapkan`,
+		`Dosen yang ditugaskan pada {{.Kegiatan}} Anda:{{range .Penugasan}}
+- {{.Peran}}: {{.NamaDosen}}{{end}}`),
+}
+
+// renderEvent mengisi templat tipe event untuk jenis penerima tertentu
+func renderEvent(tipe, penerima string, data dataTemplat) (string, string, error) {
+	t, ok := templatEvent[tipe+"/"+penerima]
+	if !ok {
+		return "", "", fmt.Errorf("templat %s/%s tidak ada", tipe, penerima)
+	}
+	var judul, isi bytes.Buffer
+	if err := t.judul.Execute(&judul, data); err != nil {
+		return "", "", err
+	}
+	if err := t.isi.Execute(&isi, data); err != nil {
+		return "", "", err
+	}
+	return judul.String(), isi.String(), nil
+}
+
+// labelStatus menerjemahkan status dokumen untuk pesan ke taruna
+var labelStatus = map[string]string{
+	"approved":  "disetujui",
+	"rejected":  "ditolak",
+	"on review": "sedang direview",
+	"pending":   "menunggu review",
+}
+
+// kegiatanPenugasan memetakan jenis penugasan ke kegiatan yang disebut di pesan
+var kegiatanPenugasan = map[string]string{
+	"dosbing_proposal":   "bimbingan tugas akhir",
+	"dosbing_pendamping": "bimbingan tugas akhir",
+	"penelaah_icp":       "penelaahan ICP",
+	"penguji_proposal":   "seminar proposal",
+	"penguji_laporan70":  "seminar laporan 70%",
+	"penguji_laporan100": "sidang laporan 100%",
+}
+
+func labelAtauAsli(label map[string]string, v, kosong string) string {
+	if l, ok := label[v]; ok {
+		return l
+	}
+	if v == "" {
+		return kosong
+	}
+	return v
+}
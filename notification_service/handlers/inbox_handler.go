@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"notification_service/config"
+	"notification_service/models"
+	"notification_service/utils"
+	"notification_service/utils/halaman"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// penerimaDariToken menentukan user pemilik inbox dari Authorization: Bearer <token>; respons 401 bila
+// token tidak valid atau akunnya tidak aktif
+func penerimaDariToken(w http.ResponseWriter, r *http.Request, inbox *models.InboxModel) (int, bool) {
+	return penerimaDari(w, utils.BearerToken(r), inbox)
+}
+
+func penerimaDari(w http.ResponseWriter, token string, inbox *models.InboxModel) (int, bool) {
+	claims, err := utils.ParseJWT(token)
+	if err != nil {
+		http.Error(w, "Sesi tidak valid, silakan login kembali", http.StatusUnauthorized)
+		return 0, false
+	}
+	userID, err := inbox.UserIDDariEmail(claims.Email)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Akun tidak ditemukan atau dinonaktifkan", http.StatusUnauthorized)
+		return 0, false
+	}
+	if err != nil {
+		log.Printf("❌ Gagal mencari user %s: %v", claims.Email, err)
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return 0, false
+	}
+	return userID, true
+}
+
+// GetInbox menampilkan notifikasi milik user yang login (GET ?status=belum_dibaca|dibaca|diarsipkan|semua).
+// Tanpa status, notifikasi yang diarsipkan tidak ditampilkan. Mendukung page/limit/cursor, sort, dan q.
+func GetInbox(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.StatusBelumDibaca, models.StatusDibaca, models.StatusDiarsipkan, models.StatusSemua:
+	default:
+		http.Error(w, "status tidak valid", http.StatusBadRequest)
+		return
+	}
+	k, err := halaman.Parse(r, models.AturanInbox)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	inbox := models.NewInboxModel(db)
+	userID, ok := penerimaDariToken(w, r, inbox)
+	if !ok {
+		return
+	}
+
+	list, total, err := inbox.GetInbox(userID, status, k)
+	if err != nil {
+		log.Printf("❌ Gagal mengambil inbox user %d: %v", userID, err)
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status": "success",
+		"data":   list,
+		"meta":   k.Meta(total),
+	})
+}
+
+// GetInboxBelumDibaca menampilkan jumlah notifikasi yang belum dibaca untuk lencana di navbar
+func GetInboxBelumDibaca(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	inbox := models.NewInboxModel(db)
+	userID, ok := penerimaDariToken(w, r, inbox)
+	if !ok {
+		return
+	}
+
+	n, err := inbox.HitungBelumDibaca(userID)
+	if err != nil {
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status": "success",
+		"data":   map[string]int{"belum_dibaca": n},
+	})
+}
+
+// TandaiInboxDibaca menandai satu notifikasi sebagai dibaca (POST /inbox/{id}/read)
+func TandaiInboxDibaca(w http.ResponseWriter, r *http.Request) {
+	ubahStatusInbox(w, r, models.StatusDibaca, "Notifikasi ditandai dibaca")
+}
+
+// ArsipkanInbox memindahkan satu notifikasi ke arsip (POST /inbox/{id}/archive)
+func ArsipkanInbox(w http.ResponseWriter, r *http.Request) {
+	ubahStatusInbox(w, r, models.StatusDiarsipkan, "Notifikasi diarsipkan")
+}
+
+func ubahStatusInbox(w http.ResponseWriter, r *http.Request, status, pesan string) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	inbox := models.NewInboxModel(db)
+	userID, ok := penerimaDariToken(w, r, inbox)
+	if !ok {
+		return
+	}
+
+	if err := inbox.UbahStatus(userID, id, status); err == sql.ErrNoRows {
+		http.Error(w, "Notifikasi tidak ditemukan", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status":  "success",
+		"message": pesan,
+	})
+}
+
+// TandaiSemuaInboxDibaca menandai seluruh notifikasi yang belum dibaca milik user yang login
+func TandaiSemuaInboxDibaca(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	inbox := models.NewInboxModel(db)
+	userID, ok := penerimaDariToken(w, r, inbox)
+	if !ok {
+		return
+	}
+
+	n, err := inbox.TandaiSemuaDibaca(userID)
+	if err != nil {
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status":  "success",
+		"message": "Semua notifikasi ditandai dibaca",
+		"data":    map[string]int64{"diperbarui": n},
+	})
+}
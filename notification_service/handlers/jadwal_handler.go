@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"notification_service/config"
+	"notification_service/models"
+	"notification_service/utils/halaman"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// wib adalah zona waktu input jadwal (UTC+7, tanpa daylight saving)
+var wib = time.FixedZone("WIB", 7*60*60)
+
+// Format waktu yang diterima dari form broadcast, ditafsirkan sebagai WIB
+var waktuInputLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+}
+
+func parseWaktuWIB(field, value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range waktuInputLayouts {
+		if t, err := time.ParseInLocation(layout, value, wib); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("format %s tidak valid: %s", field, value)
+}
+
+func parseAngkaPositif(field, value string) (*int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("%s harus berupa angka lebih dari 0", field)
+	}
+	return &n, nil
+}
+
+// aturanKirim adalah pengaturan waktu kirim, pengulangan, dan kedaluwarsa dari form broadcast
+type aturanKirim struct {
+	kirimAt         *time.Time // nil berarti dikirim sekarang
+	ulang           string
+	setiap          int
+	sampaiAt        *time.Time
+	sampaiJendelaID *int
+	kedaluwarsaAt   *time.Time
+	masaBerlakuJam  *int
+}
+
+// terjadwal bernilai true bila notifikasi disimpan sebagai jadwal, bukan dikirim langsung
+func (a aturanKirim) terjadwal() bool {
+	return a.kirimAt != nil || a.ulang != models.UlangSekali
+}
+
+// kedaluwarsaLangsung menghitung batas tampil notifikasi yang dikirim sekarang
+func (a aturanKirim) kedaluwarsaLangsung(sekarang time.Time) *time.Time {
+	batas := a.kedaluwarsaAt
+	if a.masaBerlakuJam != nil {
+		t := sekarang.Add(time.Duration(*a.masaBerlakuJam) * time.Hour)
+		if batas == nil || t.Before(*batas) {
+			batas = &t
+		}
+	}
+	return batas
+}
+
+// aturanKirimDariForm membaca field opsional kirim_at, ulang (sekali|harian|mingguan), setiap, sampai,
+// sampai_jendela_id, kedaluwarsa_at, dan masa_berlaku_jam. kirim_at yang sudah lewat pada notifikasi
+// sekali kirim berarti dikirim sekarang.
+func aturanKirimDariForm(r *http.Request, sekarang time.Time) (aturanKirim, error) {
+	a := aturanKirim{ulang: strings.TrimSpace(r.FormValue("ulang")), setiap: 1}
+	switch a.ulang {
+	case "":
+		a.ulang = models.UlangSekali
+	case models.UlangSekali, models.UlangHarian, models.UlangMingguan:
+	default:
+		return a, fmt.Errorf("ulang harus sekali, harian, atau mingguan")
+	}
+
+	var err error
+	if a.kirimAt, err = parseWaktuWIB("kirim_at", r.FormValue("kirim_at")); err != nil {
+		return a, err
+	}
+	if a.kedaluwarsaAt, err = parseWaktuWIB("kedaluwarsa_at", r.FormValue("kedaluwarsa_at")); err != nil {
+		return a, err
+	}
+	if a.masaBerlakuJam, err = parseAngkaPositif("masa_berlaku_jam", r.FormValue("masa_berlaku_jam")); err != nil {
+		return a, err
+	}
+	if a.sampaiAt, err = parseWaktuWIB("sampai", r.FormValue("sampai")); err != nil {
+		return a, err
+	}
+	if a.sampaiJendelaID, err = parseAngkaPositif("sampai_jendela_id", r.FormValue("sampai_jendela_id")); err != nil {
+		return a, err
+	}
+	setiap, err := parseAngkaPositif("setiap", r.FormValue("setiap"))
+	if err != nil {
+		return a, err
+	}
+
+	if a.ulang == models.UlangSekali {
+		if setiap != nil || a.sampaiAt != nil || a.sampaiJendelaID != nil {
+			return a, fmt.Errorf("setiap, sampai, dan sampai_jendela_id hanya berlaku untuk notifikasi berulang")
+		}
+		if a.kirimAt != nil && !a.kirimAt.After(sekarang) {
+			a.kirimAt = nil
+		}
+	} else {
+		if setiap != nil {
+			a.setiap = *setiap
+		}
+		if a.kirimAt == nil {
+			a.kirimAt = &sekarang
+		} else if a.kirimAt.Before(sekarang) {
+			return a, fmt.Errorf("kirim_at sudah lewat")
+		}
+		if a.sampaiAt != nil && !a.sampaiAt.After(*a.kirimAt) {
+			return a, fmt.Errorf("sampai harus setelah kirim_at")
+		}
+	}
+
+	mulai := sekarang
+	if a.kirimAt != nil {
+		mulai = *a.kirimAt
+	}
+	if a.kedaluwarsaAt != nil && !a.kedaluwarsaAt.After(mulai) {
+		return a, fmt.Errorf("kedaluwarsa_at harus setelah waktu kirim")
+	}
+	return a, nil
+}
+
+// GetJadwalNotifikasi menampilkan notifikasi terjadwal untuk admin
+// (GET /jadwal?status=aktif|selesai|dibatalkan|gagal). Mendukung page/limit/cursor, sort, dan q
+// (judul atau deskripsi).
+func GetJadwalNotifikasi(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !hanyaAdmin(w, r) {
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.JadwalAktif, models.JadwalSelesai, models.JadwalDibatalkan, models.JadwalGagal:
+	default:
+		http.Error(w, "status tidak valid", http.StatusBadRequest)
+		return
+	}
+	k, err := halaman.Parse(r, models.AturanJadwal)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	list, total, err := models.NewJadwalModel(db).GetJadwal(status, k)
+	if err != nil {
+		log.Printf("❌ Gagal mengambil jadwal notifikasi: %v", err)
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status": "success",
+		"data":   list,
+		"meta":   k.Meta(total),
+	})
+}
+
+// BatalkanJadwalNotifikasi menghentikan jadwal yang masih aktif (POST /jadwal/{id}/batal). Notifikasi
+// yang sudah terkirim tetap berada di inbox penerima.
+func BatalkanJadwalNotifikasi(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !hanyaAdmin(w, r) {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	if err := models.NewJadwalModel(db).Batalkan(id); err == sql.ErrNoRows {
+		http.Error(w, "Jadwal aktif tidak ditemukan", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status":  "success",
+		"message": "Jadwal notifikasi dibatalkan",
+	})
+}
@@ -8,10 +8,16 @@ import (
 	"log"
 	"mime/multipart"
 	"net/http"
+	"notification_service/aliran"
 	"notification_service/config"
+	"notification_service/email"
+	"notification_service/jobs"
 	"notification_service/models"
+	"notification_service/utils"
+	"notification_service/utils/halaman"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -108,7 +114,7 @@ func sniffMIME(rc io.ReadSeeker) (string, error) {
 func BroadcastNotification(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 	w.Header().Set("Content-Type", "application/json")
 
 	if r.Method == http.MethodOptions {
@@ -116,6 +122,13 @@ func BroadcastNotification(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Broadcast (langsung maupun terjadwal, termasuk email) hanya boleh dibuat admin
+	if !hanyaAdmin(w, r) {
+		return
+	}
+	claims, _ := utils.ParseJWT(utils.BearerToken(r))
+	pembuat := claims.Email
+
 	// Batasi total ukuran request (hard limit)
 	r.Body = http.MaxBytesReader(w, r.Body, MaxUploadBytes+1) // +1 untuk deteksi over
 	// Parse form multipart (nilai di sini adalah ambang penggunaan RAM untuk parts)
@@ -133,6 +146,28 @@ func BroadcastNotification(w http.ResponseWriter, r *http.Request) {
 		deskripsi = "-"
 	}
 
+	// email=true: notifikasi juga dikirim ke email penerima yang tidak menonaktifkan email broadcast
+	kirimEmail, _ := strconv.ParseBool(r.FormValue("email"))
+	if kirimEmail && !email.Aktif() {
+		http.Error(w, "Kanal email belum dikonfigurasi", http.StatusBadRequest)
+		return
+	}
+
+	// Sasaran selain role: user_id, jurusan, kelas, dan periode_id (boleh lebih dari satu)
+	sasaran, err := sasaranDariForm(r, targets)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Waktu kirim, pengulangan, dan kedaluwarsa (opsional)
+	sekarang := time.Now()
+	aturan, err := aturanKirimDariForm(r, sekarang)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Pastikan folder ada
 	if err := os.MkdirAll(uploadDir, 0o750); err != nil {
 		http.Error(w, `Gagal membuat direktori upload`, http.StatusInternalServerError)
@@ -233,16 +268,6 @@ func BroadcastNotification(w http.ResponseWriter, r *http.Request) {
 		fileURLs = append(fileURLs, "/uploads/"+fileName)
 	}
 
-	// Hilangkan target duplikat
-	unique := map[string]struct{}{}
-	var filteredTargets []string
-	for _, t := range targets {
-		if _, ok := unique[t]; !ok {
-			unique[t] = struct{}{}
-			filteredTargets = append(filteredTargets, t)
-		}
-	}
-
 	// Simpan notifikasi ke DB
 	db, err := config.GetDB()
 	if err != nil {
@@ -251,45 +276,173 @@ func BroadcastNotification(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
-	type Notification struct {
-		Judul     string
-		Deskripsi string
-		Target    string
-		FileURLs  string
-		CreatedAt time.Time
+	var roles []string
+	for _, sa := range sasaran {
+		if sa.Jenis == models.SasaranRole {
+			roles = append(roles, sa.Nilai)
+		}
+	}
+	fileURLsJSON := ""
+	if len(fileURLs) > 0 {
+		b, _ := json.Marshal(fileURLs)
+		fileURLsJSON = string(b)
+	}
+
+	var isiEmail *models.EmailNotifikasi
+	if kirimEmail {
+		isiEmail, err = renderEmail(models.KategoriBroadcast, models.KategoriBroadcast, dataEmail{
+			Judul:     judul,
+			Deskripsi: deskripsi,
+			Lampiran:  lampiranDariURL(fileURLs),
+		})
+		if err != nil {
+			log.Printf("❌ Gagal menyusun email broadcast: %v", err)
+			http.Error(w, "Gagal menyusun email", http.StatusInternalServerError)
+			return
+		}
 	}
-	notif := Notification{
+
+	notifikasi := models.NotifikasiBaru{
 		Judul:     judul,
 		Deskripsi: deskripsi,
-		Target:    strings.Join(filteredTargets, ","),
-		CreatedAt: time.Now(),
+		Target:    strings.Join(roles, ","),
+		FileURLs:  fileURLsJSON,
+		Sasaran:   sasaran,
+		Email:     isiEmail,
 	}
-	if len(fileURLs) > 0 {
-		b, _ := json.Marshal(fileURLs)
-		notif.FileURLs = string(b)
+
+	if aturan.terjadwal() {
+		notifikasi.KedaluwarsaAt = aturan.kedaluwarsaAt
+		id, err := models.NewJadwalModel(db).Buat(models.JadwalBaru{
+			Notifikasi:      notifikasi,
+			KirimAt:         *aturan.kirimAt,
+			Ulang:           aturan.ulang,
+			Setiap:          aturan.setiap,
+			SampaiAt:        aturan.sampaiAt,
+			SampaiJendelaID: aturan.sampaiJendelaID,
+			MasaBerlakuJam:  aturan.masaBerlakuJam,
+			DibuatOleh:      pembuat,
+		})
+		if err == models.ErrJendelaTidakAda {
+			http.Error(w, "Jendela pengumpulan tidak ditemukan", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("❌ Gagal menyimpan jadwal notifikasi: %+v", err)
+			http.Error(w, `{"error":"Database error"}`, http.StatusInternalServerError)
+			return
+		}
+		jobs.SegerakanJadwal()
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"message":   "Notifikasi berhasil dijadwalkan",
+			"jadwal_id": id,
+			"kirim_at":  aturan.kirimAt.In(wib),
+			"ulang":     aturan.ulang,
+			"files":     fileURLs,
+		})
+		return
 	}
 
-	query := `INSERT INTO notifications (judul, deskripsi, target, file_urls, created_at) VALUES (?, ?, ?, ?, ?)`
-	res, err := db.Exec(query, notif.Judul, notif.Deskripsi, notif.Target, notif.FileURLs, notif.CreatedAt)
+	notifikasi.KedaluwarsaAt = aturan.kedaluwarsaLangsung(sekarang)
+	hasil, err := models.NewInboxModel(db).Kirim(notifikasi)
 	if err != nil {
 		log.Printf("❌ INSERT error: %+v", err)
 		http.Error(w, `{"error":"Database error"}`, http.StatusInternalServerError)
 		return
 	}
+	if hasil.Email > 0 {
+		jobs.SegerakanEmail()
+	}
+	aliran.Siarkan(db, hasil.ID)
 
-	id, _ := res.LastInsertId()
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(map[string]any{
-		"message": "Notifikasi berhasil dikirim",
-		"id":      id,
-		"files":   fileURLs,
+		"message":  "Notifikasi berhasil dikirim",
+		"id":       hasil.ID,
+		"files":    fileURLs,
+		"penerima": hasil.Penerima,
+		"email":    hasil.Email,
 	})
 }
 
+// sasaranDariForm mengumpulkan sasaran notifikasi dari form broadcast tanpa duplikat.
+// target berisi role (checkbox lama); user_id dan periode_id harus berupa angka.
+func sasaranDariForm(r *http.Request, targets []string) ([]models.Sasaran, error) {
+	var sasaran []models.Sasaran
+	unik := map[models.Sasaran]struct{}{}
+	tambah := func(jenis string, nilai []string, angka bool) error {
+		for _, v := range nilai {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			if angka {
+				if _, err := strconv.Atoi(v); err != nil {
+					return fmt.Errorf("%s tidak valid: %s", jenis, v)
+				}
+			}
+			sa := models.Sasaran{Jenis: jenis, Nilai: v}
+			if _, ok := unik[sa]; !ok {
+				unik[sa] = struct{}{}
+				sasaran = append(sasaran, sa)
+			}
+		}
+		return nil
+	}
+
+	if err := tambah(models.SasaranRole, targets, false); err != nil {
+		return nil, err
+	}
+	if err := tambah(models.SasaranUser, r.Form["user_id"], true); err != nil {
+		return nil, err
+	}
+	if err := tambah(models.SasaranJurusan, r.Form["jurusan"], false); err != nil {
+		return nil, err
+	}
+	if err := tambah(models.SasaranKelas, r.Form["kelas"], false); err != nil {
+		return nil, err
+	}
+	if err := tambah(models.SasaranPeriode, r.Form["periode_id"], true); err != nil {
+		return nil, err
+	}
+	if len(sasaran) == 0 {
+		return nil, fmt.Errorf("sasaran notifikasi wajib diisi (target, user_id, jurusan, kelas, atau periode_id)")
+	}
+	return sasaran, nil
+}
+
+// aturanDaftarNotifikasi adalah urutan yang diizinkan untuk daftar notifikasi
+var aturanDaftarNotifikasi = halaman.Aturan{
+	Urut: map[string]string{
+		"created_at": "created_at",
+		"judul":      "judul",
+	},
+	UrutDefault: "-created_at",
+	Kunci:       "id DESC",
+}
+
+// jumlahNotifikasiDasbor dipakai bila klien tidak mengirim parameter halaman
+const jumlahNotifikasiDasbor = 10
+
+// GetNotifications menampilkan notifikasi untuk satu role (GET ?role=), difilter di database.
+// Mendukung page/limit/cursor, sort, dan q (judul atau deskripsi); dengan parameter halaman respons
+// berbentuk {status, data, meta}, tanpa parameter tetap array 10 notifikasi terbaru seperti sebelumnya.
 func GetNotifications(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Content-Type", "application/json")
 
+	k, err := halaman.Parse(r, aturanDaftarNotifikasi)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dipaging := k.Dipaging
+	if !dipaging {
+		k.Dipaging, k.Limit = true, jumlahNotifikasiDasbor
+	}
+
 	db, err := config.GetDB()
 	if err != nil {
 		http.Error(w, "Database error", http.StatusInternalServerError)
@@ -303,7 +456,22 @@ func GetNotifications(w http.ResponseWriter, r *http.Request) {
 		role = "Taruna" // default fallback
 	}
 
-	rows, err := db.Query("SELECT id, judul, deskripsi, target, file_urls, created_at FROM notifications ORDER BY created_at DESC LIMIT 10")
+	// target berisi daftar role tujuan, mis. "Taruna,Dosen"
+	var f halaman.Filter
+	f.Cari(role, "target")
+	f.Cari(k.Q, "judul", "deskripsi")
+	f.Tambah(models.BelumKedaluwarsa)
+
+	dari := "FROM notifications n WHERE " + f.SQL()
+	total, err := halaman.Hitung(db, dari, f.Args())
+	if err != nil {
+		http.Error(w, "Query error", http.StatusInternalServerError)
+		return
+	}
+
+	potong, argsPotong := k.Potong()
+	rows, err := db.Query("SELECT id, judul, deskripsi, target, file_urls, created_at "+dari+k.OrderBy()+potong,
+		append(f.Args(), argsPotong...)...)
 	if err != nil {
 		http.Error(w, "Query error", http.StatusInternalServerError)
 		return
@@ -318,13 +486,18 @@ func GetNotifications(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			continue
 		}
-		// Filter sesuai role
-		if strings.Contains(n.Target, role) {
-			results = append(results, n)
-		}
+		results = append(results, n)
 	}
 
-	json.NewEncoder(w).Encode(results)
+	if !dipaging {
+		json.NewEncoder(w).Encode(results)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"status": "success",
+		"data":   results,
+		"meta":   k.Meta(total),
+	})
 }
 
 func GetNotificationByID(w http.ResponseWriter, r *http.Request) {
@@ -350,8 +523,8 @@ func GetNotificationByID(w http.ResponseWriter, r *http.Request) {
 	var notif models.Notification
 	row := db.QueryRow(`
 		SELECT id, judul, deskripsi, target, file_urls, created_at 
-		FROM notifications 
-		WHERE id = ?`, id)
+		FROM notifications n
+		WHERE id = ? AND `+models.BelumKedaluwarsa, id)
 
 	err = row.Scan(&notif.ID, &notif.Judul, &notif.Deskripsi, &notif.Target, &notif.FileURLs, &notif.CreatedAt)
 	if err != nil {
@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	"notification_service/config"
+	"notification_service/email"
+	"notification_service/models"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// batchEmail adalah banyaknya email yang dikirim per putaran
+	batchEmail = 20
+	// backoffAwalEmail dan backoffMaksEmail membatasi jeda coba ulang yang berlipat dua tiap kegagalan
+	backoffAwalEmail = time.Minute
+	backoffMaksEmail = 2 * time.Hour
+)
+
+// segeraEmail membangunkan pekerja email setelah transaksi yang mengantrekan email di-commit
+var segeraEmail = make(chan struct{}, 1)
+
+// SegerakanEmail meminta pekerja mengirim email tanpa menunggu putaran berkala berikutnya
+func SegerakanEmail() {
+	select {
+	case segeraEmail <- struct{}{}:
+	default:
+	}
+}
+
+// StartPengirimEmail menjalankan pekerja yang mengirim antrean email lewat SMTP. Tidak dijalankan bila
+// SMTP_HOST kosong. Interval putaran bisa diatur lewat env EMAIL_INTERVAL (default 30 detik) dan batas
+// percobaan sebelum email dipindah ke status gagal lewat EMAIL_MAKS_PERCOBAAN (default 8).
+func StartPengirimEmail() {
+	if !email.Aktif() {
+		log.Println("ℹ️ SMTP_HOST tidak diatur, kanal email nonaktif")
+		return
+	}
+	interval := 30 * time.Second
+	if v := os.Getenv("EMAIL_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			interval = d
+		} else {
+			log.Printf("EMAIL_INTERVAL tidak valid (%q), memakai %s", v, interval)
+		}
+	}
+	maksPercobaan := 8
+	if v := os.Getenv("EMAIL_MAKS_PERCOBAAN"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			maksPercobaan = n
+		} else {
+			log.Printf("EMAIL_MAKS_PERCOBAAN tidak valid (%q), memakai %d", v, maksPercobaan)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			runPengirimEmail(maksPercobaan)
+			select {
+			case <-segeraEmail:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func runPengirimEmail(maksPercobaan int) {
+	db, err := config.GetDB()
+	if err != nil {
+		log.Printf("Email: gagal koneksi database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	model := models.NewEmailModel(db)
+	for {
+		list, err := model.AmbilSiapKirim(batchEmail)
+		if err != nil {
+			log.Printf("Email: %v", err)
+			return
+		}
+		for _, e := range list {
+			errKirim := email.Kirim(email.Pesan{Ke: e.Alamat, Subjek: e.Subjek, Teks: e.Teks, HTML: e.HTML})
+			if errKirim == nil {
+				err = model.TandaiTerkirim(e.ID)
+			} else {
+				var cobaLagi *time.Time
+				if percobaan := e.Percobaan + 1; percobaan < maksPercobaan && !errors.Is(errKirim, email.ErrPermanen) {
+					t := time.Now().Add(backoffEmail(percobaan))
+					cobaLagi = &t
+				} else {
+					log.Printf("Email: %d ke %s gagal setelah %d percobaan: %v", e.ID, e.Alamat, percobaan, errKirim)
+				}
+				err = model.TandaiGagalKirim(e.ID, errKirim.Error(), cobaLagi)
+			}
+			if err != nil {
+				log.Printf("Email: gagal memperbarui status email %d: %v", e.ID, err)
+				return
+			}
+		}
+		if len(list) < batchEmail {
+			return
+		}
+	}
+}
+
+// backoffEmail mengembalikan jeda sebelum percobaan berikutnya setelah n kegagalan
+func backoffEmail(n int) time.Duration {
+	d := backoffAwalEmail
+	for i := 1; i < n && d < backoffMaksEmail; i++ {
+		d *= 2
+	}
+	if d > backoffMaksEmail {
+		d = backoffMaksEmail
+	}
+	return d
+}
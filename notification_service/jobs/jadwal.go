@@ -0,0 +1,127 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	"notification_service/aliran"
+	"notification_service/config"
+	"notification_service/models"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// batchJadwal adalah banyaknya jadwal yang diperiksa per putaran
+	batchJadwal = 20
+	// backoffAwalJadwal dan backoffMaksJadwal membatasi jeda coba ulang jadwal yang gagal dijalankan
+	backoffAwalJadwal = time.Minute
+	backoffMaksJadwal = time.Hour
+)
+
+// segeraJadwal membangunkan penjadwal setelah jadwal baru disimpan
+var segeraJadwal = make(chan struct{}, 1)
+
+// SegerakanJadwal meminta penjadwal memeriksa jadwal tanpa menunggu putaran berkala berikutnya
+func SegerakanJadwal() {
+	select {
+	case segeraJadwal <- struct{}{}:
+	default:
+	}
+}
+
+// StartPenjadwal menjalankan pekerja yang mengirim notifikasi terjadwal. Seluruh keadaan jadwal ada di
+// database sehingga kiriman yang jatuh tempo selama layanan mati dikirim saat layanan hidup kembali.
+// Interval putaran bisa diatur lewat env JADWAL_INTERVAL (default 1 menit). Jadwal yang gagal dijalankan
+// dicoba ulang dengan jeda tanpa menahan jadwal lain; setelah JADWAL_MAKS_PERCOBAAN kegagalan beruntun
+// (default 5), atau segera bila datanya rusak, jadwal dipindah ke status gagal.
+func StartPenjadwal() {
+	interval := time.Minute
+	if v := os.Getenv("JADWAL_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			interval = d
+		} else {
+			log.Printf("JADWAL_INTERVAL tidak valid (%q), memakai %s", v, interval)
+		}
+	}
+	maksPercobaan := 5
+	if v := os.Getenv("JADWAL_MAKS_PERCOBAAN"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			maksPercobaan = n
+		} else {
+			log.Printf("JADWAL_MAKS_PERCOBAAN tidak valid (%q), memakai %d", v, maksPercobaan)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			runPenjadwal(maksPercobaan)
+			select {
+			case <-segeraJadwal:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func runPenjadwal(maksPercobaan int) {
+	db, err := config.GetDB()
+	if err != nil {
+		log.Printf("Jadwal: gagal koneksi database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	model := models.NewJadwalModel(db)
+	for {
+		list, err := model.JatuhTempo(time.Now(), batchJadwal)
+		if err != nil {
+			log.Printf("Jadwal: %v", err)
+			return
+		}
+		for _, j := range list {
+			hasil, terkirim, err := model.Jalankan(j.ID, time.Now())
+			if err != nil {
+				// Jadwal yang gagal diberi jeda agar tidak menahan jadwal lain di putaran ini
+				var cobaLagi *time.Time
+				if percobaan := j.Percobaan + 1; percobaan < maksPercobaan && !errors.Is(err, models.ErrJadwalRusak) {
+					t := time.Now().Add(backoffJadwal(percobaan))
+					cobaLagi = &t
+					log.Printf("Jadwal: gagal menjalankan jadwal %d (percobaan %d): %v", j.ID, percobaan, err)
+				} else {
+					log.Printf("Jadwal: jadwal %d dihentikan setelah %d percobaan: %v", j.ID, percobaan, err)
+				}
+				if err := model.TandaiGagal(j.ID, err.Error(), cobaLagi); err != nil {
+					log.Printf("Jadwal: gagal mencatat kegagalan jadwal %d: %v", j.ID, err)
+					return
+				}
+				continue
+			}
+			if !terkirim {
+				continue
+			}
+			log.Printf("Jadwal: jadwal %d dikirim sebagai notifikasi %d ke %d penerima", j.ID, hasil.ID, hasil.Penerima)
+			if hasil.Email > 0 {
+				SegerakanEmail()
+			}
+			aliran.Siarkan(db, hasil.ID)
+		}
+		if len(list) < batchJadwal {
+			return
+		}
+	}
+}
+
+// backoffJadwal mengembalikan jeda sebelum percobaan berikutnya setelah n kegagalan
+func backoffJadwal(n int) time.Duration {
+	d := backoffAwalJadwal
+	for i := 1; i < n && d < backoffMaksJadwal; i++ {
+		d *= 2
+	}
+	if d > backoffMaksJadwal {
+		d = backoffMaksJadwal
+	}
+	return d
+}
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"os"
 
+	"notification_service/config"
 	"notification_service/handlers"
+	"notification_service/jobs"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
@@ -19,16 +21,60 @@ func main() {
 		log.Fatal("Gagal membuat folder uploads:", err)
 	}
 
+	// Jalankan migrasi skema yang belum diterapkan
+	db, err := config.GetDB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := config.Migrate(db); err != nil {
+		log.Fatal(err)
+	}
+	db.Close()
+
+	// Event dari layanan lain ditolak sampai EVENT_SECRET diatur
+	if os.Getenv("EVENT_SECRET") == "" {
+		log.Println("⚠️ EVENT_SECRET belum diatur: POST /events menolak semua event")
+	}
+
+	// Pekerja antrean email (hanya berjalan bila SMTP_HOST diatur)
+	jobs.StartPengirimEmail()
+
+	// Penjadwal pengumuman terjadwal dan berulang
+	jobs.StartPenjadwal()
+
 	// Register endpoint
 	r.HandleFunc("/broadcast", handlers.BroadcastNotification).Methods("POST", "OPTIONS")
 	r.HandleFunc("/notifications", handlers.GetNotifications).Methods("GET", "OPTIONS")
 	r.HandleFunc("/notification/{id}", handlers.GetNotificationByID).Methods("GET", "OPTIONS")
 	r.HandleFunc("/download/{filename}", handlers.DownloadFile).Methods("GET", "OPTIONS")
 
+	// Event domain dari document_service dan user_service
+	r.HandleFunc("/events", handlers.TerimaEvent).Methods("POST")
+
+	// Inbox per user (identitas dari token)
+	r.HandleFunc("/inbox", handlers.GetInbox).Methods("GET")
+	r.HandleFunc("/inbox/unread-count", handlers.GetInboxBelumDibaca).Methods("GET")
+	r.HandleFunc("/inbox/read-all", handlers.TandaiSemuaInboxDibaca).Methods("POST")
+	r.HandleFunc("/inbox/{id:[0-9]+}/read", handlers.TandaiInboxDibaca).Methods("POST")
+	r.HandleFunc("/inbox/{id:[0-9]+}/archive", handlers.ArsipkanInbox).Methods("POST")
+
+	// Notifikasi real-time (Server-Sent Events)
+	r.HandleFunc("/stream", handlers.Aliran).Methods("GET")
+
+	// Kanal email: preferensi per user dan pemantauan antrean oleh admin
+	r.HandleFunc("/email/preferensi", handlers.GetPreferensiEmail).Methods("GET")
+	r.HandleFunc("/email/preferensi", handlers.SimpanPreferensiEmail).Methods("PUT")
+	r.HandleFunc("/email/antrean", handlers.GetAntreanEmail).Methods("GET")
+	r.HandleFunc("/email/antrean/{id:[0-9]+}/ulang", handlers.AntrekanUlangEmail).Methods("POST")
+
+	// Notifikasi terjadwal (dibuat lewat /broadcast dengan kirim_at/ulang) untuk admin
+	r.HandleFunc("/jadwal", handlers.GetJadwalNotifikasi).Methods("GET")
+	r.HandleFunc("/jadwal/{id:[0-9]+}/batal", handlers.BatalkanJadwalNotifikasi).Methods("POST")
+
 	// Setup CORS agar frontend (port 8080) bisa akses
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"http://172.210.59.9:8080"}, // sesuaikan jika pakai domain lain
-		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type", "Authorization"},
 		AllowCredentials: true,
 	})
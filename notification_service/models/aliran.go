@@ -0,0 +1,99 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+)
+
+// ItemAliran adalah satu notifikasi inbox yang dikirim lewat SSE. ID adalah id baris penerima
+// (notifikasi_penerima.id) yang naik terus per user sehingga dipakai sebagai id event SSE.
+type ItemAliran struct {
+	ID        int64
+	UserID    int
+	Item      ItemInbox
+	EventTipe string          // tipe event asal; kosong untuk broadcast
+	EventData json.RawMessage // muatan event asal
+}
+
+const kolomAliran = `p.id, p.user_id, n.id, n.judul, COALESCE(n.deskripsi, ''), COALESCE(n.file_urls, ''), p.status,
+	p.dibaca_at, n.created_at, COALESCE(n.event_tipe, ''), n.event_data`
+
+func scanAliran(rows *sql.Rows) ([]ItemAliran, error) {
+	defer rows.Close()
+	var list []ItemAliran
+	for rows.Next() {
+		var a ItemAliran
+		var fileURLs string
+		var dibaca sql.NullTime
+		var data sql.NullString
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Item.ID, &a.Item.Judul, &a.Item.Deskripsi, &fileURLs, &a.Item.Status,
+			&dibaca, &a.Item.CreatedAt, &a.EventTipe, &data); err != nil {
+			return nil, err
+		}
+		a.Item.FileURLs = []string{}
+		if fileURLs != "" {
+			_ = json.Unmarshal([]byte(fileURLs), &a.Item.FileURLs)
+		}
+		if dibaca.Valid {
+			a.Item.DibacaAt = &dibaca.Time
+		}
+		if data.Valid {
+			a.EventData = json.RawMessage(data.String)
+		}
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+// AliranNotifikasi mengambil baris penerima notifikasi yang baru disimpan, terbatas pada userIDs
+// (user yang sedang tersambung)
+func (m *InboxModel) AliranNotifikasi(notificationIDs []int64, userIDs []int) ([]ItemAliran, error) {
+	if len(notificationIDs) == 0 || len(userIDs) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, 0, len(notificationIDs)+len(userIDs))
+	for _, id := range notificationIDs {
+		args = append(args, id)
+	}
+	for _, id := range userIDs {
+		args = append(args, id)
+	}
+	rows, err := m.db.Query(`
+		SELECT `+kolomAliran+`
+		FROM notifikasi_penerima p
+		JOIN notifications n ON n.id = p.notification_id
+		WHERE p.notification_id IN (`+placeholder(len(notificationIDs))+`)
+			AND p.user_id IN (`+placeholder(len(userIDs))+`) AND `+BelumKedaluwarsa+`
+		ORDER BY p.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanAliran(rows)
+}
+
+// AliranUser mengambil paling banyak batas notifikasi user dengan id baris penerima setelah id tertentu,
+// untuk dikirim ulang saat klien SSE tersambung kembali
+func (m *InboxModel) AliranUser(userID int, setelah int64, batas int) ([]ItemAliran, error) {
+	rows, err := m.db.Query(`
+		SELECT `+kolomAliran+`
+		FROM notifikasi_penerima p
+		JOIN notifications n ON n.id = p.notification_id
+		WHERE p.user_id = ? AND p.id > ? AND `+BelumKedaluwarsa+`
+		ORDER BY p.id LIMIT ?`, userID, setelah, batas)
+	if err != nil {
+		return nil, err
+	}
+	return scanAliran(rows)
+}
+
+// IDAliranTerakhir mengembalikan id baris penerima terbaru milik user, 0 bila inbox kosong
+func (m *InboxModel) IDAliranTerakhir(userID int) (int64, error) {
+	var id int64
+	err := m.db.QueryRow("SELECT COALESCE(MAX(id), 0) FROM notifikasi_penerima WHERE user_id = ?", userID).Scan(&id)
+	return id, err
+}
+
+func placeholder(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
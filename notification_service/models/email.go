@@ -0,0 +1,244 @@
+package models
+
+import (
+	"database/sql"
+	"notification_service/utils/halaman"
+	"time"
+)
+
+// Status email di antrean
+const (
+	EmailMenunggu = "menunggu"
+	EmailTerkirim = "terkirim"
+	EmailGagal    = "gagal"
+)
+
+// KategoriBroadcast adalah kategori preferensi untuk broadcast admin; kategori lain adalah tipe event
+const KategoriBroadcast = "broadcast"
+
+// LabelKategoriEmail adalah kategori email yang bisa diatur user beserta labelnya, urut tampilan
+var LabelKategoriEmail = []struct {
+	Kategori, Label string
+}{
+	{EventDokumenDiunggah, "Dokumen baru diunggah taruna"},
+	{EventReviewDiunggah, "Hasil review dosen tersedia"},
+	{EventStatusBerubah, "Perubahan status dokumen"},
+	{EventPengujiDitugaskan, "Penugasan pembimbing, penelaah, dan penguji"},
+	{KategoriBroadcast, "Pengumuman dari admin"},
+}
+
+// IsValidKategoriEmail mengecek apakah kategori preferensi email dikenali
+func IsValidKategoriEmail(kategori string) bool {
+	for _, k := range LabelKategoriEmail {
+		if k.Kategori == kategori {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailNotifikasi adalah isi email yang menyertai satu notifikasi. Email hanya diantrekan untuk
+// penerima yang tidak menonaktifkan Kategori di preferensinya.
+type EmailNotifikasi struct {
+	Kategori string
+	Subjek   string
+	Teks     string
+	HTML     string
+}
+
+// PreferensiEmail adalah pilihan satu user untuk satu kategori
+type PreferensiEmail struct {
+	Kategori string `json:"kategori"`
+	Label    string `json:"label"`
+	Aktif    bool   `json:"aktif"`
+}
+
+// AntreanEmail adalah satu email di antrean beserta status pengirimannya
+type AntreanEmail struct {
+	ID             int64      `json:"id"`
+	NotificationID int        `json:"notification_id"`
+	UserID         int        `json:"user_id"`
+	Kategori       string     `json:"kategori"`
+	Alamat         string     `json:"alamat"`
+	Subjek         string     `json:"subjek"`
+	Teks           string     `json:"-"`
+	HTML           string     `json:"-"`
+	Status         string     `json:"status"`
+	Percobaan      int        `json:"percobaan"`
+	CobaLagiAt     time.Time  `json:"coba_lagi_at"`
+	GalatTerakhir  string     `json:"galat_terakhir,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	TerkirimAt     *time.Time `json:"terkirim_at"`
+}
+
+// AturanAntreanEmail adalah urutan yang diizinkan untuk GET /email/antrean
+var AturanAntreanEmail = halaman.Aturan{
+	Urut: map[string]string{
+		"created_at": "created_at",
+		"percobaan":  "percobaan",
+	},
+	UrutDefault: "-created_at",
+	Kunci:       "id DESC",
+}
+
+// antrekanEmailTx mengantrekan email untuk seluruh penerima notifikasi yang punya alamat email dan
+// tidak menonaktifkan kategorinya. Mengembalikan jumlah email yang diantrekan.
+func antrekanEmailTx(tx *sql.Tx, notificationID int64, e *EmailNotifikasi) (int64, error) {
+	res, err := tx.Exec(`
+		INSERT INTO antrean_email (notification_id, user_id, kategori, alamat, subjek, isi_teks, isi_html)
+		SELECT p.notification_id, u.id, ?, u.email, ?, ?, ?
+		FROM notifikasi_penerima p
+		JOIN users u ON u.id = p.user_id
+		LEFT JOIN preferensi_email pe ON pe.user_id = u.id AND pe.kategori = ?
+		WHERE p.notification_id = ? AND u.email LIKE '%_@_%' AND COALESCE(pe.aktif, 1) = 1`,
+		e.Kategori, e.Subjek, e.Teks, e.HTML, e.Kategori, notificationID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+type EmailModel struct {
+	db *sql.DB
+}
+
+func NewEmailModel(db *sql.DB) *EmailModel {
+	return &EmailModel{db: db}
+}
+
+// Preferensi mengembalikan pilihan email user untuk setiap kategori; kategori tanpa pilihan dianggap aktif
+func (m *EmailModel) Preferensi(userID int) ([]PreferensiEmail, error) {
+	rows, err := m.db.Query("SELECT kategori, aktif FROM preferensi_email WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pilihan := map[string]bool{}
+	for rows.Next() {
+		var kategori string
+		var aktif bool
+		if err := rows.Scan(&kategori, &aktif); err != nil {
+			return nil, err
+		}
+		pilihan[kategori] = aktif
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	list := make([]PreferensiEmail, 0, len(LabelKategoriEmail))
+	for _, k := range LabelKategoriEmail {
+		aktif, ada := pilihan[k.Kategori]
+		list = append(list, PreferensiEmail{Kategori: k.Kategori, Label: k.Label, Aktif: aktif || !ada})
+	}
+	return list, nil
+}
+
+// SimpanPreferensi menyimpan pilihan email user untuk kategori yang dikirim saja
+func (m *EmailModel) SimpanPreferensi(userID int, pilihan map[string]bool) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for kategori, aktif := range pilihan {
+		if _, err := tx.Exec(`
+			INSERT INTO preferensi_email (user_id, kategori, aktif) VALUES (?, ?, ?)
+			ON DUPLICATE KEY UPDATE aktif = VALUES(aktif)`, userID, kategori, aktif); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+const kolomAntreanEmail = `id, notification_id, user_id, kategori, alamat, subjek, isi_teks, isi_html, status, percobaan,
+	coba_lagi_at, COALESCE(galat_terakhir, ''), created_at, terkirim_at`
+
+func scanAntreanEmail(rows *sql.Rows) ([]AntreanEmail, error) {
+	defer rows.Close()
+	list := []AntreanEmail{}
+	for rows.Next() {
+		var e AntreanEmail
+		var terkirim sql.NullTime
+		if err := rows.Scan(&e.ID, &e.NotificationID, &e.UserID, &e.Kategori, &e.Alamat, &e.Subjek, &e.Teks, &e.HTML,
+			&e.Status, &e.Percobaan, &e.CobaLagiAt, &e.GalatTerakhir, &e.CreatedAt, &terkirim); err != nil {
+			return nil, err
+		}
+		if terkirim.Valid {
+			e.TerkirimAt = &terkirim.Time
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
+
+// AmbilSiapKirim mengambil email menunggu yang waktu coba ulangnya sudah tiba, urut waktu diantrekan
+func (m *EmailModel) AmbilSiapKirim(batas int) ([]AntreanEmail, error) {
+	rows, err := m.db.Query(`SELECT `+kolomAntreanEmail+` FROM antrean_email
+		WHERE status = ? AND coba_lagi_at <= NOW()
+		ORDER BY id LIMIT ?`, EmailMenunggu, batas)
+	if err != nil {
+		return nil, err
+	}
+	return scanAntreanEmail(rows)
+}
+
+// TandaiTerkirim mencatat email sudah diterima server SMTP
+func (m *EmailModel) TandaiTerkirim(id int64) error {
+	_, err := m.db.Exec(`UPDATE antrean_email SET status = ?, percobaan = percobaan + 1, galat_terakhir = NULL,
+		terkirim_at = NOW() WHERE id = ?`, EmailTerkirim, id)
+	return err
+}
+
+// TandaiGagalKirim mencatat percobaan yang gagal. Bila cobaLagi nil, email dipindah ke status gagal
+// dan tidak dikirim lagi kecuali diantrekan ulang.
+func (m *EmailModel) TandaiGagalKirim(id int64, galat string, cobaLagi *time.Time) error {
+	if cobaLagi == nil {
+		_, err := m.db.Exec(`UPDATE antrean_email SET status = ?, percobaan = percobaan + 1, galat_terakhir = ?
+			WHERE id = ?`, EmailGagal, galat, id)
+		return err
+	}
+	_, err := m.db.Exec(`UPDATE antrean_email SET percobaan = percobaan + 1, galat_terakhir = ?, coba_lagi_at = ?
+		WHERE id = ?`, galat, *cobaLagi, id)
+	return err
+}
+
+// GetAntrean menampilkan antrean email menurut status dan notifikasi (opsional) untuk pemantauan admin;
+// q dicari pada alamat dan subjek
+func (m *EmailModel) GetAntrean(status string, notificationID int, k *halaman.Kueri) ([]AntreanEmail, int, error) {
+	var f halaman.Filter
+	f.Sama("status", status)
+	if notificationID > 0 {
+		f.Tambah("notification_id = ?", notificationID)
+	}
+	f.Cari(k.Q, "alamat", "subjek")
+
+	dari := " FROM antrean_email WHERE " + f.SQL()
+	total, err := halaman.Hitung(m.db, dari, f.Args())
+	if err != nil {
+		return nil, 0, err
+	}
+	potong, argsPotong := k.Potong()
+	rows, err := m.db.Query(`SELECT `+kolomAntreanEmail+dari+k.OrderBy()+potong, append(f.Args(), argsPotong...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := scanAntreanEmail(rows)
+	return list, total, err
+}
+
+// AntrekanUlang mengembalikan email gagal ke antrean dengan hitungan percobaan dari awal.
+// sql.ErrNoRows bila email tidak ada atau tidak berstatus gagal.
+func (m *EmailModel) AntrekanUlang(id int64) error {
+	res, err := m.db.Exec(`UPDATE antrean_email SET status = ?, percobaan = 0, coba_lagi_at = NOW()
+		WHERE id = ? AND status = ?`, EmailMenunggu, id, EmailGagal)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Tipe event domain yang diterbitkan document_service dan user_service
+const (
+	EventDokumenDiunggah   = "document.submitted"
+	EventReviewDiunggah    = "review.uploaded"
+	EventStatusBerubah     = "status.changed"
+	EventPengujiDitugaskan = "examiner.assigned"
+)
+
+// IsValidTipeEvent mengecek apakah tipe event dikenali
+func IsValidTipeEvent(tipe string) bool {
+	switch tipe {
+	case EventDokumenDiunggah, EventReviewDiunggah, EventStatusBerubah, EventPengujiDitugaskan:
+		return true
+	}
+	return false
+}
+
+// Event adalah amplop event yang diterima di POST /events
+type Event struct {
+	ID     string    `json:"id"`
+	Tipe   string    `json:"tipe"`
+	Sumber string    `json:"sumber"`
+	Waktu  time.Time `json:"waktu"`
+	Data   DataEvent `json:"data"`
+}
+
+// DataEvent menggabungkan muatan event dokumen dan event penugasan
+type DataEvent struct {
+	// event dokumen
+	Tahap        string `json:"tahap"`
+	Dokumen      string `json:"dokumen"`
+	DokumenID    int    `json:"dokumen_id"`
+	DosenUserIDs []int  `json:"dosen_user_ids"`
+	Topik        string `json:"topik"`
+	Status       string `json:"status"`
+
+	// event penugasan
+	Jenis     string           `json:"jenis"`
+	Penugasan []PenugasanEvent `json:"penugasan"`
+
+	TarunaUserID int `json:"taruna_user_id"`
+}
+
+// PenugasanEvent adalah satu dosen yang ditugaskan beserta perannya
+type PenugasanEvent struct {
+	DosenUserID int    `json:"dosen_user_id"`
+	Peran       string `json:"peran"`
+}
+
+// NotifikasiEvent adalah satu notifikasi inbox hasil olahan event
+type NotifikasiEvent struct {
+	Penerima   []int // users.id
+	Judul, Isi string
+	Email      *EmailNotifikasi // nil bila kanal email nonaktif
+}
+
+type EventModel struct {
+	db *sql.DB
+}
+
+func NewEventModel(db *sql.DB) *EventModel {
+	return &EventModel{db: db}
+}
+
+// NamaUser mengembalikan nama lengkap user; kosong bila tidak ditemukan
+func (m *EventModel) NamaUser(userID int) (string, error) {
+	var nama sql.NullString
+	err := m.db.QueryRow("SELECT nama_lengkap FROM users WHERE id = ?", userID).Scan(&nama)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return nama.String, err
+}
+
+// PembimbingAktif mengembalikan users.id dosen pembimbing aktif taruna
+func (m *EventModel) PembimbingAktif(tarunaUserID int) ([]int, error) {
+	rows, err := m.db.Query(`
+		SELECT DISTINCT d.user_id
+		FROM v_pembimbing_taruna dp
+		JOIN dosen d ON d.id = dp.dosen_id
+		WHERE dp.user_id = ? AND dp.status = 'aktif' AND d.user_id IS NOT NULL`, tarunaUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// KirimEvent mencatat id event dan menyimpan seluruh notifikasinya dalam satu transaksi. Bila id event
+// sudah pernah diterima, tidak ada yang disimpan dan duplikat bernilai true.
+func (m *EventModel) KirimEvent(e *Event, notifikasi []NotifikasiEvent) (hasil []HasilKirim, duplikat bool, err error) {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	// INSERT IGNORE menunggu kunci baris bila event yang sama sedang diolah request lain
+	res, err := tx.Exec(`INSERT IGNORE INTO event_diterima (event_id, tipe, sumber, diterima_at) VALUES (?, ?, ?, ?)`,
+		e.ID, e.Tipe, e.Sumber, time.Now())
+	if err != nil {
+		return nil, false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, true, nil
+	}
+
+	for _, n := range notifikasi {
+		sasaran := make([]Sasaran, len(n.Penerima))
+		for i, id := range n.Penerima {
+			sasaran[i] = Sasaran{Jenis: SasaranUser, Nilai: strconv.Itoa(id)}
+		}
+		// target kosong: notifikasi event hanya tampil di inbox penerimanya, bukan di daftar broadcast
+		h, err := kirimTx(tx, NotifikasiBaru{
+			Judul:     n.Judul,
+			Deskripsi: n.Isi,
+			Sasaran:   sasaran,
+			Email:     n.Email,
+			EventTipe: e.Tipe,
+			EventData: data,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		hasil = append(hasil, h)
+	}
+	return hasil, false, tx.Commit()
+}
@@ -0,0 +1,298 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"notification_service/utils/halaman"
+	"strings"
+	"time"
+)
+
+// Status notifikasi bagi satu penerima
+const (
+	StatusBelumDibaca = "belum_dibaca"
+	StatusDibaca      = "dibaca"
+	StatusDiarsipkan  = "diarsipkan"
+	StatusSemua       = "semua" // hanya untuk filter inbox
+)
+
+// Jenis sasaran notifikasi
+const (
+	SasaranUser    = "user"
+	SasaranRole    = "role"
+	SasaranJurusan = "jurusan"
+	SasaranKelas   = "kelas"
+	SasaranPeriode = "periode"
+)
+
+// Sasaran adalah satu alamat notifikasi, mis. {role, Taruna} atau {user, 12}
+type Sasaran struct {
+	Jenis string `json:"jenis"`
+	Nilai string `json:"nilai"`
+}
+
+// ItemInbox adalah notifikasi yang diterima satu user beserta statusnya
+type ItemInbox struct {
+	ID        int        `json:"id"` // id notifikasi
+	Judul     string     `json:"judul"`
+	Deskripsi string     `json:"deskripsi"`
+	FileURLs  []string   `json:"file_urls"`
+	Status    string     `json:"status"`
+	DibacaAt  *time.Time `json:"dibaca_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// AturanInbox adalah urutan yang diizinkan untuk GET /inbox
+var AturanInbox = halaman.Aturan{
+	Urut: map[string]string{
+		"created_at": "n.created_at",
+		"judul":      "n.judul",
+	},
+	UrutDefault: "-created_at",
+	Kunci:       "n.id DESC",
+}
+
+type InboxModel struct {
+	db *sql.DB
+}
+
+func NewInboxModel(db *sql.DB) *InboxModel {
+	return &InboxModel{db: db}
+}
+
+// HasilKirim adalah hasil penyimpanan satu notifikasi
+type HasilKirim struct {
+	ID       int64 // id notifikasi
+	Penerima int64
+	Email    int64 // email yang diantrekan
+}
+
+// NotifikasiBaru adalah notifikasi yang akan disimpan beserta sasarannya
+type NotifikasiBaru struct {
+	Judul     string
+	Deskripsi string
+	Target    string // daftar role lama (dipisah koma) untuk GET /notifications
+	FileURLs  string // array JSON path lampiran
+	Sasaran   []Sasaran
+	Email     *EmailNotifikasi // nil bila tidak dikirim lewat email
+	EventTipe string           // tipe event asal; kosong untuk broadcast
+	EventData []byte           // muatan event asal (JSON)
+	// KedaluwarsaAt adalah batas notifikasi tampil di inbox; nil berarti tidak kedaluwarsa
+	KedaluwarsaAt *time.Time
+}
+
+// Kirim menyimpan notifikasi, sasarannya, baris penerima, dan antrean email (bila Email tidak nil)
+// dalam satu transaksi
+func (m *InboxModel) Kirim(n NotifikasiBaru) (HasilKirim, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return HasilKirim{}, err
+	}
+	defer tx.Rollback()
+
+	hasil, err := kirimTx(tx, n)
+	if err != nil {
+		return HasilKirim{}, err
+	}
+	return hasil, tx.Commit()
+}
+
+// kirimTx menulis satu notifikasi beserta sasaran, penerima, dan antrean emailnya di dalam tx
+func kirimTx(tx *sql.Tx, n NotifikasiBaru) (HasilKirim, error) {
+	var hasil HasilKirim
+	var eventTipe, eventData interface{}
+	if n.EventTipe != "" {
+		eventTipe, eventData = n.EventTipe, string(n.EventData)
+	}
+
+	now := time.Now()
+	res, err := tx.Exec(`
+		INSERT INTO notifications (judul, deskripsi, target, file_urls, event_tipe, event_data, kedaluwarsa_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		n.Judul, n.Deskripsi, n.Target, n.FileURLs, eventTipe, eventData, n.KedaluwarsaAt, now)
+	if err != nil {
+		return hasil, err
+	}
+	if hasil.ID, err = res.LastInsertId(); err != nil {
+		return hasil, err
+	}
+
+	for _, s := range n.Sasaran {
+		if _, err := tx.Exec(`INSERT INTO notifikasi_sasaran (notification_id, jenis, nilai) VALUES (?, ?, ?)`,
+			hasil.ID, s.Jenis, s.Nilai); err != nil {
+			return hasil, err
+		}
+	}
+
+	kondisi, args := kondisiPenerima(n.Sasaran)
+	res, err = tx.Exec(`
+		INSERT IGNORE INTO notifikasi_penerima (notification_id, user_id, created_at)
+		SELECT ?, u.id, ? FROM users u
+		WHERE u.dinonaktifkan_at IS NULL AND `+kondisi, append([]interface{}{hasil.ID, now}, args...)...)
+	if err != nil {
+		return hasil, err
+	}
+	hasil.Penerima, _ = res.RowsAffected()
+
+	if n.Email != nil && hasil.Penerima > 0 {
+		if hasil.Email, err = antrekanEmailTx(tx, hasil.ID, n.Email); err != nil {
+			return hasil, err
+		}
+	}
+	return hasil, nil
+}
+
+// BelumKedaluwarsa adalah kondisi notifikasi (alias n) yang masih boleh tampil
+const BelumKedaluwarsa = "(n.kedaluwarsa_at IS NULL OR n.kedaluwarsa_at > NOW())"
+
+// kondisiPenerima menyusun kondisi user penerima: user yang disebut langsung, ditambah user yang cocok
+// dengan semua jenis sasaran lain (OR di dalam satu jenis, AND antarjenis)
+func kondisiPenerima(sasaran []Sasaran) (string, []interface{}) {
+	nilai := map[string][]interface{}{}
+	for _, s := range sasaran {
+		nilai[s.Jenis] = append(nilai[s.Jenis], s.Nilai)
+	}
+
+	var args []interface{}
+	in := func(ekspresi string, v []interface{}) string {
+		args = append(args, v...)
+		return ekspresi + " IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(v)), ", ") + ")"
+	}
+
+	var atau []string
+	if v := nilai[SasaranUser]; len(v) > 0 {
+		atau = append(atau, in("u.id", v))
+	}
+	var dan []string
+	for _, j := range []struct{ jenis, kolom string }{
+		{SasaranRole, "u.role"},
+		{SasaranJurusan, "u.jurusan"},
+		{SasaranKelas, "u.kelas"},
+	} {
+		if v := nilai[j.jenis]; len(v) > 0 {
+			dan = append(dan, in(j.kolom, v))
+		}
+	}
+	if v := nilai[SasaranPeriode]; len(v) > 0 {
+		dan = append(dan, "u.id IN (SELECT user_id FROM taruna WHERE "+in("periode_id", v)+")")
+	}
+	if len(dan) > 0 {
+		atau = append(atau, "("+strings.Join(dan, " AND ")+")")
+	}
+	if len(atau) == 0 {
+		return "1=0", nil
+	}
+	return "(" + strings.Join(atau, " OR ") + ")", args
+}
+
+// UserIDDariEmail mencari akun aktif pemilik email pada token
+func (m *InboxModel) UserIDDariEmail(email string) (int, error) {
+	var id int
+	err := m.db.QueryRow("SELECT id FROM users WHERE email = ? AND dinonaktifkan_at IS NULL", email).Scan(&id)
+	return id, err
+}
+
+// GetInbox mengambil notifikasi milik user. status kosong berarti semua yang belum diarsipkan;
+// q dicari pada judul dan deskripsi.
+func (m *InboxModel) GetInbox(userID int, status string, k *halaman.Kueri) ([]ItemInbox, int, error) {
+	var f halaman.Filter
+	f.Tambah("p.user_id = ?", userID)
+	f.Tambah(BelumKedaluwarsa)
+	switch status {
+	case "":
+		f.Tambah("p.status <> ?", StatusDiarsipkan)
+	case StatusSemua:
+	default:
+		f.Sama("p.status", status)
+	}
+	f.Cari(k.Q, "n.judul", "n.deskripsi")
+
+	dari := `
+		FROM notifikasi_penerima p
+		JOIN notifications n ON n.id = p.notification_id
+		WHERE ` + f.SQL()
+	total, err := halaman.Hitung(m.db, dari, f.Args())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	potong, argsPotong := k.Potong()
+	rows, err := m.db.Query(`
+		SELECT n.id, n.judul, COALESCE(n.deskripsi, ''), COALESCE(n.file_urls, ''), p.status, p.dibaca_at, n.created_at`+
+		dari+k.OrderBy()+potong, append(f.Args(), argsPotong...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := []ItemInbox{}
+	for rows.Next() {
+		var it ItemInbox
+		var fileURLs string
+		var dibaca sql.NullTime
+		if err := rows.Scan(&it.ID, &it.Judul, &it.Deskripsi, &fileURLs, &it.Status, &dibaca, &it.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		it.FileURLs = []string{}
+		if fileURLs != "" {
+			_ = json.Unmarshal([]byte(fileURLs), &it.FileURLs)
+		}
+		if dibaca.Valid {
+			it.DibacaAt = &dibaca.Time
+		}
+		list = append(list, it)
+	}
+	return list, total, rows.Err()
+}
+
+// HitungBelumDibaca menghitung notifikasi user yang belum dibaca dan belum kedaluwarsa
+func (m *InboxModel) HitungBelumDibaca(userID int) (int, error) {
+	var n int
+	err := m.db.QueryRow(`
+		SELECT COUNT(*) FROM notifikasi_penerima p
+		JOIN notifications n ON n.id = p.notification_id
+		WHERE p.user_id = ? AND p.status = ? AND `+BelumKedaluwarsa,
+		userID, StatusBelumDibaca).Scan(&n)
+	return n, err
+}
+
+// UbahStatus menandai satu notifikasi sebagai dibaca atau diarsipkan. Notifikasi yang sudah diarsipkan
+// tetap diarsipkan saat dibaca. sql.ErrNoRows bila user bukan penerima notifikasi tersebut.
+func (m *InboxModel) UbahStatus(userID, notificationID int, status string) error {
+	query := `
+		UPDATE notifikasi_penerima
+		SET status = IF(status = 'diarsipkan', status, 'dibaca'), dibaca_at = COALESCE(dibaca_at, NOW())
+		WHERE user_id = ? AND notification_id = ?`
+	if status == StatusDiarsipkan {
+		query = `
+			UPDATE notifikasi_penerima
+			SET status = 'diarsipkan', dibaca_at = COALESCE(dibaca_at, NOW()), diarsipkan_at = COALESCE(diarsipkan_at, NOW())
+			WHERE user_id = ? AND notification_id = ?`
+	}
+	res, err := m.db.Exec(query, userID, notificationID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		var ada bool
+		if err := m.db.QueryRow("SELECT EXISTS(SELECT 1 FROM notifikasi_penerima WHERE user_id = ? AND notification_id = ?)",
+			userID, notificationID).Scan(&ada); err != nil {
+			return err
+		}
+		if !ada {
+			return sql.ErrNoRows
+		}
+	}
+	return nil
+}
+
+// TandaiSemuaDibaca menandai seluruh notifikasi user yang belum dibaca; mengembalikan jumlah yang berubah
+func (m *InboxModel) TandaiSemuaDibaca(userID int) (int64, error) {
+	res, err := m.db.Exec(`
+		UPDATE notifikasi_penerima SET status = 'dibaca', dibaca_at = NOW()
+		WHERE user_id = ? AND status = 'belum_dibaca'`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
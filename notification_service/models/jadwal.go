@@ -0,0 +1,389 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"notification_service/utils/halaman"
+	"time"
+)
+
+// Status jadwal notifikasi
+const (
+	JadwalAktif      = "aktif"
+	JadwalSelesai    = "selesai"
+	JadwalDibatalkan = "dibatalkan"
+	JadwalGagal      = "gagal" // data rusak atau terus gagal dijalankan; tidak dicoba lagi
+)
+
+// Pola pengulangan jadwal
+const (
+	UlangSekali   = "sekali"
+	UlangHarian   = "harian"
+	UlangMingguan = "mingguan"
+)
+
+// ErrJendelaTidakAda dikembalikan bila jendela pengumpulan untuk batas pengulangan tidak ditemukan
+var ErrJendelaTidakAda = errors.New("jendela pengumpulan tidak ditemukan")
+
+// ErrJadwalRusak menandai jadwal yang datanya tidak dapat dibaca; mencoba ulang tidak akan berhasil
+var ErrJadwalRusak = errors.New("data jadwal rusak")
+
+// JadwalNotifikasi adalah pengumuman yang dikirim pada waktu tertentu, sekali atau berulang
+type JadwalNotifikasi struct {
+	ID              int        `json:"id"`
+	Judul           string     `json:"judul"`
+	Deskripsi       string     `json:"deskripsi"`
+	FileURLs        []string   `json:"file_urls"`
+	Sasaran         []Sasaran  `json:"sasaran"`
+	Email           bool       `json:"email"`
+	KirimAt         time.Time  `json:"kirim_at"`
+	Ulang           string     `json:"ulang"`
+	Setiap          int        `json:"setiap"`
+	SampaiAt        *time.Time `json:"sampai_at"`
+	SampaiJendelaID *int       `json:"sampai_jendela_id"`
+	KedaluwarsaAt   *time.Time `json:"kedaluwarsa_at"`
+	MasaBerlakuJam  *int       `json:"masa_berlaku_jam"`
+	Status          string     `json:"status"`
+	BerikutnyaAt    *time.Time `json:"berikutnya_at"`
+	JumlahKirim     int        `json:"jumlah_kirim"`
+	TerakhirKirimAt *time.Time `json:"terakhir_kirim_at"`
+	Percobaan       int        `json:"percobaan"` // kegagalan beruntun sejak kiriman terakhir berhasil
+	GalatTerakhir   string     `json:"galat_terakhir,omitempty"`
+	DibuatOleh      string     `json:"dibuat_oleh"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// JadwalBaru adalah jadwal yang akan disimpan. Notifikasi.KedaluwarsaAt dipakai sebagai batas tampil
+// absolut untuk setiap kiriman; MasaBerlakuJam menghitung batas tampil dari waktu tiap kiriman.
+type JadwalBaru struct {
+	Notifikasi      NotifikasiBaru
+	KirimAt         time.Time
+	Ulang           string
+	Setiap          int
+	SampaiAt        *time.Time
+	SampaiJendelaID *int
+	MasaBerlakuJam  *int
+	DibuatOleh      string
+}
+
+// AturanJadwal adalah urutan yang diizinkan untuk GET /jadwal
+var AturanJadwal = halaman.Aturan{
+	Urut: map[string]string{
+		"created_at":    "created_at",
+		"kirim_at":      "kirim_at",
+		"berikutnya_at": "berikutnya_at",
+		"judul":         "judul",
+	},
+	UrutDefault: "-created_at",
+	Kunci:       "id DESC",
+}
+
+type JadwalModel struct {
+	db *sql.DB
+}
+
+func NewJadwalModel(db *sql.DB) *JadwalModel {
+	return &JadwalModel{db: db}
+}
+
+// Buat menyimpan jadwal baru; kiriman pertama pada KirimAt
+func (m *JadwalModel) Buat(j JadwalBaru) (int64, error) {
+	if j.SampaiJendelaID != nil {
+		var ada bool
+		if err := m.db.QueryRow("SELECT EXISTS(SELECT 1 FROM jendela_pengumpulan WHERE id = ?)",
+			*j.SampaiJendelaID).Scan(&ada); err != nil {
+			return 0, err
+		}
+		if !ada {
+			return 0, ErrJendelaTidakAda
+		}
+	}
+	sasaran, err := json.Marshal(j.Notifikasi.Sasaran)
+	if err != nil {
+		return 0, err
+	}
+	var isiEmail interface{}
+	if j.Notifikasi.Email != nil {
+		b, err := json.Marshal(j.Notifikasi.Email)
+		if err != nil {
+			return 0, err
+		}
+		isiEmail = string(b)
+	}
+
+	res, err := m.db.Exec(`
+		INSERT INTO jadwal_notifikasi (judul, deskripsi, target, file_urls, sasaran, email, kirim_at, ulang, setiap,
+			sampai_at, sampai_jendela_id, kedaluwarsa_at, masa_berlaku_jam, status, berikutnya_at, dibuat_oleh)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		j.Notifikasi.Judul, j.Notifikasi.Deskripsi, j.Notifikasi.Target, j.Notifikasi.FileURLs, string(sasaran), isiEmail,
+		j.KirimAt, j.Ulang, j.Setiap, j.SampaiAt, j.SampaiJendelaID, j.Notifikasi.KedaluwarsaAt, j.MasaBerlakuJam,
+		JadwalAktif, j.KirimAt, j.DibuatOleh)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+const kolomJadwal = `id, judul, COALESCE(deskripsi, ''), COALESCE(file_urls, ''), sasaran, email IS NOT NULL, kirim_at,
+	ulang, setiap, sampai_at, sampai_jendela_id, kedaluwarsa_at, masa_berlaku_jam, status, berikutnya_at,
+	jumlah_kirim, terakhir_kirim_at, percobaan, COALESCE(galat_terakhir, ''), dibuat_oleh, created_at`
+
+// GetJadwal mengambil jadwal untuk admin; status kosong berarti semua. q dicari pada judul dan deskripsi.
+func (m *JadwalModel) GetJadwal(status string, k *halaman.Kueri) ([]JadwalNotifikasi, int, error) {
+	var f halaman.Filter
+	f.Sama("status", status)
+	f.Cari(k.Q, "judul", "deskripsi")
+
+	dari := " FROM jadwal_notifikasi WHERE " + f.SQL()
+	total, err := halaman.Hitung(m.db, dari, f.Args())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	potong, argsPotong := k.Potong()
+	rows, err := m.db.Query("SELECT "+kolomJadwal+dari+k.OrderBy()+potong, append(f.Args(), argsPotong...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := []JadwalNotifikasi{}
+	for rows.Next() {
+		var j JadwalNotifikasi
+		var fileURLs, sasaran string
+		var sampai, kedaluwarsa, berikutnya, terakhir sql.NullTime
+		var jendela, masaBerlaku sql.NullInt64
+		if err := rows.Scan(&j.ID, &j.Judul, &j.Deskripsi, &fileURLs, &sasaran, &j.Email, &j.KirimAt,
+			&j.Ulang, &j.Setiap, &sampai, &jendela, &kedaluwarsa, &masaBerlaku, &j.Status, &berikutnya,
+			&j.JumlahKirim, &terakhir, &j.Percobaan, &j.GalatTerakhir, &j.DibuatOleh, &j.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		j.FileURLs = []string{}
+		if fileURLs != "" {
+			_ = json.Unmarshal([]byte(fileURLs), &j.FileURLs)
+		}
+		_ = json.Unmarshal([]byte(sasaran), &j.Sasaran)
+		j.SampaiAt = waktuAtauNil(sampai)
+		j.KedaluwarsaAt = waktuAtauNil(kedaluwarsa)
+		j.BerikutnyaAt = waktuAtauNil(berikutnya)
+		j.TerakhirKirimAt = waktuAtauNil(terakhir)
+		if jendela.Valid {
+			v := int(jendela.Int64)
+			j.SampaiJendelaID = &v
+		}
+		if masaBerlaku.Valid {
+			v := int(masaBerlaku.Int64)
+			j.MasaBerlakuJam = &v
+		}
+		list = append(list, j)
+	}
+	return list, total, rows.Err()
+}
+
+func waktuAtauNil(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// Batalkan menghentikan jadwal yang masih aktif. sql.ErrNoRows bila jadwal tidak ada atau sudah
+// tidak aktif.
+func (m *JadwalModel) Batalkan(id int) error {
+	res, err := m.db.Exec(`UPDATE jadwal_notifikasi SET status = ?, berikutnya_at = NULL WHERE id = ? AND status = ?`,
+		JadwalDibatalkan, id, JadwalAktif)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// JadwalJatuhTempo adalah jadwal yang siap dijalankan beserta jumlah kegagalan beruntunnya
+type JadwalJatuhTempo struct {
+	ID        int
+	Percobaan int
+}
+
+// JatuhTempo mengambil jadwal aktif yang waktu kirimnya sudah tiba pada saat sekarang, kecuali yang
+// sedang menunggu jeda coba ulang setelah gagal
+func (m *JadwalModel) JatuhTempo(sekarang time.Time, batas int) ([]JadwalJatuhTempo, error) {
+	rows, err := m.db.Query(`SELECT id, percobaan FROM jadwal_notifikasi
+		WHERE status = ? AND berikutnya_at <= ? AND (coba_lagi_at IS NULL OR coba_lagi_at <= ?)
+		ORDER BY berikutnya_at, id LIMIT ?`, JadwalAktif, sekarang, sekarang, batas)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []JadwalJatuhTempo
+	for rows.Next() {
+		var j JadwalJatuhTempo
+		if err := rows.Scan(&j.ID, &j.Percobaan); err != nil {
+			return nil, err
+		}
+		list = append(list, j)
+	}
+	return list, rows.Err()
+}
+
+// Jalankan mengirim kiriman jadwal yang jatuh tempo lalu memajukan jadwal ke kiriman berikutnya setelah
+// sekarang, dalam satu transaksi dengan baris jadwal dikunci. Kiriman yang terlewat saat layanan mati
+// digabung menjadi satu kiriman. Setiap (jadwal, waktu kiriman) dicatat di jadwal_kiriman sehingga
+// tidak pernah dikirim dua kali. terkirim false bila tidak ada notifikasi yang dibuat, mis. jadwal sudah
+// dibatalkan, sudah dijalankan instance lain, atau batas pengulangannya terlewati.
+func (m *JadwalModel) Jalankan(id int, sekarang time.Time) (hasil HasilKirim, terkirim bool, err error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return hasil, false, err
+	}
+	defer tx.Rollback()
+
+	var (
+		n                      NotifikasiBaru
+		sasaran                string
+		isiEmail               sql.NullString
+		jadwalAt, kirimAt      time.Time
+		ulang                  string
+		setiap                 int
+		sampai, kedaluwarsa    sql.NullTime
+		jendelaID, masaBerlaku sql.NullInt64
+	)
+	err = tx.QueryRow(`
+		SELECT judul, COALESCE(deskripsi, ''), target, COALESCE(file_urls, ''), sasaran, email, kirim_at, berikutnya_at,
+			ulang, setiap, sampai_at, sampai_jendela_id, kedaluwarsa_at, masa_berlaku_jam
+		FROM jadwal_notifikasi
+		WHERE id = ? AND status = ? AND berikutnya_at <= ?
+		FOR UPDATE`, id, JadwalAktif, sekarang).Scan(&n.Judul, &n.Deskripsi, &n.Target, &n.FileURLs, &sasaran,
+		&isiEmail, &kirimAt, &jadwalAt, &ulang, &setiap, &sampai, &jendelaID, &kedaluwarsa, &masaBerlaku)
+	if err == sql.ErrNoRows {
+		return hasil, false, nil
+	}
+	if err != nil {
+		return hasil, false, err
+	}
+
+	// Batas pengulangan: sampai_at dan/atau waktu tutup jendela pengumpulan saat ini
+	var akhir *time.Time
+	if sampai.Valid {
+		akhir = &sampai.Time
+	}
+	var ditutup *time.Time
+	if jendelaID.Valid {
+		var t time.Time
+		err := tx.QueryRow("SELECT ditutup FROM jendela_pengumpulan WHERE id = ?", jendelaID.Int64).Scan(&t)
+		switch {
+		case err == sql.ErrNoRows:
+			// jendela dihapus: pengulangan dihentikan
+			t = jadwalAt
+		case err != nil:
+			return hasil, false, err
+		}
+		ditutup = &t
+		akhir = lebihAwal(akhir, ditutup)
+	}
+
+	if akhir == nil || jadwalAt.Before(*akhir) {
+		// Kiriman ikut kedaluwarsa saat jendela ditutup agar pengingat tidak tertinggal di inbox
+		batasTampil := lebihAwal(waktuAtauNil(kedaluwarsa), ditutup)
+		if masaBerlaku.Valid {
+			t := jadwalAt.Add(time.Duration(masaBerlaku.Int64) * time.Hour)
+			batasTampil = lebihAwal(batasTampil, &t)
+		}
+
+		var sudah bool
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM jadwal_kiriman WHERE jadwal_id = ? AND jadwal_at = ?)",
+			id, jadwalAt).Scan(&sudah); err != nil {
+			return hasil, false, err
+		}
+		if !sudah && (batasTampil == nil || batasTampil.After(sekarang)) {
+			if err := json.Unmarshal([]byte(sasaran), &n.Sasaran); err != nil {
+				return hasil, false, fmt.Errorf("%w: sasaran: %v", ErrJadwalRusak, err)
+			}
+			if isiEmail.Valid {
+				n.Email = &EmailNotifikasi{}
+				if err := json.Unmarshal([]byte(isiEmail.String), n.Email); err != nil {
+					return hasil, false, fmt.Errorf("%w: email: %v", ErrJadwalRusak, err)
+				}
+			}
+			n.KedaluwarsaAt = batasTampil
+			if hasil, err = kirimTx(tx, n); err != nil {
+				return hasil, false, err
+			}
+			if _, err := tx.Exec("INSERT INTO jadwal_kiriman (jadwal_id, jadwal_at, notification_id) VALUES (?, ?, ?)",
+				id, jadwalAt, hasil.ID); err != nil {
+				return hasil, false, err
+			}
+			terkirim = true
+		}
+	}
+
+	berikutnya := kirimanBerikutnya(kirimAt, ulang, setiap, sekarang)
+	if berikutnya != nil && akhir != nil && !berikutnya.Before(*akhir) {
+		berikutnya = nil
+	}
+	status := JadwalAktif
+	if berikutnya == nil {
+		status = JadwalSelesai
+	}
+	query := `UPDATE jadwal_notifikasi SET status = ?, berikutnya_at = ?, percobaan = 0, coba_lagi_at = NULL,
+		galat_terakhir = NULL WHERE id = ?`
+	if terkirim {
+		query = `UPDATE jadwal_notifikasi SET status = ?, berikutnya_at = ?, percobaan = 0, coba_lagi_at = NULL,
+			galat_terakhir = NULL, jumlah_kirim = jumlah_kirim + 1, terakhir_kirim_at = NOW() WHERE id = ?`
+	}
+	if _, err := tx.Exec(query, status, berikutnya, id); err != nil {
+		return hasil, false, err
+	}
+	return hasil, terkirim, tx.Commit()
+}
+
+// TandaiGagal mencatat kegagalan menjalankan jadwal. Bila cobaLagi nil, jadwal dipindah ke status gagal
+// dan tidak dijalankan lagi; selain itu jadwal dilewati sampai cobaLagi.
+func (m *JadwalModel) TandaiGagal(id int, galat string, cobaLagi *time.Time) error {
+	if cobaLagi == nil {
+		_, err := m.db.Exec(`UPDATE jadwal_notifikasi SET status = ?, berikutnya_at = NULL, percobaan = percobaan + 1,
+			coba_lagi_at = NULL, galat_terakhir = ? WHERE id = ? AND status = ?`, JadwalGagal, galat, id, JadwalAktif)
+		return err
+	}
+	_, err := m.db.Exec(`UPDATE jadwal_notifikasi SET percobaan = percobaan + 1, coba_lagi_at = ?, galat_terakhir = ?
+		WHERE id = ? AND status = ?`, cobaLagi, galat, id, JadwalAktif)
+	return err
+}
+
+// kirimanBerikutnya menghitung waktu kiriman pertama setelah sekarang dari pola pengulangan yang
+// berawal di kirimAt; nil untuk jadwal sekali kirim
+func kirimanBerikutnya(kirimAt time.Time, ulang string, setiap int, sekarang time.Time) *time.Time {
+	var periode time.Duration
+	switch ulang {
+	case UlangHarian:
+		periode = 24 * time.Hour
+	case UlangMingguan:
+		periode = 7 * 24 * time.Hour
+	default:
+		return nil
+	}
+	if setiap < 1 {
+		setiap = 1
+	}
+	periode *= time.Duration(setiap)
+
+	t := kirimAt
+	if !t.After(sekarang) {
+		t = t.Add((sekarang.Sub(t)/periode + 1) * periode)
+	}
+	return &t
+}
+
+func lebihAwal(a, b *time.Time) *time.Time {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.Before(*b) {
+		return a
+	}
+	return b
+}
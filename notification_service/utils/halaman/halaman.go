@@ -0,0 +1,225 @@
+// Package halaman menyeragamkan parameter endpoint daftar antarlayanan:
+//
+//	page, limit   halaman berbasis 1 dan jumlah baris per halaman (default 20, maksimal 100)
+//	cursor        pengganti page; nilainya diambil dari meta.next_cursor respons sebelumnya
+//	sort          nama field, awali "-" untuk urutan menurun (mis. sort=-created_at)
+//	q             pencarian bebas pada kolom yang ditentukan tiap endpoint
+//
+// Filter per field (jurusan, kelas, status, periode_id, ...) dibaca oleh masing-masing handler.
+// Bila page, limit, dan cursor tidak dikirim, seluruh hasil dikembalikan agar klien lama tetap berjalan.
+package halaman
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	LimitDefault = 20
+	LimitMaks    = 100
+)
+
+// Aturan menjelaskan urutan yang diizinkan untuk satu endpoint
+type Aturan struct {
+	Urut        map[string]string // nama field di query -> ekspresi SQL
+	UrutDefault string            // nama field, awali "-" untuk menurun
+	Kunci       string            // kolom unik sebagai pengurut terakhir agar halaman stabil
+}
+
+// Kueri adalah parameter daftar yang sudah divalidasi
+type Kueri struct {
+	Dipaging bool
+	Limit    int
+	Offset   int
+	Q        string
+	urut     string
+}
+
+// Meta menyertai data daftar pada respons
+type Meta struct {
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// Parse membaca parameter daftar dari query string
+func Parse(r *http.Request, a Aturan) (*Kueri, error) {
+	q := r.URL.Query()
+	k := &Kueri{Q: strings.TrimSpace(q.Get("q"))}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("limit harus bilangan bulat positif")
+		}
+		if n > LimitMaks {
+			n = LimitMaks
+		}
+		k.Limit, k.Dipaging = n, true
+	}
+	page := 1
+	if v := q.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("page harus bilangan bulat positif")
+		}
+		page, k.Dipaging = n, true
+	}
+	cursor := q.Get("cursor")
+	if cursor != "" {
+		k.Dipaging = true
+	}
+	if k.Dipaging && k.Limit == 0 {
+		k.Limit = LimitDefault
+	}
+	// cursor didahulukan bila keduanya dikirim
+	k.Offset = (page - 1) * k.Limit
+	if cursor != "" {
+		offset, err := bacaCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		k.Offset = offset
+	}
+
+	return urutan(k, a, q.Get("sort"))
+}
+
+func urutan(k *Kueri, a Aturan, sortParam string) (*Kueri, error) {
+	if sortParam == "" {
+		sortParam = a.UrutDefault
+	}
+	var bagian []string
+	for _, s := range strings.Split(sortParam, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		arah := "ASC"
+		if strings.HasPrefix(s, "-") {
+			arah, s = "DESC", s[1:]
+		}
+		kolom, ok := a.Urut[s]
+		if !ok {
+			return nil, fmt.Errorf("sort tidak dikenali: %s (pilihan: %s)", s, strings.Join(pilihan(a), ", "))
+		}
+		bagian = append(bagian, kolom+" "+arah)
+	}
+	if a.Kunci != "" {
+		bagian = append(bagian, a.Kunci)
+	}
+	k.urut = strings.Join(bagian, ", ")
+	return k, nil
+}
+
+func pilihan(a Aturan) []string {
+	var nama []string
+	for n := range a.Urut {
+		nama = append(nama, n)
+	}
+	sort.Strings(nama)
+	return nama
+}
+
+// OrderBy mengembalikan klausa ORDER BY, diawali spasi
+func (k *Kueri) OrderBy() string {
+	if k.urut == "" {
+		return ""
+	}
+	return " ORDER BY " + k.urut
+}
+
+// Potong mengembalikan klausa LIMIT/OFFSET beserta argumennya; kosong bila tidak dipaging
+func (k *Kueri) Potong() (string, []interface{}) {
+	if !k.Dipaging {
+		return "", nil
+	}
+	return " LIMIT ? OFFSET ?", []interface{}{k.Limit, k.Offset}
+}
+
+// Meta menyusun meta respons dari jumlah total baris yang cocok
+func (k *Kueri) Meta(total int) Meta {
+	if !k.Dipaging {
+		return Meta{Total: total, Page: 1, Limit: total}
+	}
+	m := Meta{Total: total, Page: k.Offset/k.Limit + 1, Limit: k.Limit}
+	if next := k.Offset + k.Limit; next < total {
+		m.NextCursor = tulisCursor(next)
+	}
+	return m
+}
+
+// Cursor menyandikan posisi baris berikutnya; sengaja dibuat tidak bermakna bagi klien
+func tulisCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o:" + strconv.Itoa(offset)))
+}
+
+func bacaCursor(v string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(v)
+	if err == nil && strings.HasPrefix(string(b), "o:") {
+		if n, err := strconv.Atoi(string(b[2:])); err == nil && n >= 0 {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("cursor tidak valid")
+}
+
+// Filter mengumpulkan kondisi WHERE beserta argumennya
+type Filter struct {
+	kondisi []string
+	args    []interface{}
+}
+
+// Tambah menambahkan kondisi SQL apa adanya
+func (f *Filter) Tambah(kondisi string, args ...interface{}) {
+	f.kondisi = append(f.kondisi, kondisi)
+	f.args = append(f.args, args...)
+}
+
+// Sama menambahkan kolom = nilai bila nilai tidak kosong
+func (f *Filter) Sama(kolom, nilai string) {
+	if nilai = strings.TrimSpace(nilai); nilai != "" {
+		f.Tambah(kolom+" = ?", nilai)
+	}
+}
+
+// Cari menambahkan pencarian LIKE pada salah satu kolom bila q tidak kosong
+func (f *Filter) Cari(q string, kolom ...string) {
+	if q == "" || len(kolom) == 0 {
+		return
+	}
+	pola := "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(q) + "%"
+	bagian := make([]string, len(kolom))
+	args := make([]interface{}, len(kolom))
+	for i, k := range kolom {
+		bagian[i] = k + " LIKE ?"
+		args[i] = pola
+	}
+	f.Tambah("("+strings.Join(bagian, " OR ")+")", args...)
+}
+
+// SQL menggabungkan kondisi dengan AND; "1=1" bila tidak ada kondisi
+func (f *Filter) SQL() string {
+	if len(f.kondisi) == 0 {
+		return "1=1"
+	}
+	return strings.Join(f.kondisi, " AND ")
+}
+
+// Args mengembalikan argumen kondisi sesuai urutan penambahan
+func (f *Filter) Args() []interface{} {
+	return f.args
+}
+
+// Hitung menjalankan SELECT COUNT(*) untuk klausa FROM ... WHERE yang sama dengan kueri data
+func Hitung(db *sql.DB, dari string, args []interface{}) (int, error) {
+	var total int
+	err := db.QueryRow("SELECT COUNT(*) "+dari, args...).Scan(&total)
+	return total, err
+}
@@ -27,6 +27,7 @@ type LoginResponse struct {
 	DosenID     int64  `json:"dosen_id"`
 	Token       string `json:"token"`
 	Role        string `json:"role"`
+	Tamu        bool   `json:"tamu,omitempty"`
 	Success     bool   `json:"success"`
 	RedirectURL string `json:"redirect_url"`
 }
@@ -93,17 +94,37 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate JWT
-	token, err := utils.GenerateJWT(user.Email, user.Role)
+	// Akun yang dinonaktifkan tidak dapat login; password dicek lebih dulu agar status akun tidak bocor
+	nonaktif, err := userModel.IsNonaktif(user.ID)
 	if err != nil {
-		log.Println("❌ Gagal generate token:", err)
+		log.Println("❌ Gagal cek status akun:", err)
 		http.Error(w, "Server error", http.StatusInternalServerError)
 		return
 	}
+	if nonaktif {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Akun Anda telah dinonaktifkan. Hubungi admin."})
+		return
+	}
 
 	var dosenID int64 = 0
-	if user.Role == "dosen" {
+	tamu := false
+	if strings.ToLower(user.Role) == "dosen" {
 		dosenID, _ = userModel.GetDosenIDByUserID(user.ID)
+		var err error
+		if tamu, err = userModel.IsPembimbingTamu(user.ID); err != nil {
+			log.Println("❌ Gagal cek pembimbing tamu:", err)
+			http.Error(w, "Server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Generate JWT
+	token, err := utils.GenerateJWTTamu(user.Email, user.Role, tamu)
+	if err != nil {
+		log.Println("❌ Gagal generate token:", err)
+		http.Error(w, "Server error", http.StatusInternalServerError)
+		return
 	}
 
 	// Kirim response (tanpa users)
@@ -113,6 +134,7 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		DosenID:     dosenID,
 		Token:       token,
 		Role:        user.Role,
+		Tamu:        tamu,
 		Success:     true,
 		RedirectURL: getDashboardURL(user.Role),
 	}
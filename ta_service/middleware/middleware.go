@@ -66,6 +66,12 @@ func RoleRedirectMiddleware(next http.Handler) http.Handler {
 				http.Redirect(w, r, "/dosen/dashboard", http.StatusSeeOther)
 				return
 			}
+			// Pembimbing tamu hanya boleh mengakses halaman bimbingan dan profil
+			if claims.Tamu && !halamanPembimbingTamu(path) {
+				log.Printf("🚫 Akses ditolak: Pembimbing tamu mencoba mengakses %s", path)
+				http.Redirect(w, r, "/dosen/dashboard", http.StatusSeeOther)
+				return
+			}
 		default:
 			log.Printf("🚫 ERROR: Role tidak valid: %s", claims.Role)
 			http.Redirect(w, r, "/loginusers", http.StatusSeeOther)
@@ -80,3 +86,20 @@ func RoleRedirectMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// halamanPembimbingTamu adalah daftar halaman dosen yang boleh diakses pembimbing tamu
+func halamanPembimbingTamu(path string) bool {
+	for _, prefix := range []string{
+		"/dosen/dashboard",
+		"/dosen/profile",
+		"/dosen/editprofile",
+		"/dosen/bimbingan_",
+		"/dosen/viewicp",
+		"/dosen/detailinformasidosen",
+	} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -63,3 +63,20 @@ func (u UserModel) GetDosenIDByUserID(userID int64) (int64, error) {
 	}
 	return dosenID, nil
 }
+
+// IsPembimbingTamu mengecek apakah user adalah dosen eksternal (pembimbing tamu)
+func (u UserModel) IsPembimbingTamu(userID int64) (bool, error) {
+	var eksternal bool
+	err := u.db.QueryRow("SELECT COALESCE(eksternal, 0) FROM dosen WHERE user_id = ?", userID).Scan(&eksternal)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return eksternal, err
+}
+
+// IsNonaktif mengecek apakah akun telah dinonaktifkan admin
+func (u UserModel) IsNonaktif(userID int64) (bool, error) {
+	var nonaktif bool
+	err := u.db.QueryRow("SELECT dinonaktifkan_at IS NOT NULL FROM users WHERE id = ?", userID).Scan(&nonaktif)
+	return nonaktif, err
+}
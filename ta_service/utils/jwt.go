@@ -14,17 +14,24 @@ var jwtKey = []byte("secret")
 type Claims struct {
 	Email string `json:"email"`
 	Role  string `json:"role"`
+	Tamu  bool   `json:"tamu,omitempty"` // pembimbing tamu (dosen eksternal) dengan akses terbatas
 	jwt.RegisteredClaims
 }
 
 // Fungsi untuk generate token JWT dengan role
 func GenerateJWT(email, role string) (string, error) {
+	return GenerateJWTTamu(email, role, false)
+}
+
+// GenerateJWTTamu membuat token JWT dan menandai pembimbing tamu
+func GenerateJWTTamu(email, role string, tamu bool) (string, error) {
 	// Standardize role to lowercase
 	role = strings.ToLower(role)
 
 	claims := &Claims{
 		Email: email,
 		Role:  role,
+		Tamu:  tamu,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   email,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
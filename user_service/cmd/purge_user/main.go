@@ -0,0 +1,57 @@
+// Command purge_user menghapus permanen akun yang sudah lama dinonaktifkan. Tanpa -terapkan hanya
+// menampilkan daftar kandidat. Setiap akun yang dihapus dicatat di audit_akun beserta salinan datanya.
+//
+//	go run ./cmd/purge_user -hari 365
+//	go run ./cmd/purge_user -hari 365 -terapkan -oleh "nama operator"
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+	"user_service/models"
+)
+
+func main() {
+	hari := flag.Int("hari", 365, "hapus akun yang dinonaktifkan lebih dari N hari lalu")
+	terapkan := flag.Bool("terapkan", false, "benar-benar menghapus; tanpa flag ini hanya menampilkan kandidat")
+	oleh := flag.String("oleh", "", "nama operator yang menjalankan purge (wajib dengan -terapkan)")
+	flag.Parse()
+
+	if *hari < 1 {
+		log.Fatal("-hari minimal 1")
+	}
+	if *terapkan && *oleh == "" {
+		log.Fatal("-oleh wajib diisi bersama -terapkan")
+	}
+
+	userModel, err := models.NewUserModel()
+	if err != nil {
+		log.Fatalf("Gagal koneksi database: %v", err)
+	}
+	defer userModel.Close()
+
+	kandidat, err := userModel.KandidatPurge(time.Now().AddDate(0, 0, -*hari))
+	if err != nil {
+		log.Fatalf("Gagal mengambil kandidat purge: %v", err)
+	}
+	log.Printf("%d akun nonaktif lebih dari %d hari", len(kandidat), *hari)
+
+	gagal := 0
+	for _, k := range kandidat {
+		log.Printf("- #%d %s <%s>, nonaktif sejak %s", k.UserID, k.NamaLengkap, k.Email, k.DinonaktifkanAt.Format("2006-01-02"))
+		if !*terapkan {
+			continue
+		}
+		if err := userModel.Purge(k.UserID, *oleh); err != nil {
+			gagal++
+			log.Printf("  gagal: %v", err)
+		}
+	}
+
+	if !*terapkan {
+		log.Print("Mode pratinjau; jalankan dengan -terapkan -oleh <nama> untuk menghapus")
+		return
+	}
+	log.Printf("Purge selesai: %d dihapus, %d gagal", len(kandidat)-gagal, gagal)
+}
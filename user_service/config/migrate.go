@@ -0,0 +1,97 @@
+package config
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Nama layanan dipakai sebagai kunci di schema_migrations karena database dipakai bersama
+const migrationService = "user_service"
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrate menjalankan file SQL di config/migrations yang belum tercatat di schema_migrations.
+// File dijalankan berurutan sesuai nama, masing-masing sekali saja.
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			service VARCHAR(64) NOT NULL,
+			versi VARCHAR(255) NOT NULL,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (service, versi)
+		)`)
+	if err != nil {
+		return fmt.Errorf("gagal membuat tabel schema_migrations: %v", err)
+	}
+
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		versi := strings.TrimPrefix(name, "migrations/")
+
+		var applied bool
+		err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE service = ? AND versi = ?)`,
+			migrationService, versi).Scan(&applied)
+		if err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+
+		content, err := migrationFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range splitStatements(string(content)) {
+			if _, err := db.Exec(stmt); err != nil {
+				return fmt.Errorf("migrasi %s gagal: %v", versi, err)
+			}
+		}
+
+		if _, err := db.Exec(`INSERT INTO schema_migrations (service, versi) VALUES (?, ?)`,
+			migrationService, versi); err != nil {
+			return err
+		}
+		log.Printf("Migrasi %s berhasil dijalankan", versi)
+	}
+
+	return nil
+}
+
+// splitStatements memecah isi file SQL per statement (diakhiri ';' di akhir baris)
+// dan membuang baris komentar "--".
+func splitStatements(content string) []string {
+	var (
+		stmts   []string
+		current strings.Builder
+	)
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmt := strings.TrimSuffix(strings.TrimSpace(current.String()), ";")
+			stmts = append(stmts, stmt)
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
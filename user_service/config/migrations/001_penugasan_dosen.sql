@@ -0,0 +1,16 @@
+-- Status aktif akun; dosen nonaktif (cuti, pensiun, dll.) tidak boleh ditugaskan
+ALTER TABLE users ADD COLUMN aktif TINYINT(1) NOT NULL DEFAULT 1;
+
+-- Catatan penugasan penguji/penelaah yang disimpan admin walaupun melanggar aturan
+CREATE TABLE IF NOT EXISTS penugasan_override_log (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	jenis VARCHAR(32) NOT NULL,
+	taruna_id INT NOT NULL,
+	final_id INT NOT NULL,
+	dosen_ids VARCHAR(255) NOT NULL,
+	pelanggaran TEXT NOT NULL,
+	alasan TEXT NOT NULL,
+	admin_email VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	INDEX idx_override_taruna (taruna_id)
+);
@@ -0,0 +1,10 @@
+-- Batas beban penugasan (penelaah + penguji) per dosen; NULL berarti memakai batas default
+ALTER TABLE dosen ADD COLUMN maks_beban INT NULL;
+
+-- Tag keahlian dosen untuk mencocokkan topik penelitian saat penugasan otomatis
+CREATE TABLE IF NOT EXISTS dosen_keahlian (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	dosen_id INT NOT NULL,
+	tag VARCHAR(100) NOT NULL,
+	UNIQUE KEY uq_dosen_keahlian (dosen_id, tag)
+);
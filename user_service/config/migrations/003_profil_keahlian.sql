@@ -0,0 +1,2 @@
+-- Uraian bidang/minat penelitian dosen; kata kunci keahlian disimpan di dosen_keahlian
+ALTER TABLE dosen ADD COLUMN bidang_penelitian TEXT NULL;
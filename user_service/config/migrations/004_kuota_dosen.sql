@@ -0,0 +1,10 @@
+-- Kuota penugasan dosen per tahun akademik dan peran; dosen_id 0 adalah kuota default semua dosen
+CREATE TABLE IF NOT EXISTS kuota_dosen (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	dosen_id INT NOT NULL DEFAULT 0,
+	tahun_akademik VARCHAR(9) NOT NULL,
+	peran ENUM('pembimbing', 'penelaah', 'penguji') NOT NULL,
+	kuota INT NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	UNIQUE KEY uq_kuota_dosen (dosen_id, tahun_akademik, peran)
+);
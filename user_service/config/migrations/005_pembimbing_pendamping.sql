@@ -0,0 +1,22 @@
+-- Pembimbing tamu (mis. dari industri) memiliki baris dosen dengan eksternal = 1 dan akses terbatas
+ALTER TABLE dosen ADD COLUMN eksternal TINYINT(1) NOT NULL DEFAULT 0;
+ALTER TABLE dosen ADD COLUMN instansi VARCHAR(255) NULL;
+
+-- Pembimbing pendamping (pembimbing 2); pembimbing utama tetap di dosbing_proposal
+CREATE TABLE IF NOT EXISTS dosbing_pendamping (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	user_id INT NOT NULL,
+	dosen_id INT NOT NULL,
+	tanggal_ditetapkan DATE NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'aktif',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	UNIQUE KEY uq_dosbing_pendamping_user (user_id),
+	INDEX idx_dosbing_pendamping_dosen (dosen_id)
+);
+
+-- Seluruh pembimbing taruna beserta perannya; dipakai juga oleh document_service
+CREATE OR REPLACE VIEW v_pembimbing_taruna AS
+	SELECT user_id, dosen_id, 'utama' AS peran, status, tanggal_ditetapkan FROM dosbing_proposal
+	UNION ALL
+	SELECT user_id, dosen_id, 'pendamping' AS peran, status, tanggal_ditetapkan FROM dosbing_pendamping;
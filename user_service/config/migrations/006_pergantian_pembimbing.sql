@@ -0,0 +1,47 @@
+-- Riwayat penetapan pembimbing; baris dengan tanggal_selesai NULL adalah pembimbing yang sedang berlaku
+CREATE TABLE IF NOT EXISTS riwayat_pembimbing (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	user_id INT NOT NULL,
+	dosen_id INT NOT NULL,
+	peran ENUM('utama', 'pendamping') NOT NULL,
+	tanggal_mulai DATE NOT NULL,
+	tanggal_selesai DATE NULL,
+	keterangan VARCHAR(255) NULL,
+	pergantian_id INT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	INDEX idx_riwayat_pembimbing_user (user_id, peran),
+	INDEX idx_riwayat_pembimbing_dosen (dosen_id)
+);
+
+-- Penetapan yang sudah ada sebelum riwayat dicatat
+INSERT INTO riwayat_pembimbing (user_id, dosen_id, peran, tanggal_mulai, keterangan)
+	SELECT user_id, dosen_id, peran, COALESCE(tanggal_ditetapkan, CURDATE()), 'Penetapan sebelum riwayat dicatat'
+	FROM v_pembimbing_taruna;
+
+-- Pengajuan pergantian pembimbing; disetujui berurutan oleh pembimbing lama, pembimbing baru, lalu admin/kaprodi
+CREATE TABLE IF NOT EXISTS pergantian_pembimbing (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	user_id INT NOT NULL,
+	peran ENUM('utama', 'pendamping') NOT NULL,
+	dosen_lama_id INT NOT NULL,
+	dosen_baru_id INT NOT NULL,
+	alasan TEXT NOT NULL,
+	diajukan_oleh INT NOT NULL,
+	status ENUM('menunggu_dosen_lama', 'menunggu_dosen_baru', 'menunggu_admin', 'disetujui', 'ditolak', 'dibatalkan') NOT NULL,
+	tanggal_efektif DATE NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	INDEX idx_pergantian_pembimbing_user (user_id),
+	INDEX idx_pergantian_pembimbing_status (status)
+);
+
+CREATE TABLE IF NOT EXISTS persetujuan_pergantian (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	pergantian_id INT NOT NULL,
+	tahap ENUM('dosen_lama', 'dosen_baru', 'admin') NOT NULL,
+	user_id INT NOT NULL,
+	keputusan ENUM('setuju', 'tolak') NOT NULL,
+	catatan TEXT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE KEY uq_persetujuan_pergantian_tahap (pergantian_id, tahap)
+);
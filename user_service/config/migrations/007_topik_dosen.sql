@@ -0,0 +1,29 @@
+-- Topik penelitian yang ditawarkan dosen; terisi bertambah setiap lamaran diterima
+CREATE TABLE IF NOT EXISTS topik_dosen (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	dosen_id INT NOT NULL,
+	judul VARCHAR(255) NOT NULL,
+	deskripsi TEXT NOT NULL,
+	prasyarat TEXT NULL,
+	slot INT NOT NULL DEFAULT 1,
+	terisi INT NOT NULL DEFAULT 0,
+	status ENUM('dibuka', 'ditutup') NOT NULL DEFAULT 'dibuka',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	INDEX idx_topik_dosen_dosen (dosen_id),
+	INDEX idx_topik_dosen_status (status)
+);
+
+-- Lamaran taruna (users.id) terhadap topik dosen
+CREATE TABLE IF NOT EXISTS lamaran_topik (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	topik_id INT NOT NULL,
+	user_id INT NOT NULL,
+	motivasi TEXT NOT NULL,
+	status ENUM('menunggu', 'diterima', 'ditolak', 'dibatalkan') NOT NULL DEFAULT 'menunggu',
+	catatan TEXT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	UNIQUE KEY uq_lamaran_topik (topik_id, user_id),
+	INDEX idx_lamaran_topik_user (user_id)
+);
@@ -0,0 +1,17 @@
+-- Akun tidak lagi dihapus: user yang dinonaktifkan tidak dapat login dan tidak muncul di pilihan,
+-- tetapi dokumen dan riwayat penugasannya tetap utuh. Penghapusan permanen hanya lewat cmd/purge_user.
+ALTER TABLE users ADD COLUMN dinonaktifkan_at DATETIME NULL;
+ALTER TABLE users ADD COLUMN dinonaktifkan_oleh INT NULL;
+ALTER TABLE users ADD COLUMN alasan_nonaktif VARCHAR(255) NULL;
+
+-- Jejak audit status akun: nonaktifkan, pulihkan, perubahan role, dan purge permanen
+CREATE TABLE IF NOT EXISTS audit_akun (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	user_id INT NOT NULL,
+	aksi ENUM('nonaktifkan', 'pulihkan', 'ubah_role', 'purge') NOT NULL,
+	oleh INT NULL,
+	keterangan TEXT,
+	data MEDIUMTEXT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	INDEX idx_audit_akun_user (user_id)
+);
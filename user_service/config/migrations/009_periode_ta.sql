@@ -0,0 +1,37 @@
+-- Tahun akademik sebagai data tersendiri; kode sama dengan format kuota_dosen.tahun_akademik (mis. 2025/2026)
+CREATE TABLE IF NOT EXISTS tahun_akademik (
+	kode VARCHAR(9) PRIMARY KEY,
+	tanggal_mulai DATE NOT NULL,
+	tanggal_selesai DATE NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Periode (gelombang) tugas akhir dalam satu tahun akademik. Periode ditutup tidak menerima taruna
+-- baru; periode diarsipkan tidak dapat diubah lagi dan disembunyikan dari daftar bawaan.
+CREATE TABLE IF NOT EXISTS periode_ta (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	tahun_akademik VARCHAR(9) NOT NULL,
+	gelombang INT NOT NULL,
+	nama VARCHAR(100) NOT NULL,
+	tanggal_mulai DATE NOT NULL,
+	tanggal_selesai DATE NOT NULL,
+	status ENUM('aktif', 'ditutup', 'diarsipkan') NOT NULL DEFAULT 'aktif',
+	ditutup_at DATETIME NULL,
+	diarsipkan_at DATETIME NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	UNIQUE KEY uq_periode_ta (tahun_akademik, gelombang),
+	CONSTRAINT fk_periode_ta_tahun FOREIGN KEY (tahun_akademik) REFERENCES tahun_akademik (kode)
+);
+
+-- Angkatan dan periode taruna saat ini; dokumen dan penugasan menyimpan periode saat dibuat
+ALTER TABLE taruna ADD COLUMN angkatan SMALLINT NULL;
+ALTER TABLE taruna ADD COLUMN periode_id INT NULL;
+ALTER TABLE taruna ADD INDEX idx_taruna_periode (periode_id);
+
+ALTER TABLE dosbing_proposal ADD COLUMN periode_id INT NULL;
+ALTER TABLE dosbing_pendamping ADD COLUMN periode_id INT NULL;
+ALTER TABLE penelaah_icp ADD COLUMN periode_id INT NULL;
+ALTER TABLE penguji_proposal ADD COLUMN periode_id INT NULL;
+ALTER TABLE penguji_laporan70 ADD COLUMN periode_id INT NULL;
+ALTER TABLE penguji_laporan100 ADD COLUMN periode_id INT NULL;
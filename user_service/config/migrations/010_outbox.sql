@@ -0,0 +1,16 @@
+-- Outbox event domain user_service (penugasan dosen). Event ditulis dalam transaksi yang sama dengan
+-- penugasannya, lalu dikirim relay ke konsumen minimal sekali. Event yang terus gagal berhenti di status 'gagal'.
+CREATE TABLE IF NOT EXISTS outbox_pengguna (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	event_id CHAR(32) NOT NULL,
+	tipe VARCHAR(64) NOT NULL,
+	payload JSON NOT NULL,
+	status ENUM('menunggu', 'terkirim', 'gagal') NOT NULL DEFAULT 'menunggu',
+	percobaan INT NOT NULL DEFAULT 0,
+	coba_lagi_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	galat_terakhir TEXT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	terkirim_at DATETIME NULL,
+	UNIQUE KEY uq_outbox_pengguna_event (event_id),
+	INDEX idx_outbox_pengguna_antre (status, coba_lagi_at)
+);
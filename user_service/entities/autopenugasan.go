@@ -0,0 +1,54 @@
+package entities
+
+// AutoPenugasanRequest adalah permintaan usulan penugasan otomatis untuk satu jenis penugasan.
+// TarunaIDs kosong berarti semua taruna yang belum lengkap penugasannya; PeriodeID 0 berarti semua
+// periode yang belum diarsipkan.
+type AutoPenugasanRequest struct {
+	Jenis     string `json:"jenis"`
+	TarunaIDs []int  `json:"taruna_ids"`
+	MaksBeban int    `json:"maks_beban"`
+	PeriodeID int    `json:"periode_id"`
+}
+
+// UsulanSlot adalah dosen yang diusulkan untuk satu peran
+type UsulanSlot struct {
+	Peran         string   `json:"peran"`
+	DosenID       int      `json:"dosen_id"`
+	NamaDosen     string   `json:"nama_dosen"`
+	Beban         int      `json:"beban"`
+	KeahlianCocok []string `json:"keahlian_cocok"`
+}
+
+// UsulanPenugasan adalah usulan penugasan untuk satu taruna
+type UsulanPenugasan struct {
+	TarunaID   int          `json:"taruna_id"`
+	NamaTaruna string       `json:"nama_taruna"`
+	FinalID    int          `json:"final_id"`
+	Topik      string       `json:"topik_penelitian"`
+	Slots      []UsulanSlot `json:"slots"`
+	Catatan    string       `json:"catatan,omitempty"`
+}
+
+// BebanDosen adalah jumlah penugasan dosen sebelum dan sesudah usulan diterapkan.
+// Batas diisi saat penerapan ditolak: "maks_beban" atau "kuota".
+type BebanDosen struct {
+	DosenID      int    `json:"dosen_id"`
+	NamaDosen    string `json:"nama_dosen"`
+	Beban        int    `json:"beban"`
+	BebanSesudah int    `json:"beban_sesudah"`
+	MaksBeban    int    `json:"maks_beban"`
+	Batas        string `json:"batas,omitempty"`
+}
+
+type PreviewAutoPenugasan struct {
+	Jenis  string            `json:"jenis"`
+	Usulan []UsulanPenugasan `json:"usulan"`
+	Beban  []BebanDosen      `json:"beban"`
+}
+
+// TerapkanAutoPenugasanRequest berisi usulan (yang mungkin sudah disunting admin) untuk disimpan
+type TerapkanAutoPenugasanRequest struct {
+	Jenis     string            `json:"jenis"`
+	Usulan    []UsulanPenugasan `json:"usulan"`
+	MaksBeban int               `json:"maks_beban"`
+}
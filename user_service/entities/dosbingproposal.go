@@ -5,4 +5,19 @@ type DosbingProposal struct {
 	TarunaID int    `json:"taruna_id"` // Ganti jadi taruna_id
 	DosenID  int    `json:"dosen_id"`
 	Status   string `json:"status,omitempty"`
+	Peran    string `json:"peran,omitempty"` // "utama" (default) atau "pendamping"
+
+	OverridePenugasan
+}
+
+// PembimbingTamu adalah akun pembimbing dari luar kampus dengan akses terbatas pada taruna bimbingannya
+type PembimbingTamu struct {
+	UserID      int    `json:"user_id"`
+	DosenID     int    `json:"dosen_id"`
+	NamaLengkap string `json:"nama_lengkap"`
+	Email       string `json:"email"`
+	Username    string `json:"username"`
+	Password    string `json:"password,omitempty"`
+	Instansi    string `json:"instansi"`
+	Jurusan     string `json:"jurusan"`
 }
@@ -1,8 +1,22 @@
 package entities
 
 type Dosen struct {
-	ID          int    `json:"id"`
-	UserID      int    `json:"user_id"`
-	NamaLengkap string `json:"nama_lengkap"`
-	Jurusan     string `json:"jurusan"`
+	ID               int      `json:"id"`
+	UserID           int      `json:"user_id"`
+	NamaLengkap      string   `json:"nama_lengkap"`
+	Jurusan          string   `json:"jurusan"`
+	Keahlian         []string `json:"keahlian,omitempty"`
+	BidangPenelitian string   `json:"bidang_penelitian,omitempty"`
+}
+
+// KecocokanDosen adalah skor kecocokan satu dosen terhadap topik penelitian
+type KecocokanDosen struct {
+	DosenID          int      `json:"dosen_id"`
+	NamaLengkap      string   `json:"nama_lengkap"`
+	Jurusan          string   `json:"jurusan"`
+	Keahlian         []string `json:"keahlian"`
+	BidangPenelitian string   `json:"bidang_penelitian"`
+	Skor             float64  `json:"skor"`
+	IstilahCocok     []string `json:"istilah_cocok"`
+	Pembimbing       bool     `json:"pembimbing"`
 }
@@ -0,0 +1,26 @@
+package entities
+
+// BarisImporUser adalah satu baris berkas impor user beserta hasil validasinya
+type BarisImporUser struct {
+	Baris          int      `json:"baris"` // nomor baris di berkas (baris 1 adalah header)
+	NamaLengkap    string   `json:"nama_lengkap"`
+	Email          string   `json:"email"`
+	Username       string   `json:"username"`
+	NPM            string   `json:"npm"`
+	Jurusan        string   `json:"jurusan"`
+	Kelas          string   `json:"kelas"`
+	Role           string   `json:"role"`
+	Password       string   `json:"password,omitempty"` // hanya dikirim untuk password buatan sistem setelah impor disimpan
+	PasswordDibuat bool     `json:"password_dibuat"`
+	Galat          []string `json:"galat"`
+}
+
+// HasilImporUser adalah laporan impor per baris; DryRun true berarti belum ada yang disimpan
+type HasilImporUser struct {
+	DryRun   bool             `json:"dry_run"`
+	Disimpan bool             `json:"disimpan"`
+	Total    int              `json:"total"`
+	Valid    int              `json:"valid"`
+	Invalid  int              `json:"invalid"`
+	Baris    []BarisImporUser `json:"baris"`
+}
@@ -0,0 +1,27 @@
+package entities
+
+// KuotaDosen adalah batas jumlah penugasan dosen untuk satu peran dalam satu tahun akademik.
+// DosenID 0 berarti kuota default yang berlaku bagi dosen tanpa kuota khusus.
+type KuotaDosen struct {
+	ID            int    `json:"id"`
+	DosenID       int    `json:"dosen_id"`
+	NamaDosen     string `json:"nama_dosen,omitempty"`
+	TahunAkademik string `json:"tahun_akademik"`
+	Peran         string `json:"peran"`
+	Kuota         int    `json:"kuota"`
+}
+
+// PemakaianKuota adalah kuota terpakai dan sisa satu peran; Kuota dan Sisa nil berarti tanpa batas
+type PemakaianKuota struct {
+	Peran    string `json:"peran"`
+	Kuota    *int   `json:"kuota"`
+	Terpakai int    `json:"terpakai"`
+	Sisa     *int   `json:"sisa"`
+}
+
+type LaporanKuotaDosen struct {
+	DosenID   int              `json:"dosen_id"`
+	NamaDosen string           `json:"nama_dosen"`
+	Jurusan   string           `json:"jurusan"`
+	Pemakaian []PemakaianKuota `json:"pemakaian"`
+}
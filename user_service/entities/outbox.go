@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent adalah event domain yang menunggu, sudah, atau gagal dikirim ke konsumen
+type OutboxEvent struct {
+	ID            int64           `json:"id"`
+	EventID       string          `json:"event_id"`
+	Tipe          string          `json:"tipe"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"` // menunggu | terkirim | gagal
+	Percobaan     int             `json:"percobaan"`
+	CobaLagiAt    time.Time       `json:"coba_lagi_at"`
+	GalatTerakhir string          `json:"galat_terakhir"`
+	CreatedAt     time.Time       `json:"created_at"`
+	TerkirimAt    *time.Time      `json:"terkirim_at"`
+}
@@ -6,4 +6,6 @@ type PenelaahICP struct {
 	FinalICPID  int `json:"final_icp_id"`
 	Penelaah1ID int `json:"penelaah_1_id"`
 	Penelaah2ID int `json:"penelaah_2_id"`
+
+	OverridePenugasan
 }
@@ -6,4 +6,6 @@ type PengujiLaporan70 struct {
 	FinalLaporan70ID int `json:"final_laporan70_id"`
 	Penguji1ID       int `json:"penguji_1_id"`
 	Penguji2ID       int `json:"penguji_2_id"`
+
+	OverridePenugasan
 }
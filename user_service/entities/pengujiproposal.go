@@ -7,4 +7,6 @@ type PengujiProposal struct {
 	KetuaID         int `json:"ketua_id"`
 	Penguji1ID      int `json:"penguji_1_id"`
 	Penguji2ID      int `json:"penguji_2_id"`
+
+	OverridePenugasan
 }
@@ -0,0 +1,22 @@
+package entities
+
+// SlotPenugasan adalah satu posisi penguji/penelaah beserta dosen yang dipilih
+type SlotPenugasan struct {
+	Peran   string
+	DosenID int
+}
+
+// Pelanggaran adalah satu aturan penugasan yang dilanggar
+type Pelanggaran struct {
+	Aturan       string `json:"aturan"`
+	Peran        string `json:"peran"`
+	DosenID      int    `json:"dosen_id"`
+	Pesan        string `json:"pesan"`
+	BisaOverride bool   `json:"bisa_override"`
+}
+
+// OverridePenugasan disertakan admin untuk tetap menyimpan penugasan yang melanggar aturan
+type OverridePenugasan struct {
+	Override       bool   `json:"override"`
+	AlasanOverride string `json:"alasan_override"`
+}
@@ -0,0 +1,59 @@
+package entities
+
+// PengajuanPergantianPembimbing adalah isi request taruna/dosen untuk mengganti pembimbing.
+// TarunaID hanya diisi bila pengaju adalah dosen atau admin; taruna selalu mengajukan untuk dirinya sendiri.
+type PengajuanPergantianPembimbing struct {
+	TarunaID    int    `json:"taruna_id"`
+	Peran       string `json:"peran"` // "utama" (default) atau "pendamping"
+	DosenBaruID int    `json:"dosen_baru_id"`
+	Alasan      string `json:"alasan"`
+}
+
+// KeputusanPergantianPembimbing adalah persetujuan atau penolakan satu tahap pergantian.
+// Override hanya dipakai admin pada tahap terakhir bila pembimbing baru melanggar aturan penugasan.
+type KeputusanPergantianPembimbing struct {
+	PergantianID int    `json:"pergantian_id"`
+	Setuju       bool   `json:"setuju"`
+	Catatan      string `json:"catatan"`
+
+	OverridePenugasan
+}
+
+type PersetujuanPergantian struct {
+	Tahap     string `json:"tahap"`
+	UserID    int    `json:"user_id"`
+	Nama      string `json:"nama"`
+	Keputusan string `json:"keputusan"`
+	Catatan   string `json:"catatan"`
+	Tanggal   string `json:"tanggal"`
+}
+
+type PergantianPembimbing struct {
+	ID             int                     `json:"id"`
+	TarunaID       int                     `json:"taruna_id"`
+	UserID         int                     `json:"user_id"`
+	NamaTaruna     string                  `json:"nama_taruna"`
+	Peran          string                  `json:"peran"`
+	DosenLamaID    int                     `json:"dosen_lama_id"`
+	NamaDosenLama  string                  `json:"nama_dosen_lama"`
+	DosenBaruID    int                     `json:"dosen_baru_id"`
+	NamaDosenBaru  string                  `json:"nama_dosen_baru"`
+	Alasan         string                  `json:"alasan"`
+	DiajukanOleh   int                     `json:"diajukan_oleh"`
+	Status         string                  `json:"status"`
+	TanggalEfektif string                  `json:"tanggal_efektif,omitempty"`
+	CreatedAt      string                  `json:"created_at"`
+	Persetujuan    []PersetujuanPergantian `json:"persetujuan"`
+}
+
+// RiwayatPembimbing adalah satu masa penetapan pembimbing; TanggalSelesai kosong berarti masih berlaku
+type RiwayatPembimbing struct {
+	ID             int    `json:"id"`
+	DosenID        int    `json:"dosen_id"`
+	NamaDosen      string `json:"nama_dosen"`
+	Peran          string `json:"peran"`
+	TanggalMulai   string `json:"tanggal_mulai"`
+	TanggalSelesai string `json:"tanggal_selesai,omitempty"`
+	Keterangan     string `json:"keterangan"`
+	PergantianID   *int   `json:"pergantian_id,omitempty"`
+}
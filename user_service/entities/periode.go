@@ -0,0 +1,30 @@
+package entities
+
+// TahunAkademikTA adalah satu tahun akademik (mis. "2025/2026") beserta rentang tanggalnya
+type TahunAkademikTA struct {
+	Kode           string `json:"kode"`
+	TanggalMulai   string `json:"tanggal_mulai"`
+	TanggalSelesai string `json:"tanggal_selesai"`
+}
+
+// PeriodeTA adalah satu gelombang tugas akhir dalam tahun akademik. Taruna didaftarkan ke satu
+// periode, dan setiap dokumen serta penugasan menyimpan periode taruna saat dibuat.
+type PeriodeTA struct {
+	ID             int     `json:"id"`
+	TahunAkademik  string  `json:"tahun_akademik"`
+	Gelombang      int     `json:"gelombang"`
+	Nama           string  `json:"nama"`
+	TanggalMulai   string  `json:"tanggal_mulai"`
+	TanggalSelesai string  `json:"tanggal_selesai"`
+	Status         string  `json:"status"`
+	DitutupAt      *string `json:"ditutup_at,omitempty"`
+	DiarsipkanAt   *string `json:"diarsipkan_at,omitempty"`
+	JumlahTaruna   int     `json:"jumlah_taruna"`
+}
+
+// PendaftaranPeriode memindahkan sejumlah taruna ke satu periode; Angkatan diisi bila ingin diperbarui
+type PendaftaranPeriode struct {
+	PeriodeID int   `json:"periode_id"`
+	TarunaIDs []int `json:"taruna_ids"`
+	Angkatan  *int  `json:"angkatan"`
+}
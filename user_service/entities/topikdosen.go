@@ -0,0 +1,37 @@
+package entities
+
+// TopikDosen adalah topik penelitian yang ditawarkan dosen kepada taruna
+type TopikDosen struct {
+	ID        int    `json:"id"`
+	DosenID   int    `json:"dosen_id"`
+	NamaDosen string `json:"nama_dosen"`
+	Jurusan   string `json:"jurusan"`
+	Judul     string `json:"judul"`
+	Deskripsi string `json:"deskripsi"`
+	Prasyarat string `json:"prasyarat"`
+	Slot      int    `json:"slot"`
+	Terisi    int    `json:"terisi"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+}
+
+type LamaranTopik struct {
+	ID         int    `json:"id"`
+	TopikID    int    `json:"topik_id"`
+	JudulTopik string `json:"judul_topik"`
+	DosenID    int    `json:"dosen_id"`
+	NamaDosen  string `json:"nama_dosen"`
+	TarunaID   int    `json:"taruna_id"`
+	NamaTaruna string `json:"nama_taruna"`
+	Motivasi   string `json:"motivasi"`
+	Status     string `json:"status"`
+	Catatan    string `json:"catatan"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// KeputusanLamaranTopik adalah keputusan dosen atas satu lamaran
+type KeputusanLamaranTopik struct {
+	LamaranID int    `json:"lamaran_id"`
+	Terima    bool   `json:"terima"`
+	Catatan   string `json:"catatan"`
+}
@@ -10,4 +10,8 @@ type User struct {
 	Jurusan     string `json:"jurusan"`
 	Kelas       string `json:"kelas,omitempty"` // omitempty karena Dosen tidak memiliki kelas
 	NPM         *int   `json:"npm,omitempty"`
+
+	// Terisi bila akun dinonaktifkan; akun nonaktif tidak dapat login dan tidak muncul di pilihan
+	DinonaktifkanAt *string `json:"dinonaktifkan_at,omitempty"`
+	AlasanNonaktif  string  `json:"alasan_nonaktif,omitempty"`
 }
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"user_service/entities"
+	"user_service/jobs"
+	"user_service/models"
+)
+
+func setAutoPenugasanHeader(w http.ResponseWriter) {
+	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Content-Type", "application/json")
+}
+
+// PreviewAutoPenugasan menyusun usulan penugasan penelaah/penguji untuk taruna yang belum lengkap
+// penugasannya, tanpa menyimpan apa pun
+func PreviewAutoPenugasan(w http.ResponseWriter, r *http.Request) {
+	setAutoPenugasanHeader(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hanyaAdmin(w, r) {
+		return
+	}
+
+	var req entities.AutoPenugasanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if !models.IsValidJenisPenugasan(req.Jenis) {
+		responJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"status":  "error",
+			"message": "Jenis penugasan tidak valid",
+		})
+		return
+	}
+
+	model, err := models.NewAutoPenugasanModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	preview, err := model.Usulkan(req)
+	if err != nil {
+		http.Error(w, "Gagal menyusun usulan: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   preview,
+	})
+}
+
+// TerapkanAutoPenugasan menyimpan usulan penugasan otomatis dalam satu transaksi.
+// Setiap usulan divalidasi dengan aturan penugasan yang sama seperti penugasan manual; penugasan
+// yang memerlukan override harus disimpan lewat form penugasan manual.
+func TerapkanAutoPenugasan(w http.ResponseWriter, r *http.Request) {
+	setAutoPenugasanHeader(w)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hanyaAdmin(w, r) {
+		return
+	}
+
+	var req entities.TerapkanAutoPenugasanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if !models.IsValidJenisPenugasan(req.Jenis) {
+		responJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"status":  "error",
+			"message": "Jenis penugasan tidak valid",
+		})
+		return
+	}
+	if len(req.Usulan) == 0 {
+		responJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"status":  "error",
+			"message": "Tidak ada usulan yang diterapkan",
+		})
+		return
+	}
+
+	peran := models.PeranPenugasan(req.Jenis)
+	for _, u := range req.Usulan {
+		lengkap := len(u.Slots) == len(peran) && u.FinalID != 0
+		for _, s := range u.Slots {
+			if s.DosenID == 0 {
+				lengkap = false
+			}
+		}
+		if !lengkap {
+			responJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"status":  "error",
+				"message": fmt.Sprintf("Usulan untuk taruna %d belum lengkap", u.TarunaID),
+			})
+			return
+		}
+	}
+
+	penugasanModel, err := models.NewPenugasanModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer penugasanModel.DB.Close()
+
+	pelanggaranTaruna := map[int][]entities.Pelanggaran{}
+	for _, u := range req.Usulan {
+		slots := make([]entities.SlotPenugasan, len(u.Slots))
+		for i, s := range u.Slots {
+			slots[i] = entities.SlotPenugasan{Peran: peran[i], DosenID: s.DosenID}
+		}
+		pelanggaran, err := penugasanModel.Validasi(req.Jenis, u.TarunaID, slots)
+		if err != nil {
+			responJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		if len(pelanggaran) > 0 {
+			pelanggaranTaruna[u.TarunaID] = pelanggaran
+		}
+	}
+	if len(pelanggaranTaruna) > 0 {
+		responJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"status":      "error",
+			"message":     "Sebagian usulan melanggar aturan penugasan; tidak ada yang disimpan",
+			"pelanggaran": pelanggaranTaruna,
+		})
+		return
+	}
+
+	model, err := models.NewAutoPenugasanModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	melebihi, err := model.Terapkan(req.Jenis, req.Usulan, req.MaksBeban)
+	if err != nil {
+		http.Error(w, "Gagal menyimpan penugasan: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(melebihi) > 0 {
+		responJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"status":   "error",
+			"message":  "Beban atau kuota dosen melebihi batas; tidak ada yang disimpan",
+			"melebihi": melebihi,
+		})
+		return
+	}
+
+	jobs.SegerakanOutbox()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"message": fmt.Sprintf("%d penugasan berhasil disimpan", len(req.Usulan)),
+	})
+}
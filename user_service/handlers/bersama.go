@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"user_service/models"
+	"user_service/utils"
+)
+
+// setCORSHeader mengatur header CORS dan JSON untuk method yang diizinkan
+func setCORSHeader(w http.ResponseWriter, methods string) {
+	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Allow-Methods", methods+", OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Content-Type", "application/json")
+}
+
+// responJSON menulis body JSON dengan status code
+func responJSON(w http.ResponseWriter, code int, body map[string]interface{}) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+// hanyaAdmin memastikan request dikirim oleh admin; bila bukan, response 403 ditulis
+func hanyaAdmin(w http.ResponseWriter, r *http.Request) bool {
+	claims, err := utils.ClaimsFromRequest(r)
+	if err != nil || strings.ToLower(claims.Role) != "admin" {
+		responJSON(w, http.StatusForbidden, map[string]interface{}{
+			"status":  "error",
+			"message": "Hanya admin yang dapat mengakses fitur ini",
+		})
+		return false
+	}
+	return true
+}
+
+// penggunaDariToken mengambil identitas pemanggil dari token; bila gagal, response error ditulis
+func penggunaDariToken(w http.ResponseWriter, r *http.Request, db *sql.DB) (*models.Pengguna, bool) {
+	claims, err := utils.ClaimsFromRequest(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	p, err := models.GetPengguna(db, claims.Email)
+	if err != nil {
+		http.Error(w, "Pengguna tidak ditemukan", http.StatusUnauthorized)
+		return nil, false
+	}
+	return p, true
+}
+
+// responGalat menulis models.ErrPermintaan sesuai kodenya; error lain dicatat dan menjadi 500
+func responGalat(w http.ResponseWriter, err error, pesan500 string) {
+	var galat *models.ErrPermintaan
+	if errors.As(err, &galat) {
+		responJSON(w, galat.Kode, map[string]interface{}{
+			"status":  "error",
+			"message": galat.Pesan,
+		})
+		return
+	}
+	log.Printf("%s: %v", pesan500, err)
+	http.Error(w, pesan500, http.StatusInternalServerError)
+}
@@ -3,10 +3,17 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"user_service/config"
 	"user_service/entities"
+	"user_service/jobs"
 	"user_service/models"
+	"user_service/utils"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 // AssignDosbingProposal digunakan untuk menyimpan data dosen pembimbing ke dalam database
@@ -33,20 +40,38 @@ func AssignDosbingProposal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if payload.Peran == "" {
+		payload.Peran = models.PeranPembimbingUtama
+	}
+	jenis, peran, ok := models.JenisPembimbing(payload.Peran)
+	if !ok {
+		http.Error(w, "Peran pembimbing harus utama atau pendamping", http.StatusBadRequest)
+		return
+	}
+
+	slots := []entities.SlotPenugasan{{Peran: peran, DosenID: payload.DosenID}}
+	if !validasiPenugasan(w, r, jenis, payload.TarunaID, 0, slots, payload.OverridePenugasan) {
+		return
+	}
+
 	model, err := models.NewDosbingModel()
 	if err != nil {
 		http.Error(w, "Database error", http.StatusInternalServerError)
 		return
 	}
 
-	if err := model.AssignPembimbing(&payload); err != nil {
+	dialihkan, err := model.AssignPembimbing(&payload)
+	if err != nil {
 		http.Error(w, "Failed to assign pembimbing", http.StatusInternalServerError)
 		return
 	}
 
+	jobs.SegerakanOutbox()
+
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":  "success",
-		"message": "Dosen pembimbing berhasil disimpan",
+		"status":            "success",
+		"message":           "Dosen pembimbing berhasil disimpan",
+		"dokumen_dialihkan": dialihkan,
 	})
 }
 
@@ -67,6 +92,12 @@ func GetTarunaWithDosbing(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
+	kondisiPeriode, argsPeriode := models.KondisiPeriode("t.periode_id", periodeID)
+
 	db, err := config.ConnectDB()
 	if err != nil {
 		http.Error(w, "Database connection error", http.StatusInternalServerError)
@@ -80,13 +111,17 @@ func GetTarunaWithDosbing(w http.ResponseWriter, r *http.Request) {
 			t.nama_lengkap AS nama_taruna,
 			t.jurusan,
 			t.kelas,
-			d.nama_lengkap AS dosen_pembimbing
+			d.nama_lengkap AS dosen_pembimbing,
+			dpd.nama_lengkap AS dosen_pendamping
 		FROM taruna t
 		LEFT JOIN dosbing_proposal dp ON dp.user_id = t.user_id
-		LEFT JOIN dosen d ON dp.dosen_id = d.id;
+		LEFT JOIN dosen d ON dp.dosen_id = d.id
+		LEFT JOIN dosbing_pendamping pd ON pd.user_id = t.user_id
+		LEFT JOIN dosen dpd ON pd.dosen_id = dpd.id
+		WHERE ` + kondisiPeriode + `;
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, argsPeriode...)
 	if err != nil {
 		http.Error(w, "Query execution error", http.StatusInternalServerError)
 		return
@@ -96,10 +131,10 @@ func GetTarunaWithDosbing(w http.ResponseWriter, r *http.Request) {
 	var result []map[string]interface{}
 	for rows.Next() {
 		var tarunaID int
-		var namaTaruna, jurusan, kelas, dosbing sql.NullString
+		var namaTaruna, jurusan, kelas, dosbing, pendamping sql.NullString
 
-		// Scan kelima kolom sesuai SELECT
-		if err := rows.Scan(&tarunaID, &namaTaruna, &jurusan, &kelas, &dosbing); err != nil {
+		// Scan keenam kolom sesuai SELECT
+		if err := rows.Scan(&tarunaID, &namaTaruna, &jurusan, &kelas, &dosbing, &pendamping); err != nil {
 			http.Error(w, "Row scan error", http.StatusInternalServerError)
 			return
 		}
@@ -110,6 +145,7 @@ func GetTarunaWithDosbing(w http.ResponseWriter, r *http.Request) {
 			"jurusan":          jurusan.String,
 			"kelas":            kelas.String,
 			"dosen_pembimbing": dosbing.String,
+			"dosen_pendamping": pendamping.String,
 		})
 	}
 
@@ -118,3 +154,131 @@ func GetTarunaWithDosbing(w http.ResponseWriter, r *http.Request) {
 		"data":   result,
 	})
 }
+
+// HapusDosbingPendamping menghapus pembimbing pendamping taruna (DELETE ?taruna_id=)
+func HapusDosbingPendamping(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Allow-Methods", "DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hanyaAdmin(w, r) {
+		return
+	}
+
+	tarunaID, err := strconv.Atoi(r.URL.Query().Get("taruna_id"))
+	if err != nil {
+		http.Error(w, "taruna_id tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	model, err := models.NewDosbingModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	ok, err := model.HapusPendamping(tarunaID)
+	if err != nil {
+		http.Error(w, "Gagal menghapus pembimbing pendamping", http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, "Taruna belum memiliki pembimbing pendamping", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"message": "Pembimbing pendamping berhasil dihapus",
+	})
+}
+
+// PembimbingTamuHandler menampilkan (GET) dan membuat (POST) akun pembimbing tamu (admin).
+// Pembimbing tamu login sebagai dosen tetapi hanya dapat mengakses halaman bimbingan.
+func PembimbingTamuHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !hanyaAdmin(w, r) {
+		return
+	}
+
+	model, err := models.NewDosbingModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	switch r.Method {
+	case http.MethodGet:
+		list, err := model.GetPembimbingTamu()
+		if err != nil {
+			http.Error(w, "Gagal mengambil pembimbing tamu", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   list,
+		})
+
+	case http.MethodPost:
+		var payload entities.PembimbingTamu
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		payload.NamaLengkap = strings.TrimSpace(payload.NamaLengkap)
+		payload.Email = strings.ToLower(strings.TrimSpace(payload.Email))
+		payload.Username = strings.TrimSpace(payload.Username)
+		payload.Instansi = strings.TrimSpace(payload.Instansi)
+		if payload.NamaLengkap == "" || payload.Email == "" || payload.Username == "" || payload.Instansi == "" {
+			http.Error(w, "nama_lengkap, email, username, dan instansi harus diisi", http.StatusBadRequest)
+			return
+		}
+		if !utils.IsValidPassword(payload.Password) {
+			http.Error(w, "Password harus minimal 8 karakter, mengandung huruf besar, huruf kecil, angka, dan simbol", http.StatusBadRequest)
+			return
+		}
+
+		hashed, err := bcrypt.GenerateFromPassword([]byte(payload.Password), bcrypt.DefaultCost)
+		if err != nil {
+			http.Error(w, "Gagal memproses password", http.StatusInternalServerError)
+			return
+		}
+		if err := model.CreatePembimbingTamu(&payload, string(hashed)); err != nil {
+			log.Printf("Gagal membuat pembimbing tamu: %v", err)
+			http.Error(w, "Gagal membuat pembimbing tamu (email/username mungkin sudah dipakai)", http.StatusConflict)
+			return
+		}
+		payload.Password = ""
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "success",
+			"message": "Pembimbing tamu berhasil dibuat",
+			"data":    payload,
+		})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
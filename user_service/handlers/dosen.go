@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"user_service/entities"
 	"user_service/models"
 	"user_service/utils"
@@ -30,13 +32,21 @@ func GetAllDosen(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	k, ok := kueriDaftar(w, r, models.AturanDaftarDosen)
+	if !ok {
+		return
+	}
+
 	dosenModel, err := models.NewDosenModel()
 	if err != nil {
 		http.Error(w, "Database connection error", http.StatusInternalServerError)
 		return
 	}
 
-	dosens, err := dosenModel.GetAllDosen()
+	dosens, total, err := dosenModel.GetAllDosen(models.FilterDosen{
+		Jurusan: r.URL.Query().Get("jurusan"),
+		Aktif:   r.URL.Query().Get("aktif"),
+	}, k)
 	if err != nil {
 		http.Error(w, "Failed to fetch dosen data", http.StatusInternalServerError)
 		return
@@ -45,6 +55,7 @@ func GetAllDosen(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status": "success",
 		"data":   dosens,
+		"meta":   k.Meta(total),
 	})
 }
 
@@ -99,8 +110,27 @@ func EditUserDosen(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// Sertakan profil keahlian bila user adalah dosen
+		dosenModel, err := models.NewDosenModel()
+		if err != nil {
+			log.Printf("Database connection error: %v", err)
+			http.Error(w, "Database connection error", http.StatusInternalServerError)
+			return
+		}
+		keahlian, bidang, err := dosenModel.GetProfilKeahlian(userID)
+		if err != nil && err != sql.ErrNoRows {
+			log.Printf("Failed to get profil keahlian: %v", err)
+		}
+		if keahlian == nil {
+			keahlian = []string{}
+		}
+
 		// Kirim response dalam format JSON
-		json.NewEncoder(w).Encode(user)
+		json.NewEncoder(w).Encode(struct {
+			*entities.User
+			Keahlian         []string `json:"keahlian"`
+			BidangPenelitian string   `json:"bidang_penelitian"`
+		}{user, keahlian, bidang})
 
 	} else if r.Method == http.MethodPut {
 		// Parse request body
@@ -112,6 +142,10 @@ func EditUserDosen(w http.ResponseWriter, r *http.Request) {
 			Role     string `json:"role"`
 			Jurusan  string `json:"jurusan"`
 			Password string `json:"password,omitempty"`
+
+			// Opsional; bila tidak dikirim, profil keahlian tidak diubah
+			Keahlian         *[]string `json:"keahlian"`
+			BidangPenelitian *string   `json:"bidang_penelitian"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&userData); err != nil {
@@ -135,7 +169,7 @@ func EditUserDosen(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Update data user
-		err = userModel.UpdateUser(userData.UserID, userData.FullName, userData.Email, userData.Username, "dosen", userData.Jurusan, "", nil, nil)
+		err = userModel.UpdateUser(userData.UserID, userData.FullName, userData.Email, userData.Username, "dosen", userData.Jurusan, "", nil, nil, idPelaku(r, userModel))
 		if err != nil {
 			log.Printf("Failed to update user: %v", err)
 			http.Error(w, "Gagal mengupdate user", http.StatusInternalServerError)
@@ -157,6 +191,26 @@ func EditUserDosen(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if userData.Keahlian != nil || userData.BidangPenelitian != nil {
+			keahlian, bidang, err := dosenModel.GetProfilKeahlian(userData.UserID)
+			if err != nil {
+				log.Printf("Failed to get profil keahlian: %v", err)
+				http.Error(w, "Gagal mengupdate profil keahlian", http.StatusInternalServerError)
+				return
+			}
+			if userData.Keahlian != nil {
+				keahlian = *userData.Keahlian
+			}
+			if userData.BidangPenelitian != nil {
+				bidang = *userData.BidangPenelitian
+			}
+			if err := dosenModel.UpdateProfilKeahlian(userData.UserID, keahlian, bidang); err != nil {
+				log.Printf("Failed to update profil keahlian: %v", err)
+				http.Error(w, "Gagal mengupdate profil keahlian", http.StatusInternalServerError)
+				return
+			}
+		}
+
 		// Jika password diisi, hash dan update password
 		if userData.Password != "" {
 			// Validasi kekuatan password
@@ -189,3 +243,59 @@ func EditUserDosen(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
+
+// SetDosenAktif mengaktifkan / menonaktifkan dosen (admin)
+func SetDosenAktif(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, err := utils.ClaimsFromRequest(r)
+	if err != nil || strings.ToLower(claims.Role) != "admin" {
+		utils.RespondWithError(w, http.StatusForbidden, "Hanya admin yang dapat mengubah status dosen")
+		return
+	}
+
+	var payload struct {
+		UserID int  `json:"user_id"`
+		Aktif  bool `json:"aktif"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.UserID == 0 {
+		utils.RespondWithError(w, http.StatusBadRequest, "user_id wajib diisi")
+		return
+	}
+
+	dosenModel, err := models.NewDosenModel()
+	if err != nil {
+		http.Error(w, "Database connection error", http.StatusInternalServerError)
+		return
+	}
+
+	ok, err := dosenModel.SetAktif(payload.UserID, payload.Aktif)
+	if err != nil {
+		log.Printf("Gagal mengubah status aktif dosen %d: %v", payload.UserID, err)
+		http.Error(w, "Failed to update dosen", http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		utils.RespondWithError(w, http.StatusNotFound, "Dosen tidak ditemukan")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"message": "Status dosen berhasil diubah",
+	})
+}
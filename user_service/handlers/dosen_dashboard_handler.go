@@ -23,6 +23,7 @@ type DosenDashboardResponse struct {
 type BimbinganResponse struct {
 	NamaTaruna string `json:"nama_taruna"`
 	Jurusan    string `json:"jurusan"`
+	Peran      string `json:"peran"` // utama atau pendamping
 }
 
 func DosenDashboardHandler(w http.ResponseWriter, r *http.Request) {
@@ -219,8 +220,8 @@ func GetBimbinganByDosenHandler(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	query := `
-		SELECT t.nama_lengkap, t.jurusan
-		FROM dosbing_proposal d
+		SELECT t.nama_lengkap, t.jurusan, d.peran
+		FROM v_pembimbing_taruna d
 		JOIN users u ON d.user_id = u.id
 		JOIN taruna t ON u.id = t.user_id
 		WHERE d.dosen_id = ?
@@ -235,8 +236,8 @@ func GetBimbinganByDosenHandler(w http.ResponseWriter, r *http.Request) {
 
 	var results []BimbinganResponse
 	for rows.Next() {
-		var nama, jurusan string
-		err := rows.Scan(&nama, &jurusan)
+		var nama, jurusan, peran string
+		err := rows.Scan(&nama, &jurusan, &peran)
 		if err != nil {
 			http.Error(w, "Scan error", http.StatusInternalServerError)
 			return
@@ -244,6 +245,7 @@ func GetBimbinganByDosenHandler(w http.ResponseWriter, r *http.Request) {
 		results = append(results, BimbinganResponse{
 			NamaTaruna: nama,
 			Jurusan:    jurusan,
+			Peran:      peran,
 		})
 	}
 
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+	"user_service/utils/halaman"
+)
+
+// kueriDaftar membaca page/limit/cursor/sort/q untuk endpoint daftar; respons 400 bila tidak valid
+func kueriDaftar(w http.ResponseWriter, r *http.Request, aturan halaman.Aturan) (*halaman.Kueri, bool) {
+	k, err := halaman.Parse(r, aturan)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return k, true
+}
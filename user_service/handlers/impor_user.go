@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"user_service/entities"
+	"user_service/models"
+	"user_service/utils"
+	"user_service/utils/lembarkerja"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	maksUkuranImpor = 5 << 20 // 5MB
+	maksBarisImpor  = 2000
+)
+
+// kolomImpor memetakan judul kolom (huruf kecil, spasi diganti _) ke field BarisImporUser
+var kolomImpor = map[string]string{
+	"nama":         "nama",
+	"nama_lengkap": "nama",
+	"email":        "email",
+	"npm":          "npm",
+	"jurusan":      "jurusan",
+	"kelas":        "kelas",
+	"role":         "role",
+	"peran":        "role",
+	"username":     "username",
+	"password":     "password",
+}
+
+// ImportUsers mengimpor user secara massal dari berkas CSV/XLSX (multipart field "file") dengan
+// kolom nama, email, npm, jurusan, kelas, role, serta username dan password opsional. Secara default
+// berjalan sebagai dry-run yang hanya mengembalikan laporan validasi per baris; dengan dry_run=false
+// semua baris disimpan dalam satu transaksi, dan impor ditolak seluruhnya bila ada baris yang tidak valid.
+// Password yang dikosongkan dibuat otomatis dan hanya ditampilkan sekali pada respons penyimpanan.
+func ImportUsers(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hanyaAdmin(w, r) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maksUkuranImpor+1<<20)
+	if err := r.ParseMultipartForm(maksUkuranImpor); err != nil {
+		http.Error(w, "Berkas terlalu besar (maksimal 5MB) atau form tidak valid", http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Berkas impor (field file) wajib diunggah", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maksUkuranImpor+1))
+	if err != nil || len(data) > maksUkuranImpor {
+		http.Error(w, "Berkas terlalu besar (maksimal 5MB)", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := lembarkerja.Baca(header.Filename, data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	baris, pesan := barisImpor(rows)
+	if pesan != "" {
+		http.Error(w, pesan, http.StatusBadRequest)
+		return
+	}
+
+	dryRun := true
+	switch strings.ToLower(r.FormValue("dry_run")) {
+	case "false", "0", "tidak":
+		dryRun = false
+	}
+
+	userModel, err := models.NewUserModel()
+	if err != nil {
+		log.Printf("Database connection error: %v", err)
+		http.Error(w, "Database connection error", http.StatusInternalServerError)
+		return
+	}
+	defer userModel.Close()
+
+	if err := userModel.ValidasiImpor(baris); err != nil {
+		log.Printf("Gagal memvalidasi impor user: %v", err)
+		http.Error(w, "Gagal memvalidasi data impor", http.StatusInternalServerError)
+		return
+	}
+
+	// Password dari berkas tidak pernah dikirim balik; hanya password buatan sistem yang ditampilkan
+	password := make([]string, len(baris))
+	for i := range baris {
+		password[i], baris[i].Password = baris[i].Password, ""
+	}
+
+	hasil := entities.HasilImporUser{DryRun: dryRun, Total: len(baris), Baris: baris}
+	for _, b := range baris {
+		if len(b.Galat) == 0 {
+			hasil.Valid++
+		} else {
+			hasil.Invalid++
+		}
+	}
+
+	if dryRun {
+		utils.RespondWithJSON(w, http.StatusOK, hasil)
+		return
+	}
+	if hasil.Invalid > 0 {
+		utils.RespondWithJSON(w, http.StatusUnprocessableEntity, hasil)
+		return
+	}
+
+	// Hash dihitung sebelum transaksi dibuka agar transaksi tidak menahan lock terlalu lama
+	hashed := make([]string, len(baris))
+	for i := range baris {
+		if baris[i].PasswordDibuat {
+			if password[i], err = utils.GeneratePassword(12); err != nil {
+				http.Error(w, "Gagal membuat password", http.StatusInternalServerError)
+				return
+			}
+		}
+		h, err := bcrypt.GenerateFromPassword([]byte(password[i]), bcrypt.DefaultCost)
+		if err != nil {
+			http.Error(w, "Error hashing password", http.StatusInternalServerError)
+			return
+		}
+		hashed[i] = string(h)
+	}
+
+	if err := userModel.Impor(baris, hashed); err != nil {
+		log.Printf("Impor user dibatalkan: %v", err)
+		http.Error(w, "Impor dibatalkan, tidak ada user yang disimpan: "+err.Error(), http.StatusConflict)
+		return
+	}
+	log.Printf("✅ Impor user: %d user ditambahkan", len(baris))
+
+	for i := range baris {
+		if baris[i].PasswordDibuat {
+			baris[i].Password = password[i]
+		}
+	}
+	hasil.Disimpan = true
+	utils.RespondWithJSON(w, http.StatusCreated, hasil)
+}
+
+// barisImpor mengubah isi berkas menjadi baris impor berdasarkan header di baris pertama
+func barisImpor(rows [][]string) ([]entities.BarisImporUser, string) {
+	if len(rows) == 0 {
+		return nil, "Berkas kosong"
+	}
+
+	indeks := map[string]int{}
+	for i, judul := range rows[0] {
+		kunci := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(judul)), " ", "_")
+		if field, ok := kolomImpor[kunci]; ok {
+			if _, sudah := indeks[field]; !sudah {
+				indeks[field] = i
+			}
+		}
+	}
+	for _, wajib := range []string{"nama", "email", "role"} {
+		if _, ok := indeks[wajib]; !ok {
+			return nil, "Kolom " + wajib + " tidak ditemukan di header berkas"
+		}
+	}
+
+	ambil := func(row []string, field string) string {
+		i, ok := indeks[field]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var baris []entities.BarisImporUser
+	for n, row := range rows[1:] {
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		baris = append(baris, entities.BarisImporUser{
+			Baris:       n + 2,
+			NamaLengkap: ambil(row, "nama"),
+			Email:       ambil(row, "email"),
+			Username:    ambil(row, "username"),
+			NPM:         ambil(row, "npm"),
+			Jurusan:     ambil(row, "jurusan"),
+			Kelas:       ambil(row, "kelas"),
+			Role:        ambil(row, "role"),
+			Password:    ambil(row, "password"),
+		})
+	}
+	if len(baris) == 0 {
+		return nil, "Berkas tidak berisi data"
+	}
+	if len(baris) > maksBarisImpor {
+		return nil, "Maksimal 2000 baris per impor"
+	}
+	return baris, ""
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"user_service/models"
+)
+
+// GetKecocokanDosen memeringkat dosen berdasarkan kecocokan keahlian dengan topik penelitian.
+// Topik dan abstrak dapat dikirim langsung, atau diambil dari berkas terbaru taruna lewat taruna_id.
+// Dipakai admin saat memilih pembimbing/penguji dan taruna saat memilih topik.
+func GetKecocokanDosen(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload struct {
+		TopikPenelitian string `json:"topik_penelitian"`
+		Abstrak         string `json:"abstrak"`
+		TarunaID        int    `json:"taruna_id"`
+		Limit           int    `json:"limit"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	model, err := models.NewKecocokanModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	var userIDTaruna int
+	topik := strings.TrimSpace(payload.TopikPenelitian)
+	if payload.TarunaID != 0 {
+		var topikTaruna string
+		userIDTaruna, topikTaruna, err = model.TopikTaruna(payload.TarunaID)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Taruna tidak ditemukan", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Database error", http.StatusInternalServerError)
+			return
+		}
+		if topik == "" {
+			topik = topikTaruna
+		}
+	}
+
+	teks := strings.TrimSpace(topik + " " + payload.Abstrak)
+	if teks == "" {
+		http.Error(w, "topik_penelitian atau abstrak harus diisi", http.StatusBadRequest)
+		return
+	}
+
+	hasil, err := model.Peringkat(teks, userIDTaruna)
+	if err != nil {
+		http.Error(w, "Gagal menghitung kecocokan: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if payload.Limit > 0 && payload.Limit < len(hasil) {
+		hasil = hasil[:payload.Limit]
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":           "success",
+		"topik_penelitian": topik,
+		"data":             hasil,
+	})
+}
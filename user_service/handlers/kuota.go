@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"user_service/entities"
+	"user_service/models"
+)
+
+// tahunAkademikDariQuery mengambil ?tahun_akademik=, atau tahun akademik yang sedang berjalan bila kosong
+func tahunAkademikDariQuery(r *http.Request, model *models.KuotaModel) (string, error) {
+	if tahun := strings.TrimSpace(r.URL.Query().Get("tahun_akademik")); tahun != "" {
+		return tahun, nil
+	}
+	return model.TahunAkademikBerjalan()
+}
+
+// KuotaDosenHandler mengelola kuota penugasan dosen per tahun akademik (admin).
+// GET ?tahun_akademik= menampilkan pengaturan, POST menyimpan, DELETE ?id= menghapus.
+func KuotaDosenHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !hanyaAdmin(w, r) {
+		return
+	}
+
+	model, err := models.NewKuotaModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	switch r.Method {
+	case http.MethodGet:
+		tahun, err := tahunAkademikDariQuery(r, model)
+		if err != nil {
+			http.Error(w, "Gagal menentukan tahun akademik", http.StatusInternalServerError)
+			return
+		}
+		if !models.IsValidTahunAkademik(tahun) {
+			http.Error(w, "tahun_akademik tidak valid (format YYYY/YYYY)", http.StatusBadRequest)
+			return
+		}
+		list, err := model.GetKuota(tahun)
+		if err != nil {
+			http.Error(w, "Gagal mengambil kuota", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":         "success",
+			"tahun_akademik": tahun,
+			"data":           list,
+		})
+
+	case http.MethodPost:
+		var payload entities.KuotaDosen
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if !models.IsValidTahunAkademik(payload.TahunAkademik) {
+			http.Error(w, "tahun_akademik tidak valid (format YYYY/YYYY)", http.StatusBadRequest)
+			return
+		}
+		if !models.IsValidPeranKuota(payload.Peran) {
+			http.Error(w, "peran harus pembimbing, penelaah, atau penguji", http.StatusBadRequest)
+			return
+		}
+		if payload.Kuota < 0 || payload.DosenID < 0 {
+			http.Error(w, "kuota dan dosen_id tidak boleh negatif", http.StatusBadRequest)
+			return
+		}
+		if err := model.SimpanKuota(&payload); err != nil {
+			http.Error(w, "Gagal menyimpan kuota", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "success",
+			"message": "Kuota berhasil disimpan",
+		})
+
+	case http.MethodDelete:
+		id, err := strconv.Atoi(r.URL.Query().Get("id"))
+		if err != nil {
+			http.Error(w, "ID tidak valid", http.StatusBadRequest)
+			return
+		}
+		ok, err := model.HapusKuota(id)
+		if err != nil {
+			http.Error(w, "Gagal menghapus kuota", http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.Error(w, "Kuota tidak ditemukan", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "success",
+			"message": "Kuota berhasil dihapus",
+		})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetLaporanKuota menampilkan kuota terpakai dan sisa setiap dosen untuk semua peran
+func GetLaporanKuota(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hanyaAdmin(w, r) {
+		return
+	}
+
+	model, err := models.NewKuotaModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	tahun, err := tahunAkademikDariQuery(r, model)
+	if err != nil {
+		http.Error(w, "Gagal menentukan tahun akademik", http.StatusInternalServerError)
+		return
+	}
+	if !models.IsValidTahunAkademik(tahun) {
+		http.Error(w, "tahun_akademik tidak valid (format YYYY/YYYY)", http.StatusBadRequest)
+		return
+	}
+
+	laporan, err := model.Laporan(tahun)
+	if err != nil {
+		http.Error(w, "Gagal menyusun laporan kuota", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":         "success",
+		"tahun_akademik": tahun,
+		"data":           laporan,
+	})
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"user_service/jobs"
+	"user_service/models"
+)
+
+// OutboxHandler khusus admin untuk memantau event penugasan yang dikirim ke service lain.
+// GET menampilkan outbox (?status=menunggu|terkirim|gagal, page/limit/cursor, sort, q);
+// POST ?id= mengantrekan ulang event yang berstatus gagal.
+func OutboxHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "GET, POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !hanyaAdmin(w, r) {
+		return
+	}
+
+	model, err := models.NewOutboxModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	switch r.Method {
+	case http.MethodGet:
+		status := r.URL.Query().Get("status")
+		switch status {
+		case "", models.OutboxMenunggu, models.OutboxTerkirim, models.OutboxGagal:
+		default:
+			http.Error(w, "status tidak valid", http.StatusBadRequest)
+			return
+		}
+		k, ok := kueriDaftar(w, r, models.AturanOutbox)
+		if !ok {
+			return
+		}
+		list, total, err := model.GetOutbox(status, k)
+		if err != nil {
+			http.Error(w, "Gagal mengambil outbox", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   list,
+			"meta":   k.Meta(total),
+		})
+
+	case http.MethodPost:
+		id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "id tidak valid", http.StatusBadRequest)
+			return
+		}
+		err = model.AntrekanUlang(id)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Event gagal tidak ditemukan", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Gagal mengantrekan ulang event", http.StatusInternalServerError)
+			return
+		}
+		jobs.SegerakanOutbox()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "success",
+			"message": "Event diantrekan untuk dikirim ulang",
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"user_service/config"
 	"user_service/entities"
+	"user_service/jobs"
 	"user_service/models"
 )
 
@@ -37,6 +38,14 @@ func AssignPenelaahICP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	slots := []entities.SlotPenugasan{
+		{Peran: "Penelaah 1", DosenID: payload.Penelaah1ID},
+		{Peran: "Penelaah 2", DosenID: payload.Penelaah2ID},
+	}
+	if !validasiPenugasan(w, r, "penelaah_icp", payload.TarunaID, payload.FinalICPID, slots, payload.OverridePenugasan) {
+		return
+	}
+
 	model, err := models.NewPenelaahICPModel()
 	if err != nil {
 		http.Error(w, "Database error", http.StatusInternalServerError)
@@ -52,6 +61,8 @@ func AssignPenelaahICP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	jobs.SegerakanOutbox()
+
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
 		"message": "Penguji berhasil disimpan",
@@ -74,6 +85,12 @@ func GetTarunaWithPenelaahICP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
+	kondisiPeriode, argsPeriode := models.KondisiPeriode("t.periode_id", periodeID)
+
 	db, err := config.ConnectDB()
 	if err != nil {
 		http.Error(w, "Database connection error", http.StatusInternalServerError)
@@ -98,10 +115,11 @@ func GetTarunaWithPenelaahICP(w http.ResponseWriter, r *http.Request) {
 		LEFT JOIN penelaah_icp pp ON pp.user_id = t.user_id
 		LEFT JOIN dosen dp1 ON pp.penelaah_1_id = dp1.id
 		LEFT JOIN dosen dp2 ON pp.penelaah_2_id = dp2.id
+		WHERE ` + kondisiPeriode + `
 		ORDER BY t.nama_lengkap ASC;
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, argsPeriode...)
 	if err != nil {
 		http.Error(w, "Query execution error", http.StatusInternalServerError)
 		return
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"user_service/config"
 	"user_service/entities"
+	"user_service/jobs"
 	"user_service/models"
 )
 
@@ -33,6 +34,15 @@ func AssignPengujiLaporan100(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	slots := []entities.SlotPenugasan{
+		{Peran: "Ketua Penguji", DosenID: payload.KetuaID},
+		{Peran: "Penguji 1", DosenID: payload.Penguji1ID},
+		{Peran: "Penguji 2", DosenID: payload.Penguji2ID},
+	}
+	if !validasiPenugasan(w, r, "penguji_laporan100", payload.TarunaID, payload.FinalLaporan100ID, slots, payload.OverridePenugasan) {
+		return
+	}
+
 	model, err := models.NewPengujiLaporan100Model()
 	if err != nil {
 		http.Error(w, "Database error", http.StatusInternalServerError)
@@ -44,6 +54,8 @@ func AssignPengujiLaporan100(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	jobs.SegerakanOutbox()
+
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "success",
 		"message": "Penguji berhasil disimpan",
@@ -66,6 +78,12 @@ func GetTarunaWithPengujiLaporan100(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
+	kondisiPeriode, argsPeriode := models.KondisiPeriode("t.periode_id", periodeID)
+
 	db, err := config.ConnectDB()
 	if err != nil {
 		http.Error(w, "Database connection error", http.StatusInternalServerError)
@@ -92,10 +110,11 @@ func GetTarunaWithPengujiLaporan100(w http.ResponseWriter, r *http.Request) {
 		LEFT JOIN dosen dk ON pp.ketua_penguji_id = dk.id
 		LEFT JOIN dosen dp1 ON pp.penguji_1_id = dp1.id
 		LEFT JOIN dosen dp2 ON pp.penguji_2_id = dp2.id
+		WHERE ` + kondisiPeriode + `
 		ORDER BY t.nama_lengkap ASC;
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, argsPeriode...)
 	if err != nil {
 		http.Error(w, "Query execution error", http.StatusInternalServerError)
 		return
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"user_service/entities"
+	"user_service/models"
+	"user_service/utils"
+)
+
+// validasiPenugasan menjalankan aturan penugasan penguji/penelaah sebelum disimpan.
+// Bila ada pelanggaran, response 422 berisi daftar pelanggaran ditulis dan fungsi mengembalikan false.
+// Admin dapat tetap menyimpan dengan override=true dan alasan tertulis; override dicatat di log.
+func validasiPenugasan(w http.ResponseWriter, r *http.Request, jenis string, tarunaID, finalID int,
+	slots []entities.SlotPenugasan, ov entities.OverridePenugasan) bool {
+	model, err := models.NewPenugasanModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return false
+	}
+	defer model.DB.Close()
+
+	pelanggaran, err := model.Validasi(jenis, tarunaID, slots)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return false
+	}
+	if len(pelanggaran) == 0 {
+		return true
+	}
+
+	bisaOverride := true
+	for _, p := range pelanggaran {
+		if !p.BisaOverride {
+			bisaOverride = false
+		}
+	}
+	tolak := func(message string) bool {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":        "error",
+			"message":       message,
+			"pelanggaran":   pelanggaran,
+			"bisa_override": bisaOverride,
+		})
+		return false
+	}
+
+	if !ov.Override {
+		return tolak("Penugasan melanggar aturan")
+	}
+	if !bisaOverride {
+		return tolak("Penugasan memuat pelanggaran yang tidak dapat di-override")
+	}
+
+	claims, err := utils.ClaimsFromRequest(r)
+	if err != nil || strings.ToLower(claims.Role) != "admin" {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "error",
+			"message": "Hanya admin yang dapat meng-override aturan penugasan",
+		})
+		return false
+	}
+	alasan := strings.TrimSpace(ov.AlasanOverride)
+	if alasan == "" {
+		return tolak("Alasan override wajib diisi")
+	}
+
+	if err := model.LogOverride(jenis, tarunaID, finalID, slots, pelanggaran, alasan, claims.Email); err != nil {
+		log.Printf("Gagal mencatat override penugasan %s taruna %d: %v", jenis, tarunaID, err)
+		http.Error(w, "Gagal mencatat override", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
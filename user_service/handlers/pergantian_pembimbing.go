@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"user_service/entities"
+	"user_service/jobs"
+	"user_service/models"
+)
+
+// PergantianPembimbingHandler menampilkan pengajuan pergantian pembimbing yang relevan bagi pengguna (GET)
+// dan menerima pengajuan baru dari taruna, dosen, atau admin (POST)
+func PergantianPembimbingHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "GET, POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	model, err := models.NewPergantianPembimbingModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	p, ok := penggunaDariToken(w, r, model.DB)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		list, err := model.GetDaftar(p, r.URL.Query().Get("status"))
+		if err != nil {
+			http.Error(w, "Gagal mengambil pengajuan pergantian", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   list,
+		})
+
+	case http.MethodPost:
+		ajukanPergantianPembimbing(w, r, model, p)
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func ajukanPergantianPembimbing(w http.ResponseWriter, r *http.Request, model *models.PergantianPembimbingModel, p *models.Pengguna) {
+	var req entities.PengajuanPergantianPembimbing
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Alasan = strings.TrimSpace(req.Alasan)
+	if req.Peran == "" {
+		req.Peran = models.PeranPembimbingUtama
+	}
+	jenis, peran, ok := models.JenisPembimbing(req.Peran)
+	if !ok {
+		http.Error(w, "Peran pembimbing harus utama atau pendamping", http.StatusBadRequest)
+		return
+	}
+	if p.Role == "taruna" {
+		req.TarunaID = p.TarunaID
+	}
+	if req.TarunaID == 0 || req.DosenBaruID == 0 || req.Alasan == "" {
+		http.Error(w, "taruna_id, dosen_baru_id, dan alasan harus diisi", http.StatusBadRequest)
+		return
+	}
+
+	userID, dosenLamaID, err := model.PembimbingSaatIni(req.TarunaID, req.Peran)
+	if err != nil {
+		responGalat(w, err, "Gagal mengambil pembimbing taruna")
+		return
+	}
+	if dosenLamaID == 0 {
+		http.Error(w, "Taruna belum memiliki pembimbing "+req.Peran+"; tetapkan pembimbing lewat admin", http.StatusBadRequest)
+		return
+	}
+	if dosenLamaID == req.DosenBaruID {
+		http.Error(w, "Pembimbing baru sama dengan pembimbing saat ini", http.StatusBadRequest)
+		return
+	}
+
+	switch p.Role {
+	case "admin", "taruna":
+	case "dosen":
+		if p.DosenID != dosenLamaID && p.DosenID != req.DosenBaruID {
+			http.Error(w, "Dosen hanya dapat mengajukan pergantian bila menjadi pembimbing lama atau pembimbing baru", http.StatusForbidden)
+			return
+		}
+	default:
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	// Pelanggaran yang bisa di-override hanya menjadi peringatan; admin memutuskannya pada tahap terakhir
+	penugasanModel, err := models.NewPenugasanModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer penugasanModel.DB.Close()
+	pelanggaran, err := penugasanModel.Validasi(jenis, req.TarunaID,
+		[]entities.SlotPenugasan{{Peran: peran, DosenID: req.DosenBaruID}})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, pl := range pelanggaran {
+		if !pl.BisaOverride {
+			responJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"status":      "error",
+				"message":     "Pembimbing baru tidak memenuhi aturan penugasan",
+				"pelanggaran": pelanggaran,
+			})
+			return
+		}
+	}
+
+	g, err := model.Ajukan(p, userID, dosenLamaID, req)
+	if err != nil {
+		responGalat(w, err, "Gagal menyimpan pengajuan pergantian")
+		return
+	}
+
+	responJSON(w, http.StatusCreated, map[string]interface{}{
+		"status":     "success",
+		"message":    "Pengajuan pergantian pembimbing berhasil dikirim",
+		"data":       g,
+		"peringatan": pelanggaran,
+	})
+}
+
+// KeputusanPergantianPembimbing mencatat persetujuan/penolakan tahap yang sedang berjalan.
+// Urutan tahap: pembimbing lama, pembimbing baru, lalu admin/kaprodi. Persetujuan admin langsung
+// menetapkan pembimbing baru dan mengalihkan dokumen yang masih menunggu review.
+func KeputusanPergantianPembimbing(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req entities.KeputusanPergantianPembimbing
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Catatan = strings.TrimSpace(req.Catatan)
+	if !req.Setuju && req.Catatan == "" {
+		http.Error(w, "Catatan wajib diisi saat menolak pengajuan", http.StatusBadRequest)
+		return
+	}
+
+	model, err := models.NewPergantianPembimbingModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	p, ok := penggunaDariToken(w, r, model.DB)
+	if !ok {
+		return
+	}
+	g, err := model.GetByID(req.PergantianID)
+	if err != nil {
+		responGalat(w, err, "Gagal mengambil pengajuan pergantian")
+		return
+	}
+	if !models.BolehMemutuskan(p, g) {
+		responJSON(w, http.StatusForbidden, map[string]interface{}{
+			"status":  "error",
+			"message": "Anda tidak berwenang memutuskan pengajuan ini pada tahap " + g.Status,
+		})
+		return
+	}
+
+	// Aturan penugasan diperiksa ulang saat admin menyetujui karena kuota/status dosen bisa berubah
+	if req.Setuju && models.TahapMenunggu(g.Status) == models.TahapPergantianAdmin {
+		jenis, peran, _ := models.JenisPembimbing(g.Peran)
+		slots := []entities.SlotPenugasan{{Peran: peran, DosenID: g.DosenBaruID}}
+		if !validasiPenugasan(w, r, jenis, g.TarunaID, 0, slots, req.OverridePenugasan) {
+			return
+		}
+	}
+
+	dialihkan, err := model.Putuskan(p, req)
+	if err != nil {
+		responGalat(w, err, "Gagal menyimpan keputusan pergantian")
+		return
+	}
+	g, err = model.GetByID(req.PergantianID)
+	if err != nil {
+		responGalat(w, err, "Gagal mengambil pengajuan pergantian")
+		return
+	}
+
+	message := "Keputusan berhasil disimpan"
+	if g.Status == models.StatusPergantianDisetujui {
+		message = fmt.Sprintf("Pergantian pembimbing disetujui; %d dokumen yang belum selesai direview dialihkan ke pembimbing baru", dialihkan)
+		jobs.SegerakanOutbox()
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"message": message,
+		"data":    g,
+	})
+}
+
+// BatalkanPergantianPembimbing membatalkan pengajuan yang belum selesai (POST ?id=)
+func BatalkanPergantianPembimbing(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "id tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	model, err := models.NewPergantianPembimbingModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	p, ok := penggunaDariToken(w, r, model.DB)
+	if !ok {
+		return
+	}
+	if err := model.Batalkan(p, id); err != nil {
+		responGalat(w, err, "Gagal membatalkan pengajuan pergantian")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"message": "Pengajuan pergantian pembimbing dibatalkan",
+	})
+}
+
+// GetRiwayatPembimbing menampilkan riwayat pembimbing taruna beserta tanggal berlakunya (GET ?taruna_id=).
+// Taruna hanya dapat melihat riwayatnya sendiri.
+func GetRiwayatPembimbing(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "GET")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	model, err := models.NewPergantianPembimbingModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	p, ok := penggunaDariToken(w, r, model.DB)
+	if !ok {
+		return
+	}
+
+	tarunaID := p.TarunaID
+	if p.Role != "taruna" {
+		tarunaID, err = strconv.Atoi(r.URL.Query().Get("taruna_id"))
+		if err != nil {
+			http.Error(w, "taruna_id tidak valid", http.StatusBadRequest)
+			return
+		}
+	}
+
+	riwayat, err := model.GetRiwayat(tarunaID)
+	if err != nil {
+		http.Error(w, "Gagal mengambil riwayat pembimbing", http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data":   riwayat,
+	})
+}
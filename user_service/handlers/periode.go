@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"user_service/entities"
+	"user_service/models"
+)
+
+// periodeDariQuery membaca ?periode_id= untuk filter daftar; 0 berarti semua periode yang belum diarsipkan.
+// Bila nilainya tidak valid, response 400 ditulis.
+func periodeDariQuery(w http.ResponseWriter, r *http.Request) (int, bool) {
+	v := strings.TrimSpace(r.URL.Query().Get("periode_id"))
+	if v == "" {
+		return 0, true
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil || id < 0 {
+		http.Error(w, "periode_id tidak valid", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// TahunAkademikHandler menampilkan daftar tahun akademik (GET) dan menambah/mengubah rentang
+// tanggalnya (POST, admin)
+func TahunAkademikHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "GET, POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	model, err := models.NewPeriodeModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	switch r.Method {
+	case http.MethodGet:
+		list, err := model.GetTahunAkademik()
+		if err != nil {
+			http.Error(w, "Gagal mengambil tahun akademik", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   list,
+		})
+
+	case http.MethodPost:
+		if !hanyaAdmin(w, r) {
+			return
+		}
+		var t entities.TahunAkademikTA
+		if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		t.Kode = strings.TrimSpace(t.Kode)
+		if err := model.SimpanTahunAkademik(&t); err != nil {
+			responGalat(w, err, "Gagal menyimpan tahun akademik")
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "success",
+			"message": "Tahun akademik berhasil disimpan",
+			"data":    t,
+		})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// PeriodeHandler menampilkan periode tugas akhir (GET ?tahun_akademik=&status=), membuat periode (POST),
+// dan mengubahnya (PUT ?id=). Perubahan hanya oleh admin.
+func PeriodeHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "GET, POST, PUT")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	model, err := models.NewPeriodeModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		if id, err := strconv.Atoi(q.Get("id")); err == nil {
+			p, err := model.GetPeriodeByID(id)
+			if err != nil {
+				responGalat(w, err, "Gagal mengambil periode")
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status": "success",
+				"data":   p,
+			})
+			return
+		}
+		list, err := model.GetPeriode(q.Get("tahun_akademik"), q.Get("status"))
+		if err != nil {
+			http.Error(w, "Gagal mengambil periode", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   list,
+		})
+
+	case http.MethodPost, http.MethodPut:
+		if !hanyaAdmin(w, r) {
+			return
+		}
+		var p entities.PeriodeTA
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		p.ID = 0
+		if r.Method == http.MethodPut {
+			if p.ID, err = strconv.Atoi(r.URL.Query().Get("id")); err != nil {
+				http.Error(w, "id tidak valid", http.StatusBadRequest)
+				return
+			}
+		}
+		p.TahunAkademik = strings.TrimSpace(p.TahunAkademik)
+		p.Nama = strings.TrimSpace(p.Nama)
+		if err := model.SimpanPeriode(&p); err != nil {
+			responGalat(w, err, "Gagal menyimpan periode")
+			return
+		}
+		kode := http.StatusOK
+		if r.Method == http.MethodPost {
+			kode = http.StatusCreated
+		}
+		responJSON(w, kode, map[string]interface{}{
+			"status":  "success",
+			"message": "Periode berhasil disimpan",
+			"data":    p,
+		})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// StatusPeriodeHandler menutup, membuka kembali, atau mengarsipkan periode (POST ?id=&status=, admin)
+func StatusPeriodeHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hanyaAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "id tidak valid", http.StatusBadRequest)
+		return
+	}
+	status := r.URL.Query().Get("status")
+
+	model, err := models.NewPeriodeModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	if err := model.UbahStatusPeriode(id, status); err != nil {
+		responGalat(w, err, "Gagal mengubah status periode")
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"message": "Status periode menjadi " + status,
+	})
+}
+
+// TarunaPeriodeHandler menampilkan taruna yang terdaftar di periode (GET ?periode_id=) dan
+// mendaftarkan taruna ke periode aktif beserta angkatannya (POST, admin)
+func TarunaPeriodeHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "GET, POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	model, err := models.NewPeriodeModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	switch r.Method {
+	case http.MethodGet:
+		periodeID, err := strconv.Atoi(r.URL.Query().Get("periode_id"))
+		if err != nil {
+			http.Error(w, "periode_id tidak valid", http.StatusBadRequest)
+			return
+		}
+		list, err := model.GetTarunaPeriode(periodeID)
+		if err != nil {
+			http.Error(w, "Gagal mengambil taruna periode", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   list,
+		})
+
+	case http.MethodPost:
+		if !hanyaAdmin(w, r) {
+			return
+		}
+		var d entities.PendaftaranPeriode
+		if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		n, err := model.DaftarkanTaruna(d)
+		if err != nil {
+			responGalat(w, err, "Gagal mendaftarkan taruna")
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "success",
+			"message": strconv.FormatInt(n, 10) + " taruna didaftarkan ke periode",
+		})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -32,13 +32,27 @@ func GetAllTaruna(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
+	k, ok := kueriDaftar(w, r, models.AturanDaftarTaruna)
+	if !ok {
+		return
+	}
+
 	tarunaModel, err := models.NewTarunaModel()
 	if err != nil {
 		http.Error(w, "Database connection error", http.StatusInternalServerError)
 		return
 	}
 
-	tarunas, err := tarunaModel.GetAllTaruna()
+	tarunas, total, err := tarunaModel.GetAllTaruna(models.FilterTaruna{
+		PeriodeID: periodeID,
+		Jurusan:   r.URL.Query().Get("jurusan"),
+		Kelas:     r.URL.Query().Get("kelas"),
+		Angkatan:  r.URL.Query().Get("angkatan"),
+	}, k)
 	if err != nil {
 		http.Error(w, "Failed to fetch taruna data", http.StatusInternalServerError)
 		return
@@ -47,6 +61,7 @@ func GetAllTaruna(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status": "success",
 		"data":   tarunas,
+		"meta":   k.Meta(total),
 	})
 }
 
@@ -148,6 +163,7 @@ func EditUserTaruna(w http.ResponseWriter, r *http.Request) {
 			userData.Kelas,
 			npm,
 			nil,
+			idPelaku(r, userModel),
 		)
 
 		if err != nil {
@@ -211,6 +227,12 @@ func GetTarunaWithTopik(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	periodeID, ok := periodeDariQuery(w, r)
+	if !ok {
+		return
+	}
+	kondisiPeriode, argsPeriode := models.KondisiPeriode("t.periode_id", periodeID)
+
 	db, err := config.ConnectDB()
 	if err != nil {
 		http.Error(w, "Database connection error", http.StatusInternalServerError)
@@ -225,10 +247,10 @@ func GetTarunaWithTopik(w http.ResponseWriter, r *http.Request) {
 			t.kelas
 		FROM taruna t
 		JOIN users u ON t.user_id = u.id
-		WHERE u.role = 'Taruna'
+		WHERE u.role = 'Taruna' AND ` + kondisiPeriode + `
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, argsPeriode...)
 	if err != nil {
 		http.Error(w, "Query error", http.StatusInternalServerError)
 		return
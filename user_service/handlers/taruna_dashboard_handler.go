@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 	"user_service/models"
 )
 
+// wib dipakai untuk membaca DATETIME jadwal yang disimpan sebagai jam dinding WIB
+var wib = time.FixedZone("WIB", 7*60*60)
+
 type TarunaDashboardResponse struct {
 	Status string      `json:"status"`
 	Data   interface{} `json:"data,omitempty"`
@@ -79,6 +83,31 @@ func TarunaDashboardHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	data["dosen_pembimbing"] = dosenPembimbing
 
+	// Seluruh pembimbing aktif (utama dan pendamping, termasuk pembimbing tamu)
+	pembimbingList := []map[string]interface{}{}
+	pembimbingRows, err := db.Query(`
+		SELECT d.nama_lengkap, p.peran, COALESCE(d.eksternal, 0), COALESCE(d.instansi, '')
+		FROM v_pembimbing_taruna p
+		JOIN dosen d ON d.id = p.dosen_id
+		WHERE p.user_id = ? AND p.status = 'aktif'
+		ORDER BY p.peran = 'pendamping', p.tanggal_ditetapkan`, userId)
+	if err == nil {
+		defer pembimbingRows.Close()
+		for pembimbingRows.Next() {
+			var nama, peran, instansi string
+			var eksternal bool
+			if err := pembimbingRows.Scan(&nama, &peran, &eksternal, &instansi); err == nil {
+				pembimbingList = append(pembimbingList, map[string]interface{}{
+					"nama_lengkap": nama,
+					"peran":        peran,
+					"eksternal":    eksternal,
+					"instansi":     instansi,
+				})
+			}
+		}
+	}
+	data["pembimbing"] = pembimbingList
+
 	// Ambil status proposal dari tabel final_proposal (terbaru)
 	var proposalStatus string = "-"
 	var finalProposalID int
@@ -188,6 +217,38 @@ func TarunaDashboardHandler(w http.ResponseWriter, r *http.Request) {
 		"penguji_2":     laporan100Penguji2,
 	}
 
+	// Batas pengumpulan revisi pasca seminar; status dan sisa waktunya dihitung oleh view v_batas_revisi
+	// yang dikelola document_service
+	batasRevisi := []map[string]interface{}{}
+	batasRows, err := db.Query(`
+		SELECT tahap, DATE_FORMAT(batas, '%Y-%m-%d %H:%i:%s'), perpanjangan_hari, status, sisa_detik
+		FROM v_batas_revisi
+		WHERE user_id = ?
+		ORDER BY batas ASC`, userId)
+	if err == nil {
+		defer batasRows.Close()
+		for batasRows.Next() {
+			var tahap, batasStr, status string
+			var perpanjangan int
+			var sisa int64
+			if err := batasRows.Scan(&tahap, &batasStr, &perpanjangan, &status, &sisa); err != nil {
+				continue
+			}
+			batas, err := time.ParseInLocation("2006-01-02 15:04:05", batasStr, wib)
+			if err != nil {
+				continue
+			}
+			batasRevisi = append(batasRevisi, map[string]interface{}{
+				"tahap":             tahap,
+				"batas":             batas,
+				"perpanjangan_hari": perpanjangan,
+				"status":            status,
+				"sisa_detik":        sisa,
+			})
+		}
+	}
+	data["batas_revisi"] = batasRevisi
+
 	json.NewEncoder(w).Encode(TarunaDashboardResponse{
 		Status: "success",
 		Data:   data,
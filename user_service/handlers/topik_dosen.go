@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"user_service/entities"
+	"user_service/jobs"
+	"user_service/models"
+)
+
+// TopikDosenHandler menampilkan topik penelitian yang ditawarkan dosen (GET), membuat topik baru (POST),
+// dan mengubah topik milik dosen (PUT ?id=). Taruna hanya melihat topik yang masih dibuka.
+func TopikDosenHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "GET, POST, PUT")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	model, err := models.NewTopikDosenModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	p, ok := penggunaDariToken(w, r, model.DB)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		dosenID, _ := strconv.Atoi(q.Get("dosen_id"))
+		if q.Get("milik") == "saya" && p.Role == "dosen" {
+			dosenID = p.DosenID
+		}
+		status := q.Get("status")
+		if p.Role == "taruna" {
+			status = models.StatusTopikDibuka
+		}
+		list, err := model.GetTopik(dosenID, status)
+		if err != nil {
+			http.Error(w, "Gagal mengambil topik", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   list,
+		})
+
+	case http.MethodPost, http.MethodPut:
+		if p.Role != "dosen" {
+			http.Error(w, "Hanya dosen yang dapat menawarkan topik", http.StatusForbidden)
+			return
+		}
+		var t entities.TopikDosen
+		if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		t.DosenID = p.DosenID
+		t.Judul = strings.TrimSpace(t.Judul)
+		t.Deskripsi = strings.TrimSpace(t.Deskripsi)
+		t.Prasyarat = strings.TrimSpace(t.Prasyarat)
+		if t.Judul == "" || t.Deskripsi == "" {
+			http.Error(w, "judul dan deskripsi harus diisi", http.StatusBadRequest)
+			return
+		}
+		if t.Slot < 1 {
+			http.Error(w, "slot minimal 1", http.StatusBadRequest)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			if err := model.CreateTopik(&t); err != nil {
+				http.Error(w, "Gagal menyimpan topik", http.StatusInternalServerError)
+				return
+			}
+			responJSON(w, http.StatusCreated, map[string]interface{}{
+				"status":  "success",
+				"message": "Topik berhasil ditawarkan",
+				"data":    t,
+			})
+			return
+		}
+
+		t.ID, err = strconv.Atoi(r.URL.Query().Get("id"))
+		if err != nil {
+			http.Error(w, "id tidak valid", http.StatusBadRequest)
+			return
+		}
+		if t.Status != models.StatusTopikDibuka && t.Status != models.StatusTopikDitutup {
+			http.Error(w, "status harus dibuka atau ditutup", http.StatusBadRequest)
+			return
+		}
+		if err := model.UpdateTopik(&t); err != nil {
+			responGalat(w, err, "Gagal mengubah topik")
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "success",
+			"message": "Topik berhasil diubah",
+		})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// LamaranTopikHandler menampilkan lamaran topik yang relevan bagi pengguna (GET ?status=)
+// dan menerima lamaran taruna beserta motivasinya (POST)
+func LamaranTopikHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "GET, POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	model, err := models.NewTopikDosenModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	p, ok := penggunaDariToken(w, r, model.DB)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		list, err := model.GetLamaran(p, r.URL.Query().Get("status"))
+		if err != nil {
+			http.Error(w, "Gagal mengambil lamaran", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   list,
+		})
+
+	case http.MethodPost:
+		if p.Role != "taruna" {
+			http.Error(w, "Hanya taruna yang dapat melamar topik", http.StatusForbidden)
+			return
+		}
+		var req struct {
+			TopikID  int    `json:"topik_id"`
+			Motivasi string `json:"motivasi"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		req.Motivasi = strings.TrimSpace(req.Motivasi)
+		if req.TopikID == 0 || req.Motivasi == "" {
+			http.Error(w, "topik_id dan motivasi harus diisi", http.StatusBadRequest)
+			return
+		}
+		if len(req.Motivasi) > 2000 {
+			http.Error(w, "Motivasi maksimal 2000 karakter", http.StatusBadRequest)
+			return
+		}
+
+		id, err := model.Lamar(p.UserID, req.TopikID, req.Motivasi)
+		if err != nil {
+			responGalat(w, err, "Gagal menyimpan lamaran")
+			return
+		}
+		responJSON(w, http.StatusCreated, map[string]interface{}{
+			"status":  "success",
+			"message": "Lamaran topik berhasil dikirim",
+			"id":      id,
+		})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// KeputusanLamaranTopik menerima atau menolak lamaran (dosen pemilik topik). Lamaran yang diterima
+// langsung menetapkan dosen sebagai pembimbing utama taruna; judul topik dipakai sebagai topik ICP.
+func KeputusanLamaranTopik(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req entities.KeputusanLamaranTopik
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Catatan = strings.TrimSpace(req.Catatan)
+
+	model, err := models.NewTopikDosenModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	p, ok := penggunaDariToken(w, r, model.DB)
+	if !ok {
+		return
+	}
+	if p.Role != "dosen" {
+		http.Error(w, "Hanya dosen pemilik topik yang dapat memutuskan lamaran", http.StatusForbidden)
+		return
+	}
+	lamaran, err := model.GetLamaranByID(req.LamaranID)
+	if err != nil {
+		responGalat(w, err, "Gagal mengambil lamaran")
+		return
+	}
+	if lamaran.DosenID != p.DosenID {
+		http.Error(w, "Lamaran ini bukan untuk topik Anda", http.StatusForbidden)
+		return
+	}
+
+	// Penerimaan mengikuti aturan penetapan pembimbing (diperiksa di dalam transaksi); dosen tidak dapat
+	// meng-override, admin dapat menetapkan pembimbing secara manual bila diperlukan
+	if err := model.PutuskanLamaran(p.DosenID, req); err != nil {
+		var langgar *models.ErrPelanggaran
+		if errors.As(err, &langgar) {
+			responJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"status":      "error",
+				"message":     "Lamaran tidak dapat diterima karena melanggar aturan penetapan pembimbing",
+				"pelanggaran": langgar.Pelanggaran,
+			})
+			return
+		}
+		responGalat(w, err, "Gagal menyimpan keputusan lamaran")
+		return
+	}
+
+	message := "Lamaran ditolak"
+	if req.Terima {
+		message = "Lamaran diterima; Anda ditetapkan sebagai pembimbing utama taruna"
+		jobs.SegerakanOutbox()
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"message": message,
+	})
+}
+
+// BatalkanLamaranTopik membatalkan lamaran taruna yang belum diputuskan (POST ?id=)
+func BatalkanLamaranTopik(w http.ResponseWriter, r *http.Request) {
+	setCORSHeader(w, "POST")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "id tidak valid", http.StatusBadRequest)
+		return
+	}
+
+	model, err := models.NewTopikDosenModel()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer model.DB.Close()
+
+	p, ok := penggunaDariToken(w, r, model.DB)
+	if !ok {
+		return
+	}
+	if err := model.BatalkanLamaran(p.UserID, id); err != nil {
+		responGalat(w, err, "Gagal membatalkan lamaran")
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "success",
+		"message": "Lamaran dibatalkan",
+	})
+}
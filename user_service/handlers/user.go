@@ -64,17 +64,37 @@ func UserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Ambil semua user
-	users, err := userModel.FindAll()
+	// Ambil daftar user; ?status=nonaktif atau ?status=semua untuk melihat akun yang dinonaktifkan,
+	// ditambah filter role/jurusan/kelas dan parameter halaman (page, limit, cursor, sort, q)
+	k, ok := kueriDaftar(w, r, models.AturanDaftarUser)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	users, total, err := userModel.FindAll(models.FilterUser{
+		Status:  query.Get("status"),
+		Role:    query.Get("role"),
+		Jurusan: query.Get("jurusan"),
+		Kelas:   query.Get("kelas"),
+	}, k)
 	if err != nil {
 		log.Printf("❌ Gagal ambil data user dari DB: %v", err)
 		http.Error(w, "Failed to fetch users", http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("✅ Berhasil ambil %d users", len(users))
+	log.Printf("✅ Berhasil ambil %d dari %d users", len(users), total)
 
-	err = json.NewEncoder(w).Encode(users)
+	// Tanpa parameter halaman respons tetap berupa array seperti sebelumnya
+	var respons interface{} = users
+	if k.Dipaging {
+		respons = map[string]interface{}{
+			"status": "success",
+			"data":   users,
+			"meta":   k.Meta(total),
+		}
+	}
+	err = json.NewEncoder(w).Encode(respons)
 	if err != nil {
 		log.Printf("❌ Gagal encode JSON: %v", err)
 		http.Error(w, "Gagal encode JSON", http.StatusInternalServerError)
@@ -332,7 +352,7 @@ func EditUser(w http.ResponseWriter, r *http.Request) {
 
 		if err := userModel.UpdateUser(
 			req.UserID, req.FullName, req.Email, req.Username,
-			req.Role, req.Jurusan, req.Kelas, npm, hashedPassword,
+			req.Role, req.Jurusan, req.Kelas, npm, hashedPassword, idPelaku(r, userModel),
 		); err != nil {
 			log.Printf("UpdateUser: %v", err)
 			writeJSON(w, http.StatusInternalServerError, map[string]any{
@@ -426,7 +446,22 @@ func GetUserDetail(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(user)
 }
 
-// Fungsi Delete user
+// idPelaku mengembalikan id user pemilik token untuk jejak audit; 0 bila tidak dapat ditentukan
+func idPelaku(r *http.Request, userModel *models.UserModel) int {
+	claims, err := utils.ClaimsFromRequest(r)
+	if err != nil {
+		return 0
+	}
+	p, err := userModel.Pengguna(claims.Email)
+	if err != nil {
+		return 0
+	}
+	return p.UserID
+}
+
+// DeleteUser menonaktifkan akun (DELETE ?id=, body opsional {"alasan": "..."}). Data user tidak dihapus:
+// akun tidak dapat login dan tidak muncul di pilihan, dan dapat dipulihkan lewat RestoreUser.
+// Penghapusan permanen hanya dilakukan oleh cmd/purge_user.
 func DeleteUser(w http.ResponseWriter, r *http.Request) {
 	// Header CORS
 	w.Header().Set("Content-Type", "application/json")
@@ -450,64 +485,149 @@ func DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Ambil ID user dari parameter URL
-	userID := r.URL.Query().Get("id")
-	if userID == "" {
+	userModel, id, ok := siapkanStatusAkun(w, r)
+	if !ok {
+		return
+	}
+	defer userModel.Close()
+
+	oleh := idPelaku(r, userModel)
+	if oleh == id {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  false,
-			"message": "ID User tidak ditemukan",
+			"message": "Tidak dapat menonaktifkan akun sendiri",
 		})
 		return
 	}
 
-	// Konversi userID string ke int
-	id, err := strconv.Atoi(userID)
+	var body struct {
+		Alasan string `json:"alasan"`
+	}
+	if r.ContentLength != 0 {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	berubah, err := userModel.Nonaktifkan(id, oleh, strings.TrimSpace(body.Alasan))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  false,
-			"message": "ID User tidak valid",
+			"message": "Gagal menonaktifkan user: " + err.Error(),
 		})
 		return
 	}
-
-	// Inisialisasi user model
-	userModel, err := models.NewUserModel()
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	if !berubah {
+		w.WriteHeader(http.StatusConflict)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  false,
-			"message": "Gagal menghubungkan ke database",
+			"message": "User sudah nonaktif",
 		})
 		return
 	}
 
-	// Cek apakah user ada di database
-	user, err := userModel.GetUserByID(id)
-	if err != nil || user == nil {
-		w.WriteHeader(http.StatusNotFound)
+	// Kirim response sukses
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  true,
+		"message": "User berhasil dinonaktifkan",
+	})
+}
+
+// RestoreUser mengaktifkan kembali akun yang dinonaktifkan (POST ?id=)
+func RestoreUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "https://securesimta.my.id")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  false,
-			"message": "User tidak ditemukan",
+			"message": "Metode tidak diizinkan",
 		})
 		return
 	}
 
-	// Hapus user dari database
-	err = userModel.DeleteUser(id)
+	userModel, id, ok := siapkanStatusAkun(w, r)
+	if !ok {
+		return
+	}
+	defer userModel.Close()
+
+	berubah, err := userModel.Pulihkan(id, idPelaku(r, userModel))
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  false,
-			"message": "Gagal menghapus user: " + err.Error(),
+			"message": "Gagal memulihkan user: " + err.Error(),
+		})
+		return
+	}
+	if !berubah {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  false,
+			"message": "User masih aktif",
 		})
 		return
 	}
 
-	// Kirim response sukses
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  true,
-		"message": "User berhasil dihapus",
+		"message": "User berhasil dipulihkan",
 	})
 }
+
+// siapkanStatusAkun memeriksa bahwa pemanggil admin, membaca ?id=, dan memastikan user ada.
+// Bila gagal, response sudah ditulis.
+func siapkanStatusAkun(w http.ResponseWriter, r *http.Request) (*models.UserModel, int, bool) {
+	claims, err := utils.ClaimsFromRequest(r)
+	if err != nil || strings.ToLower(claims.Role) != "admin" {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  false,
+			"message": "Hanya admin yang dapat mengubah status akun",
+		})
+		return nil, 0, false
+	}
+
+	// Ambil ID user dari parameter URL
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  false,
+			"message": "ID User tidak valid",
+		})
+		return nil, 0, false
+	}
+
+	userModel, err := models.NewUserModel()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  false,
+			"message": "Gagal menghubungkan ke database",
+		})
+		return nil, 0, false
+	}
+
+	// Cek apakah user ada di database
+	if user, err := userModel.GetUserByID(id); err != nil || user == nil {
+		userModel.Close()
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  false,
+			"message": "User tidak ditemukan",
+		})
+		return nil, 0, false
+	}
+	return userModel, id, true
+}
@@ -4,25 +4,60 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"user_service/config"
 	"user_service/handlers"
+	"user_service/jobs"
 	"user_service/middleware"
 )
 
 // ... existing code ...
 func main() {
+	// Jalankan migrasi skema yang belum diterapkan
+	db, err := config.ConnectDB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := config.Migrate(db); err != nil {
+		log.Fatal(err)
+	}
+	db.Close()
+
+	// Relay outbox mengirim event penugasan ke notification_service
+	jobs.StartRelayOutbox()
+
 	http.HandleFunc("/users", middleware.AuthMiddleware(handlers.UserHandler))
 	http.HandleFunc("/users/add", middleware.AuthMiddleware(handlers.AddUser))
 	http.HandleFunc("/users/edit", middleware.AuthMiddleware(handlers.EditUser))
 	http.HandleFunc("/users/detail", middleware.AuthMiddleware(handlers.GetUserDetail))
 	http.HandleFunc("/users/delete", middleware.AuthMiddleware(handlers.DeleteUser))
+	http.HandleFunc("/users/restore", middleware.AuthMiddleware(handlers.RestoreUser))
+	http.HandleFunc("/users/import", middleware.AuthMiddleware(handlers.ImportUsers))
 
 	http.HandleFunc("/dosen", middleware.AuthMiddleware(handlers.GetAllDosen))
 	http.HandleFunc("/taruna", middleware.AuthMiddleware(handlers.GetAllTaruna))
 	http.HandleFunc("/taruna/edituser", middleware.AuthMiddleware(handlers.EditUserTaruna))
 	http.HandleFunc("/dosen/edituser", middleware.AuthMiddleware(handlers.EditUserDosen))
+	http.HandleFunc("/dosen/aktif", middleware.AuthMiddleware(handlers.SetDosenAktif))
+	http.HandleFunc("/dosen/kecocokan", middleware.AuthMiddleware(handlers.GetKecocokanDosen))
+	http.HandleFunc("/tahun_akademik", middleware.AuthMiddleware(handlers.TahunAkademikHandler))
+	http.HandleFunc("/periode", middleware.AuthMiddleware(handlers.PeriodeHandler))
+	http.HandleFunc("/periode/status", middleware.AuthMiddleware(handlers.StatusPeriodeHandler))
+	http.HandleFunc("/periode/taruna", middleware.AuthMiddleware(handlers.TarunaPeriodeHandler))
+	http.HandleFunc("/kuota", middleware.AuthMiddleware(handlers.KuotaDosenHandler))
+	http.HandleFunc("/kuota/laporan", middleware.AuthMiddleware(handlers.GetLaporanKuota))
 	http.HandleFunc("/taruna/topik", middleware.AuthMiddleware(handlers.GetTarunaWithTopik))
 
 	http.HandleFunc("/dosbing_proposal", middleware.AuthMiddleware(handlers.AssignDosbingProposal))
+	http.HandleFunc("/dosbing_pendamping", middleware.AuthMiddleware(handlers.HapusDosbingPendamping))
+	http.HandleFunc("/pembimbing_tamu", middleware.AuthMiddleware(handlers.PembimbingTamuHandler))
+	http.HandleFunc("/pergantian_pembimbing", middleware.AuthMiddleware(handlers.PergantianPembimbingHandler))
+	http.HandleFunc("/pergantian_pembimbing/keputusan", middleware.AuthMiddleware(handlers.KeputusanPergantianPembimbing))
+	http.HandleFunc("/pergantian_pembimbing/batal", middleware.AuthMiddleware(handlers.BatalkanPergantianPembimbing))
+	http.HandleFunc("/riwayat_pembimbing", middleware.AuthMiddleware(handlers.GetRiwayatPembimbing))
+	http.HandleFunc("/topik", middleware.AuthMiddleware(handlers.TopikDosenHandler))
+	http.HandleFunc("/topik/lamaran", middleware.AuthMiddleware(handlers.LamaranTopikHandler))
+	http.HandleFunc("/topik/lamaran/keputusan", middleware.AuthMiddleware(handlers.KeputusanLamaranTopik))
+	http.HandleFunc("/topik/lamaran/batal", middleware.AuthMiddleware(handlers.BatalkanLamaranTopik))
 	http.HandleFunc("/penguji_proposal", middleware.AuthMiddleware(handlers.AssignPengujiProposal))
 	http.HandleFunc("/final_proposal", middleware.AuthMiddleware(handlers.GetFinalProposalByTarunaIDHandler))
 
@@ -51,7 +86,10 @@ func main() {
 
 	http.HandleFunc("/taruna/penelaahicp", middleware.AuthMiddleware(handlers.GetTarunaWithPenelaahICP))
 	http.HandleFunc("/penelaah_icp", middleware.AuthMiddleware(handlers.AssignPenelaahICP))
+	http.HandleFunc("/penugasan/auto/preview", middleware.AuthMiddleware(handlers.PreviewAutoPenugasan))
+	http.HandleFunc("/penugasan/auto/terapkan", middleware.AuthMiddleware(handlers.TerapkanAutoPenugasan))
 	http.HandleFunc("/final_icp", middleware.AuthMiddleware(handlers.GetFinalICPByTarunaIDHandler))
+	http.HandleFunc("/outbox", middleware.AuthMiddleware(handlers.OutboxHandler))
 
 	fmt.Println("API Server running on port 8081...")
 	log.Fatal(http.ListenAndServe(":8081", nil))
@@ -1,14 +1,12 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
-	"strings"
-
-	"github.com/golang-jwt/jwt/v4"
+	"user_service/models"
+	"user_service/utils"
 )
 
-var jwtKey = []byte("secret")
-
 func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Perbaikan header CORS
@@ -24,25 +22,37 @@ func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		}
 
 		// Ambil token dari header Authorization
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
+		if r.Header.Get("Authorization") == "" {
 			http.Error(w, "Unauthorized: No token provided", http.StatusUnauthorized)
 			return
 		}
 
-		// Format token: "Bearer <token>"
-		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-
 		// Validasi token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return jwtKey, nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, err := utils.ClaimsFromRequest(r)
+		if err != nil {
 			http.Error(w, "Unauthorized: Invalid token", http.StatusUnauthorized)
 			return
 		}
 
+		// Token tetap berlaku sampai kedaluwarsa walaupun akunnya dinonaktifkan,
+		// sehingga status akun dicek di setiap request
+		userModel, err := models.NewUserModel()
+		if err != nil {
+			http.Error(w, "Database error", http.StatusInternalServerError)
+			return
+		}
+		aktif, err := userModel.AkunAktif(claims.Email)
+		userModel.Close()
+		if err != nil {
+			log.Printf("Gagal memeriksa status akun %s: %v", claims.Email, err)
+			http.Error(w, "Database error", http.StatusInternalServerError)
+			return
+		}
+		if !aktif {
+			http.Error(w, "Unauthorized: Akun telah dinonaktifkan", http.StatusUnauthorized)
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	}
 }
@@ -0,0 +1,497 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"user_service/config"
+	"user_service/entities"
+)
+
+// DefaultMaksBeban dipakai bila dosen belum memiliki maks_beban dan permintaan tidak menentukannya
+const DefaultMaksBeban = 10
+
+// toleransiBeban adalah selisih beban dari dosen paling ringan yang masih boleh dipilih
+// demi mendapatkan dosen dengan keahlian yang lebih cocok
+const toleransiBeban = 1
+
+// jenisPenugasan mendeskripsikan tabel penugasan, tabel final yang ditugaskan, dan kolom dosennya
+type jenisPenugasan struct {
+	Tabel      string
+	TabelFinal string
+	KolomFinal string
+	Kolom      []string
+	Peran      []string
+}
+
+var daftarJenisPenugasan = map[string]jenisPenugasan{
+	"penelaah_icp": {
+		Tabel: "penelaah_icp", TabelFinal: "final_icp", KolomFinal: "final_icp_id",
+		Kolom: []string{"penelaah_1_id", "penelaah_2_id"},
+		Peran: []string{"Penelaah 1", "Penelaah 2"},
+	},
+	"penguji_proposal": {
+		Tabel: "penguji_proposal", TabelFinal: "final_proposal", KolomFinal: "final_proposal_id",
+		Kolom: []string{"ketua_penguji_id", "penguji_1_id", "penguji_2_id"},
+		Peran: []string{"Ketua Penguji", "Penguji 1", "Penguji 2"},
+	},
+	"penguji_laporan70": {
+		Tabel: "penguji_laporan70", TabelFinal: "final_laporan70", KolomFinal: "final_laporan70_id",
+		Kolom: []string{"penguji_1_id", "penguji_2_id"},
+		Peran: []string{"Penguji 1", "Penguji 2"},
+	},
+	"penguji_laporan100": {
+		Tabel: "penguji_laporan100", TabelFinal: "final_laporan100", KolomFinal: "final_laporan100_id",
+		Kolom: []string{"ketua_penguji_id", "penguji_1_id", "penguji_2_id"},
+		Peran: []string{"Ketua Penguji", "Penguji 1", "Penguji 2"},
+	},
+}
+
+// urutanJenisPenugasan menjaga urutan UNION beban tetap stabil
+var urutanJenisPenugasan = []string{"penelaah_icp", "penguji_proposal", "penguji_laporan70", "penguji_laporan100"}
+
+func IsValidJenisPenugasan(jenis string) bool {
+	_, ok := daftarJenisPenugasan[jenis]
+	return ok
+}
+
+// PeranPenugasan mengembalikan daftar peran (urut sesuai slot) untuk jenis penugasan
+func PeranPenugasan(jenis string) []string {
+	return daftarJenisPenugasan[jenis].Peran
+}
+
+type AutoPenugasanModel struct {
+	DB *sql.DB
+}
+
+func NewAutoPenugasanModel() (*AutoPenugasanModel, error) {
+	db, err := config.ConnectDB()
+	if err != nil {
+		return nil, err
+	}
+	return &AutoPenugasanModel{DB: db}, nil
+}
+
+// queryer dipenuhi *sql.DB dan *sql.Tx sehingga beban bisa dihitung di dalam transaksi
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// kandidatDosen adalah dosen aktif yang dapat menerima penugasan otomatis
+type kandidatDosen struct {
+	ID        int
+	Nama      string
+	Jurusan   string
+	MaksBeban int
+	Keahlian  []string
+}
+
+type tarunaPending struct {
+	TarunaID int
+	UserID   int
+	Nama     string
+	Jurusan  string
+	FinalID  int
+	Topik    string
+}
+
+// hitungBeban menghitung jumlah penugasan setiap dosen di semua peran penelaah dan penguji
+func hitungBeban(q queryer) (map[int]int, error) {
+	var bagian []string
+	for _, jenis := range urutanJenisPenugasan {
+		def := daftarJenisPenugasan[jenis]
+		for _, kolom := range def.Kolom {
+			bagian = append(bagian, fmt.Sprintf("SELECT %s AS dosen_id FROM %s", kolom, def.Tabel))
+		}
+	}
+	rows, err := q.Query(`
+		SELECT dosen_id, COUNT(*) FROM (` + strings.Join(bagian, " UNION ALL ") + `) b
+		WHERE dosen_id IS NOT NULL AND dosen_id <> 0
+		GROUP BY dosen_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	beban := map[int]int{}
+	for rows.Next() {
+		var dosenID, jumlah int
+		if err := rows.Scan(&dosenID, &jumlah); err != nil {
+			return nil, err
+		}
+		beban[dosenID] = jumlah
+	}
+	return beban, rows.Err()
+}
+
+func (m *AutoPenugasanModel) getKandidatDosen(maksDefault int) ([]kandidatDosen, error) {
+	rows, err := m.DB.Query(`
+		SELECT d.id, d.nama_lengkap, COALESCE(d.jurusan, ''), d.maks_beban
+		FROM dosen d
+		JOIN users u ON u.id = d.user_id
+		WHERE COALESCE(u.aktif, 1) = 1 AND u.dinonaktifkan_at IS NULL AND u.role = 'dosen'
+			AND COALESCE(d.eksternal, 0) = 0
+		ORDER BY d.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []kandidatDosen
+	index := map[int]int{}
+	for rows.Next() {
+		var d kandidatDosen
+		var maks sql.NullInt64
+		if err := rows.Scan(&d.ID, &d.Nama, &d.Jurusan, &maks); err != nil {
+			return nil, err
+		}
+		d.MaksBeban = maksDefault
+		if maks.Valid {
+			d.MaksBeban = int(maks.Int64)
+		}
+		index[d.ID] = len(list)
+		list = append(list, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagRows, err := m.DB.Query("SELECT dosen_id, tag FROM dosen_keahlian ORDER BY tag")
+	if err != nil {
+		return nil, err
+	}
+	defer tagRows.Close()
+	for tagRows.Next() {
+		var dosenID int
+		var tag string
+		if err := tagRows.Scan(&dosenID, &tag); err != nil {
+			return nil, err
+		}
+		if i, ok := index[dosenID]; ok {
+			list[i].Keahlian = append(list[i].Keahlian, tag)
+		}
+	}
+	return list, tagRows.Err()
+}
+
+// getTarunaPending mengambil taruna yang sudah mengumpulkan berkas final tetapi penugasannya belum lengkap
+func (m *AutoPenugasanModel) getTarunaPending(def jenisPenugasan, tarunaIDs []int, periodeID int) ([]tarunaPending, error) {
+	var kosong []string
+	for _, kolom := range def.Kolom {
+		kosong = append(kosong, fmt.Sprintf("p.%s IS NULL OR p.%s = 0", kolom, kolom))
+	}
+	query := fmt.Sprintf(`
+		SELECT t.id, t.user_id, COALESCE(t.nama_lengkap, ''), COALESCE(t.jurusan, ''), f.id, COALESCE(f.topik_penelitian, '')
+		FROM taruna t
+		JOIN %[1]s f ON f.id = (SELECT MAX(f2.id) FROM %[1]s f2 WHERE f2.user_id = t.user_id)
+		LEFT JOIN %[2]s p ON p.user_id = t.user_id
+		WHERE (p.user_id IS NULL OR %[3]s)`, def.TabelFinal, def.Tabel, strings.Join(kosong, " OR "))
+
+	kondisi, args := KondisiPeriode("t.periode_id", periodeID)
+	query += " AND " + kondisi
+	if len(tarunaIDs) > 0 {
+		query += " AND t.id IN (?" + strings.Repeat(", ?", len(tarunaIDs)-1) + ")"
+		for _, id := range tarunaIDs {
+			args = append(args, id)
+		}
+	}
+	query += " ORDER BY f.id ASC"
+
+	rows, err := m.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []tarunaPending
+	for rows.Next() {
+		var t tarunaPending
+		if err := rows.Scan(&t.TarunaID, &t.UserID, &t.Nama, &t.Jurusan, &t.FinalID, &t.Topik); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+func (m *AutoPenugasanModel) getPembimbingAktif() (map[int]map[int]bool, error) {
+	rows, err := m.DB.Query("SELECT user_id, dosen_id FROM v_pembimbing_taruna WHERE status = 'aktif'")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pembimbing := map[int]map[int]bool{}
+	for rows.Next() {
+		var userID, dosenID int
+		if err := rows.Scan(&userID, &dosenID); err != nil {
+			return nil, err
+		}
+		if pembimbing[userID] == nil {
+			pembimbing[userID] = map[int]bool{}
+		}
+		pembimbing[userID][dosenID] = true
+	}
+	return pembimbing, rows.Err()
+}
+
+// normalisasiTeks mengubah teks menjadi huruf kecil dengan kata dipisah satu spasi
+func normalisasiTeks(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), " ")
+}
+
+// keahlianCocok mengembalikan tag keahlian dosen yang muncul utuh di topik penelitian
+func keahlianCocok(topik string, keahlian []string) []string {
+	teks := " " + normalisasiTeks(topik) + " "
+	cocok := []string{}
+	for _, tag := range keahlian {
+		t := normalisasiTeks(tag)
+		if t != "" && strings.Contains(teks, " "+t+" ") {
+			cocok = append(cocok, tag)
+		}
+	}
+	return cocok
+}
+
+// Usulkan menyusun usulan penugasan tanpa menyimpannya. Untuk setiap slot, dosen dipilih dari
+// kandidat yang bukan pembimbing taruna dan belum mencapai maks_beban, diutamakan yang sejurusan.
+// Di antara kandidat dengan beban paling ringan (+toleransiBeban), dipilih yang keahliannya paling
+// cocok dengan topik; beban dihitung lintas semua peran dan diperbarui setelah setiap pilihan.
+func (m *AutoPenugasanModel) Usulkan(req entities.AutoPenugasanRequest) (*entities.PreviewAutoPenugasan, error) {
+	def, ok := daftarJenisPenugasan[req.Jenis]
+	if !ok {
+		return nil, fmt.Errorf("jenis penugasan tidak valid: %s", req.Jenis)
+	}
+	maksDefault := req.MaksBeban
+	if maksDefault <= 0 {
+		maksDefault = DefaultMaksBeban
+	}
+
+	dosen, err := m.getKandidatDosen(maksDefault)
+	if err != nil {
+		return nil, err
+	}
+	tarunaList, err := m.getTarunaPending(def, req.TarunaIDs, req.PeriodeID)
+	if err != nil {
+		return nil, err
+	}
+	pembimbing, err := m.getPembimbingAktif()
+	if err != nil {
+		return nil, err
+	}
+	bebanAwal, err := hitungBeban(m.DB)
+	if err != nil {
+		return nil, err
+	}
+	beban := map[int]int{}
+	for id, n := range bebanAwal {
+		beban[id] = n
+	}
+
+	// Kuota peran tahun akademik berjalan ikut membatasi kandidat
+	peranKuota := peranKuotaJenis[req.Jenis]
+	tahun, err := tahunAkademikBerjalan(m.DB, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	terpakaiKuota, err := hitungTerpakaiKuota(m.DB, tahun, peranKuota, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	kuotaKhusus, kuotaStandar, err := kuotaBerlaku(m.DB, tahun, peranKuota)
+	if err != nil {
+		return nil, err
+	}
+	kuotaPenuh := func(dosenID int) bool {
+		kuota := kuotaDosen(kuotaKhusus, kuotaStandar, dosenID)
+		return kuota != nil && terpakaiKuota[dosenID] >= *kuota
+	}
+
+	preview := &entities.PreviewAutoPenugasan{Jenis: req.Jenis, Usulan: []entities.UsulanPenugasan{}}
+	for _, t := range tarunaList {
+		usulan := entities.UsulanPenugasan{
+			TarunaID:   t.TarunaID,
+			NamaTaruna: t.Nama,
+			FinalID:    t.FinalID,
+			Topik:      t.Topik,
+			Slots:      []entities.UsulanSlot{},
+		}
+		dipakai := map[int]bool{}
+		var kosong []string
+
+		for _, peran := range def.Peran {
+			var kandidat, sejurusan []kandidatDosen
+			for _, d := range dosen {
+				if dipakai[d.ID] || pembimbing[t.UserID][d.ID] || beban[d.ID] >= d.MaksBeban || kuotaPenuh(d.ID) {
+					continue
+				}
+				kandidat = append(kandidat, d)
+				if t.Jurusan != "" && strings.EqualFold(strings.TrimSpace(d.Jurusan), strings.TrimSpace(t.Jurusan)) {
+					sejurusan = append(sejurusan, d)
+				}
+			}
+			if len(sejurusan) > 0 {
+				kandidat = sejurusan
+			}
+			if len(kandidat) == 0 {
+				usulan.Slots = append(usulan.Slots, entities.UsulanSlot{Peran: peran, KeahlianCocok: []string{}})
+				kosong = append(kosong, peran)
+				continue
+			}
+
+			minBeban := beban[kandidat[0].ID]
+			for _, d := range kandidat {
+				if beban[d.ID] < minBeban {
+					minBeban = beban[d.ID]
+				}
+			}
+			var terpilih *kandidatDosen
+			var terpilihCocok []string
+			for i := range kandidat {
+				d := &kandidat[i]
+				if beban[d.ID] > minBeban+toleransiBeban {
+					continue
+				}
+				cocok := keahlianCocok(t.Topik, d.Keahlian)
+				if terpilih == nil || len(cocok) > len(terpilihCocok) ||
+					(len(cocok) == len(terpilihCocok) && beban[d.ID] < beban[terpilih.ID]) {
+					terpilih, terpilihCocok = d, cocok
+				}
+			}
+
+			usulan.Slots = append(usulan.Slots, entities.UsulanSlot{
+				Peran:         peran,
+				DosenID:       terpilih.ID,
+				NamaDosen:     terpilih.Nama,
+				Beban:         beban[terpilih.ID],
+				KeahlianCocok: terpilihCocok,
+			})
+			dipakai[terpilih.ID] = true
+			beban[terpilih.ID]++
+			terpakaiKuota[terpilih.ID]++
+		}
+
+		if len(kosong) > 0 {
+			usulan.Catatan = "Tidak ada dosen yang memenuhi syarat untuk " + strings.Join(kosong, ", ")
+		}
+		preview.Usulan = append(preview.Usulan, usulan)
+	}
+
+	preview.Beban = []entities.BebanDosen{}
+	for _, d := range dosen {
+		preview.Beban = append(preview.Beban, entities.BebanDosen{
+			DosenID:      d.ID,
+			NamaDosen:    d.Nama,
+			Beban:        bebanAwal[d.ID],
+			BebanSesudah: beban[d.ID],
+			MaksBeban:    d.MaksBeban,
+		})
+	}
+	sort.SliceStable(preview.Beban, func(i, j int) bool {
+		return preview.Beban[i].BebanSesudah > preview.Beban[j].BebanSesudah
+	})
+	return preview, nil
+}
+
+// Terapkan menyimpan seluruh usulan dalam satu transaksi. Setelah semua penugasan ditulis, beban dan
+// kuota dihitung ulang di dalam transaksi; bila ada dosen yang melewati maks_beban atau kuota perannya,
+// seluruh usulan dibatalkan dan daftar dosen tersebut dikembalikan.
+func (m *AutoPenugasanModel) Terapkan(jenis string, usulan []entities.UsulanPenugasan, maksDefault int) ([]entities.BebanDosen, error) {
+	def, ok := daftarJenisPenugasan[jenis]
+	if !ok {
+		return nil, fmt.Errorf("jenis penugasan tidak valid: %s", jenis)
+	}
+	if maksDefault <= 0 {
+		maksDefault = DefaultMaksBeban
+	}
+
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	placeholder := strings.TrimSuffix(strings.Repeat("?, ", len(def.Kolom)), ", ")
+	var update []string
+	for _, kolom := range def.Kolom {
+		update = append(update, fmt.Sprintf("%s = VALUES(%s)", kolom, kolom))
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, %s, %s, periode_id, created_at, updated_at)
+		VALUES (?, ?, %s, %s, NOW(), NOW())
+		ON DUPLICATE KEY UPDATE %s, updated_at = NOW()`,
+		def.Tabel, def.KolomFinal, strings.Join(def.Kolom, ", "), placeholder, periodeTarunaSQL, strings.Join(update, ", "))
+
+	terlibat := map[int]bool{}
+	for _, u := range usulan {
+		var userID int
+		if err := tx.QueryRow("SELECT user_id FROM taruna WHERE id = ?", u.TarunaID).Scan(&userID); err != nil {
+			return nil, fmt.Errorf("taruna %d tidak ditemukan: %v", u.TarunaID, err)
+		}
+		args := []interface{}{userID, u.FinalID}
+		for _, s := range u.Slots {
+			args = append(args, s.DosenID)
+			terlibat[s.DosenID] = true
+		}
+		args = append(args, userID)
+		if _, err := tx.Exec(query, args...); err != nil {
+			return nil, err
+		}
+
+		var dosenIDs []int
+		for _, s := range u.Slots {
+			dosenIDs = append(dosenIDs, s.DosenID)
+		}
+		if err := catatPenugasan(tx, jenis, userID, slotJenis(jenis, dosenIDs...)); err != nil {
+			return nil, err
+		}
+	}
+
+	beban, err := hitungBeban(tx)
+	if err != nil {
+		return nil, err
+	}
+	tahun, err := tahunAkademikBerjalan(tx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	terpakaiKuota, err := hitungTerpakaiKuota(tx, tahun, peranKuotaJenis[jenis], "", 0)
+	if err != nil {
+		return nil, err
+	}
+	kuotaKhusus, kuotaStandar, err := kuotaBerlaku(tx, tahun, peranKuotaJenis[jenis])
+	if err != nil {
+		return nil, err
+	}
+	var melebihi []entities.BebanDosen
+	for dosenID := range terlibat {
+		var nama string
+		var maks sql.NullInt64
+		if err := tx.QueryRow("SELECT nama_lengkap, maks_beban FROM dosen WHERE id = ?", dosenID).Scan(&nama, &maks); err != nil {
+			return nil, fmt.Errorf("dosen %d tidak ditemukan: %v", dosenID, err)
+		}
+		batas := maksDefault
+		if maks.Valid {
+			batas = int(maks.Int64)
+		}
+		if beban[dosenID] > batas {
+			melebihi = append(melebihi, entities.BebanDosen{
+				DosenID: dosenID, NamaDosen: nama, BebanSesudah: beban[dosenID], MaksBeban: batas, Batas: "maks_beban",
+			})
+		}
+		if kuota := kuotaDosen(kuotaKhusus, kuotaStandar, dosenID); kuota != nil && terpakaiKuota[dosenID] > *kuota {
+			melebihi = append(melebihi, entities.BebanDosen{
+				DosenID: dosenID, NamaDosen: nama, BebanSesudah: terpakaiKuota[dosenID], MaksBeban: *kuota, Batas: "kuota",
+			})
+		}
+	}
+	if len(melebihi) > 0 {
+		sort.Slice(melebihi, func(i, j int) bool { return melebihi[i].DosenID < melebihi[j].DosenID })
+		return melebihi, nil
+	}
+	return nil, tx.Commit()
+}
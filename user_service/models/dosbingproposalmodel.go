@@ -8,6 +8,31 @@ import (
 	"user_service/entities"
 )
 
+const (
+	PeranPembimbingUtama      = "utama"
+	PeranPembimbingPendamping = "pendamping"
+)
+
+// JenisPembimbing memetakan peran pembimbing ke jenis penugasan dan nama slot untuk validasi
+func JenisPembimbing(peran string) (string, string, bool) {
+	switch peran {
+	case PeranPembimbingUtama:
+		return "dosbing_proposal", "Pembimbing Utama", true
+	case PeranPembimbingPendamping:
+		return "dosbing_pendamping", "Pembimbing Pendamping", true
+	}
+	return "", "", false
+}
+
+// catatPembimbing mencatat event penetapan pembimbing ke outbox di dalam tx penetapannya
+func catatPembimbing(tx DBTX, userID int, peran string, dosenID int) error {
+	jenis, slot, ok := JenisPembimbing(peran)
+	if !ok {
+		return nil
+	}
+	return catatPenugasan(tx, jenis, userID, []entities.SlotPenugasan{{Peran: slot, DosenID: dosenID}})
+}
+
 type DosbingModel struct {
 	DB *sql.DB
 }
@@ -20,27 +45,125 @@ func NewDosbingModel() (*DosbingModel, error) {
 	return &DosbingModel{DB: db}, nil
 }
 
-func (m *DosbingModel) AssignPembimbing(dp *entities.DosbingProposal) error {
+// AssignPembimbing menetapkan pembimbing taruna oleh admin. Pembimbing sebelumnya tidak hilang:
+// riwayatnya ditutup dan dokumen yang masih pending atau on review dialihkan ke pembimbing baru.
+func (m *DosbingModel) AssignPembimbing(dp *entities.DosbingProposal) (int, error) {
 	// Ambil user_id berdasarkan taruna_id
 	var userID int
 	err := m.DB.QueryRow("SELECT user_id FROM taruna WHERE id = ?", dp.TarunaID).Scan(&userID)
 	if err != nil {
-		return fmt.Errorf("taruna not found: %v", err)
+		return 0, fmt.Errorf("taruna not found: %v", err)
 	}
 
-	query := `
-		INSERT INTO dosbing_proposal (user_id, dosen_id, tanggal_ditetapkan, status)
-		VALUES (?, ?, CURDATE(), ?)
-		ON DUPLICATE KEY UPDATE 
-			dosen_id = VALUES(dosen_id),
-			tanggal_ditetapkan = CURDATE(),
-			status = VALUES(status)
-	`
 	status := dp.Status
 	if status == "" {
 		status = "aktif"
 	}
 
-	_, err = m.DB.Exec(query, userID, dp.DosenID, status)
-	return err
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	dialihkan, err := tetapkanPembimbing(tx, userID, dp.Peran, dp.DosenID, status, "Ditetapkan admin", nil)
+	if err != nil {
+		return 0, err
+	}
+	if err := catatPembimbing(tx, userID, dp.Peran, dp.DosenID); err != nil {
+		return 0, err
+	}
+	return dialihkan, tx.Commit()
+}
+
+// HapusPendamping menghapus pembimbing pendamping taruna dan menutup riwayatnya
+func (m *DosbingModel) HapusPendamping(tarunaID int) (bool, error) {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	err = tx.QueryRow("SELECT user_id FROM taruna WHERE id = ?", tarunaID).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec("DELETE FROM dosbing_pendamping WHERE user_id = ?", userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+	if err := tutupRiwayatPembimbing(tx, userID, PeranPembimbingPendamping); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// CreatePembimbingTamu membuat akun dosen eksternal beserta baris dosennya dalam satu transaksi
+func (m *DosbingModel) CreatePembimbingTamu(p *entities.PembimbingTamu, hashedPassword string) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO users (nama_lengkap, email, username, role, password, jurusan)
+		VALUES (?, ?, ?, 'dosen', ?, ?)`,
+		p.NamaLengkap, p.Email, p.Username, hashedPassword, p.Jurusan)
+	if err != nil {
+		return fmt.Errorf("error inserting user: %v", err)
+	}
+	userID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	result, err = tx.Exec(`
+		INSERT INTO dosen (user_id, nama_lengkap, email, jurusan, eksternal, instansi)
+		VALUES (?, ?, ?, ?, 1, ?)`,
+		userID, p.NamaLengkap, p.Email, p.Jurusan, p.Instansi)
+	if err != nil {
+		return fmt.Errorf("error inserting dosen: %v", err)
+	}
+	dosenID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	p.UserID, p.DosenID = int(userID), int(dosenID)
+	return tx.Commit()
+}
+
+// GetPembimbingTamu mengambil semua pembimbing tamu
+func (m *DosbingModel) GetPembimbingTamu() ([]entities.PembimbingTamu, error) {
+	rows, err := m.DB.Query(`
+		SELECT d.user_id, d.id, d.nama_lengkap, COALESCE(u.email, ''), COALESCE(u.username, ''),
+			COALESCE(d.instansi, ''), COALESCE(d.jurusan, '')
+		FROM dosen d
+		JOIN users u ON u.id = d.user_id
+		WHERE d.eksternal = 1
+		ORDER BY d.nama_lengkap`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.PembimbingTamu{}
+	for rows.Next() {
+		var p entities.PembimbingTamu
+		if err := rows.Scan(&p.UserID, &p.DosenID, &p.NamaLengkap, &p.Email, &p.Username, &p.Instansi, &p.Jurusan); err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
 }
@@ -2,8 +2,11 @@ package models
 
 import (
 	"database/sql"
+	"strconv"
+	"strings"
 	"user_service/config"
 	"user_service/entities"
+	"user_service/utils/halaman"
 )
 
 type DosenModel struct {
@@ -24,15 +27,49 @@ func (d *DosenModel) CreateDosen(userID int, namaLengkap, jurusan string) error
 	return err
 }
 
-func (d *DosenModel) GetAllDosen() ([]map[string]interface{}, error) {
-	rows, err := d.db.Query(`
-        SELECT d.id, d.user_id, d.nama_lengkap, d.jurusan 
+// AturanDaftarDosen adalah field yang boleh dipakai untuk sort daftar dosen
+var AturanDaftarDosen = halaman.Aturan{
+	Urut: map[string]string{
+		"id":           "d.id",
+		"nama_lengkap": "d.nama_lengkap",
+		"jurusan":      "d.jurusan",
+		"aktif":        "u.aktif",
+	},
+	UrutDefault: "id",
+	Kunci:       "d.id",
+}
+
+// FilterDosen adalah filter field daftar dosen; Aktif "true"/"false", kosong berarti semua
+type FilterDosen struct {
+	Jurusan string
+	Aktif   string
+}
+
+// GetAllDosen mengambil satu halaman dosen internal beserta jumlah total; q dicari pada nama
+func (d *DosenModel) GetAllDosen(filter FilterDosen, k *halaman.Kueri) ([]map[string]interface{}, int, error) {
+	var f halaman.Filter
+	f.Tambah("u.role = 'Dosen' AND u.dinonaktifkan_at IS NULL AND COALESCE(d.eksternal, 0) = 0")
+	f.Sama("d.jurusan", filter.Jurusan)
+	if aktif, err := strconv.ParseBool(filter.Aktif); err == nil {
+		f.Tambah("u.aktif = ?", aktif)
+	}
+	f.Cari(k.Q, "d.nama_lengkap")
+
+	dari := `
         FROM dosen d
         JOIN users u ON d.user_id = u.id
-        WHERE u.role = 'Dosen'
-    `)
+        WHERE ` + f.SQL()
+	total, err := halaman.Hitung(d.db, dari, f.Args())
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	potong, argsPotong := k.Potong()
+	rows, err := d.db.Query(`
+        SELECT d.id, d.user_id, d.nama_lengkap, d.jurusan, u.aktif`+
+		dari+k.OrderBy()+potong, append(f.Args(), argsPotong...)...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -40,10 +77,11 @@ func (d *DosenModel) GetAllDosen() ([]map[string]interface{}, error) {
 	for rows.Next() {
 		var id, userID int
 		var namaLengkap, jurusan string
+		var aktif bool
 
-		err := rows.Scan(&id, &userID, &namaLengkap, &jurusan)
+		err := rows.Scan(&id, &userID, &namaLengkap, &jurusan, &aktif)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		dosen := map[string]interface{}{
@@ -51,11 +89,12 @@ func (d *DosenModel) GetAllDosen() ([]map[string]interface{}, error) {
 			"user_id":      userID,
 			"nama_lengkap": namaLengkap,
 			"jurusan":      jurusan,
+			"aktif":        aktif,
 		}
 		dosens = append(dosens, dosen)
 	}
 
-	return dosens, nil
+	return dosens, total, rows.Err()
 }
 
 // Update password dosen berdasarkan user_id
@@ -81,3 +120,76 @@ func (d *DosenModel) GetDosenByUserID(userID int) (*entities.Dosen, error) {
 	}
 	return &dosen, nil
 }
+
+// SetAktif mengubah status aktif akun dosen; dosen nonaktif tidak dapat ditugaskan sebagai penguji
+func (d *DosenModel) SetAktif(userID int, aktif bool) (bool, error) {
+	result, err := d.db.Exec("UPDATE users SET aktif = ? WHERE id = ? AND LOWER(role) = 'dosen'", aktif, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// GetProfilKeahlian mengambil kata kunci keahlian dan bidang penelitian dosen berdasarkan user_id
+func (d *DosenModel) GetProfilKeahlian(userID int) ([]string, string, error) {
+	var dosenID int
+	var bidang sql.NullString
+	err := d.db.QueryRow("SELECT id, bidang_penelitian FROM dosen WHERE user_id = ?", userID).Scan(&dosenID, &bidang)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := d.db.Query("SELECT tag FROM dosen_keahlian WHERE dosen_id = ? ORDER BY id", dosenID)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	keahlian := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, "", err
+		}
+		keahlian = append(keahlian, tag)
+	}
+	return keahlian, bidang.String, rows.Err()
+}
+
+// UpdateProfilKeahlian mengganti seluruh kata kunci keahlian dosen dan bidang penelitiannya.
+// Kata kunci dirapikan (trim, huruf kecil, tanpa duplikat) sebelum disimpan.
+func (d *DosenModel) UpdateProfilKeahlian(userID int, keahlian []string, bidangPenelitian string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var dosenID int
+	if err := tx.QueryRow("SELECT id FROM dosen WHERE user_id = ?", userID).Scan(&dosenID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE dosen SET bidang_penelitian = ? WHERE id = ?", strings.TrimSpace(bidangPenelitian), dosenID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM dosen_keahlian WHERE dosen_id = ?", dosenID); err != nil {
+		return err
+	}
+
+	ada := map[string]bool{}
+	for _, k := range keahlian {
+		tag := strings.ToLower(strings.Join(strings.Fields(k), " "))
+		if tag == "" || ada[tag] {
+			continue
+		}
+		if r := []rune(tag); len(r) > 100 {
+			tag = string(r[:100])
+		}
+		ada[tag] = true
+		if _, err := tx.Exec("INSERT INTO dosen_keahlian (dosen_id, tag) VALUES (?, ?)", dosenID, tag); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
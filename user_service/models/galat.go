@@ -0,0 +1,30 @@
+package models
+
+import (
+	"fmt"
+	"user_service/entities"
+)
+
+// ErrPermintaan adalah kesalahan permintaan pengguna yang pesannya aman ditampilkan;
+// Kode adalah status HTTP yang sesuai
+type ErrPermintaan struct {
+	Kode  int
+	Pesan string
+}
+
+func (e *ErrPermintaan) Error() string {
+	return e.Pesan
+}
+
+// ErrPelanggaran dikembalikan bila penyimpanan ditolak karena melanggar aturan penugasan
+type ErrPelanggaran struct {
+	Pelanggaran []entities.Pelanggaran
+}
+
+func (e *ErrPelanggaran) Error() string {
+	return fmt.Sprintf("melanggar %d aturan penugasan", len(e.Pelanggaran))
+}
+
+func galatPermintaan(kode int, format string, args ...interface{}) error {
+	return &ErrPermintaan{Kode: kode, Pesan: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"user_service/entities"
+	"user_service/utils"
+)
+
+// roleImpor memetakan role di berkas (tidak peka huruf besar) ke nilai yang disimpan AddUser
+var roleImpor = map[string]string{
+	"taruna": "Taruna",
+	"dosen":  "Dosen",
+	"admin":  "Admin",
+}
+
+// ValidasiImpor menormalkan dan memeriksa setiap baris impor: field wajib per role seperti CreateUser,
+// format email dan NPM, kebijakan password, serta duplikat email/username/NPM di dalam berkas maupun
+// dengan user yang sudah terdaftar. Galat ditulis ke field Galat masing-masing baris.
+func (u UserModel) ValidasiImpor(baris []entities.BarisImporUser) error {
+	emailAda, usernameAda, npmAda, err := u.identitasTerdaftar()
+	if err != nil {
+		return err
+	}
+
+	emailBerkas := map[string]int{}
+	usernameBerkas := map[string]int{}
+	npmBerkas := map[string]int{}
+
+	for i := range baris {
+		b := &baris[i]
+		b.Galat = []string{}
+		b.NamaLengkap = strings.TrimSpace(b.NamaLengkap)
+		b.Email = strings.TrimSpace(b.Email)
+		b.Username = strings.TrimSpace(b.Username)
+		b.NPM = strings.TrimSpace(b.NPM)
+		b.Jurusan = strings.TrimSpace(b.Jurusan)
+		b.Kelas = strings.TrimSpace(b.Kelas)
+
+		if b.NamaLengkap == "" {
+			b.Galat = append(b.Galat, "nama wajib diisi")
+		}
+		if b.Email == "" {
+			b.Galat = append(b.Galat, "email wajib diisi")
+		} else if addr, err := mail.ParseAddress(b.Email); err != nil || addr.Address != b.Email {
+			b.Galat = append(b.Galat, "format email tidak valid")
+		}
+
+		role, ok := roleImpor[strings.ToLower(strings.TrimSpace(b.Role))]
+		if !ok {
+			b.Galat = append(b.Galat, "role harus Taruna, Dosen, atau Admin")
+		}
+		b.Role = role
+		switch role {
+		case "Taruna":
+			if b.Jurusan == "" || b.Kelas == "" || b.NPM == "" {
+				b.Galat = append(b.Galat, "taruna wajib mengisi jurusan, kelas, dan NPM")
+			}
+			if b.NPM != "" {
+				if _, err := strconv.Atoi(b.NPM); err != nil {
+					b.Galat = append(b.Galat, "NPM harus berupa angka")
+				}
+			}
+		case "Dosen":
+			if b.Jurusan == "" {
+				b.Galat = append(b.Galat, "dosen wajib mengisi jurusan")
+			}
+			b.Kelas, b.NPM = "", ""
+		case "Admin":
+			b.Jurusan, b.Kelas, b.NPM = "", "", ""
+		}
+
+		if b.Username == "" && b.Email != "" {
+			b.Username = strings.SplitN(b.Email, "@", 2)[0]
+		}
+		if b.Password != "" {
+			if !utils.IsValidPassword(b.Password) {
+				b.Galat = append(b.Galat, "password harus minimal 8 karakter, mengandung huruf besar, huruf kecil, angka, dan simbol")
+			}
+		} else {
+			b.PasswordDibuat = true
+		}
+
+		for _, cek := range []struct {
+			nilai, label string
+			berkas       map[string]int
+			terdaftar    map[string]bool
+		}{
+			{strings.ToLower(b.Email), "email", emailBerkas, emailAda},
+			{strings.ToLower(b.Username), "username", usernameBerkas, usernameAda},
+			{b.NPM, "NPM", npmBerkas, npmAda},
+		} {
+			if cek.nilai == "" {
+				continue
+			}
+			if cek.terdaftar[cek.nilai] {
+				b.Galat = append(b.Galat, fmt.Sprintf("%s %s sudah terdaftar", cek.label, cek.nilai))
+			}
+			if n, ok := cek.berkas[cek.nilai]; ok {
+				b.Galat = append(b.Galat, fmt.Sprintf("%s sama dengan baris %d", cek.label, n))
+			} else {
+				cek.berkas[cek.nilai] = b.Baris
+			}
+		}
+	}
+	return nil
+}
+
+// identitasTerdaftar mengambil email, username, dan NPM yang sudah dipakai (huruf kecil untuk email/username)
+func (u UserModel) identitasTerdaftar() (email, username, npm map[string]bool, err error) {
+	email, username, npm = map[string]bool{}, map[string]bool{}, map[string]bool{}
+
+	rows, err := u.db.Query(`
+		SELECT LOWER(email), LOWER(COALESCE(username, '')), COALESCE(CAST(npm AS CHAR), '') FROM users
+		UNION ALL
+		SELECT '', '', COALESCE(CAST(npm AS CHAR), '') FROM taruna`)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e, un, n string
+		if err := rows.Scan(&e, &un, &n); err != nil {
+			return nil, nil, nil, err
+		}
+		if e != "" {
+			email[e] = true
+		}
+		if un != "" {
+			username[un] = true
+		}
+		if n != "" {
+			npm[n] = true
+		}
+	}
+	return email, username, npm, rows.Err()
+}
+
+// Impor menyimpan semua baris dalam satu transaksi; satu baris gagal membatalkan seluruh impor.
+// hashedPassword sejajar dengan baris.
+func (u UserModel) Impor(baris []entities.BarisImporUser, hashedPassword []string) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error memulai transaksi: %v", err)
+	}
+	defer tx.Rollback()
+
+	for i, b := range baris {
+		_, err := insertUser(tx, b.NamaLengkap, b.Email, b.Username, b.Role, hashedPassword[i], b.Jurusan, b.Kelas, b.NPM)
+		if err != nil {
+			return fmt.Errorf("baris %d: %v", b.Baris, err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"database/sql"
+	"sort"
+	"user_service/config"
+	"user_service/entities"
+	"user_service/utils"
+)
+
+// bobotKeahlian adalah pengali kata kunci keahlian terhadap uraian bidang penelitian
+const bobotKeahlian = 2
+
+type KecocokanModel struct {
+	DB *sql.DB
+}
+
+func NewKecocokanModel() (*KecocokanModel, error) {
+	db, err := config.ConnectDB()
+	if err != nil {
+		return nil, err
+	}
+	return &KecocokanModel{DB: db}, nil
+}
+
+// TopikTaruna mengambil user_id taruna dan topik penelitian terbarunya (proposal, lalu ICP)
+func (m *KecocokanModel) TopikTaruna(tarunaID int) (int, string, error) {
+	var userID int
+	if err := m.DB.QueryRow("SELECT user_id FROM taruna WHERE id = ?", tarunaID).Scan(&userID); err != nil {
+		return 0, "", err
+	}
+
+	var topik string
+	err := m.DB.QueryRow(`
+		SELECT topik_penelitian FROM (
+			SELECT topik_penelitian, 1 AS urutan, created_at FROM final_proposal WHERE user_id = ?
+			UNION ALL
+			SELECT topik_penelitian, 2 AS urutan, created_at FROM final_icp WHERE user_id = ?
+		) t
+		ORDER BY urutan, created_at DESC
+		LIMIT 1`, userID, userID).Scan(&topik)
+	if err == sql.ErrNoRows {
+		return userID, "", nil
+	}
+	return userID, topik, err
+}
+
+// Peringkat menilai kecocokan setiap dosen aktif terhadap teks topik/abstrak memakai TF-IDF atas
+// profil keahlian dosen, lalu mengurutkannya dari skor tertinggi. Bila userIDTaruna diisi,
+// pembimbing aktif taruna tersebut ditandai agar tidak diusulkan sebagai penguji.
+func (m *KecocokanModel) Peringkat(teks string, userIDTaruna int) ([]entities.KecocokanDosen, error) {
+	rows, err := m.DB.Query(`
+		SELECT d.id, d.nama_lengkap, COALESCE(d.jurusan, ''), COALESCE(d.bidang_penelitian, '')
+		FROM dosen d
+		JOIN users u ON u.id = d.user_id
+		WHERE COALESCE(u.aktif, 1) = 1 AND u.dinonaktifkan_at IS NULL AND u.role = 'dosen'
+		ORDER BY d.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []entities.KecocokanDosen
+	index := map[int]int{}
+	for rows.Next() {
+		d := entities.KecocokanDosen{Keahlian: []string{}, IstilahCocok: []string{}}
+		if err := rows.Scan(&d.DosenID, &d.NamaLengkap, &d.Jurusan, &d.BidangPenelitian); err != nil {
+			return nil, err
+		}
+		index[d.DosenID] = len(list)
+		list = append(list, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagRows, err := m.DB.Query("SELECT dosen_id, tag FROM dosen_keahlian ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer tagRows.Close()
+	for tagRows.Next() {
+		var dosenID int
+		var tag string
+		if err := tagRows.Scan(&dosenID, &tag); err != nil {
+			return nil, err
+		}
+		if i, ok := index[dosenID]; ok {
+			list[i].Keahlian = append(list[i].Keahlian, tag)
+		}
+	}
+	if err := tagRows.Err(); err != nil {
+		return nil, err
+	}
+
+	if userIDTaruna != 0 {
+		pRows, err := m.DB.Query("SELECT dosen_id FROM v_pembimbing_taruna WHERE user_id = ? AND status = 'aktif'", userIDTaruna)
+		if err != nil {
+			return nil, err
+		}
+		defer pRows.Close()
+		for pRows.Next() {
+			var dosenID int
+			if err := pRows.Scan(&dosenID); err != nil {
+				return nil, err
+			}
+			if i, ok := index[dosenID]; ok {
+				list[i].Pembimbing = true
+			}
+		}
+		if err := pRows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	dokumen := make([][]string, len(list))
+	for i, d := range list {
+		for _, tag := range d.Keahlian {
+			tokens := utils.Tokenize(tag)
+			for j := 0; j < bobotKeahlian; j++ {
+				dokumen[i] = append(dokumen[i], tokens...)
+			}
+		}
+		dokumen[i] = append(dokumen[i], utils.Tokenize(d.BidangPenelitian)...)
+	}
+	korpus := utils.NewKorpusTFIDF(dokumen)
+	query := utils.Tokenize(teks)
+	for i := range list {
+		skor, istilah := korpus.Skor(query, i)
+		list[i].Skor = float64(int(skor*10000+0.5)) / 10000
+		list[i].IstilahCocok = istilah
+	}
+
+	sort.SliceStable(list, func(a, b int) bool {
+		if list[a].Skor != list[b].Skor {
+			return list[a].Skor > list[b].Skor
+		}
+		return list[a].NamaLengkap < list[b].NamaLengkap
+	})
+	return list, nil
+}
@@ -0,0 +1,311 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"user_service/config"
+	"user_service/entities"
+)
+
+const (
+	PeranKuotaPembimbing = "pembimbing"
+	PeranKuotaPenelaah   = "penelaah"
+	PeranKuotaPenguji    = "penguji"
+)
+
+var daftarPeranKuota = []string{PeranKuotaPembimbing, PeranKuotaPenelaah, PeranKuotaPenguji}
+
+// peranKuotaJenis memetakan jenis penugasan ke peran kuota yang dipakainya
+var peranKuotaJenis = map[string]string{
+	"dosbing_proposal":   PeranKuotaPembimbing,
+	"dosbing_pendamping": PeranKuotaPembimbing,
+	"penelaah_icp":       PeranKuotaPenelaah,
+	"penguji_proposal":   PeranKuotaPenguji,
+	"penguji_laporan70":  PeranKuotaPenguji,
+	"penguji_laporan100": PeranKuotaPenguji,
+}
+
+var polaTahunAkademik = regexp.MustCompile(`^(\d{4})/(\d{4})$`)
+
+func IsValidPeranKuota(peran string) bool {
+	for _, p := range daftarPeranKuota {
+		if p == peran {
+			return true
+		}
+	}
+	return false
+}
+
+// TahunAkademik mengembalikan tahun akademik (mis. "2025/2026") untuk tanggal t menurut batas baku
+// 1 Agustus; dipakai bila tanggal t belum tercakup tabel tahun_akademik
+func TahunAkademik(t time.Time) string {
+	y := t.Year()
+	if t.Month() < time.August {
+		y--
+	}
+	return fmt.Sprintf("%d/%d", y, y+1)
+}
+
+// tahunAkademikBerjalan mengembalikan kode tahun akademik yang rentang tanggalnya di tabel
+// tahun_akademik mencakup tanggal t, atau TahunAkademik(t) bila belum ada baris yang cocok
+func tahunAkademikBerjalan(db queryer, t time.Time) (string, error) {
+	rows, err := db.Query(`
+		SELECT kode FROM tahun_akademik
+		WHERE ? BETWEEN tanggal_mulai AND tanggal_selesai
+		ORDER BY tanggal_mulai DESC
+		LIMIT 1`, t.Format("2006-01-02"))
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var kode string
+		err := rows.Scan(&kode)
+		return kode, err
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return TahunAkademik(t), nil
+}
+
+// rentangKuota mengembalikan tanggal mulai (inklusif) dan selesai (eksklusif) tahun akademik dari tabel
+// tahun_akademik, atau rentang baku bila tahun tersebut belum terdaftar
+func rentangKuota(db queryer, tahun string) (string, string, error) {
+	mulai, selesai, err := rentangTahunAkademik(tahun)
+	if err != nil {
+		return "", "", err
+	}
+	rows, err := db.Query(`
+		SELECT DATE_FORMAT(tanggal_mulai, '%Y-%m-%d'),
+			DATE_FORMAT(DATE_ADD(tanggal_selesai, INTERVAL 1 DAY), '%Y-%m-%d')
+		FROM tahun_akademik WHERE kode = ?`, tahun)
+	if err != nil {
+		return "", "", err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		err := rows.Scan(&mulai, &selesai)
+		return mulai, selesai, err
+	}
+	return mulai, selesai, rows.Err()
+}
+
+// rentangTahunAkademik mengembalikan tanggal mulai (inklusif) dan selesai (eksklusif) tahun akademik
+func rentangTahunAkademik(tahun string) (string, string, error) {
+	m := polaTahunAkademik.FindStringSubmatch(tahun)
+	if m == nil {
+		return "", "", fmt.Errorf("tahun akademik tidak valid: %s", tahun)
+	}
+	awal, _ := strconv.Atoi(m[1])
+	akhir, _ := strconv.Atoi(m[2])
+	if akhir != awal+1 {
+		return "", "", fmt.Errorf("tahun akademik tidak valid: %s", tahun)
+	}
+	return fmt.Sprintf("%d-08-01", awal), fmt.Sprintf("%d-08-01", akhir), nil
+}
+
+func IsValidTahunAkademik(tahun string) bool {
+	_, _, err := rentangTahunAkademik(tahun)
+	return err == nil
+}
+
+// sumberPenugasanPeran menyusun subquery (dosen_id, user_id, tabel, tanggal) untuk satu peran kuota
+func sumberPenugasanPeran(peran string) string {
+	if peran == PeranKuotaPembimbing {
+		return `SELECT dosen_id, user_id, 'dosbing_proposal' AS tabel, tanggal_ditetapkan AS tanggal
+			FROM dosbing_proposal WHERE status = 'aktif'
+			UNION ALL
+			SELECT dosen_id, user_id, 'dosbing_pendamping' AS tabel, tanggal_ditetapkan AS tanggal
+			FROM dosbing_pendamping WHERE status = 'aktif'`
+	}
+	var bagian []string
+	for _, jenis := range urutanJenisPenugasan {
+		if peranKuotaJenis[jenis] != peran {
+			continue
+		}
+		def := daftarJenisPenugasan[jenis]
+		for _, kolom := range def.Kolom {
+			bagian = append(bagian, fmt.Sprintf(
+				"SELECT %s AS dosen_id, user_id, '%s' AS tabel, created_at AS tanggal FROM %s", kolom, def.Tabel, def.Tabel))
+		}
+	}
+	return strings.Join(bagian, " UNION ALL ")
+}
+
+// hitungTerpakaiKuota menghitung penugasan setiap dosen untuk satu peran dalam tahun akademik.
+// Penugasan taruna yang sedang diubah (tabel dan user_id yang sama) tidak ikut dihitung.
+func hitungTerpakaiKuota(db queryer, tahun, peran, kecualiTabel string, kecualiUserID int) (map[int]int, error) {
+	mulai, selesai, err := rentangKuota(db, tahun)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(`
+		SELECT dosen_id, COUNT(*) FROM (`+sumberPenugasanPeran(peran)+`) s
+		WHERE dosen_id IS NOT NULL AND dosen_id <> 0
+			AND tanggal >= ? AND tanggal < ?
+			AND NOT (tabel = ? AND user_id = ?)
+		GROUP BY dosen_id`, mulai, selesai, kecualiTabel, kecualiUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	terpakai := map[int]int{}
+	for rows.Next() {
+		var dosenID, jumlah int
+		if err := rows.Scan(&dosenID, &jumlah); err != nil {
+			return nil, err
+		}
+		terpakai[dosenID] = jumlah
+	}
+	return terpakai, rows.Err()
+}
+
+// kuotaBerlaku mengembalikan kuota khusus per dosen dan kuota default (nil bila tidak ada)
+func kuotaBerlaku(db queryer, tahun, peran string) (map[int]int, *int, error) {
+	rows, err := db.Query("SELECT dosen_id, kuota FROM kuota_dosen WHERE tahun_akademik = ? AND peran = ?", tahun, peran)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	khusus := map[int]int{}
+	var standar *int
+	for rows.Next() {
+		var dosenID, kuota int
+		if err := rows.Scan(&dosenID, &kuota); err != nil {
+			return nil, nil, err
+		}
+		if dosenID == 0 {
+			k := kuota
+			standar = &k
+			continue
+		}
+		khusus[dosenID] = kuota
+	}
+	return khusus, standar, rows.Err()
+}
+
+func kuotaDosen(khusus map[int]int, standar *int, dosenID int) *int {
+	if k, ok := khusus[dosenID]; ok {
+		return &k
+	}
+	return standar
+}
+
+type KuotaModel struct {
+	DB *sql.DB
+}
+
+func NewKuotaModel() (*KuotaModel, error) {
+	db, err := config.ConnectDB()
+	if err != nil {
+		return nil, err
+	}
+	return &KuotaModel{DB: db}, nil
+}
+
+// GetKuota mengambil pengaturan kuota untuk satu tahun akademik
+// TahunAkademikBerjalan mengembalikan kode tahun akademik yang sedang berjalan
+func (m *KuotaModel) TahunAkademikBerjalan() (string, error) {
+	return tahunAkademikBerjalan(m.DB, time.Now())
+}
+
+func (m *KuotaModel) GetKuota(tahun string) ([]entities.KuotaDosen, error) {
+	rows, err := m.DB.Query(`
+		SELECT k.id, k.dosen_id, COALESCE(d.nama_lengkap, ''), k.tahun_akademik, k.peran, k.kuota
+		FROM kuota_dosen k
+		LEFT JOIN dosen d ON d.id = k.dosen_id
+		WHERE k.tahun_akademik = ?
+		ORDER BY k.dosen_id, k.peran`, tahun)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.KuotaDosen{}
+	for rows.Next() {
+		var k entities.KuotaDosen
+		if err := rows.Scan(&k.ID, &k.DosenID, &k.NamaDosen, &k.TahunAkademik, &k.Peran, &k.Kuota); err != nil {
+			return nil, err
+		}
+		list = append(list, k)
+	}
+	return list, rows.Err()
+}
+
+// SimpanKuota menambah atau mengubah kuota dosen (atau default bila DosenID 0)
+func (m *KuotaModel) SimpanKuota(k *entities.KuotaDosen) error {
+	_, err := m.DB.Exec(`
+		INSERT INTO kuota_dosen (dosen_id, tahun_akademik, peran, kuota)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE kuota = VALUES(kuota)`,
+		k.DosenID, k.TahunAkademik, k.Peran, k.Kuota)
+	return err
+}
+
+func (m *KuotaModel) HapusKuota(id int) (bool, error) {
+	result, err := m.DB.Exec("DELETE FROM kuota_dosen WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Laporan menghitung kuota terpakai dan sisa setiap dosen untuk semua peran dalam tahun akademik
+func (m *KuotaModel) Laporan(tahun string) ([]entities.LaporanKuotaDosen, error) {
+	rows, err := m.DB.Query(`
+		SELECT d.id, d.nama_lengkap, COALESCE(d.jurusan, '')
+		FROM dosen d
+		JOIN users u ON u.id = d.user_id
+		ORDER BY d.nama_lengkap`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	laporan := []entities.LaporanKuotaDosen{}
+	for rows.Next() {
+		var l entities.LaporanKuotaDosen
+		if err := rows.Scan(&l.DosenID, &l.NamaDosen, &l.Jurusan); err != nil {
+			return nil, err
+		}
+		laporan = append(laporan, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, peran := range daftarPeranKuota {
+		terpakai, err := hitungTerpakaiKuota(m.DB, tahun, peran, "", 0)
+		if err != nil {
+			return nil, err
+		}
+		khusus, standar, err := kuotaBerlaku(m.DB, tahun, peran)
+		if err != nil {
+			return nil, err
+		}
+		for i := range laporan {
+			p := entities.PemakaianKuota{
+				Peran:    peran,
+				Kuota:    kuotaDosen(khusus, standar, laporan[i].DosenID),
+				Terpakai: terpakai[laporan[i].DosenID],
+			}
+			if p.Kuota != nil {
+				sisa := *p.Kuota - p.Terpakai
+				if sisa < 0 {
+					sisa = 0
+				}
+				p.Sisa = &sisa
+			}
+			laporan[i].Pemakaian = append(laporan[i].Pemakaian, p)
+		}
+	}
+	return laporan, nil
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+	"user_service/config"
+	"user_service/entities"
+	"user_service/events"
+	"user_service/utils/halaman"
+)
+
+// Status event di outbox
+const (
+	OutboxMenunggu = "menunggu"
+	OutboxTerkirim = "terkirim"
+	OutboxGagal    = "gagal"
+)
+
+// DBTX dipenuhi *sql.DB dan *sql.Tx sehingga model dapat dipakai di dalam transaksi
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// CatatOutbox menulis event ke outbox; panggil dengan transaksi yang juga menyimpan perubahan datanya
+func CatatOutbox(tx DBTX, e events.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO outbox_pengguna (event_id, tipe, payload) VALUES (?, ?, ?)`,
+		e.ID, e.Tipe, string(payload))
+	return err
+}
+
+// catatPenugasan mencatat event penugasan dosen untuk taruna (users.id) ke outbox di dalam tx
+// penugasannya. Slot kosong dan dosen tanpa akun dilewati; bila tidak ada yang tersisa, tidak ada event.
+func catatPenugasan(tx DBTX, jenis string, tarunaUserID int, slots []entities.SlotPenugasan) error {
+	data := events.Data{Jenis: jenis, TarunaUserID: tarunaUserID}
+	for _, s := range slots {
+		if s.DosenID == 0 {
+			continue
+		}
+		var dosenUserID sql.NullInt64
+		err := tx.QueryRow("SELECT user_id FROM dosen WHERE id = ?", s.DosenID).Scan(&dosenUserID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if dosenUserID.Valid && dosenUserID.Int64 != 0 {
+			data.Penugasan = append(data.Penugasan, events.Penugasan{DosenUserID: int(dosenUserID.Int64), Peran: s.Peran})
+		}
+	}
+	if len(data.Penugasan) == 0 {
+		return nil
+	}
+	return CatatOutbox(tx, events.Baru(events.PengujiDitugaskan, data))
+}
+
+// slotJenis memasangkan dosen dengan nama slot jenis penugasan sesuai urutan kolomnya
+func slotJenis(jenis string, dosenIDs ...int) []entities.SlotPenugasan {
+	peran := daftarJenisPenugasan[jenis].Peran
+	slots := make([]entities.SlotPenugasan, 0, len(dosenIDs))
+	for i, id := range dosenIDs {
+		if i < len(peran) {
+			slots = append(slots, entities.SlotPenugasan{Peran: peran[i], DosenID: id})
+		}
+	}
+	return slots
+}
+
+// AturanOutbox adalah urutan yang diizinkan untuk GET /outbox
+var AturanOutbox = halaman.Aturan{
+	Urut: map[string]string{
+		"created_at": "created_at",
+		"percobaan":  "percobaan",
+	},
+	UrutDefault: "-created_at",
+	Kunci:       "id DESC",
+}
+
+type OutboxModel struct {
+	DB *sql.DB
+}
+
+func NewOutboxModel() (*OutboxModel, error) {
+	db, err := config.ConnectDB()
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxModel{DB: db}, nil
+}
+
+const kolomOutbox = `id, event_id, tipe, payload, status, percobaan, coba_lagi_at, COALESCE(galat_terakhir, ''), created_at, terkirim_at`
+
+func scanOutbox(rows *sql.Rows) ([]entities.OutboxEvent, error) {
+	defer rows.Close()
+	list := []entities.OutboxEvent{}
+	for rows.Next() {
+		var e entities.OutboxEvent
+		var payload string
+		var terkirim sql.NullTime
+		if err := rows.Scan(&e.ID, &e.EventID, &e.Tipe, &payload, &e.Status, &e.Percobaan, &e.CobaLagiAt,
+			&e.GalatTerakhir, &e.CreatedAt, &terkirim); err != nil {
+			return nil, err
+		}
+		e.Payload = json.RawMessage(payload)
+		if terkirim.Valid {
+			e.TerkirimAt = &terkirim.Time
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
+
+// AmbilSiapKirim mengambil event menunggu yang waktu coba ulangnya sudah tiba, urut waktu dicatat
+func (m *OutboxModel) AmbilSiapKirim(batas int) ([]entities.OutboxEvent, error) {
+	rows, err := m.DB.Query(`SELECT `+kolomOutbox+` FROM outbox_pengguna
+		WHERE status = ? AND coba_lagi_at <= NOW()
+		ORDER BY id LIMIT ?`, OutboxMenunggu, batas)
+	if err != nil {
+		return nil, err
+	}
+	return scanOutbox(rows)
+}
+
+// TandaiTerkirim mencatat event sudah diterima seluruh konsumen
+func (m *OutboxModel) TandaiTerkirim(id int64) error {
+	_, err := m.DB.Exec(`UPDATE outbox_pengguna SET status = ?, percobaan = percobaan + 1, galat_terakhir = NULL,
+		terkirim_at = NOW() WHERE id = ?`, OutboxTerkirim, id)
+	return err
+}
+
+// TandaiGagalKirim mencatat percobaan yang gagal. Bila cobaLagi nil, event dipindah ke status gagal
+// (dead letter) dan tidak dikirim lagi kecuali diantrekan ulang.
+func (m *OutboxModel) TandaiGagalKirim(id int64, galat string, cobaLagi *time.Time) error {
+	if cobaLagi == nil {
+		_, err := m.DB.Exec(`UPDATE outbox_pengguna SET status = ?, percobaan = percobaan + 1, galat_terakhir = ?
+			WHERE id = ?`, OutboxGagal, galat, id)
+		return err
+	}
+	_, err := m.DB.Exec(`UPDATE outbox_pengguna SET percobaan = percobaan + 1, galat_terakhir = ?, coba_lagi_at = ?
+		WHERE id = ?`, galat, *cobaLagi, id)
+	return err
+}
+
+// GetOutbox menampilkan isi outbox menurut status (opsional) untuk pemantauan admin
+func (m *OutboxModel) GetOutbox(status string, k *halaman.Kueri) ([]entities.OutboxEvent, int, error) {
+	var f halaman.Filter
+	f.Sama("status", status)
+	f.Cari(k.Q, "tipe", "event_id")
+
+	dari := " FROM outbox_pengguna WHERE " + f.SQL()
+	total, err := halaman.Hitung(m.DB, dari, f.Args())
+	if err != nil {
+		return nil, 0, err
+	}
+	potong, argsPotong := k.Potong()
+	rows, err := m.DB.Query(`SELECT `+kolomOutbox+dari+k.OrderBy()+potong, append(f.Args(), argsPotong...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := scanOutbox(rows)
+	return list, total, err
+}
+
+// AntrekanUlang mengembalikan event gagal ke antrean dengan hitungan percobaan dari awal.
+// sql.ErrNoRows bila event tidak ada atau tidak berstatus gagal.
+func (m *OutboxModel) AntrekanUlang(id int64) error {
+	res, err := m.DB.Exec(`UPDATE outbox_pengguna SET status = ?, percobaan = 0, coba_lagi_at = NOW()
+		WHERE id = ? AND status = ?`, OutboxMenunggu, id, OutboxGagal)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -30,14 +30,25 @@ func (m *PenelaahICPModel) AssignPenelaahICP(p *entities.PenelaahICP) error {
 
 	query := `
 		INSERT INTO penelaah_icp
-			(user_id, final_icp_id, penelaah_1_id, penelaah_2_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, NOW(), NOW())
+			(user_id, final_icp_id, penelaah_1_id, penelaah_2_id, periode_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, `+periodeTarunaSQL+`, NOW(), NOW())
 		ON DUPLICATE KEY UPDATE 
 			penelaah_1_id = VALUES(penelaah_1_id),
 			penelaah_2_id = VALUES(penelaah_2_id),
 			updated_at = NOW()
 	`
 
-	_, err = m.DB.Exec(query, userID, p.FinalICPID, p.Penelaah1ID, p.Penelaah2ID)
-	return err
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query, userID, p.FinalICPID, p.Penelaah1ID, p.Penelaah2ID, userID); err != nil {
+		return err
+	}
+	if err := catatPenugasan(tx, "penelaah_icp", userID, slotJenis("penelaah_icp", p.Penelaah1ID, p.Penelaah2ID)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
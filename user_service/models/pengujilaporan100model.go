@@ -30,8 +30,8 @@ func (m *PengujiLaporan100Model) AssignPengujiLaporan100(p *entities.PengujiLapo
 
 	query := `
 		INSERT INTO penguji_laporan100 
-			(user_id, final_laporan100_id, ketua_penguji_id, penguji_1_id, penguji_2_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, NOW(), NOW())
+			(user_id, final_laporan100_id, ketua_penguji_id, penguji_1_id, penguji_2_id, periode_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, `+periodeTarunaSQL+`, NOW(), NOW())
 		ON DUPLICATE KEY UPDATE 
 			ketua_penguji_id = VALUES(ketua_penguji_id),
 			penguji_1_id = VALUES(penguji_1_id),
@@ -39,6 +39,17 @@ func (m *PengujiLaporan100Model) AssignPengujiLaporan100(p *entities.PengujiLapo
 			updated_at = NOW()
 	`
 
-	_, err = m.DB.Exec(query, userID, p.FinalLaporan100ID, p.KetuaID, p.Penguji1ID, p.Penguji2ID)
-	return err
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query, userID, p.FinalLaporan100ID, p.KetuaID, p.Penguji1ID, p.Penguji2ID, userID); err != nil {
+		return err
+	}
+	if err := catatPenugasan(tx, "penguji_laporan100", userID, slotJenis("penguji_laporan100", p.KetuaID, p.Penguji1ID, p.Penguji2ID)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
@@ -30,14 +30,25 @@ func (m *PengujiLaporan70Model) AssignPengujiLaporan70(p *entities.PengujiLapora
 
 	query := `
 		INSERT INTO penguji_laporan70
-			(user_id, final_laporan70_id, penguji_1_id, penguji_2_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, NOW(), NOW())
+			(user_id, final_laporan70_id, penguji_1_id, penguji_2_id, periode_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, `+periodeTarunaSQL+`, NOW(), NOW())
 		ON DUPLICATE KEY UPDATE 
 			penguji_1_id = VALUES(penguji_1_id),
 			penguji_2_id = VALUES(penguji_2_id),
 			updated_at = NOW()
 	`
 
-	_, err = m.DB.Exec(query, userID, p.FinalLaporan70ID, p.Penguji1ID, p.Penguji2ID)
-	return err
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query, userID, p.FinalLaporan70ID, p.Penguji1ID, p.Penguji2ID, userID); err != nil {
+		return err
+	}
+	if err := catatPenugasan(tx, "penguji_laporan70", userID, slotJenis("penguji_laporan70", p.Penguji1ID, p.Penguji2ID)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
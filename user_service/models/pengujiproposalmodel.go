@@ -30,8 +30,8 @@ func (m *PengujiProposalModel) AssignPengujiProposal(p *entities.PengujiProposal
 
 	query := `
 		INSERT INTO penguji_proposal 
-			(user_id, final_proposal_id, ketua_penguji_id, penguji_1_id, penguji_2_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, NOW(), NOW())
+			(user_id, final_proposal_id, ketua_penguji_id, penguji_1_id, penguji_2_id, periode_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, `+periodeTarunaSQL+`, NOW(), NOW())
 		ON DUPLICATE KEY UPDATE 
 			ketua_penguji_id = VALUES(ketua_penguji_id),
 			penguji_1_id = VALUES(penguji_1_id),
@@ -39,6 +39,17 @@ func (m *PengujiProposalModel) AssignPengujiProposal(p *entities.PengujiProposal
 			updated_at = NOW()
 	`
 
-	_, err = m.DB.Exec(query, userID, p.FinalProposalID, p.KetuaID, p.Penguji1ID, p.Penguji2ID)
-	return err
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query, userID, p.FinalProposalID, p.KetuaID, p.Penguji1ID, p.Penguji2ID, userID); err != nil {
+		return err
+	}
+	if err := catatPenugasan(tx, "penguji_proposal", userID, slotJenis("penguji_proposal", p.KetuaID, p.Penguji1ID, p.Penguji2ID)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
@@ -0,0 +1,366 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"user_service/config"
+	"user_service/entities"
+)
+
+type PenugasanModel struct {
+	DB *sql.DB
+}
+
+func NewPenugasanModel() (*PenugasanModel, error) {
+	db, err := config.ConnectDB()
+	if err != nil {
+		return nil, err
+	}
+	return &PenugasanModel{DB: db}, nil
+}
+
+// dosenPenugasan adalah data dosen yang dibutuhkan aturan penugasan
+type dosenPenugasan struct {
+	Nama      string
+	Jurusan   string
+	Aktif     bool
+	Eksternal bool
+}
+
+// konteksPenugasan berisi data taruna dan dosen yang dipakai bersama oleh semua aturan
+type konteksPenugasan struct {
+	Jenis         string
+	JurusanTaruna string
+	Pembimbing    map[int]bool
+	Dosen         map[int]dosenPenugasan
+
+	// Kuota peran pada tahun akademik berjalan; dosen tanpa entri di Kuota tidak dibatasi
+	PeranKuota    string
+	TahunAkademik string
+	Kuota         map[int]int
+	TerpakaiKuota map[int]int
+}
+
+// aturanPenugasan memeriksa satu aturan dan mengembalikan pelanggarannya
+type aturanPenugasan func(k *konteksPenugasan, slots []entities.SlotPenugasan) []entities.Pelanggaran
+
+// Urutan aturan menentukan urutan pelanggaran di response
+var daftarAturanPenugasan = []aturanPenugasan{
+	aturanDosenTerdaftar,
+	aturanTanpaDuplikat,
+	aturanBukanPembimbing,
+	aturanJurusanSama,
+	aturanDosenAktif,
+	aturanPembimbingTamu,
+	aturanKuota,
+}
+
+// daftarAturanPembimbing berlaku untuk penetapan dosen pembimbing utama dan pendamping
+var daftarAturanPembimbing = []aturanPenugasan{
+	aturanDosenTerdaftar,
+	aturanPembimbingTamu,
+	aturanPembimbingBerbeda,
+	aturanDosenAktif,
+	aturanKuota,
+}
+
+// peranPembimbingJenis adalah peran di v_pembimbing_taruna yang sedang ditetapkan oleh jenis penugasan
+var peranPembimbingJenis = map[string]string{
+	"dosbing_proposal":   PeranPembimbingUtama,
+	"dosbing_pendamping": PeranPembimbingPendamping,
+}
+
+// Validasi menjalankan seluruh aturan penugasan jenis tertentu untuk taruna (taruna.id) dan dosen
+// yang dipilih. Slot dengan DosenID 0 dianggap kosong dan dilewati.
+func (m *PenugasanModel) Validasi(jenis string, tarunaID int, slots []entities.SlotPenugasan) ([]entities.Pelanggaran, error) {
+	return validasiPenugasan(m.DB, jenis, tarunaID, slots)
+}
+
+// validasiPenugasan sama dengan Validasi, tetapi membaca data lewat db; panggil dengan transaksi agar
+// kuota dihitung dari data yang sudah dikunci
+func validasiPenugasan(db DBTX, jenis string, tarunaID int, slots []entities.SlotPenugasan) ([]entities.Pelanggaran, error) {
+	var terisi []entities.SlotPenugasan
+	for _, s := range slots {
+		if s.DosenID != 0 {
+			terisi = append(terisi, s)
+		}
+	}
+
+	k, err := loadKonteks(db, jenis, tarunaID, terisi)
+	if err != nil {
+		return nil, err
+	}
+
+	daftarAturan := daftarAturanPenugasan
+	if _, ok := peranPembimbingJenis[jenis]; ok {
+		daftarAturan = daftarAturanPembimbing
+	}
+	pelanggaran := []entities.Pelanggaran{}
+	for _, aturan := range daftarAturan {
+		pelanggaran = append(pelanggaran, aturan(k, terisi)...)
+	}
+	return pelanggaran, nil
+}
+
+func loadKonteks(db DBTX, jenis string, tarunaID int, slots []entities.SlotPenugasan) (*konteksPenugasan, error) {
+	k := &konteksPenugasan{
+		Jenis:      jenis,
+		Pembimbing: map[int]bool{},
+		Dosen:      map[int]dosenPenugasan{},
+	}
+
+	var userID int
+	var jurusan sql.NullString
+	err := db.QueryRow("SELECT user_id, jurusan FROM taruna WHERE id = ?", tarunaID).Scan(&userID, &jurusan)
+	if err != nil {
+		return nil, fmt.Errorf("taruna not found: %v", err)
+	}
+	k.JurusanTaruna = jurusan.String
+
+	// Saat menetapkan pembimbing, baris peran yang sedang diganti tidak ikut dihitung
+	rows, err := db.Query(`
+		SELECT dosen_id FROM v_pembimbing_taruna
+		WHERE user_id = ? AND status = 'aktif' AND peran <> ?`, userID, peranPembimbingJenis[jenis])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var dosenID int
+		if err := rows.Scan(&dosenID); err != nil {
+			return nil, err
+		}
+		k.Pembimbing[dosenID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, s := range slots {
+		if _, ok := k.Dosen[s.DosenID]; ok {
+			continue
+		}
+		var d dosenPenugasan
+		var nama, jurusanDosen sql.NullString
+		err := db.QueryRow(`
+			SELECT d.nama_lengkap, d.jurusan,
+				COALESCE(u.aktif, 1) = 1 AND u.dinonaktifkan_at IS NULL AND COALESCE(u.role, 'dosen') = 'dosen',
+				COALESCE(d.eksternal, 0)
+			FROM dosen d
+			LEFT JOIN users u ON u.id = d.user_id
+			WHERE d.id = ?`, s.DosenID).Scan(&nama, &jurusanDosen, &d.Aktif, &d.Eksternal)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.Nama, d.Jurusan = nama.String, jurusanDosen.String
+		k.Dosen[s.DosenID] = d
+	}
+
+	if peran, ok := peranKuotaJenis[jenis]; ok {
+		k.PeranKuota = peran
+		k.TahunAkademik, err = tahunAkademikBerjalan(db, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		k.TerpakaiKuota, err = hitungTerpakaiKuota(db, k.TahunAkademik, peran, jenis, userID)
+		if err != nil {
+			return nil, err
+		}
+		khusus, standar, err := kuotaBerlaku(db, k.TahunAkademik, peran)
+		if err != nil {
+			return nil, err
+		}
+		k.Kuota = map[int]int{}
+		for _, s := range slots {
+			if kuota := kuotaDosen(khusus, standar, s.DosenID); kuota != nil {
+				k.Kuota[s.DosenID] = *kuota
+			}
+		}
+	}
+	return k, nil
+}
+
+func aturanDosenTerdaftar(k *konteksPenugasan, slots []entities.SlotPenugasan) []entities.Pelanggaran {
+	var hasil []entities.Pelanggaran
+	for _, s := range slots {
+		if _, ok := k.Dosen[s.DosenID]; !ok {
+			hasil = append(hasil, entities.Pelanggaran{
+				Aturan:  "dosen_tidak_ditemukan",
+				Peran:   s.Peran,
+				DosenID: s.DosenID,
+				Pesan:   fmt.Sprintf("%s: dosen dengan ID %d tidak ditemukan", s.Peran, s.DosenID),
+			})
+		}
+	}
+	return hasil
+}
+
+func aturanTanpaDuplikat(k *konteksPenugasan, slots []entities.SlotPenugasan) []entities.Pelanggaran {
+	var hasil []entities.Pelanggaran
+	peranPertama := map[int]string{}
+	for _, s := range slots {
+		if peran, ok := peranPertama[s.DosenID]; ok {
+			hasil = append(hasil, entities.Pelanggaran{
+				Aturan:  "dosen_ganda",
+				Peran:   s.Peran,
+				DosenID: s.DosenID,
+				Pesan:   fmt.Sprintf("%s sudah ditugaskan sebagai %s dan %s sekaligus", namaDosen(k, s.DosenID), peran, s.Peran),
+			})
+			continue
+		}
+		peranPertama[s.DosenID] = s.Peran
+	}
+	return hasil
+}
+
+func aturanBukanPembimbing(k *konteksPenugasan, slots []entities.SlotPenugasan) []entities.Pelanggaran {
+	var hasil []entities.Pelanggaran
+	for _, s := range slots {
+		if k.Pembimbing[s.DosenID] {
+			hasil = append(hasil, entities.Pelanggaran{
+				Aturan:       "pembimbing_sebagai_penguji",
+				Peran:        s.Peran,
+				DosenID:      s.DosenID,
+				Pesan:        fmt.Sprintf("%s adalah dosen pembimbing taruna ini dan tidak boleh menjadi %s", namaDosen(k, s.DosenID), s.Peran),
+				BisaOverride: true,
+			})
+		}
+	}
+	return hasil
+}
+
+func aturanJurusanSama(k *konteksPenugasan, slots []entities.SlotPenugasan) []entities.Pelanggaran {
+	jurusanTaruna := strings.TrimSpace(k.JurusanTaruna)
+	if jurusanTaruna == "" {
+		return nil
+	}
+	var hasil []entities.Pelanggaran
+	for _, s := range slots {
+		d, ok := k.Dosen[s.DosenID]
+		if !ok || strings.EqualFold(strings.TrimSpace(d.Jurusan), jurusanTaruna) {
+			continue
+		}
+		hasil = append(hasil, entities.Pelanggaran{
+			Aturan:       "jurusan_berbeda",
+			Peran:        s.Peran,
+			DosenID:      s.DosenID,
+			Pesan:        fmt.Sprintf("%s berasal dari jurusan %s, sedangkan taruna dari jurusan %s", d.Nama, orStrip(d.Jurusan), jurusanTaruna),
+			BisaOverride: true,
+		})
+	}
+	return hasil
+}
+
+func aturanDosenAktif(k *konteksPenugasan, slots []entities.SlotPenugasan) []entities.Pelanggaran {
+	var hasil []entities.Pelanggaran
+	for _, s := range slots {
+		if d, ok := k.Dosen[s.DosenID]; ok && !d.Aktif {
+			hasil = append(hasil, entities.Pelanggaran{
+				Aturan:       "dosen_nonaktif",
+				Peran:        s.Peran,
+				DosenID:      s.DosenID,
+				Pesan:        fmt.Sprintf("%s berstatus nonaktif", d.Nama),
+				BisaOverride: true,
+			})
+		}
+	}
+	return hasil
+}
+
+// aturanPembimbingTamu membatasi pembimbing tamu hanya sebagai pembimbing pendamping
+func aturanPembimbingTamu(k *konteksPenugasan, slots []entities.SlotPenugasan) []entities.Pelanggaran {
+	if k.Jenis == "dosbing_pendamping" {
+		return nil
+	}
+	var hasil []entities.Pelanggaran
+	for _, s := range slots {
+		if d, ok := k.Dosen[s.DosenID]; ok && d.Eksternal {
+			hasil = append(hasil, entities.Pelanggaran{
+				Aturan:  "pembimbing_tamu",
+				Peran:   s.Peran,
+				DosenID: s.DosenID,
+				Pesan:   fmt.Sprintf("%s adalah pembimbing tamu dan hanya dapat ditetapkan sebagai pembimbing pendamping", d.Nama),
+			})
+		}
+	}
+	return hasil
+}
+
+// aturanPembimbingBerbeda mencegah satu dosen menjadi pembimbing utama sekaligus pendamping
+func aturanPembimbingBerbeda(k *konteksPenugasan, slots []entities.SlotPenugasan) []entities.Pelanggaran {
+	var hasil []entities.Pelanggaran
+	for _, s := range slots {
+		if k.Pembimbing[s.DosenID] {
+			hasil = append(hasil, entities.Pelanggaran{
+				Aturan:  "pembimbing_ganda",
+				Peran:   s.Peran,
+				DosenID: s.DosenID,
+				Pesan:   fmt.Sprintf("%s sudah menjadi pembimbing taruna ini dengan peran lain", namaDosen(k, s.DosenID)),
+			})
+		}
+	}
+	return hasil
+}
+
+func aturanKuota(k *konteksPenugasan, slots []entities.SlotPenugasan) []entities.Pelanggaran {
+	var hasil []entities.Pelanggaran
+	diperiksa := map[int]bool{}
+	for _, s := range slots {
+		kuota, ok := k.Kuota[s.DosenID]
+		if !ok || diperiksa[s.DosenID] {
+			continue
+		}
+		diperiksa[s.DosenID] = true
+		if k.TerpakaiKuota[s.DosenID]+1 > kuota {
+			hasil = append(hasil, entities.Pelanggaran{
+				Aturan:  "kuota_terlampaui",
+				Peran:   s.Peran,
+				DosenID: s.DosenID,
+				Pesan: fmt.Sprintf("%s telah memakai %d dari kuota %d sebagai %s pada tahun akademik %s",
+					namaDosen(k, s.DosenID), k.TerpakaiKuota[s.DosenID], kuota, k.PeranKuota, k.TahunAkademik),
+				BisaOverride: true,
+			})
+		}
+	}
+	return hasil
+}
+
+func namaDosen(k *konteksPenugasan, dosenID int) string {
+	if d, ok := k.Dosen[dosenID]; ok && d.Nama != "" {
+		return d.Nama
+	}
+	return "Dosen #" + strconv.Itoa(dosenID)
+}
+
+func orStrip(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
+
+// LogOverride mencatat penugasan yang disimpan admin meskipun melanggar aturan
+func (m *PenugasanModel) LogOverride(jenis string, tarunaID, finalID int, slots []entities.SlotPenugasan,
+	pelanggaran []entities.Pelanggaran, alasan, adminEmail string) error {
+	ids := make([]string, 0, len(slots))
+	for _, s := range slots {
+		ids = append(ids, fmt.Sprintf("%s=%d", s.Peran, s.DosenID))
+	}
+	detail, err := json.Marshal(pelanggaran)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.DB.Exec(`
+		INSERT INTO penugasan_override_log (jenis, taruna_id, final_id, dosen_ids, pelanggaran, alasan, admin_email)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		jenis, tarunaID, finalID, strings.Join(ids, ","), string(detail), alasan, adminEmail)
+	return err
+}
@@ -0,0 +1,494 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"user_service/config"
+	"user_service/entities"
+)
+
+const (
+	StatusPergantianMenungguDosenLama = "menunggu_dosen_lama"
+	StatusPergantianMenungguDosenBaru = "menunggu_dosen_baru"
+	StatusPergantianMenungguAdmin     = "menunggu_admin"
+	StatusPergantianDisetujui         = "disetujui"
+	StatusPergantianDitolak           = "ditolak"
+	StatusPergantianDibatalkan        = "dibatalkan"
+
+	TahapPergantianDosenLama = "dosen_lama"
+	TahapPergantianDosenBaru = "dosen_baru"
+	TahapPergantianAdmin     = "admin"
+)
+
+// Urutan persetujuan pergantian pembimbing
+var urutanTahapPergantian = []string{TahapPergantianDosenLama, TahapPergantianDosenBaru, TahapPergantianAdmin}
+
+// dokumenBimbingan adalah dokumen taruna yang direview pembimbing (kolom dosen_id dan status) beserta
+// tabel siklus reviewnya: revisi yang diunggah taruna dan review balasan dosen, dihubungkan lewat kolom.
+type dokumenBimbingan struct {
+	tabel        string
+	reviewTaruna string
+	reviewDosen  string
+	kolom        string
+}
+
+var tabelDokumenBimbingan = []dokumenBimbingan{
+	{"icp", "review_icp_taruna", "review_icp_dosen", "icp_id"},
+	{"proposal", "review_proposal_taruna", "review_proposal_dosen", "proposal_id"},
+	{"laporan_70", "review_laporan70_taruna", "review_laporan70_dosen", "laporan70_id"},
+	{"laporan_100", "review_laporan100_taruna", "review_laporan100_dosen", "laporan100_id"},
+}
+
+func tabelPembimbing(peran string) string {
+	if peran == PeranPembimbingPendamping {
+		return "dosbing_pendamping"
+	}
+	return "dosbing_proposal"
+}
+
+// tetapkanPembimbing menyimpan pembimbing taruna beserta riwayatnya di dalam transaksi tx.
+// Bila pembimbing berganti, riwayat lama ditutup dan dokumen yang masih menunggu atau sedang dalam
+// review pembimbing lama dialihkan ke pembimbing baru, termasuk revisi taruna yang belum dibalas.
+// Mengembalikan jumlah dokumen yang dialihkan.
+func tetapkanPembimbing(tx *sql.Tx, userID int, peran string, dosenID int, status, keterangan string, pergantianID *int) (int, error) {
+	table := tabelPembimbing(peran)
+
+	var lama int
+	err := tx.QueryRow("SELECT dosen_id FROM "+table+" WHERE user_id = ? FOR UPDATE", userID).Scan(&lama)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO `+table+` (user_id, dosen_id, tanggal_ditetapkan, status, periode_id)
+		VALUES (?, ?, CURDATE(), ?, `+periodeTarunaSQL+`)
+		ON DUPLICATE KEY UPDATE
+			tanggal_ditetapkan = IF(dosen_id = VALUES(dosen_id), tanggal_ditetapkan, CURDATE()),
+			dosen_id = VALUES(dosen_id),
+			status = VALUES(status)`, userID, dosenID, status, userID)
+	if err != nil {
+		return 0, err
+	}
+	if lama == dosenID {
+		return 0, nil
+	}
+
+	dialihkan := 0
+	if lama != 0 {
+		if err := tutupRiwayatPembimbing(tx, userID, peran); err != nil {
+			return 0, err
+		}
+		for _, t := range tabelDokumenBimbingan {
+			// Revisi taruna yang belum dibalas review dosen ikut berpindah; review yang sudah ditulis
+			// pembimbing lama tetap tercatat atas namanya
+			_, err := tx.Exec(`
+				UPDATE `+t.reviewTaruna+` r
+				JOIN `+t.tabel+` d ON d.id = r.`+t.kolom+`
+				SET r.dosen_id = ?, r.updated_at = NOW()
+				WHERE d.user_id = ? AND d.dosen_id = ? AND d.status = 'on review' AND r.dosen_id = ?
+					AND NOT EXISTS (
+						SELECT 1 FROM `+t.reviewDosen+` b
+						WHERE b.`+t.kolom+` = r.`+t.kolom+` AND b.created_at > r.created_at
+					)`, dosenID, userID, lama, lama)
+			if err != nil {
+				return 0, fmt.Errorf("gagal mengalihkan review %s: %v", t.tabel, err)
+			}
+			result, err := tx.Exec(`
+				UPDATE `+t.tabel+` SET dosen_id = ?, updated_at = NOW()
+				WHERE user_id = ? AND dosen_id = ? AND status IN ('pending', 'on review')`, dosenID, userID, lama)
+			if err != nil {
+				return 0, fmt.Errorf("gagal mengalihkan dokumen %s: %v", t.tabel, err)
+			}
+			n, _ := result.RowsAffected()
+			dialihkan += int(n)
+		}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO riwayat_pembimbing (user_id, dosen_id, peran, tanggal_mulai, keterangan, pergantian_id)
+		VALUES (?, ?, ?, CURDATE(), ?, ?)`, userID, dosenID, peran, keterangan, pergantianID)
+	return dialihkan, err
+}
+
+func tutupRiwayatPembimbing(tx *sql.Tx, userID int, peran string) error {
+	_, err := tx.Exec(`
+		UPDATE riwayat_pembimbing SET tanggal_selesai = CURDATE()
+		WHERE user_id = ? AND peran = ? AND tanggal_selesai IS NULL`, userID, peran)
+	return err
+}
+
+type PergantianPembimbingModel struct {
+	DB *sql.DB
+}
+
+func NewPergantianPembimbingModel() (*PergantianPembimbingModel, error) {
+	db, err := config.ConnectDB()
+	if err != nil {
+		return nil, err
+	}
+	return &PergantianPembimbingModel{DB: db}, nil
+}
+
+// PembimbingSaatIni mengembalikan user_id taruna dan dosen_id pembimbing peran tersebut (0 bila belum ada)
+func (m *PergantianPembimbingModel) PembimbingSaatIni(tarunaID int, peran string) (int, int, error) {
+	var userID int
+	var dosenID sql.NullInt64
+	err := m.DB.QueryRow(`
+		SELECT t.user_id, p.dosen_id
+		FROM taruna t
+		LEFT JOIN `+tabelPembimbing(peran)+` p ON p.user_id = t.user_id
+		WHERE t.id = ?`, tarunaID).Scan(&userID, &dosenID)
+	if err == sql.ErrNoRows {
+		return 0, 0, galatPermintaan(404, "Taruna tidak ditemukan")
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return userID, int(dosenID.Int64), nil
+}
+
+// Ajukan menyimpan pengajuan pergantian pembimbing. Tahap persetujuan milik pengaju sendiri
+// (pembimbing lama atau baru yang mengajukan) langsung dianggap disetujui.
+func (m *PergantianPembimbingModel) Ajukan(p *Pengguna, userID, dosenLamaID int, req entities.PengajuanPergantianPembimbing) (*entities.PergantianPembimbing, error) {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var menunggu int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM pergantian_pembimbing
+		WHERE user_id = ? AND peran = ? AND status LIKE 'menunggu_%' FOR UPDATE`, userID, req.Peran).Scan(&menunggu)
+	if err != nil {
+		return nil, err
+	}
+	if menunggu > 0 {
+		return nil, galatPermintaan(409, "Masih ada pengajuan pergantian pembimbing %s yang belum selesai", req.Peran)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO pergantian_pembimbing (user_id, peran, dosen_lama_id, dosen_baru_id, alasan, diajukan_oleh, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		userID, req.Peran, dosenLamaID, req.DosenBaruID, req.Alasan, p.UserID, StatusPergantianMenungguDosenLama)
+	if err != nil {
+		return nil, err
+	}
+	id64, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	id := int(id64)
+
+	disetujui := map[string]bool{}
+	if p.Role == "dosen" {
+		tahap := TahapPergantianDosenBaru
+		if p.DosenID == dosenLamaID {
+			tahap = TahapPergantianDosenLama
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO persetujuan_pergantian (pergantian_id, tahap, user_id, keputusan, catatan)
+			VALUES (?, ?, ?, 'setuju', 'Diajukan sendiri')`, id, tahap, p.UserID); err != nil {
+			return nil, err
+		}
+		disetujui[tahap] = true
+	}
+	if _, err := tx.Exec("UPDATE pergantian_pembimbing SET status = ? WHERE id = ?",
+		statusBerikutnya(disetujui), id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return m.GetByID(id)
+}
+
+// statusBerikutnya mengembalikan status menunggu tahap pertama yang belum disetujui
+func statusBerikutnya(disetujui map[string]bool) string {
+	for _, tahap := range urutanTahapPergantian {
+		if !disetujui[tahap] {
+			return "menunggu_" + tahap
+		}
+	}
+	return StatusPergantianDisetujui
+}
+
+// TahapMenunggu mengembalikan tahap yang sedang menunggu keputusan ("" bila pengajuan sudah selesai)
+func TahapMenunggu(status string) string {
+	if !strings.HasPrefix(status, "menunggu_") {
+		return ""
+	}
+	return strings.TrimPrefix(status, "menunggu_")
+}
+
+// BolehMemutuskan memeriksa apakah pengguna adalah pihak yang berwenang pada tahap pengajuan saat ini
+func BolehMemutuskan(p *Pengguna, g *entities.PergantianPembimbing) bool {
+	switch TahapMenunggu(g.Status) {
+	case TahapPergantianDosenLama:
+		return p.Role == "dosen" && p.DosenID == g.DosenLamaID
+	case TahapPergantianDosenBaru:
+		return p.Role == "dosen" && p.DosenID == g.DosenBaruID
+	case TahapPergantianAdmin:
+		return p.Role == "admin"
+	}
+	return false
+}
+
+// Putuskan mencatat keputusan tahap yang sedang menunggu. Persetujuan admin (tahap terakhir)
+// langsung menetapkan pembimbing baru, menutup riwayat lama, dan mengalihkan dokumen yang belum selesai
+// direview. Mengembalikan jumlah dokumen yang dialihkan.
+func (m *PergantianPembimbingModel) Putuskan(p *Pengguna, req entities.KeputusanPergantianPembimbing) (int, error) {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var g entities.PergantianPembimbing
+	err = tx.QueryRow(`
+		SELECT id, user_id, peran, dosen_lama_id, dosen_baru_id, status
+		FROM pergantian_pembimbing WHERE id = ? FOR UPDATE`, req.PergantianID).
+		Scan(&g.ID, &g.UserID, &g.Peran, &g.DosenLamaID, &g.DosenBaruID, &g.Status)
+	if err == sql.ErrNoRows {
+		return 0, galatPermintaan(404, "Pengajuan pergantian tidak ditemukan")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	tahap := TahapMenunggu(g.Status)
+	if tahap == "" {
+		return 0, galatPermintaan(409, "Pengajuan sudah %s", g.Status)
+	}
+	if !BolehMemutuskan(p, &g) {
+		return 0, galatPermintaan(403, "Anda tidak berwenang memutuskan tahap %s", tahap)
+	}
+
+	keputusan := "tolak"
+	if req.Setuju {
+		keputusan = "setuju"
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO persetujuan_pergantian (pergantian_id, tahap, user_id, keputusan, catatan)
+		VALUES (?, ?, ?, ?, ?)`, g.ID, tahap, p.UserID, keputusan, req.Catatan); err != nil {
+		return 0, err
+	}
+
+	if !req.Setuju {
+		_, err := tx.Exec("UPDATE pergantian_pembimbing SET status = ? WHERE id = ?", StatusPergantianDitolak, g.ID)
+		if err != nil {
+			return 0, err
+		}
+		return 0, tx.Commit()
+	}
+
+	disetujui := map[string]bool{}
+	rows, err := tx.Query("SELECT tahap FROM persetujuan_pergantian WHERE pergantian_id = ? AND keputusan = 'setuju'", g.ID)
+	if err != nil {
+		return 0, err
+	}
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		disetujui[t] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	status := statusBerikutnya(disetujui)
+	if status != StatusPergantianDisetujui {
+		if _, err := tx.Exec("UPDATE pergantian_pembimbing SET status = ? WHERE id = ?", status, g.ID); err != nil {
+			return 0, err
+		}
+		return 0, tx.Commit()
+	}
+
+	// Pembimbing lama bisa saja sudah diganti lewat jalur lain selama pengajuan berjalan
+	var saatIni int
+	err = tx.QueryRow("SELECT dosen_id FROM "+tabelPembimbing(g.Peran)+" WHERE user_id = ? FOR UPDATE", g.UserID).Scan(&saatIni)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	if saatIni != g.DosenLamaID {
+		return 0, galatPermintaan(409, "Pembimbing taruna sudah berubah sejak pengajuan dibuat; ajukan ulang pergantian")
+	}
+
+	dialihkan, err := tetapkanPembimbing(tx, g.UserID, g.Peran, g.DosenBaruID, "aktif",
+		fmt.Sprintf("Pergantian pembimbing #%d", g.ID), &g.ID)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`
+		UPDATE pergantian_pembimbing SET status = ?, tanggal_efektif = CURDATE() WHERE id = ?`,
+		StatusPergantianDisetujui, g.ID); err != nil {
+		return 0, err
+	}
+	if err := catatPembimbing(tx, g.UserID, g.Peran, g.DosenBaruID); err != nil {
+		return 0, err
+	}
+	return dialihkan, tx.Commit()
+}
+
+// Batalkan membatalkan pengajuan yang belum selesai; hanya pengaju atau admin yang dapat membatalkan
+func (m *PergantianPembimbingModel) Batalkan(p *Pengguna, id int) error {
+	g, err := m.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if g.DiajukanOleh != p.UserID && p.Role != "admin" {
+		return galatPermintaan(403, "Hanya pengaju atau admin yang dapat membatalkan pengajuan")
+	}
+	result, err := m.DB.Exec(`
+		UPDATE pergantian_pembimbing SET status = ?
+		WHERE id = ? AND status LIKE 'menunggu_%'`, StatusPergantianDibatalkan, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return galatPermintaan(409, "Pengajuan sudah %s", g.Status)
+	}
+	return nil
+}
+
+const selectPergantian = `
+	SELECT g.id, COALESCE(t.id, 0), g.user_id, COALESCE(t.nama_lengkap, ''), g.peran,
+		g.dosen_lama_id, COALESCE(dl.nama_lengkap, ''), g.dosen_baru_id, COALESCE(db.nama_lengkap, ''),
+		g.alasan, g.diajukan_oleh, g.status, COALESCE(DATE_FORMAT(g.tanggal_efektif, '%Y-%m-%d'), ''),
+		DATE_FORMAT(g.created_at, '%Y-%m-%d %H:%i:%s')
+	FROM pergantian_pembimbing g
+	LEFT JOIN taruna t ON t.user_id = g.user_id
+	LEFT JOIN dosen dl ON dl.id = g.dosen_lama_id
+	LEFT JOIN dosen db ON db.id = g.dosen_baru_id`
+
+func (m *PergantianPembimbingModel) GetByID(id int) (*entities.PergantianPembimbing, error) {
+	list, err := m.query(selectPergantian+" WHERE g.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, galatPermintaan(404, "Pengajuan pergantian tidak ditemukan")
+	}
+	return &list[0], nil
+}
+
+// GetDaftar mengambil pengajuan yang relevan bagi pengguna: semua untuk admin, pengajuan yang
+// melibatkan dosen tersebut, atau pengajuan milik taruna sendiri. Status kosong berarti semua status.
+func (m *PergantianPembimbingModel) GetDaftar(p *Pengguna, status string) ([]entities.PergantianPembimbing, error) {
+	var (
+		kondisi []string
+		args    []interface{}
+	)
+	switch p.Role {
+	case "admin":
+	case "dosen":
+		kondisi = append(kondisi, "(g.dosen_lama_id = ? OR g.dosen_baru_id = ?)")
+		args = append(args, p.DosenID, p.DosenID)
+	default:
+		kondisi = append(kondisi, "g.user_id = ?")
+		args = append(args, p.UserID)
+	}
+	if status != "" {
+		kondisi = append(kondisi, "g.status = ?")
+		args = append(args, status)
+	}
+
+	query := selectPergantian
+	if len(kondisi) > 0 {
+		query += " WHERE " + strings.Join(kondisi, " AND ")
+	}
+	return m.query(query+" ORDER BY g.created_at DESC", args...)
+}
+
+func (m *PergantianPembimbingModel) query(query string, args ...interface{}) ([]entities.PergantianPembimbing, error) {
+	rows, err := m.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.PergantianPembimbing{}
+	indeks := map[int]int{}
+	for rows.Next() {
+		var g entities.PergantianPembimbing
+		if err := rows.Scan(&g.ID, &g.TarunaID, &g.UserID, &g.NamaTaruna, &g.Peran,
+			&g.DosenLamaID, &g.NamaDosenLama, &g.DosenBaruID, &g.NamaDosenBaru,
+			&g.Alasan, &g.DiajukanOleh, &g.Status, &g.TanggalEfektif, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		g.Persetujuan = []entities.PersetujuanPergantian{}
+		indeks[g.ID] = len(list)
+		list = append(list, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return list, nil
+	}
+
+	ids := make([]string, 0, len(list))
+	for _, g := range list {
+		ids = append(ids, fmt.Sprint(g.ID))
+	}
+	prows, err := m.DB.Query(`
+		SELECT s.pergantian_id, s.tahap, s.user_id, COALESCE(u.nama_lengkap, ''), s.keputusan,
+			COALESCE(s.catatan, ''), DATE_FORMAT(s.created_at, '%Y-%m-%d %H:%i:%s')
+		FROM persetujuan_pergantian s
+		LEFT JOIN users u ON u.id = s.user_id
+		WHERE s.pergantian_id IN (` + strings.Join(ids, ",") + `)
+		ORDER BY s.created_at, s.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer prows.Close()
+	for prows.Next() {
+		var id int
+		var s entities.PersetujuanPergantian
+		if err := prows.Scan(&id, &s.Tahap, &s.UserID, &s.Nama, &s.Keputusan, &s.Catatan, &s.Tanggal); err != nil {
+			return nil, err
+		}
+		i := indeks[id]
+		list[i].Persetujuan = append(list[i].Persetujuan, s)
+	}
+	return list, prows.Err()
+}
+
+// GetRiwayat mengambil seluruh riwayat pembimbing taruna, yang terbaru lebih dulu
+func (m *PergantianPembimbingModel) GetRiwayat(tarunaID int) ([]entities.RiwayatPembimbing, error) {
+	rows, err := m.DB.Query(`
+		SELECT r.id, r.dosen_id, COALESCE(d.nama_lengkap, ''), r.peran,
+			DATE_FORMAT(r.tanggal_mulai, '%Y-%m-%d'), COALESCE(DATE_FORMAT(r.tanggal_selesai, '%Y-%m-%d'), ''),
+			COALESCE(r.keterangan, ''), r.pergantian_id
+		FROM riwayat_pembimbing r
+		JOIN taruna t ON t.user_id = r.user_id
+		LEFT JOIN dosen d ON d.id = r.dosen_id
+		WHERE t.id = ?
+		ORDER BY r.tanggal_mulai DESC, r.id DESC`, tarunaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.RiwayatPembimbing{}
+	for rows.Next() {
+		var r entities.RiwayatPembimbing
+		var pergantianID sql.NullInt64
+		if err := rows.Scan(&r.ID, &r.DosenID, &r.NamaDosen, &r.Peran, &r.TanggalMulai, &r.TanggalSelesai,
+			&r.Keterangan, &pergantianID); err != nil {
+			return nil, err
+		}
+		if pergantianID.Valid {
+			id := int(pergantianID.Int64)
+			r.PergantianID = &id
+		}
+		list = append(list, r)
+	}
+	return list, rows.Err()
+}
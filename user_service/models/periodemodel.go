@@ -0,0 +1,332 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"user_service/config"
+	"user_service/entities"
+)
+
+const (
+	StatusPeriodeAktif      = "aktif"
+	StatusPeriodeDitutup    = "ditutup"
+	StatusPeriodeDiarsipkan = "diarsipkan"
+)
+
+// periodeTarunaSQL adalah subquery periode taruna saat ini berdasarkan user_id, untuk menstempel
+// periode pada baris penugasan baru
+const periodeTarunaSQL = "(SELECT periode_id FROM taruna WHERE user_id = ?)"
+
+// KondisiPeriode menyusun kondisi SQL untuk kolom periode: periode tertentu bila periodeID > 0,
+// selain itu semua data kecuali yang periodenya sudah diarsipkan (data tanpa periode tetap tampil)
+func KondisiPeriode(kolom string, periodeID int) (string, []interface{}) {
+	if periodeID > 0 {
+		return kolom + " = ?", []interface{}{periodeID}
+	}
+	return fmt.Sprintf("COALESCE(%s, 0) NOT IN (SELECT id FROM periode_ta WHERE status = '%s')",
+		kolom, StatusPeriodeDiarsipkan), nil
+}
+
+type PeriodeModel struct {
+	DB *sql.DB
+}
+
+func NewPeriodeModel() (*PeriodeModel, error) {
+	db, err := config.ConnectDB()
+	if err != nil {
+		return nil, err
+	}
+	return &PeriodeModel{DB: db}, nil
+}
+
+// GetTahunAkademik mengambil seluruh tahun akademik, terbaru lebih dulu
+func (m *PeriodeModel) GetTahunAkademik() ([]entities.TahunAkademikTA, error) {
+	rows, err := m.DB.Query(`
+		SELECT kode, DATE_FORMAT(tanggal_mulai, '%Y-%m-%d'), DATE_FORMAT(tanggal_selesai, '%Y-%m-%d')
+		FROM tahun_akademik
+		ORDER BY kode DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.TahunAkademikTA{}
+	for rows.Next() {
+		var t entities.TahunAkademikTA
+		if err := rows.Scan(&t.Kode, &t.TanggalMulai, &t.TanggalSelesai); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// SimpanTahunAkademik menambah tahun akademik; tanggal yang kosong diisi rentang baku 1 Agustus–31 Juli
+func (m *PeriodeModel) SimpanTahunAkademik(t *entities.TahunAkademikTA) error {
+	mulai, selesai, err := rentangTahunAkademik(t.Kode)
+	if err != nil {
+		return galatPermintaan(http.StatusBadRequest, "kode tahun akademik tidak valid (format YYYY/YYYY)")
+	}
+	if t.TanggalMulai == "" {
+		t.TanggalMulai = mulai
+	}
+	if t.TanggalSelesai == "" {
+		s, _ := time.Parse("2006-01-02", selesai)
+		t.TanggalSelesai = s.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	if err := validasiRentang(t.TanggalMulai, t.TanggalSelesai); err != nil {
+		return err
+	}
+
+	_, err = m.DB.Exec(`
+		INSERT INTO tahun_akademik (kode, tanggal_mulai, tanggal_selesai) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE tanggal_mulai = VALUES(tanggal_mulai), tanggal_selesai = VALUES(tanggal_selesai)`,
+		t.Kode, t.TanggalMulai, t.TanggalSelesai)
+	return err
+}
+
+func validasiRentang(mulai, selesai string) error {
+	a, err1 := time.Parse("2006-01-02", mulai)
+	b, err2 := time.Parse("2006-01-02", selesai)
+	if err1 != nil || err2 != nil {
+		return galatPermintaan(http.StatusBadRequest, "tanggal harus berformat YYYY-MM-DD")
+	}
+	if b.Before(a) {
+		return galatPermintaan(http.StatusBadRequest, "tanggal selesai tidak boleh sebelum tanggal mulai")
+	}
+	return nil
+}
+
+const kolomPeriode = `
+	p.id, p.tahun_akademik, p.gelombang, p.nama,
+	DATE_FORMAT(p.tanggal_mulai, '%Y-%m-%d'), DATE_FORMAT(p.tanggal_selesai, '%Y-%m-%d'), p.status,
+	DATE_FORMAT(p.ditutup_at, '%Y-%m-%d %H:%i:%s'), DATE_FORMAT(p.diarsipkan_at, '%Y-%m-%d %H:%i:%s'),
+	(SELECT COUNT(*) FROM taruna t WHERE t.periode_id = p.id)`
+
+func scanPeriode(s interface{ Scan(...interface{}) error }) (entities.PeriodeTA, error) {
+	var p entities.PeriodeTA
+	var ditutup, diarsipkan sql.NullString
+	err := s.Scan(&p.ID, &p.TahunAkademik, &p.Gelombang, &p.Nama, &p.TanggalMulai, &p.TanggalSelesai,
+		&p.Status, &ditutup, &diarsipkan, &p.JumlahTaruna)
+	if ditutup.Valid {
+		p.DitutupAt = &ditutup.String
+	}
+	if diarsipkan.Valid {
+		p.DiarsipkanAt = &diarsipkan.String
+	}
+	return p, err
+}
+
+// GetPeriode mengambil periode menurut tahun akademik dan status (keduanya opsional). Tanpa status,
+// periode yang diarsipkan tidak ikut.
+func (m *PeriodeModel) GetPeriode(tahunAkademik, status string) ([]entities.PeriodeTA, error) {
+	var kondisi []string
+	var args []interface{}
+	if tahunAkademik != "" {
+		kondisi = append(kondisi, "p.tahun_akademik = ?")
+		args = append(args, tahunAkademik)
+	}
+	if status != "" {
+		kondisi = append(kondisi, "p.status = ?")
+		args = append(args, status)
+	} else {
+		kondisi = append(kondisi, "p.status <> ?")
+		args = append(args, StatusPeriodeDiarsipkan)
+	}
+
+	rows, err := m.DB.Query(`SELECT `+kolomPeriode+` FROM periode_ta p
+		WHERE `+strings.Join(kondisi, " AND ")+`
+		ORDER BY p.tahun_akademik DESC, p.gelombang DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.PeriodeTA{}
+	for rows.Next() {
+		p, err := scanPeriode(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+func (m *PeriodeModel) GetPeriodeByID(id int) (*entities.PeriodeTA, error) {
+	p, err := scanPeriode(m.DB.QueryRow(`SELECT `+kolomPeriode+` FROM periode_ta p WHERE p.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, galatPermintaan(http.StatusNotFound, "Periode tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SimpanPeriode membuat periode baru (ID 0) atau mengubah periode yang belum diarsipkan.
+// Tahun akademiknya dibuat otomatis bila belum ada.
+func (m *PeriodeModel) SimpanPeriode(p *entities.PeriodeTA) error {
+	if !IsValidTahunAkademik(p.TahunAkademik) {
+		return galatPermintaan(http.StatusBadRequest, "tahun_akademik tidak valid (format YYYY/YYYY)")
+	}
+	if p.Gelombang < 1 {
+		return galatPermintaan(http.StatusBadRequest, "gelombang minimal 1")
+	}
+	if p.Nama == "" {
+		p.Nama = fmt.Sprintf("Gelombang %d %s", p.Gelombang, p.TahunAkademik)
+	}
+	if err := validasiRentang(p.TanggalMulai, p.TanggalSelesai); err != nil {
+		return err
+	}
+
+	if _, err := m.DB.Exec(`INSERT IGNORE INTO tahun_akademik (kode, tanggal_mulai, tanggal_selesai)
+		SELECT ?, ?, DATE_SUB(?, INTERVAL 1 DAY)`, p.TahunAkademik, mulaiTahun(p.TahunAkademik), selesaiTahun(p.TahunAkademik)); err != nil {
+		return err
+	}
+
+	var duplikat bool
+	if err := m.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM periode_ta WHERE tahun_akademik = ? AND gelombang = ? AND id <> ?)`,
+		p.TahunAkademik, p.Gelombang, p.ID).Scan(&duplikat); err != nil {
+		return err
+	}
+	if duplikat {
+		return galatPermintaan(http.StatusConflict, "Gelombang %d tahun akademik %s sudah ada", p.Gelombang, p.TahunAkademik)
+	}
+
+	if p.ID == 0 {
+		res, err := m.DB.Exec(`
+			INSERT INTO periode_ta (tahun_akademik, gelombang, nama, tanggal_mulai, tanggal_selesai)
+			VALUES (?, ?, ?, ?, ?)`,
+			p.TahunAkademik, p.Gelombang, p.Nama, p.TanggalMulai, p.TanggalSelesai)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		p.ID = int(id)
+		p.Status = StatusPeriodeAktif
+		return err
+	}
+
+	lama, err := m.GetPeriodeByID(p.ID)
+	if err != nil {
+		return err
+	}
+	if lama.Status == StatusPeriodeDiarsipkan {
+		return galatPermintaan(http.StatusConflict, "Periode yang diarsipkan tidak dapat diubah")
+	}
+	_, err = m.DB.Exec(`
+		UPDATE periode_ta SET tahun_akademik = ?, gelombang = ?, nama = ?, tanggal_mulai = ?, tanggal_selesai = ?
+		WHERE id = ?`,
+		p.TahunAkademik, p.Gelombang, p.Nama, p.TanggalMulai, p.TanggalSelesai, p.ID)
+	return err
+}
+
+func mulaiTahun(kode string) string {
+	mulai, _, _ := rentangTahunAkademik(kode)
+	return mulai
+}
+
+func selesaiTahun(kode string) string {
+	_, selesai, _ := rentangTahunAkademik(kode)
+	return selesai
+}
+
+// UbahStatusPeriode menjalankan transisi status: aktif → ditutup → diarsipkan, atau membuka kembali
+// periode yang ditutup. Periode yang diarsipkan bersifat final.
+func (m *PeriodeModel) UbahStatusPeriode(id int, status string) error {
+	p, err := m.GetPeriodeByID(id)
+	if err != nil {
+		return err
+	}
+
+	var query string
+	switch {
+	case status == StatusPeriodeDitutup && p.Status == StatusPeriodeAktif:
+		query = "UPDATE periode_ta SET status = 'ditutup', ditutup_at = NOW() WHERE id = ?"
+	case status == StatusPeriodeAktif && p.Status == StatusPeriodeDitutup:
+		query = "UPDATE periode_ta SET status = 'aktif', ditutup_at = NULL WHERE id = ?"
+	case status == StatusPeriodeDiarsipkan && p.Status == StatusPeriodeDitutup:
+		query = "UPDATE periode_ta SET status = 'diarsipkan', diarsipkan_at = NOW() WHERE id = ?"
+	case status == StatusPeriodeDiarsipkan:
+		return galatPermintaan(http.StatusConflict, "Periode harus ditutup sebelum diarsipkan")
+	default:
+		return galatPermintaan(http.StatusConflict, "Periode berstatus %s tidak dapat diubah menjadi %s", p.Status, status)
+	}
+	_, err = m.DB.Exec(query, id)
+	return err
+}
+
+// DaftarkanTaruna memindahkan taruna ke periode aktif. Dokumen dan penugasan yang sudah ada tetap
+// tercatat di periode lamanya; hanya data baru yang mengikuti periode ini.
+func (m *PeriodeModel) DaftarkanTaruna(d entities.PendaftaranPeriode) (int64, error) {
+	p, err := m.GetPeriodeByID(d.PeriodeID)
+	if err != nil {
+		return 0, err
+	}
+	if p.Status != StatusPeriodeAktif {
+		return 0, galatPermintaan(http.StatusConflict, "Periode %s sudah %s", p.Nama, p.Status)
+	}
+	if len(d.TarunaIDs) == 0 {
+		return 0, galatPermintaan(http.StatusBadRequest, "taruna_ids tidak boleh kosong")
+	}
+	if d.Angkatan != nil && (*d.Angkatan < 1990 || *d.Angkatan > 2100) {
+		return 0, galatPermintaan(http.StatusBadRequest, "angkatan tidak valid")
+	}
+
+	placeholder := strings.TrimSuffix(strings.Repeat("?, ", len(d.TarunaIDs)), ", ")
+	args := []interface{}{d.PeriodeID, d.Angkatan}
+	for _, id := range d.TarunaIDs {
+		args = append(args, id)
+	}
+	res, err := m.DB.Exec(`
+		UPDATE taruna SET periode_id = ?, angkatan = COALESCE(?, angkatan)
+		WHERE id IN (`+placeholder+`)`, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetTarunaPeriode mengambil taruna yang terdaftar di satu periode
+func (m *PeriodeModel) GetTarunaPeriode(periodeID int) ([]map[string]interface{}, error) {
+	rows, err := m.DB.Query(`
+		SELECT t.id, t.user_id, t.nama_lengkap, COALESCE(t.jurusan, ''), COALESCE(t.kelas, ''), t.npm, t.angkatan
+		FROM taruna t
+		WHERE t.periode_id = ?
+		ORDER BY t.nama_lengkap`, periodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []map[string]interface{}{}
+	for rows.Next() {
+		var id, userID int
+		var nama, jurusan, kelas string
+		var npm, angkatan sql.NullInt64
+		if err := rows.Scan(&id, &userID, &nama, &jurusan, &kelas, &npm, &angkatan); err != nil {
+			return nil, err
+		}
+		item := map[string]interface{}{
+			"id":           id,
+			"user_id":      userID,
+			"nama_lengkap": nama,
+			"jurusan":      jurusan,
+			"kelas":        kelas,
+			"npm":          nil,
+			"angkatan":     nil,
+		}
+		if npm.Valid {
+			item["npm"] = npm.Int64
+		}
+		if angkatan.Valid {
+			item["angkatan"] = angkatan.Int64
+		}
+		list = append(list, item)
+	}
+	return list, rows.Err()
+}
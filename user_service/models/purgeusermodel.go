@@ -0,0 +1,128 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// KandidatPurge adalah akun nonaktif yang sudah melewati masa retensi
+type KandidatPurge struct {
+	UserID          int
+	NamaLengkap     string
+	Email           string
+	DinonaktifkanAt time.Time
+}
+
+// KandidatPurge mengambil akun yang dinonaktifkan sebelum batas waktu
+func (u UserModel) KandidatPurge(sebelum time.Time) ([]KandidatPurge, error) {
+	rows, err := u.db.Query(`
+		SELECT id, nama_lengkap, email, dinonaktifkan_at
+		FROM users
+		WHERE dinonaktifkan_at IS NOT NULL AND dinonaktifkan_at < ?
+		ORDER BY dinonaktifkan_at`, sebelum)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []KandidatPurge
+	for rows.Next() {
+		var k KandidatPurge
+		if err := rows.Scan(&k.UserID, &k.NamaLengkap, &k.Email, &k.DinonaktifkanAt); err != nil {
+			return nil, err
+		}
+		list = append(list, k)
+	}
+	return list, rows.Err()
+}
+
+// Purge menghapus permanen satu akun nonaktif beserta baris dosen/taruna-nya. Isi baris yang dihapus
+// (tanpa password) disimpan lebih dulu di audit_akun dalam transaksi yang sama, sehingga purge yang
+// gagal (mis. masih dirujuk dokumen) tidak meninggalkan jejak setengah jadi.
+func (u UserModel) Purge(userID int, operator string) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var nonaktif bool
+	err = tx.QueryRow("SELECT dinonaktifkan_at IS NOT NULL FROM users WHERE id = ? FOR UPDATE", userID).Scan(&nonaktif)
+	if err != nil {
+		return err
+	}
+	if !nonaktif {
+		return fmt.Errorf("user %d masih aktif", userID)
+	}
+
+	snapshot := map[string]interface{}{}
+	for _, t := range []struct{ tabel, kolom string }{
+		{"users", "id"},
+		{"dosen", "user_id"},
+		{"taruna", "user_id"},
+	} {
+		baris, err := ambilBaris(tx, fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", t.tabel, t.kolom), userID)
+		if err != nil {
+			return fmt.Errorf("gagal membaca %s: %v", t.tabel, err)
+		}
+		for _, b := range baris {
+			delete(b, "password")
+		}
+		snapshot[t.tabel] = baris
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err := catatAuditAkun(tx, userID, "purge", 0, "purge oleh "+operator, string(data)); err != nil {
+		return err
+	}
+
+	for _, q := range []string{
+		"DELETE FROM dosen WHERE user_id = ?",
+		"DELETE FROM taruna WHERE user_id = ?",
+		"DELETE FROM users WHERE id = ?",
+	} {
+		if _, err := tx.Exec(q, userID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ambilBaris membaca hasil query sebagai map kolom → nilai untuk disimpan sebagai JSON
+func ambilBaris(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	kolom, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var hasil []map[string]interface{}
+	for rows.Next() {
+		nilai := make([]interface{}, len(kolom))
+		ptr := make([]interface{}, len(kolom))
+		for i := range nilai {
+			ptr[i] = &nilai[i]
+		}
+		if err := rows.Scan(ptr...); err != nil {
+			return nil, err
+		}
+		baris := map[string]interface{}{}
+		for i, k := range kolom {
+			if b, ok := nilai[i].([]byte); ok {
+				baris[k] = string(b)
+			} else {
+				baris[k] = nilai[i]
+			}
+		}
+		hasil = append(hasil, baris)
+	}
+	return hasil, rows.Err()
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql"
 	"user_service/config"
+	"user_service/utils/halaman"
 )
 
 type TarunaModel struct {
@@ -23,16 +24,55 @@ func (d *TarunaModel) CreateTaruna(userID int, namaLengkap, email, jurusan, kela
 	return err
 }
 
+// AturanDaftarTaruna adalah field yang boleh dipakai untuk sort daftar taruna
+var AturanDaftarTaruna = halaman.Aturan{
+	Urut: map[string]string{
+		"id":           "t.id",
+		"nama_lengkap": "t.nama_lengkap",
+		"jurusan":      "t.jurusan",
+		"kelas":        "t.kelas",
+		"npm":          "t.npm",
+		"angkatan":     "t.angkatan",
+	},
+	UrutDefault: "id",
+	Kunci:       "t.id",
+}
+
+// FilterTaruna adalah filter field daftar taruna; PeriodeID 0 berarti semua periode yang belum diarsipkan
+type FilterTaruna struct {
+	PeriodeID int
+	Jurusan   string
+	Kelas     string
+	Angkatan  string
+}
+
 // GetAllTaruna: aman terhadap NULL di npm, kembalikan sebagai int atau nil.
-func (m *TarunaModel) GetAllTaruna() ([]map[string]interface{}, error) {
-	rows, err := m.db.Query(`
-		SELECT t.id, t.user_id, t.nama_lengkap, t.jurusan, t.kelas, t.npm
+// Mengembalikan satu halaman taruna beserta jumlah total; q dicari pada nama dan NPM.
+func (m *TarunaModel) GetAllTaruna(filter FilterTaruna, k *halaman.Kueri) ([]map[string]interface{}, int, error) {
+	var f halaman.Filter
+	f.Tambah("u.role = 'Taruna' AND u.dinonaktifkan_at IS NULL")
+	kondisiPeriode, argsPeriode := KondisiPeriode("t.periode_id", filter.PeriodeID)
+	f.Tambah(kondisiPeriode, argsPeriode...)
+	f.Sama("t.jurusan", filter.Jurusan)
+	f.Sama("t.kelas", filter.Kelas)
+	f.Sama("t.angkatan", filter.Angkatan)
+	f.Cari(k.Q, "t.nama_lengkap", "CAST(t.npm AS CHAR)")
+
+	dari := `
 		FROM taruna t
 		JOIN users u ON t.user_id = u.id
-		WHERE u.role = 'Taruna'
-	`)
+		WHERE ` + f.SQL()
+	total, err := halaman.Hitung(m.db, dari, f.Args())
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	potong, argsPotong := k.Potong()
+	rows, err := m.db.Query(`
+		SELECT t.id, t.user_id, t.nama_lengkap, t.jurusan, t.kelas, t.npm, t.angkatan, t.periode_id`+
+		dari+k.OrderBy()+potong, append(f.Args(), argsPotong...)...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -41,10 +81,10 @@ func (m *TarunaModel) GetAllTaruna() ([]map[string]interface{}, error) {
 		var (
 			id, userID                  int
 			namaLengkap, jurusan, kelas string
-			npm                         sql.NullInt64
+			npm, angkatan, periode      sql.NullInt64
 		)
-		if err := rows.Scan(&id, &userID, &namaLengkap, &jurusan, &kelas, &npm); err != nil {
-			return nil, err
+		if err := rows.Scan(&id, &userID, &namaLengkap, &jurusan, &kelas, &npm, &angkatan, &periode); err != nil {
+			return nil, 0, err
 		}
 
 		item := map[string]interface{}{
@@ -59,13 +99,18 @@ func (m *TarunaModel) GetAllTaruna() ([]map[string]interface{}, error) {
 		} else {
 			item["npm"] = nil // atau gunakan "-" jika ingin string default
 		}
+		item["angkatan"] = nil
+		if angkatan.Valid {
+			item["angkatan"] = int(angkatan.Int64)
+		}
+		item["periode_id"] = nil
+		if periode.Valid {
+			item["periode_id"] = int(periode.Int64)
+		}
 
 		tarunas = append(tarunas, item)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return tarunas, nil
+	return tarunas, total, rows.Err()
 }
 
 // Update password taruna berdasarkan user_id
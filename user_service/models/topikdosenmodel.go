@@ -0,0 +1,387 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"user_service/config"
+	"user_service/entities"
+)
+
+const (
+	StatusTopikDibuka  = "dibuka"
+	StatusTopikDitutup = "ditutup"
+
+	StatusLamaranMenunggu   = "menunggu"
+	StatusLamaranDiterima   = "diterima"
+	StatusLamaranDitolak    = "ditolak"
+	StatusLamaranDibatalkan = "dibatalkan"
+)
+
+type TopikDosenModel struct {
+	DB *sql.DB
+}
+
+func NewTopikDosenModel() (*TopikDosenModel, error) {
+	db, err := config.ConnectDB()
+	if err != nil {
+		return nil, err
+	}
+	return &TopikDosenModel{DB: db}, nil
+}
+
+const selectTopik = `
+	SELECT k.id, k.dosen_id, COALESCE(d.nama_lengkap, ''), COALESCE(d.jurusan, ''), k.judul, k.deskripsi,
+		COALESCE(k.prasyarat, ''), k.slot, k.terisi, k.status, DATE_FORMAT(k.created_at, '%Y-%m-%d %H:%i:%s')
+	FROM topik_dosen k
+	LEFT JOIN dosen d ON d.id = k.dosen_id`
+
+// GetTopik mengambil topik; dosenID 0 berarti semua dosen, status kosong berarti semua status
+func (m *TopikDosenModel) GetTopik(dosenID int, status string) ([]entities.TopikDosen, error) {
+	var (
+		kondisi []string
+		args    []interface{}
+	)
+	if dosenID != 0 {
+		kondisi = append(kondisi, "k.dosen_id = ?")
+		args = append(args, dosenID)
+	}
+	if status != "" {
+		kondisi = append(kondisi, "k.status = ?")
+		args = append(args, status)
+	}
+	query := selectTopik
+	if len(kondisi) > 0 {
+		query += " WHERE " + strings.Join(kondisi, " AND ")
+	}
+
+	rows, err := m.DB.Query(query+" ORDER BY k.created_at DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.TopikDosen{}
+	for rows.Next() {
+		var t entities.TopikDosen
+		if err := rows.Scan(&t.ID, &t.DosenID, &t.NamaDosen, &t.Jurusan, &t.Judul, &t.Deskripsi,
+			&t.Prasyarat, &t.Slot, &t.Terisi, &t.Status, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+func (m *TopikDosenModel) GetTopikByID(id int) (*entities.TopikDosen, error) {
+	var t entities.TopikDosen
+	err := m.DB.QueryRow(selectTopik+" WHERE k.id = ?", id).Scan(&t.ID, &t.DosenID, &t.NamaDosen, &t.Jurusan,
+		&t.Judul, &t.Deskripsi, &t.Prasyarat, &t.Slot, &t.Terisi, &t.Status, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, galatPermintaan(404, "Topik tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (m *TopikDosenModel) CreateTopik(t *entities.TopikDosen) error {
+	result, err := m.DB.Exec(`
+		INSERT INTO topik_dosen (dosen_id, judul, deskripsi, prasyarat, slot, status)
+		VALUES (?, ?, ?, ?, ?, ?)`, t.DosenID, t.Judul, t.Deskripsi, t.Prasyarat, t.Slot, StatusTopikDibuka)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	t.ID = int(id)
+	return err
+}
+
+// UpdateTopik mengubah topik milik dosen; slot tidak boleh lebih kecil dari jumlah lamaran yang sudah diterima
+func (m *TopikDosenModel) UpdateTopik(t *entities.TopikDosen) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var dosenID, terisi int
+	err = tx.QueryRow("SELECT dosen_id, terisi FROM topik_dosen WHERE id = ? FOR UPDATE", t.ID).Scan(&dosenID, &terisi)
+	if err == sql.ErrNoRows {
+		return galatPermintaan(404, "Topik tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+	if dosenID != t.DosenID {
+		return galatPermintaan(403, "Topik ini bukan milik Anda")
+	}
+	if t.Slot < terisi {
+		return galatPermintaan(400, "Slot tidak boleh kurang dari %d lamaran yang sudah diterima", terisi)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE topik_dosen SET judul = ?, deskripsi = ?, prasyarat = ?, slot = ?, status = ?
+		WHERE id = ?`, t.Judul, t.Deskripsi, t.Prasyarat, t.Slot, t.Status, t.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// kunciTaruna mengunci baris taruna agar lamaran dan penerimaan untuk taruna yang sama berjalan bergantian
+func kunciTaruna(tx *sql.Tx, userID int) error {
+	var id int
+	err := tx.QueryRow("SELECT id FROM taruna WHERE user_id = ? FOR UPDATE", userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return galatPermintaan(404, "Taruna tidak ditemukan")
+	}
+	return err
+}
+
+func punyaPembimbingUtama(tx *sql.Tx, userID int) (bool, error) {
+	var ada bool
+	err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM dosbing_proposal WHERE user_id = ? AND status = 'aktif')", userID).Scan(&ada)
+	return ada, err
+}
+
+// Lamar menyimpan lamaran taruna (users.id) terhadap topik yang masih dibuka dan memiliki slot
+func (m *TopikDosenModel) Lamar(userID, topikID int, motivasi string) (int, error) {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := kunciTaruna(tx, userID); err != nil {
+		return 0, err
+	}
+	ada, err := punyaPembimbingUtama(tx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if ada {
+		return 0, galatPermintaan(409, "Anda sudah memiliki dosen pembimbing; gunakan pengajuan pergantian pembimbing")
+	}
+
+	var slot, terisi int
+	var status string
+	err = tx.QueryRow("SELECT slot, terisi, status FROM topik_dosen WHERE id = ? FOR UPDATE", topikID).Scan(&slot, &terisi, &status)
+	if err == sql.ErrNoRows {
+		return 0, galatPermintaan(404, "Topik tidak ditemukan")
+	}
+	if err != nil {
+		return 0, err
+	}
+	if status != StatusTopikDibuka || terisi >= slot {
+		return 0, galatPermintaan(409, "Topik sudah ditutup atau slotnya penuh")
+	}
+
+	var lama string
+	err = tx.QueryRow("SELECT status FROM lamaran_topik WHERE topik_id = ? AND user_id = ?", topikID, userID).Scan(&lama)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return 0, err
+	case lama != StatusLamaranDibatalkan:
+		return 0, galatPermintaan(409, "Anda sudah melamar topik ini (status %s)", lama)
+	}
+
+	// Lamaran yang pernah dibatalkan taruna boleh diajukan ulang
+	result, err := tx.Exec(`
+		INSERT INTO lamaran_topik (topik_id, user_id, motivasi, status)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id), motivasi = VALUES(motivasi), status = VALUES(status), catatan = NULL`,
+		topikID, userID, motivasi, StatusLamaranMenunggu)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), tx.Commit()
+}
+
+// BatalkanLamaran membatalkan lamaran taruna yang belum diputuskan
+func (m *TopikDosenModel) BatalkanLamaran(userID, id int) error {
+	result, err := m.DB.Exec(`
+		UPDATE lamaran_topik SET status = ?
+		WHERE id = ? AND user_id = ? AND status = ?`, StatusLamaranDibatalkan, id, userID, StatusLamaranMenunggu)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return galatPermintaan(409, "Lamaran tidak ditemukan atau sudah diputuskan")
+	}
+	return nil
+}
+
+// GetLamaranByID mengambil satu lamaran beserta taruna dan dosen pemilik topiknya
+func (m *TopikDosenModel) GetLamaranByID(id int) (*entities.LamaranTopik, error) {
+	list, err := m.queryLamaran(" WHERE l.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, galatPermintaan(404, "Lamaran tidak ditemukan")
+	}
+	return &list[0], nil
+}
+
+// GetLamaran mengambil lamaran yang relevan bagi pengguna: semua untuk admin, lamaran ke topik milik
+// dosen, atau lamaran taruna sendiri. Status kosong berarti semua status.
+func (m *TopikDosenModel) GetLamaran(p *Pengguna, status string) ([]entities.LamaranTopik, error) {
+	var (
+		kondisi []string
+		args    []interface{}
+	)
+	switch p.Role {
+	case "admin":
+	case "dosen":
+		kondisi = append(kondisi, "k.dosen_id = ?")
+		args = append(args, p.DosenID)
+	default:
+		kondisi = append(kondisi, "l.user_id = ?")
+		args = append(args, p.UserID)
+	}
+	if status != "" {
+		kondisi = append(kondisi, "l.status = ?")
+		args = append(args, status)
+	}
+	where := ""
+	if len(kondisi) > 0 {
+		where = " WHERE " + strings.Join(kondisi, " AND ")
+	}
+	return m.queryLamaran(where, args...)
+}
+
+func (m *TopikDosenModel) queryLamaran(where string, args ...interface{}) ([]entities.LamaranTopik, error) {
+	rows, err := m.DB.Query(`
+		SELECT l.id, l.topik_id, k.judul, k.dosen_id, COALESCE(d.nama_lengkap, ''),
+			COALESCE(t.id, 0), COALESCE(t.nama_lengkap, ''), l.motivasi, l.status, COALESCE(l.catatan, ''),
+			DATE_FORMAT(l.created_at, '%Y-%m-%d %H:%i:%s')
+		FROM lamaran_topik l
+		JOIN topik_dosen k ON k.id = l.topik_id
+		LEFT JOIN dosen d ON d.id = k.dosen_id
+		LEFT JOIN taruna t ON t.user_id = l.user_id`+where+`
+		ORDER BY l.created_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []entities.LamaranTopik{}
+	for rows.Next() {
+		var l entities.LamaranTopik
+		if err := rows.Scan(&l.ID, &l.TopikID, &l.JudulTopik, &l.DosenID, &l.NamaDosen, &l.TarunaID, &l.NamaTaruna,
+			&l.Motivasi, &l.Status, &l.Catatan, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, l)
+	}
+	return list, rows.Err()
+}
+
+// PutuskanLamaran menyimpan keputusan dosen pemilik topik. Lamaran yang diterima menambah slot terisi,
+// menetapkan dosen sebagai pembimbing utama taruna, dan membatalkan lamaran taruna lainnya yang masih menunggu.
+// Baris taruna lalu topik dikunci sehingga penerimaan bersamaan tidak melampaui slot.
+func (m *TopikDosenModel) PutuskanLamaran(dosenID int, req entities.KeputusanLamaranTopik) error {
+	var topikID, userID int
+	err := m.DB.QueryRow("SELECT topik_id, user_id FROM lamaran_topik WHERE id = ?", req.LamaranID).Scan(&topikID, &userID)
+	if err == sql.ErrNoRows {
+		return galatPermintaan(404, "Lamaran tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := kunciTaruna(tx, userID); err != nil {
+		return err
+	}
+	var pemilik, slot, terisi int
+	err = tx.QueryRow("SELECT dosen_id, slot, terisi FROM topik_dosen WHERE id = ? FOR UPDATE", topikID).
+		Scan(&pemilik, &slot, &terisi)
+	if err != nil {
+		return err
+	}
+	if pemilik != dosenID {
+		return galatPermintaan(403, "Lamaran ini bukan untuk topik Anda")
+	}
+	var status string
+	if err := tx.QueryRow("SELECT status FROM lamaran_topik WHERE id = ? FOR UPDATE", req.LamaranID).Scan(&status); err != nil {
+		return err
+	}
+	if status != StatusLamaranMenunggu {
+		return galatPermintaan(409, "Lamaran sudah %s", status)
+	}
+
+	if !req.Terima {
+		_, err := tx.Exec("UPDATE lamaran_topik SET status = ?, catatan = ? WHERE id = ?", StatusLamaranDitolak, req.Catatan, req.LamaranID)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	if terisi >= slot {
+		return galatPermintaan(409, "Slot topik sudah penuh")
+	}
+	ada, err := punyaPembimbingUtama(tx, userID)
+	if err != nil {
+		return err
+	}
+	if ada {
+		return galatPermintaan(409, "Taruna sudah memiliki dosen pembimbing")
+	}
+
+	// Kuota dihitung ulang di dalam transaksi setelah baris dosen dikunci, sehingga dua penerimaan
+	// bersamaan untuk dosen yang sama tidak dapat sama-sama lolos dan melampaui kuota
+	var idDosen int
+	if err := tx.QueryRow("SELECT id FROM dosen WHERE id = ? FOR UPDATE", dosenID).Scan(&idDosen); err != nil {
+		return err
+	}
+	var tarunaID int
+	if err := tx.QueryRow("SELECT id FROM taruna WHERE user_id = ?", userID).Scan(&tarunaID); err != nil {
+		return err
+	}
+	pelanggaran, err := validasiPenugasan(tx, "dosbing_proposal", tarunaID,
+		[]entities.SlotPenugasan{{Peran: "Pembimbing Utama", DosenID: dosenID}})
+	if err != nil {
+		return err
+	}
+	if len(pelanggaran) > 0 {
+		return &ErrPelanggaran{Pelanggaran: pelanggaran}
+	}
+
+	if _, err := tx.Exec("UPDATE lamaran_topik SET status = ?, catatan = ? WHERE id = ?",
+		StatusLamaranDiterima, req.Catatan, req.LamaranID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE topik_dosen SET terisi = terisi + 1, status = IF(terisi >= slot, ?, status)
+		WHERE id = ?`, StatusTopikDitutup, topikID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE lamaran_topik SET status = ?, catatan = 'Taruna sudah diterima pada topik lain'
+		WHERE user_id = ? AND id <> ? AND status = ?`,
+		StatusLamaranDibatalkan, userID, req.LamaranID, StatusLamaranMenunggu); err != nil {
+		return err
+	}
+	if _, err := tetapkanPembimbing(tx, userID, PeranPembimbingUtama, dosenID, "aktif",
+		fmt.Sprintf("Diterima pada topik dosen #%d", topikID), nil); err != nil {
+		return err
+	}
+	if err := catatPembimbing(tx, userID, PeranPembimbingUtama, dosenID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
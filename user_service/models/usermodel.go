@@ -7,6 +7,7 @@ import (
 	"strings"
 	"user_service/config"
 	"user_service/entities"
+	"user_service/utils/halaman"
 )
 
 type UserModel struct {
@@ -21,6 +22,10 @@ func NewUserModel() (*UserModel, error) {
 	return &UserModel{db: db}, nil
 }
 
+func (u UserModel) Close() error {
+	return u.db.Close()
+}
+
 func (u UserModel) Where(user *entities.User, fieldName, fieldValue string) error {
 	// Whitelist kolom agar aman dari SQL injection pada fieldName
 	allowed := map[string]bool{"email": true, "username": true, "id": true}
@@ -66,13 +71,65 @@ func (u UserModel) Where(user *entities.User, fieldName, fieldValue string) erro
 	return nil
 }
 
-func (m *UserModel) FindAll() ([]entities.User, error) {
+// Filter status akun untuk FindAll
+const (
+	StatusAkunAktif    = "aktif"
+	StatusAkunNonaktif = "nonaktif"
+	StatusAkunSemua    = "semua"
+)
+
+// AturanDaftarUser adalah field yang boleh dipakai untuk sort daftar user
+var AturanDaftarUser = halaman.Aturan{
+	Urut: map[string]string{
+		"id":           "id",
+		"nama_lengkap": "nama_lengkap",
+		"username":     "username",
+		"email":        "email",
+		"role":         "role",
+		"jurusan":      "jurusan",
+		"kelas":        "kelas",
+		"npm":          "npm",
+	},
+	UrutDefault: "id",
+	Kunci:       "id",
+}
+
+// FilterUser adalah filter field daftar user; nilai kosong berarti tidak difilter
+type FilterUser struct {
+	Status  string // aktif (default) | nonaktif | semua
+	Role    string
+	Jurusan string
+	Kelas   string
+}
+
+// FindAll mengambil satu halaman user sesuai filter beserta jumlah total yang cocok; default hanya
+// akun yang tidak dinonaktifkan. q dicari pada nama, username, email, dan NPM.
+func (m *UserModel) FindAll(filter FilterUser, k *halaman.Kueri) ([]entities.User, int, error) {
+	var f halaman.Filter
+	switch filter.Status {
+	case StatusAkunNonaktif:
+		f.Tambah("dinonaktifkan_at IS NOT NULL")
+	case StatusAkunSemua:
+	default:
+		f.Tambah("dinonaktifkan_at IS NULL")
+	}
+	f.Sama("role", filter.Role)
+	f.Sama("jurusan", filter.Jurusan)
+	f.Sama("kelas", filter.Kelas)
+	f.Cari(k.Q, "nama_lengkap", "username", "email", "CAST(npm AS CHAR)")
+
+	dari := "FROM users WHERE " + f.SQL()
+	total, err := halaman.Hitung(m.db, dari, f.Args())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	potong, argsPotong := k.Potong()
 	rows, err := m.db.Query(`
-        SELECT id, nama_lengkap, username, email, role, jurusan, kelas, npm
-        FROM users
-    `)
+        SELECT id, nama_lengkap, username, email, role, jurusan, kelas, npm, dinonaktifkan_at, alasan_nonaktif
+        `+dari+k.OrderBy()+potong, append(f.Args(), argsPotong...)...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -81,6 +138,8 @@ func (m *UserModel) FindAll() ([]entities.User, error) {
 		var user entities.User
 		var jurusan, kelas sql.NullString
 		var npm sql.NullInt64
+		var nonaktifAt sql.NullTime
+		var alasan sql.NullString
 
 		err := rows.Scan(
 			&user.ID,
@@ -91,10 +150,13 @@ func (m *UserModel) FindAll() ([]entities.User, error) {
 			&jurusan,
 			&kelas,
 			&npm,
+			&nonaktifAt,
+			&alasan,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		setStatusNonaktif(&user, nonaktifAt, alasan)
 
 		// Handle jurusan
 		if jurusan.Valid {
@@ -121,7 +183,7 @@ func (m *UserModel) FindAll() ([]entities.User, error) {
 		users = append(users, user)
 	}
 
-	return users, nil
+	return users, total, rows.Err()
 }
 
 func (u UserModel) CreateUser(fullName, email, username, role, password, jurusan, kelas, npm string) (int64, error) {
@@ -131,11 +193,28 @@ func (u UserModel) CreateUser(fullName, email, username, role, password, jurusan
 		return 0, fmt.Errorf("error memulai transaksi: %v", err)
 	}
 
+	userID, err := insertUser(tx, fullName, email, username, role, password, jurusan, kelas, npm)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	// Commit
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("error committing transaction: %v", err)
+	}
+
+	return userID, nil
+}
+
+// insertUser menyimpan user beserta baris dosen/taruna-nya di dalam transaksi tx; dipakai oleh
+// CreateUser dan impor massal agar aturan field wajib per role sama
+func insertUser(tx *sql.Tx, fullName, email, username, role, password, jurusan, kelas, npm string) (int64, error) {
 	// Siapkan nilai NULL untuk field opsional (taruna only)
 	var kelasVal, npmVal, jurusanVal interface{}
 	if strings.ToLower(role) == "taruna" {
 		if jurusan == "" || kelas == "" || npm == "" {
-			tx.Rollback()
 			return 0, fmt.Errorf("taruna wajib mengisi jurusan, kelas, dan NPM")
 		}
 		jurusanVal = jurusan
@@ -143,7 +222,6 @@ func (u UserModel) CreateUser(fullName, email, username, role, password, jurusan
 		npmVal = npm
 	} else if strings.ToLower(role) == "dosen" {
 		if jurusan == "" {
-			tx.Rollback()
 			return 0, fmt.Errorf("dosen wajib mengisi jurusan")
 		}
 		jurusanVal = jurusan
@@ -155,26 +233,18 @@ func (u UserModel) CreateUser(fullName, email, username, role, password, jurusan
 		npmVal = nil
 	}
 
-	// Validasi tambahan untuk dosen
-	if strings.ToLower(role) == "dosen" && jurusan == "" {
-		tx.Rollback()
-		return 0, fmt.Errorf("dosen wajib mengisi jurusan")
-	}
-
 	// Insert ke tabel users
 	result, err := tx.Exec(`
 		INSERT INTO users (nama_lengkap, email, username, role, password, jurusan, kelas, npm) 
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
 		fullName, email, username, role, password, jurusanVal, kelasVal, npmVal)
 	if err != nil {
-		tx.Rollback()
 		return 0, fmt.Errorf("error inserting user: %v", err)
 	}
 
 	// Ambil user_id
 	userID, err := result.LastInsertId()
 	if err != nil {
-		tx.Rollback()
 		return 0, fmt.Errorf("error getting last insert ID: %v", err)
 	}
 
@@ -188,7 +258,6 @@ func (u UserModel) CreateUser(fullName, email, username, role, password, jurusan
 			userID, fullName, email, jurusan)
 		if err != nil {
 			log.Printf("Insert ke dosen gagal! userID=%d, err=%v", userID, err)
-			tx.Rollback()
 			return 0, fmt.Errorf("error inserting dosen: %v", err)
 		}
 
@@ -200,7 +269,6 @@ func (u UserModel) CreateUser(fullName, email, username, role, password, jurusan
 			VALUES (?, ?, ?, ?, ?, ?)`,
 			userID, fullName, email, jurusan, kelas, npm)
 		if err != nil {
-			tx.Rollback()
 			return 0, fmt.Errorf("error inserting taruna: %v", err)
 		}
 
@@ -209,16 +277,13 @@ func (u UserModel) CreateUser(fullName, email, username, role, password, jurusan
 		// Tidak perlu insert tambahan
 	}
 
-	// Commit
-	if err = tx.Commit(); err != nil {
-		tx.Rollback()
-		return 0, fmt.Errorf("error committing transaction: %v", err)
-	}
-
 	return userID, nil
 }
 
-func (u UserModel) UpdateUser(userID int, fullName, email, username, role, jurusan, kelas string, npm *int, hashedPassword []byte) error {
+// UpdateUser mengubah data user. Bila role berubah, baris dosen/taruna role lama TIDAK dihapus agar
+// dokumen, penilaian, dan riwayat penugasan tetap terhubung; perubahan dicatat di audit_akun atas nama
+// olehUserID (0 bila tidak diketahui).
+func (u UserModel) UpdateUser(userID int, fullName, email, username, role, jurusan, kelas string, npm *int, hashedPassword []byte, olehUserID int) error {
 	role = strings.ToLower(role)
 
 	tx, err := u.db.Begin()
@@ -278,26 +343,31 @@ func (u UserModel) UpdateUser(userID int, fullName, email, username, role, jurus
 		return fmt.Errorf("error updating user: %v", err)
 	}
 
-	// ================================
-	// Bagian ini TIDAK diubah
-	// ================================
 	if oldRole != role {
-		switch oldRole {
+		// Baris role baru dipakai ulang bila user pernah memegang role itu sebelumnya
+		var ada bool
+		switch role {
 		case "dosen":
-			_, err = tx.Exec("DELETE FROM dosen WHERE user_id = ?", userID)
+			err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM dosen WHERE user_id = ?)", userID).Scan(&ada)
 		case "taruna":
-			_, err = tx.Exec("DELETE FROM taruna WHERE user_id = ?", userID)
+			err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM taruna WHERE user_id = ?)", userID).Scan(&ada)
 		}
 		if err != nil {
 			tx.Rollback()
-			return fmt.Errorf("error menghapus data role lama: %v", err)
+			return fmt.Errorf("error memeriksa data role baru: %v", err)
 		}
 
-		switch role {
-		case "dosen":
+		switch {
+		case role == "dosen" && ada:
+			_, err = tx.Exec("UPDATE dosen SET nama_lengkap = ?, email = ?, jurusan = ? WHERE user_id = ?",
+				fullName, email, jurusan, userID)
+		case role == "dosen":
 			_, err = tx.Exec("INSERT INTO dosen (user_id, nama_lengkap, email, jurusan) VALUES (?, ?, ?, ?)",
 				userID, fullName, email, jurusan)
-		case "taruna":
+		case role == "taruna" && ada:
+			_, err = tx.Exec("UPDATE taruna SET nama_lengkap = ?, email = ?, jurusan = ?, kelas = ?, npm = ? WHERE user_id = ?",
+				fullName, email, jurusan, kelas, npm, userID)
+		case role == "taruna":
 			_, err = tx.Exec("INSERT INTO taruna (user_id, nama_lengkap, email, jurusan, kelas, npm) VALUES (?, ?, ?, ?, ?, ?)",
 				userID, fullName, email, jurusan, kelas, npm)
 		}
@@ -305,6 +375,12 @@ func (u UserModel) UpdateUser(userID int, fullName, email, username, role, jurus
 			tx.Rollback()
 			return fmt.Errorf("error menambah data role baru: %v", err)
 		}
+
+		if err := catatAuditAkun(tx, userID, "ubah_role", olehUserID,
+			fmt.Sprintf("role %s diubah menjadi %s", oldRole, role), ""); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error mencatat audit: %v", err)
+		}
 	} else {
 		switch role {
 		case "dosen":
@@ -334,15 +410,18 @@ func (u UserModel) GetUserByID(userID int) (*entities.User, error) {
 		kelas   sql.NullString
 		npm     sql.NullInt64
 	)
+	var nonaktifAt sql.NullTime
+	var alasan sql.NullString
 	user := &entities.User{}
 	err := u.db.QueryRow(`
-		SELECT id, nama_lengkap, email, username, role, jurusan, kelas, npm
+		SELECT id, nama_lengkap, email, username, role, jurusan, kelas, npm, dinonaktifkan_at, alasan_nonaktif
 		FROM users WHERE id = ?`, userID).
 		Scan(&user.ID, &user.NamaLengkap, &user.Email, &user.Username,
-			&user.Role, &jurusan, &kelas, &npm)
+			&user.Role, &jurusan, &kelas, &npm, &nonaktifAt, &alasan)
 	if err != nil {
 		return nil, err
 	}
+	setStatusNonaktif(user, nonaktifAt, alasan)
 	if jurusan.Valid {
 		user.Jurusan = jurusan.String
 	} else {
@@ -362,7 +441,99 @@ func (u UserModel) GetUserByID(userID int) (*entities.User, error) {
 	return user, nil
 }
 
-func (u UserModel) DeleteUser(userID int) error {
-	_, err := u.db.Exec("DELETE FROM users WHERE id = ?", userID)
+func setStatusNonaktif(user *entities.User, nonaktifAt sql.NullTime, alasan sql.NullString) {
+	if nonaktifAt.Valid {
+		t := nonaktifAt.Time.Format("2006-01-02 15:04:05")
+		user.DinonaktifkanAt = &t
+		user.AlasanNonaktif = alasan.String
+	}
+}
+
+// Nonaktifkan menonaktifkan akun (pengganti DELETE): user tidak dapat login dan tidak muncul di
+// pilihan, tetapi seluruh data dan riwayatnya tetap ada. false bila akun tidak ada atau sudah nonaktif.
+func (u UserModel) Nonaktifkan(userID, olehUserID int, alasan string) (bool, error) {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE users SET dinonaktifkan_at = NOW(), dinonaktifkan_oleh = NULLIF(?, 0), alasan_nonaktif = NULLIF(?, '')
+		WHERE id = ? AND dinonaktifkan_at IS NULL`, olehUserID, alasan, userID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if err := catatAuditAkun(tx, userID, "nonaktifkan", olehUserID, alasan, ""); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// Pulihkan mengaktifkan kembali akun yang dinonaktifkan. false bila akun tidak ada atau masih aktif.
+func (u UserModel) Pulihkan(userID, olehUserID int) (bool, error) {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE users SET dinonaktifkan_at = NULL, dinonaktifkan_oleh = NULL, alasan_nonaktif = NULL
+		WHERE id = ? AND dinonaktifkan_at IS NOT NULL`, userID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if err := catatAuditAkun(tx, userID, "pulihkan", olehUserID, "", ""); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// AkunAktif mengecek apakah email milik akun yang ada dan tidak dinonaktifkan
+func (u UserModel) AkunAktif(email string) (bool, error) {
+	var aktif bool
+	err := u.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE email = ? AND dinonaktifkan_at IS NULL)", email).Scan(&aktif)
+	return aktif, err
+}
+
+// Pengguna mengambil identitas akun aktif pemilik email (lihat GetPengguna)
+func (u UserModel) Pengguna(email string) (*Pengguna, error) {
+	return GetPengguna(u.db, email)
+}
+
+// catatAuditAkun menulis jejak audit status akun; oleh 0 berarti sistem/job
+func catatAuditAkun(tx *sql.Tx, userID int, aksi string, oleh int, keterangan, data string) error {
+	_, err := tx.Exec(`
+		INSERT INTO audit_akun (user_id, aksi, oleh, keterangan, data)
+		VALUES (?, ?, NULLIF(?, 0), ?, NULLIF(?, ''))`, userID, aksi, oleh, keterangan, data)
 	return err
 }
+
+// Pengguna adalah identitas pemanggil yang diambil dari email di token beserta id taruna/dosennya
+type Pengguna struct {
+	UserID   int
+	Role     string
+	DosenID  int
+	TarunaID int
+}
+
+func GetPengguna(db *sql.DB, email string) (*Pengguna, error) {
+	var p Pengguna
+	err := db.QueryRow(`
+		SELECT u.id, LOWER(u.role), COALESCE(d.id, 0), COALESCE(t.id, 0)
+		FROM users u
+		LEFT JOIN dosen d ON d.user_id = u.id
+		LEFT JOIN taruna t ON t.user_id = u.id
+		WHERE u.email = ? AND u.dinonaktifkan_at IS NULL`, email).Scan(&p.UserID, &p.Role, &p.DosenID, &p.TarunaID)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -1,11 +1,23 @@
 package utils
 
 import (
+	"errors"
+	"net/http"
+	"strings"
+
 	"github.com/golang-jwt/jwt/v4"
 )
 
 var jwtKey = []byte("secret")
 
+// Claims adalah isi token yang diterbitkan ta_service saat login
+type Claims struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	Tamu  bool   `json:"tamu,omitempty"` // pembimbing tamu (dosen eksternal)
+	jwt.RegisteredClaims
+}
+
 func VerifyJWT(tokenStr string) (*jwt.RegisteredClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return jwtKey, nil
@@ -17,3 +29,23 @@ func VerifyJWT(tokenStr string) (*jwt.RegisteredClaims, error) {
 		return nil, err
 	}
 }
+
+// ClaimsFromRequest mengambil email dan role dari header Authorization: Bearer <token>
+func ClaimsFromRequest(r *http.Request) (*Claims, error) {
+	tokenStr := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if tokenStr == "" {
+		return nil, errors.New("token tidak ditemukan")
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("metode signing tidak valid")
+		}
+		return jwtKey, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.New("token tidak valid")
+	}
+	return claims, nil
+}
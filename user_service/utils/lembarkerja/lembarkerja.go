@@ -0,0 +1,215 @@
+// Package lembarkerja membaca baris dari berkas CSV atau XLSX (lembar pertama) untuk impor data,
+// tanpa dependensi luar. XLSX dibaca langsung dari arsip zip Office Open XML; format, rumus, dan
+// lembar lain diabaikan.
+package lembarkerja
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Baca memilih pembaca berdasarkan ekstensi nama berkas (.csv atau .xlsx)
+func Baca(nama string, data []byte) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(nama)) {
+	case ".csv":
+		return BacaCSV(data)
+	case ".xlsx":
+		return BacaXLSX(data)
+	default:
+		return nil, fmt.Errorf("format berkas tidak didukung (hanya .csv dan .xlsx)")
+	}
+}
+
+// BacaCSV membaca CSV berpemisah koma atau titik koma (ekspor Excel berlokal Indonesia)
+func BacaCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	baris1 := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		baris1 = data[:i]
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(baris1, []byte(";")) > bytes.Count(baris1, []byte(",")) {
+		r.Comma = ';'
+	}
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	return r.ReadAll()
+}
+
+type xmlWorkbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xmlRelationships struct {
+	Rel []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xmlTeks struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xmlTeks) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.R {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xmlSharedStrings struct {
+	SI []xmlTeks `xml:"si"`
+}
+
+type xmlSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string  `xml:"r,attr"`
+			Type   string  `xml:"t,attr"`
+			Value  string  `xml:"v"`
+			Inline xmlTeks `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// BacaXLSX membaca lembar pertama workbook sebagai baris teks
+func BacaXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("berkas XLSX rusak: %v", err)
+	}
+	berkas := map[string]*zip.File{}
+	for _, f := range zr.File {
+		berkas[f.Name] = f
+	}
+
+	var wb xmlWorkbook
+	if err := bacaXML(berkas, "xl/workbook.xml", &wb); err != nil {
+		return nil, err
+	}
+	if len(wb.Sheets) == 0 {
+		return nil, errors.New("workbook tidak memiliki lembar")
+	}
+	var rels xmlRelationships
+	if err := bacaXML(berkas, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	lembar := ""
+	for _, r := range rels.Rel {
+		if r.ID == wb.Sheets[0].RID {
+			if strings.HasPrefix(r.Target, "/") {
+				lembar = strings.TrimPrefix(r.Target, "/")
+			} else {
+				lembar = path.Join("xl", r.Target)
+			}
+		}
+	}
+	if lembar == "" {
+		return nil, errors.New("lembar pertama tidak ditemukan")
+	}
+
+	// sharedStrings tidak ada bila workbook hanya berisi angka
+	var ss xmlSharedStrings
+	if _, ok := berkas["xl/sharedStrings.xml"]; ok {
+		if err := bacaXML(berkas, "xl/sharedStrings.xml", &ss); err != nil {
+			return nil, err
+		}
+	}
+
+	var sheet xmlSheet
+	if err := bacaXML(berkas, lembar, &sheet); err != nil {
+		return nil, err
+	}
+
+	hasil := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		// Baris kosong tidak ditulis di XLSX; diisi agar nomor baris sama dengan yang terlihat di Excel
+		for row.R > 0 && len(hasil) < row.R-1 {
+			hasil = append(hasil, nil)
+		}
+		var baris []string
+		for i, c := range row.Cells {
+			kolom := i
+			if k := indeksKolom(c.Ref); k >= 0 {
+				kolom = k
+			}
+			for len(baris) <= kolom {
+				baris = append(baris, "")
+			}
+			switch c.Type {
+			case "s":
+				n, err := strconv.Atoi(c.Value)
+				if err != nil || n < 0 || n >= len(ss.SI) {
+					return nil, fmt.Errorf("sel %s merujuk teks yang tidak ada", c.Ref)
+				}
+				baris[kolom] = ss.SI[n].String()
+			case "inlineStr":
+				baris[kolom] = c.Inline.String()
+			case "str", "b", "e":
+				baris[kolom] = c.Value
+			default:
+				baris[kolom] = angka(c.Value)
+			}
+		}
+		hasil = append(hasil, baris)
+	}
+	return hasil, nil
+}
+
+func bacaXML(berkas map[string]*zip.File, nama string, v interface{}) error {
+	f, ok := berkas[nama]
+	if !ok {
+		return fmt.Errorf("berkas XLSX tidak lengkap: %s tidak ada", nama)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, 64<<20)).Decode(v); err != nil {
+		return fmt.Errorf("gagal membaca %s: %v", nama, err)
+	}
+	return nil
+}
+
+// indeksKolom mengubah referensi sel (mis. "AB12") menjadi indeks kolom berbasis nol
+func indeksKolom(ref string) int {
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		n = n*26 + int(r-'A'+1)
+	}
+	return n - 1
+}
+
+// angka menulis bilangan bulat tanpa notasi ilmiah agar NPM yang diketik sebagai angka tetap utuh
+func angka(v string) string {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f != float64(int64(f)) {
+		return v
+	}
+	return strconv.FormatInt(int64(f), 10)
+}
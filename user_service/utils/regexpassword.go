@@ -1,6 +1,10 @@
 package utils
 
-import "regexp"
+import (
+	"crypto/rand"
+	"math/big"
+	"regexp"
+)
 
 func IsValidPassword(password string) bool {
 	// Minimal 8 karakter, 1 huruf besar, 1 huruf kecil, 1 angka, dan 1 simbol
@@ -17,3 +21,44 @@ func IsValidPassword(password string) bool {
 		number.MatchString(password) &&
 		special.MatchString(password)
 }
+
+const (
+	hurufBesarPassword = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	hurufKecilPassword = "abcdefghijkmnopqrstuvwxyz"
+	angkaPassword      = "23456789"
+	simbolPassword     = "!@#$%&*?"
+)
+
+// GeneratePassword membuat password acak sepanjang n (minimal 8) yang lolos IsValidPassword.
+// Huruf dan angka yang mudah tertukar (O/0, I/l/1) tidak dipakai karena password dibagikan manual.
+func GeneratePassword(n int) (string, error) {
+	if n < 8 {
+		n = 8
+	}
+	kelompok := []string{hurufBesarPassword, hurufKecilPassword, angkaPassword, simbolPassword}
+	semua := hurufBesarPassword + hurufKecilPassword + angkaPassword + simbolPassword
+
+	b := make([]byte, n)
+	for i := range b {
+		sumber := semua
+		if i < len(kelompok) {
+			sumber = kelompok[i]
+		}
+		k, err := rand.Int(rand.Reader, big.NewInt(int64(len(sumber))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = sumber[k.Int64()]
+	}
+
+	// Acak urutan agar empat karakter pertama tidak selalu besar-kecil-angka-simbol
+	for i := len(b) - 1; i > 0; i-- {
+		k, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		j := k.Int64()
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b), nil
+}
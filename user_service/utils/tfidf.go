@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// stopwords bahasa Indonesia dan Inggris yang sering muncul di judul/abstrak penelitian
+var stopwords = map[string]bool{
+	"dan": true, "atau": true, "yang": true, "di": true, "ke": true, "dari": true, "untuk": true,
+	"pada": true, "dengan": true, "dalam": true, "sebagai": true, "terhadap": true, "oleh": true,
+	"ini": true, "itu": true, "adalah": true, "akan": true, "juga": true, "serta": true, "secara": true,
+	"berbasis": true, "menggunakan": true, "penggunaan": true, "studi": true, "kasus": true,
+	"analisis": true, "implementasi": true, "perancangan": true, "rancang": true, "bangun": true,
+	"sistem": true, "metode": true, "penerapan": true, "pengembangan": true, "penelitian": true,
+	"the": true, "of": true, "and": true, "or": true, "for": true, "in": true, "on": true, "to": true,
+	"a": true, "an": true, "with": true, "using": true, "based": true, "by": true, "is": true,
+}
+
+// Tokenize memecah teks menjadi kata huruf kecil tanpa tanda baca dan stopword
+func Tokenize(teks string) []string {
+	kata := strings.FieldsFunc(strings.ToLower(teks), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	hasil := make([]string, 0, len(kata))
+	for _, k := range kata {
+		if len(k) < 3 || stopwords[k] {
+			continue
+		}
+		hasil = append(hasil, k)
+	}
+	return hasil
+}
+
+// KorpusTFIDF menyimpan bobot TF-IDF setiap dokumen dalam korpus kecil (mis. profil dosen)
+type KorpusTFIDF struct {
+	idf     map[string]float64
+	vektor  []map[string]float64
+	panjang []float64
+}
+
+// NewKorpusTFIDF membangun korpus dari daftar dokumen yang sudah ditokenisasi
+func NewKorpusTFIDF(dokumen [][]string) *KorpusTFIDF {
+	df := map[string]int{}
+	for _, doc := range dokumen {
+		unik := map[string]bool{}
+		for _, t := range doc {
+			if !unik[t] {
+				unik[t] = true
+				df[t]++
+			}
+		}
+	}
+
+	// IDF dengan smoothing agar istilah yang muncul di semua dokumen tetap berbobot kecil, bukan nol
+	n := float64(len(dokumen))
+	k := &KorpusTFIDF{idf: map[string]float64{}}
+	for t, f := range df {
+		k.idf[t] = math.Log((1+n)/(1+float64(f))) + 1
+	}
+	for _, doc := range dokumen {
+		v := k.bobot(doc)
+		k.vektor = append(k.vektor, v)
+		k.panjang = append(k.panjang, norma(v))
+	}
+	return k
+}
+
+// bobot menghitung vektor TF-IDF; istilah yang tidak ada di korpus diabaikan
+func (k *KorpusTFIDF) bobot(tokens []string) map[string]float64 {
+	tf := map[string]float64{}
+	for _, t := range tokens {
+		if _, ok := k.idf[t]; ok {
+			tf[t]++
+		}
+	}
+	for t, f := range tf {
+		tf[t] = (1 + math.Log(f)) * k.idf[t]
+	}
+	return tf
+}
+
+func norma(v map[string]float64) float64 {
+	var total float64
+	for _, x := range v {
+		total += x * x
+	}
+	return math.Sqrt(total)
+}
+
+// Skor mengembalikan kemiripan kosinus query terhadap dokumen ke-i beserta istilah yang cocok,
+// diurutkan dari kontribusi terbesar
+func (k *KorpusTFIDF) Skor(query []string, i int) (float64, []string) {
+	q := k.bobot(query)
+	nq := norma(q)
+	if nq == 0 || k.panjang[i] == 0 {
+		return 0, []string{}
+	}
+
+	var dot float64
+	kontribusi := map[string]float64{}
+	for t, w := range q {
+		if d, ok := k.vektor[i][t]; ok {
+			dot += w * d
+			kontribusi[t] = w * d
+		}
+	}
+	istilah := make([]string, 0, len(kontribusi))
+	for t := range kontribusi {
+		istilah = append(istilah, t)
+	}
+	sort.Slice(istilah, func(a, b int) bool {
+		if kontribusi[istilah[a]] != kontribusi[istilah[b]] {
+			return kontribusi[istilah[a]] > kontribusi[istilah[b]]
+		}
+		return istilah[a] < istilah[b]
+	})
+	return dot / (nq * k.panjang[i]), istilah
+}